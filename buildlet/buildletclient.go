@@ -19,6 +19,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"golang.org/x/build/pargzip"
 )
 
 var _ Client = (*client)(nil)
@@ -408,10 +410,22 @@ func (c *client) doOK(req *http.Request) error {
 // The dir is created if necessary.
 // The Reader must be of a tar.gz file.
 func (c *client) PutTar(ctx context.Context, r io.Reader, dir string) error {
+	return c.PutTarAs(ctx, r, dir, pargzip.GzipContentType)
+}
+
+// PutTarAs is like PutTar, but the Reader is of a tar file compressed
+// in the format with the given content type: one of
+// pargzip.GzipContentType, pargzip.ZstdContentType, or
+// pargzip.XzContentType. Buildlets built before zstd and xz support
+// only accept gzip.
+func (c *client) PutTarAs(ctx context.Context, r io.Reader, dir, contentType string) error {
 	req, err := http.NewRequest("PUT", c.URL()+"/writetgz?dir="+url.QueryEscape(dir), r)
 	if err != nil {
 		return err
 	}
+	if contentType != pargzip.GzipContentType {
+		req.Header.Set("Content-Type", contentType)
+	}
 	return c.doOK(req.WithContext(ctx))
 }
 
@@ -420,6 +434,9 @@ func (c *client) PutTar(ctx context.Context, r io.Reader, dir string) error {
 // If dir is empty, they're placed at the root of the buildlet's work directory.
 // The dir is created if necessary.
 // The url must be of a tar.gz file.
+// Buildlets with a host-level cache untar a cached copy of the file,
+// if they have one that the server reports is unchanged, instead of
+// downloading it again.
 func (c *client) PutTarFromURL(ctx context.Context, tarURL, dir string) error {
 	form := url.Values{
 		"url": {tarURL},
@@ -449,20 +466,39 @@ func (c *client) Put(ctx context.Context, r io.Reader, path string, mode os.File
 // GetTar returns a .tar.gz stream of the given directory, relative to the buildlet's work dir.
 // The provided dir may be empty to get everything.
 func (c *client) GetTar(ctx context.Context, dir string) (io.ReadCloser, error) {
+	tgz, _, err := c.GetTarAs(ctx, dir, pargzip.GzipContentType)
+	return tgz, err
+}
+
+// GetTarAs is like GetTar, but asks for the tar stream to be compressed
+// in the format with the given content type: one of
+// pargzip.GzipContentType, pargzip.ZstdContentType, or
+// pargzip.XzContentType. Buildlets fall back to gzip if they don't
+// support that format, so GetTarAs also returns the content type of the
+// stream it returns.
+func (c *client) GetTarAs(ctx context.Context, dir, contentType string) (tarStream io.ReadCloser, streamType string, err error) {
 	req, err := http.NewRequest("GET", c.URL()+"/tgz?dir="+url.QueryEscape(dir), nil)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+	if contentType != pargzip.GzipContentType {
+		req.Header.Set("Accept", contentType)
 	}
 	res, err := c.do(req.WithContext(ctx))
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	if res.StatusCode != http.StatusOK {
 		slurp, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10))
 		res.Body.Close()
-		return nil, fmt.Errorf("%v; body: %s", res.Status, slurp)
+		return nil, "", fmt.Errorf("%v; body: %s", res.Status, slurp)
 	}
-	return res.Body, nil
+	// Older buildlets always send gzip, without saying so.
+	streamType = pargzip.GzipContentType
+	if ct := res.Header.Get("Content-Type"); ct == pargzip.ZstdContentType || ct == pargzip.XzContentType {
+		streamType = ct
+	}
+	return res.Body, streamType, nil
 }
 
 // ExecOpts are options for a remote command invocation.
@@ -512,6 +548,15 @@ type ExecOpts struct {
 	// response from the buildlet, but before the output begins
 	// writing to Output.
 	OnStartExec func()
+
+	// Stats, if non-nil, is set to the resource usage of the command
+	// once it finishes. It's left unchanged if the buildlet doesn't
+	// report resource usage, as is the case before buildlet version 32.
+	Stats *ExecStats
+
+	// Sandbox, if non-nil, runs the command in a sandbox on the
+	// buildlet. See Sandbox for which buildlets support it.
+	Sandbox *Sandbox
 }
 
 // ErrTimeout is a sentinel error that represents that waiting
@@ -533,25 +578,7 @@ var ErrTimeout = errors.New("buildlet: timeout waiting for command to complete")
 // If the context's deadline is exceeded while waiting for the command
 // to complete, the returned execErr is ErrTimeout.
 func (c *client) Exec(ctx context.Context, cmd string, opts ExecOpts) (remoteErr, execErr error) {
-	var mode string
-	if opts.SystemLevel {
-		mode = "sys"
-	}
-	path := opts.Path
-	if len(path) == 0 && path != nil {
-		// url.Values doesn't distinguish between a nil slice and
-		// a non-nil zero-length slice, so use this sentinel value.
-		path = []string{"$EMPTY"}
-	}
-	form := url.Values{
-		"cmd":    {cmd},
-		"mode":   {mode},
-		"dir":    {opts.Dir},
-		"cmdArg": opts.Args,
-		"env":    opts.ExtraEnv,
-		"path":   path,
-		"debug":  {fmt.Sprint(opts.Debug)},
-	}
+	form := execForm(cmd, opts)
 	req, err := http.NewRequest("POST", c.URL()+"/exec", strings.NewReader(form.Encode()))
 	if err != nil {
 		return nil, err
@@ -603,6 +630,11 @@ func (c *client) Exec(ctx context.Context, cmd string, opts ExecOpts) (remoteErr
 			resc <- errs{execErr: errors.New("missing Process-State trailer from HTTP response; buildlet built with old (<= 1.4) Go?")}
 			return
 		}
+		if v := res.Trailer.Get("Exec-Stats"); v != "" && opts.Stats != nil {
+			if st, err := ParseExecStats(v); err == nil {
+				*opts.Stats = st
+			}
+		}
 		if state != "ok" {
 			resc <- errs{remoteErr: errors.New(state)}
 		} else {
@@ -630,6 +662,31 @@ func (c *client) Exec(ctx context.Context, cmd string, opts ExecOpts) (remoteErr
 	}
 }
 
+// execForm returns the parameters of an /exec request.
+func execForm(cmd string, opts ExecOpts) url.Values {
+	var mode string
+	if opts.SystemLevel {
+		mode = "sys"
+	}
+	path := opts.Path
+	if len(path) == 0 && path != nil {
+		// url.Values doesn't distinguish between a nil slice and
+		// a non-nil zero-length slice, so use this sentinel value.
+		path = []string{"$EMPTY"}
+	}
+	form := url.Values{
+		"cmd":    {cmd},
+		"mode":   {mode},
+		"dir":    {opts.Dir},
+		"cmdArg": opts.Args,
+		"env":    opts.ExtraEnv,
+		"path":   path,
+		"debug":  {fmt.Sprint(opts.Debug)},
+	}
+	addSandboxForm(form, opts.Sandbox)
+	return form
+}
+
 // RemoveAll deletes the provided paths, relative to the work directory.
 func (c *client) RemoveAll(ctx context.Context, paths ...string) error {
 	if len(paths) == 0 {
@@ -0,0 +1,73 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package buildlet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExecStats is the resource usage of a command run by Client.Exec,
+// including any processes it started that were waited for.
+// Resources the buildlet's platform can't measure are zero.
+type ExecStats struct {
+	Wall   time.Duration // elapsed real time
+	User   time.Duration // user CPU time
+	System time.Duration // system CPU time
+
+	// MaxRSS is the maximum resident set size of the command, or of
+	// its largest child, in bytes. On Windows, it's the peak memory
+	// committed by all the command's processes together.
+	MaxRSS int64
+
+	// WriteBytes is the number of bytes written to storage.
+	// On Unix systems, it's estimated from the number of block
+	// output operations.
+	WriteBytes int64
+
+	// OutputBytes is the number of bytes of stdout and stderr.
+	OutputBytes int64
+}
+
+// String returns the stats in the form used by the Exec-Stats trailer
+// of the buildlet's /exec handler, such as
+// "wall=2.5s user=4.1s sys=310ms maxrss=104857600 write=4096 output=1200".
+func (s ExecStats) String() string {
+	return fmt.Sprintf("wall=%v user=%v sys=%v maxrss=%d write=%d output=%d",
+		s.Wall, s.User, s.System, s.MaxRSS, s.WriteBytes, s.OutputBytes)
+}
+
+// ParseExecStats parses stats in the form returned by ExecStats.String.
+// Unknown fields are ignored, so that buildlets can report more.
+func ParseExecStats(v string) (ExecStats, error) {
+	var s ExecStats
+	for _, f := range strings.Fields(v) {
+		key, val, ok := strings.Cut(f, "=")
+		if !ok {
+			return ExecStats{}, fmt.Errorf("buildlet: malformed exec stat %q", f)
+		}
+		var err error
+		switch key {
+		case "wall":
+			s.Wall, err = time.ParseDuration(val)
+		case "user":
+			s.User, err = time.ParseDuration(val)
+		case "sys":
+			s.System, err = time.ParseDuration(val)
+		case "maxrss":
+			s.MaxRSS, err = strconv.ParseInt(val, 10, 64)
+		case "write":
+			s.WriteBytes, err = strconv.ParseInt(val, 10, 64)
+		case "output":
+			s.OutputBytes, err = strconv.ParseInt(val, 10, 64)
+		}
+		if err != nil {
+			return ExecStats{}, fmt.Errorf("buildlet: malformed exec stat %q: %v", f, err)
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package buildlet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExecStats(t *testing.T) {
+	want := ExecStats{
+		Wall:        2500 * time.Millisecond,
+		User:        4100*time.Millisecond + 3*time.Nanosecond,
+		System:      310 * time.Millisecond,
+		MaxRSS:      100 << 20,
+		WriteBytes:  4096,
+		OutputBytes: 1200,
+	}
+	got, err := ParseExecStats(want.String())
+	if err != nil {
+		t.Fatalf("ParseExecStats(%q): %v", want.String(), err)
+	}
+	if got != want {
+		t.Errorf("ParseExecStats(%q) = %+v, want %+v", want.String(), got, want)
+	}
+
+	// Fields added by newer buildlets are ignored.
+	if got, err := ParseExecStats("wall=1s future=7"); err != nil || got != (ExecStats{Wall: time.Second}) {
+		t.Errorf("ParseExecStats with unknown field = %+v, %v", got, err)
+	}
+	for _, bad := range []string{"wall", "wall=fast", "maxrss=1.5"} {
+		if _, err := ParseExecStats(bad); err == nil {
+			t.Errorf("ParseExecStats(%q) succeeded, want error", bad)
+		}
+	}
+}
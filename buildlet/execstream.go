@@ -0,0 +1,391 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package buildlet
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// The /exec-stream protocol runs a command with its standard input,
+// output, and error streamed separately in both directions.
+//
+// The client sends a POST request to /exec-stream with the same
+// parameters as /exec in the URL query, plus pty=true (and optionally
+// term, rows, and cols) to run the command in a pseudo-terminal, and
+// asks to upgrade the connection to the ExecStreamProtocol protocol.
+// Like /connect-ssh, this only needs a plain connection to the buildlet,
+// so it works through revdial.
+//
+// After the buildlet responds with 101 Switching Protocols, both sides
+// exchange frames. The client sends ExecFrameStdin, ExecFrameSignal, and
+// ExecFrameResize frames, and the buildlet sends ExecFrameStdout,
+// ExecFrameStderr, and finally a single ExecFrameExit frame, after which
+// it closes the connection.
+
+// ExecStreamProtocol is the protocol name used to upgrade /exec-stream connections.
+const ExecStreamProtocol = "buildlet-exec"
+
+// An ExecFrameType identifies the contents of an /exec-stream frame.
+type ExecFrameType byte
+
+const (
+	// ExecFrameStdin holds data for the command's standard input.
+	// An empty frame closes the command's standard input.
+	ExecFrameStdin ExecFrameType = 'i'
+
+	// ExecFrameSignal holds the name of a Signal to forward to the command.
+	ExecFrameSignal ExecFrameType = 's'
+
+	// ExecFrameResize holds the new size of the command's terminal,
+	// as encoded by TermSize.MarshalBinary.
+	ExecFrameResize ExecFrameType = 'r'
+
+	// ExecFrameStdout holds output written to the command's standard output.
+	ExecFrameStdout ExecFrameType = 'o'
+
+	// ExecFrameStderr holds output written to the command's standard error.
+	ExecFrameStderr ExecFrameType = 'e'
+
+	// ExecFrameExit holds the command's JSON-encoded ExitStatus.
+	ExecFrameExit ExecFrameType = 'x'
+)
+
+// maxExecFrameSize is the largest frame ReadExecFrame accepts.
+const maxExecFrameSize = 1 << 20
+
+// WriteExecFrame writes a single /exec-stream frame to w: a one-byte
+// type, a four-byte big-endian length, and the data.
+func WriteExecFrame(w io.Writer, typ ExecFrameType, data []byte) error {
+	if len(data) > maxExecFrameSize {
+		return fmt.Errorf("buildlet: exec frame of %d bytes is too large", len(data))
+	}
+	buf := make([]byte, 5+len(data))
+	buf[0] = byte(typ)
+	binary.BigEndian.PutUint32(buf[1:5], uint32(len(data)))
+	copy(buf[5:], data)
+	_, err := w.Write(buf)
+	return err
+}
+
+// ReadExecFrame reads a single /exec-stream frame from r.
+func ReadExecFrame(r io.Reader) (typ ExecFrameType, data []byte, err error) {
+	var hdr [5]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	n := binary.BigEndian.Uint32(hdr[1:])
+	if n > maxExecFrameSize {
+		return 0, nil, fmt.Errorf("buildlet: exec frame of %d bytes is too large", n)
+	}
+	data = make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, unexpectedEOF(err)
+	}
+	return ExecFrameType(hdr[0]), data, nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// A Signal is a signal that can be forwarded to a command
+// run with StreamExec.
+type Signal string
+
+const (
+	SignalInterrupt Signal = "INT"
+	SignalTerminate Signal = "TERM"
+	SignalQuit      Signal = "QUIT"
+)
+
+// Valid reports whether s is a known signal.
+func (s Signal) Valid() bool {
+	switch s {
+	case SignalInterrupt, SignalTerminate, SignalQuit:
+		return true
+	}
+	return false
+}
+
+// TermSize is the size of a terminal, in characters.
+type TermSize struct {
+	Rows, Cols uint16
+}
+
+// MarshalBinary encodes s as two big-endian uint16 values, rows then columns.
+func (s TermSize) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint16(b[0:2], s.Rows)
+	binary.BigEndian.PutUint16(b[2:4], s.Cols)
+	return b, nil
+}
+
+// UnmarshalBinary decodes s as encoded by MarshalBinary.
+func (s *TermSize) UnmarshalBinary(b []byte) error {
+	if len(b) != 4 {
+		return fmt.Errorf("buildlet: bad terminal size of %d bytes", len(b))
+	}
+	s.Rows = binary.BigEndian.Uint16(b[0:2])
+	s.Cols = binary.BigEndian.Uint16(b[2:4])
+	return nil
+}
+
+// ExitStatus describes how a command run with StreamExec finished.
+type ExitStatus struct {
+	// Code is the command's exit code, or -1 if it was killed
+	// by a signal or didn't run.
+	Code int `json:"code"`
+
+	// Signal is the signal that killed the command, if any,
+	// like "interrupt".
+	Signal string `json:"signal,omitempty"`
+
+	// Error is why the command couldn't be started or waited for, if it couldn't.
+	Error string `json:"error,omitempty"`
+}
+
+// Success reports whether the command exited with code 0.
+func (s *ExitStatus) Success() bool {
+	return s.Code == 0 && s.Signal == "" && s.Error == ""
+}
+
+// Err returns nil if the command succeeded, or an error describing
+// how it failed otherwise.
+func (s *ExitStatus) Err() error {
+	if s.Success() {
+		return nil
+	}
+	return errors.New(s.String())
+}
+
+func (s *ExitStatus) String() string {
+	switch {
+	case s.Error != "":
+		return s.Error
+	case s.Signal != "":
+		return "signal: " + s.Signal
+	}
+	return "exit status " + strconv.Itoa(s.Code)
+}
+
+// StreamExecOpts are options for a remote command invocation
+// with StreamExec.
+type StreamExecOpts struct {
+	// ExecOpts holds the options shared with Exec.
+	// Output receives the command's standard output, and its
+	// standard error unless Stderr is set.
+	// OnStartExec runs once the buildlet has accepted the command.
+	ExecOpts
+
+	// Stdin, if non-nil, is streamed to the command's standard input,
+	// which is closed when Stdin returns EOF.
+	// If nil, the command's standard input is empty.
+	Stdin io.Reader
+
+	// Stderr, if non-nil, receives the command's standard error.
+	Stderr io.Writer
+
+	// PTY, if non-nil, runs the command in a pseudo-terminal.
+	// The command's standard output and error then both go to Output.
+	PTY *PTYOpts
+
+	// Signals, if non-nil, delivers signals to forward to the command.
+	Signals <-chan Signal
+}
+
+// PTYOpts are options for the pseudo-terminal of a command
+// run with StreamExec.
+type PTYOpts struct {
+	// Term is the TERM environment variable for the command,
+	// like "xterm-256color".
+	Term string
+
+	// Size is the initial size of the terminal.
+	Size TermSize
+
+	// Resize, if non-nil, delivers new sizes of the terminal.
+	Resize <-chan TermSize
+}
+
+// streamExecForm returns the URL query for an /exec-stream request.
+func streamExecForm(cmd string, opts StreamExecOpts) url.Values {
+	form := execForm(cmd, opts.ExecOpts)
+	if p := opts.PTY; p != nil {
+		form.Set("pty", "true")
+		form.Set("term", p.Term)
+		form.Set("rows", fmt.Sprint(p.Size.Rows))
+		form.Set("cols", fmt.Sprint(p.Size.Cols))
+	}
+	return form
+}
+
+// StreamExec runs cmd on the buildlet like Exec, but streams the command's
+// standard input, output, and error separately, can run it in a
+// pseudo-terminal, and forwards signals to it.
+//
+// The returned error is non-nil if the command couldn't be run or seen to
+// completion. Otherwise, the returned ExitStatus reports how it finished.
+// If ctx is canceled, the connection to the buildlet is closed, which
+// kills the command.
+func (c *client) StreamExec(ctx context.Context, cmd string, opts StreamExecOpts) (*ExitStatus, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	conn, err := c.getDialer()(dialCtx)
+	if err != nil {
+		return nil, fmt.Errorf("error dialing HTTP connection before exec upgrade: %v", err)
+	}
+	defer conn.Close()
+	deadline, _ := dialCtx.Deadline()
+	conn.SetDeadline(deadline)
+	req, err := http.NewRequest("POST", "/exec-stream?"+streamExecForm(cmd, opts).Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", ExecStreamProtocol)
+	if !c.tls.IsZero() {
+		req.SetBasicAuth(c.authUsername(), c.password)
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, fmt.Errorf("writing /exec-stream HTTP request failed: %v", err)
+	}
+	bufr := bufio.NewReader(conn)
+	res, err := http.ReadResponse(bufr, req)
+	if err != nil {
+		return nil, fmt.Errorf("reading /exec-stream response: %v", err)
+	}
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		slurp, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10))
+		return nil, fmt.Errorf("buildlet: HTTP status %v: %s", res.Status, slurp)
+	}
+	conn.SetDeadline(time.Time{})
+	condRun(opts.OnStartExec)
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	done := make(chan struct{})
+	defer close(done)
+	var stdinc chan []byte // of data read from opts.Stdin; nil for its end
+	if opts.Stdin != nil {
+		stdinc = make(chan []byte)
+		go func() {
+			for {
+				buf := make([]byte, 32<<10)
+				n, err := opts.Stdin.Read(buf)
+				if n > 0 {
+					select {
+					case stdinc <- buf[:n]:
+					case <-done:
+						return
+					}
+				}
+				if err != nil {
+					select {
+					case stdinc <- nil:
+					case <-done:
+					}
+					return
+				}
+			}
+		}()
+	} else if err := WriteExecFrame(conn, ExecFrameStdin, nil); err != nil {
+		return nil, err
+	}
+	var resize <-chan TermSize
+	if opts.PTY != nil {
+		resize = opts.PTY.Resize
+	}
+	if stdinc != nil || opts.Signals != nil || resize != nil {
+		// Frames from the client are written by this goroutine, which
+		// writes signals and resizes ahead of any standard input, so
+		// that they don't wait for a command that isn't reading it.
+		go func() {
+			stdinc := stdinc
+			var err error
+			for err == nil {
+				select {
+				case sig := <-opts.Signals:
+					err = WriteExecFrame(conn, ExecFrameSignal, []byte(sig))
+					continue
+				case size := <-resize:
+					b, _ := size.MarshalBinary()
+					err = WriteExecFrame(conn, ExecFrameResize, b)
+					continue
+				case <-done:
+					return
+				default:
+				}
+				select {
+				case sig := <-opts.Signals:
+					err = WriteExecFrame(conn, ExecFrameSignal, []byte(sig))
+				case size := <-resize:
+					b, _ := size.MarshalBinary()
+					err = WriteExecFrame(conn, ExecFrameResize, b)
+				case data := <-stdinc:
+					if data == nil {
+						stdinc = nil // closed by this empty frame
+					}
+					err = WriteExecFrame(conn, ExecFrameStdin, data)
+				case <-done:
+					return
+				}
+			}
+			// The connection is broken, which the reading of frames
+			// below reports.
+		}()
+	}
+
+	stdout, stderr := opts.Output, opts.Stderr
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = stdout
+	}
+	for {
+		typ, data, err := ReadExecFrame(bufr)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, ErrTimeout
+			}
+			return nil, fmt.Errorf("reading /exec-stream frame: %w", unexpectedEOF(err))
+		}
+		switch typ {
+		case ExecFrameStdout:
+			if _, err := stdout.Write(data); err != nil {
+				return nil, fmt.Errorf("error copying output: %w", err)
+			}
+		case ExecFrameStderr:
+			if _, err := stderr.Write(data); err != nil {
+				return nil, fmt.Errorf("error copying output: %w", err)
+			}
+		case ExecFrameExit:
+			st := new(ExitStatus)
+			if err := json.Unmarshal(data, st); err != nil {
+				return nil, fmt.Errorf("decoding exit status: %v", err)
+			}
+			return st, nil
+		default:
+			return nil, fmt.Errorf("unexpected /exec-stream frame type %q", typ)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package buildlet
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestExecFrames(t *testing.T) {
+	var buf bytes.Buffer
+	frames := []struct {
+		typ  ExecFrameType
+		data string
+	}{
+		{ExecFrameStdout, "hello"},
+		{ExecFrameStdin, ""},
+		{ExecFrameSignal, "INT"},
+	}
+	for _, f := range frames {
+		if err := WriteExecFrame(&buf, f.typ, []byte(f.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range frames {
+		typ, data, err := ReadExecFrame(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ != f.typ || string(data) != f.data {
+			t.Errorf("ReadExecFrame = %q, %q; want %q, %q", typ, data, f.typ, f.data)
+		}
+	}
+	if _, _, err := ReadExecFrame(&buf); err != io.EOF {
+		t.Errorf("ReadExecFrame at end = %v, want EOF", err)
+	}
+
+	WriteExecFrame(&buf, ExecFrameStdout, []byte("truncated"))
+	buf.Truncate(buf.Len() - 1)
+	if _, _, err := ReadExecFrame(&buf); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ReadExecFrame of truncated frame = %v, want ErrUnexpectedEOF", err)
+	}
+}
+
+func TestTermSizeBinary(t *testing.T) {
+	want := TermSize{Rows: 50, Cols: 300}
+	b, _ := want.MarshalBinary()
+	var got TermSize
+	if err := got.UnmarshalBinary(b); err != nil || got != want {
+		t.Errorf("UnmarshalBinary(MarshalBinary(%v)) = %v, %v", want, got, err)
+	}
+	if err := got.UnmarshalBinary(b[:3]); err == nil {
+		t.Errorf("UnmarshalBinary of 3 bytes succeeded")
+	}
+}
+
+func TestExitStatusString(t *testing.T) {
+	for _, tt := range []struct {
+		st   ExitStatus
+		want string
+	}{
+		{ExitStatus{Code: 0}, "exit status 0"},
+		{ExitStatus{Code: 2}, "exit status 2"},
+		{ExitStatus{Code: -1, Signal: "interrupt"}, "signal: interrupt"},
+		{ExitStatus{Code: -1, Error: "exec: not found"}, "exec: not found"},
+	} {
+		if got := tt.st.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.st, got, tt.want)
+		}
+		if got, want := tt.st.Err() == nil, tt.st.Success(); got != want {
+			t.Errorf("%+v: Err() == nil is %v, but Success() is %v", tt.st, got, want)
+		}
+	}
+}
+
+// endlessReader is an io.Reader of endless zeros.
+type endlessReader struct{}
+
+func (endlessReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestStreamExecSignalAheadOfStdin(t *testing.T) {
+	cc, sc := net.Pipe()
+	cl := NewClient("buildlet", NoKeyPair).(*client)
+	cl.SetDialer(func(context.Context) (net.Conn, error) { return cc, nil })
+
+	signals := make(chan Signal, 1)
+	paused := make(chan struct{})
+	resume := make(chan struct{})
+	errc := make(chan error, 1)
+	go func() {
+		defer sc.Close()
+		errc <- func() error {
+			br := bufio.NewReader(sc)
+			if _, err := http.ReadRequest(br); err != nil {
+				return err
+			}
+			io.WriteString(sc, "HTTP/1.1 101 Switching Protocols\r\n\r\n")
+			if typ, _, err := ReadExecFrame(br); err != nil || typ != ExecFrameStdin {
+				return fmt.Errorf("first frame = %q, %v; want stdin", typ, err)
+			}
+			// Stop reading, like a buildlet whose command doesn't
+			// read its input, while a signal is sent.
+			close(paused)
+			<-resume
+			// At most one frame of input was already being written.
+			for i := 0; ; i++ {
+				typ, data, err := ReadExecFrame(br)
+				if err != nil {
+					return err
+				}
+				if typ == ExecFrameSignal {
+					if Signal(data) != SignalInterrupt {
+						return fmt.Errorf("signal = %q; want %q", data, SignalInterrupt)
+					}
+					break
+				}
+				if i > 0 {
+					return fmt.Errorf("%d frames of type %q before the signal", i+1, typ)
+				}
+			}
+			st, _ := json.Marshal(ExitStatus{Code: 1})
+			return WriteExecFrame(sc, ExecFrameExit, st)
+		}()
+	}()
+	go func() {
+		<-paused
+		signals <- SignalInterrupt
+		close(resume)
+	}()
+	st, err := cl.StreamExec(context.Background(), "cat", StreamExecOpts{
+		Stdin:   endlessReader{},
+		Signals: signals,
+	})
+	if err != nil {
+		t.Fatalf("StreamExec: %v; server: %v", err, <-errc)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if st.Code != 1 {
+		t.Errorf("exit status = %v; want exit status 1", st)
+	}
+}
@@ -27,6 +27,10 @@ type RemoteClient interface {
 	ProxyTCP(port int) (io.ReadWriteCloser, error)
 	RemoteName() string
 	RemoveAll(ctx context.Context, paths ...string) error
+	StreamExec(ctx context.Context, cmd string, opts StreamExecOpts) (*ExitStatus, error)
+	Sync(ctx context.Context, dir string, m *SyncManifest, open func(SyncFile) (io.ReadCloser, error)) (*SyncResult, error)
+	Tail(ctx context.Context, path string, opts TailOpts, w io.Writer) error
+	Watch(ctx context.Context, dir string, fn func(WatchEvent)) error
 	WorkDir(ctx context.Context) (string, error)
 }
 
@@ -38,12 +42,14 @@ type RemoteClient interface {
 type Client interface {
 	RemoteClient
 	ConnectSSH(user, authorizedPubKey string) (net.Conn, error)
+	GetTarAs(ctx context.Context, dir, contentType string) (io.ReadCloser, string, error)
 	IPPort() string
 	InstanceName() string
 	IsBroken() bool
 	MarkBroken()
 	Name() string
 	ProxyRoundTripper() http.RoundTripper
+	PutTarAs(ctx context.Context, r io.Reader, dir, contentType string) error
 	SetDescription(v string)
 	SetDialer(dialer func(context.Context) (net.Conn, error))
 	SetHTTPClient(httpClient *http.Client)
@@ -105,6 +111,12 @@ func (fc *FakeClient) GetTar(ctx context.Context, dir string) (io.ReadCloser, er
 	return io.NopCloser(r), nil
 }
 
+// GetTarAs gives a fake tar zipped directory.
+func (fc *FakeClient) GetTarAs(ctx context.Context, dir, contentType string) (io.ReadCloser, string, error) {
+	tgz, err := fc.GetTar(ctx, dir)
+	return tgz, contentType, err
+}
+
 // IPPort provides a fake ip and port pair.
 func (fc *FakeClient) IPPort() string { return "" }
 
@@ -152,6 +164,11 @@ func (fc *FakeClient) PutTar(ctx context.Context, r io.Reader, dir string) error
 	return errUnimplemented
 }
 
+// PutTarAs fakes putting a compressed tar file on a buildlet.
+func (fc *FakeClient) PutTarAs(ctx context.Context, r io.Reader, dir, contentType string) error {
+	return fc.PutTar(ctx, r, dir)
+}
+
 // PutTarFromURL fakes putting a tar zipped file on a builelt.
 func (fc *FakeClient) PutTarFromURL(ctx context.Context, tarURL, dir string) error {
 	return nil
@@ -196,6 +213,76 @@ func (fc *FakeClient) WorkDir(ctx context.Context) (string, error) {
 	return "/work", nil
 }
 
+// StreamExec fakes the execution, consuming all of the standard input.
+func (fc *FakeClient) StreamExec(ctx context.Context, cmd string, opts StreamExecOpts) (*ExitStatus, error) {
+	if opts.Stdin != nil {
+		if _, err := io.Copy(io.Discard, opts.Stdin); err != nil {
+			return nil, err
+		}
+	}
+	remoteErr, execErr := fc.Exec(ctx, cmd, opts.ExecOpts)
+	if execErr != nil {
+		return nil, execErr
+	}
+	if remoteErr != nil {
+		return &ExitStatus{Code: 1}, nil
+	}
+	return &ExitStatus{}, nil
+}
+
+// Sync fakes a sync to a buildlet that has none of the files' contents.
+func (fc *FakeClient) Sync(ctx context.Context, dir string, m *SyncManifest, open func(SyncFile) (io.ReadCloser, error)) (*SyncResult, error) {
+	res := new(SyncResult)
+	for _, f := range m.Files {
+		if open == nil {
+			res.Missing = append(res.Missing, f.Digest)
+			continue
+		}
+		rc, err := open(f)
+		if err != nil {
+			return nil, err
+		}
+		n, err := io.Copy(io.Discard, rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		res.Uploaded += n
+		res.Written++
+	}
+	if len(res.Missing) > 0 {
+		return &SyncResult{Missing: res.Missing}, nil
+	}
+	res.Deleted = len(m.Delete)
+	return res, nil
+}
+
+// Tail writes fake contents of the file, and then waits for the
+// context to be done if opts.Follow is set.
+func (fc *FakeClient) Tail(ctx context.Context, path string, opts TailOpts, w io.Writer) error {
+	if path == "" {
+		return errors.New("invalid path")
+	}
+	if _, err := fmt.Fprintf(w, "fake contents of %s\n", path); err != nil {
+		return err
+	}
+	if opts.Follow {
+		<-ctx.Done()
+	}
+	return nil
+}
+
+// Watch reports the creation of a fake file, and then waits for the
+// context to be done.
+func (fc *FakeClient) Watch(ctx context.Context, dir string, fn func(WatchEvent)) error {
+	if dir == "" {
+		return errors.New("invalid directory")
+	}
+	fn(WatchEvent{Op: "create", Path: "fake.txt", Mode: 0644, Size: 4})
+	<-ctx.Done()
+	return nil
+}
+
 // RemoveAll deletes the provided paths, relative to the work directory for a fake buildlet.
 func (fc *FakeClient) RemoveAll(ctx context.Context, paths ...string) error {
 	// TODO(go.dev/issue/48742) add a file system implementation which would enable proper testing.
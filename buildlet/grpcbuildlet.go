@@ -14,6 +14,8 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/build/internal/gomote/protos"
 	"golang.org/x/build/types"
@@ -83,6 +85,7 @@ func (b *grpcBuildlet) Exec(ctx context.Context, cmd string, opts ExecOpts) (rem
 		Path:              opts.Path,
 		Directory:         opts.Dir,
 		Args:              opts.Args,
+		Sandbox:           sandboxProto(opts.Sandbox),
 	})
 	if err != nil {
 		return nil, err
@@ -106,6 +109,139 @@ func (b *grpcBuildlet) Exec(ctx context.Context, cmd string, opts ExecOpts) (rem
 		if opts.Output != nil {
 			opts.Output.Write(update.Output)
 		}
+		if st := update.GetStats(); st != nil && opts.Stats != nil {
+			*opts.Stats = ExecStats{
+				Wall:        time.Duration(st.GetWallNanos()),
+				User:        time.Duration(st.GetUserNanos()),
+				System:      time.Duration(st.GetSystemNanos()),
+				MaxRSS:      st.GetMaxRssBytes(),
+				WriteBytes:  st.GetWriteBytes(),
+				OutputBytes: st.GetOutputBytes(),
+			}
+		}
+	}
+}
+
+// sandboxProto returns the protocol buffer form of sb.
+func sandboxProto(sb *Sandbox) *protos.Sandbox {
+	if sb == nil {
+		return nil
+	}
+	return &protos.Sandbox{
+		Network:         string(sb.Network),
+		Allow:           sb.Allow,
+		ReadOnlyWorkDir: sb.ReadOnlyWorkDir,
+	}
+}
+
+func (b *grpcBuildlet) StreamExec(ctx context.Context, cmd string, opts StreamExecOpts) (*ExitStatus, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := b.client.StreamCommand(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start := &protos.StreamCommandStart{
+		Command: &protos.ExecuteCommandRequest{
+			GomoteId:          b.id,
+			Command:           cmd,
+			SystemLevel:       opts.SystemLevel,
+			Debug:             opts.Debug,
+			AppendEnvironment: opts.ExtraEnv,
+			Path:              opts.Path,
+			Directory:         opts.Dir,
+			Args:              opts.Args,
+			Sandbox:           sandboxProto(opts.Sandbox),
+		},
+	}
+	if p := opts.PTY; p != nil {
+		start.Pty = true
+		start.Term = p.Term
+		start.Size = &protos.TerminalSize{Rows: uint32(p.Size.Rows), Cols: uint32(p.Size.Cols)}
+	}
+	if err := stream.Send(&protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_Start{Start: start}}); err != nil {
+		return nil, err
+	}
+	if opts.OnStartExec != nil {
+		opts.OnStartExec()
+	}
+
+	// A gRPC stream allows only one goroutine to send at a time.
+	var smu sync.Mutex
+	send := func(req *protos.StreamCommandRequest) error {
+		smu.Lock()
+		defer smu.Unlock()
+		return stream.Send(req)
+	}
+	closeStdin := &protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_CloseStdin{CloseStdin: true}}
+	if opts.Stdin != nil {
+		go func() {
+			buf := make([]byte, 32<<10)
+			for {
+				n, err := opts.Stdin.Read(buf)
+				if n > 0 {
+					data := bytes.Clone(buf[:n])
+					if send(&protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_Stdin{Stdin: data}}) != nil {
+						return
+					}
+				}
+				if err != nil {
+					send(closeStdin)
+					return
+				}
+			}
+		}()
+	} else if err := send(closeStdin); err != nil {
+		return nil, err
+	}
+	var resize <-chan TermSize
+	if opts.PTY != nil {
+		resize = opts.PTY.Resize
+	}
+	if opts.Signals != nil || resize != nil {
+		go func() {
+			for {
+				select {
+				case sig := <-opts.Signals:
+					send(&protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_Signal{Signal: string(sig)}})
+				case size := <-resize:
+					send(&protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_Resize{
+						Resize: &protos.TerminalSize{Rows: uint32(size.Rows), Cols: uint32(size.Cols)},
+					}})
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	stdout, stderr := opts.Output, opts.Stderr
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = stdout
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		switch r := resp.GetResponse().(type) {
+		case *protos.StreamCommandResponse_Stdout:
+			stdout.Write(r.Stdout)
+		case *protos.StreamCommandResponse_Stderr:
+			stderr.Write(r.Stderr)
+		case *protos.StreamCommandResponse_ExitStatus:
+			return &ExitStatus{
+				Code:   int(r.ExitStatus.GetCode()),
+				Signal: r.ExitStatus.GetSignal(),
+				Error:  r.ExitStatus.GetError(),
+			}, nil
+		}
 	}
 }
 
@@ -184,6 +320,104 @@ func (b *grpcBuildlet) PutTarFromURL(ctx context.Context, url string, dir string
 	return err
 }
 
+func (b *grpcBuildlet) Sync(ctx context.Context, dir string, m *SyncManifest, open func(SyncFile) (io.ReadCloser, error)) (*SyncResult, error) {
+	req := &protos.SyncFilesRequest{
+		GomoteId:  b.id,
+		Directory: dir,
+		Delete:    m.Delete,
+	}
+	for _, f := range m.Files {
+		req.Files = append(req.Files, &protos.SyncFile{Path: f.Path, Digest: f.Digest, Mode: uint32(f.Mode)})
+	}
+	resp, err := b.client.SyncFiles(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var uploaded int64
+	if missing := resp.GetMissingDigests(); len(missing) > 0 {
+		if open == nil {
+			return &SyncResult{Missing: missing}, nil
+		}
+		pr := syncTGZReader(m, missing, open, &uploaded)
+		req.BlobsUrl, err = b.upload(ctx, pr)
+		pr.Close() // stops the writing if the upload failed early
+		if err != nil {
+			return nil, err
+		}
+		resp, err = b.client.SyncFiles(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.GetMissingDigests()) > 0 {
+			return nil, fmt.Errorf("still missing %d file contents after upload", len(resp.GetMissingDigests()))
+		}
+	}
+	return &SyncResult{
+		Written:  int(resp.GetWritten()),
+		Deleted:  int(resp.GetDeleted()),
+		Uploaded: uploaded,
+	}, nil
+}
+
+func (b *grpcBuildlet) Tail(ctx context.Context, path string, opts TailOpts, w io.Writer) error {
+	stream, err := b.client.TailFile(ctx, &protos.TailFileRequest{
+		GomoteId: b.id,
+		Path:     path,
+		Offset:   opts.Offset,
+		Follow:   opts.Follow,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if opts.Follow && ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if _, err := w.Write(resp.GetData()); err != nil {
+			return err
+		}
+	}
+}
+
+func (b *grpcBuildlet) Watch(ctx context.Context, dir string, fn func(WatchEvent)) error {
+	stream, err := b.client.WatchDirectory(ctx, &protos.WatchDirectoryRequest{
+		GomoteId:  b.id,
+		Directory: dir,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, io.EOF) {
+				return errors.New("buildlet: watch ended unexpectedly")
+			}
+			return err
+		}
+		e := WatchEvent{
+			Op:   resp.GetOp(),
+			Path: resp.GetPath(),
+			Mode: os.FileMode(resp.GetMode()),
+			Size: resp.GetSize(),
+		}
+		if t := resp.GetModTime(); t != 0 {
+			e.ModTime = time.Unix(t, 0)
+		}
+		fn(e)
+	}
+}
+
 func (b *grpcBuildlet) upload(ctx context.Context, r io.Reader) (string, error) {
 	resp, err := b.client.UploadFile(ctx, &protos.UploadFileRequest{})
 	if err != nil {
@@ -0,0 +1,80 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package buildlet
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// A Sandbox limits what a command run by Client.Exec can reach: the
+// network, and the temporary files of other builds on a shared host.
+//
+// A sandboxed command runs in its own Linux mount namespace, with
+// private, empty /tmp and TMPDIR directories that are discarded when
+// it finishes; the buildlet's work directory stays visible if it's in
+// one of them. The rest of the file system is left as it is for the
+// buildlet's user: other builds' work directories outside /tmp, the
+// home directory, and the buildlet's artifact cache can still be read
+// and written.
+//
+// Only Linux buildlets support sandboxes, as of buildlet version 35;
+// others reject commands that ask for one.
+type Sandbox struct {
+	// Network is the network the command can reach.
+	Network SandboxNet
+
+	// Allow lists the hosts the command may connect to if Network is
+	// SandboxNetAllowlist, as "host" or "host:port". A host of the form
+	// "*.example.com" matches any subdomain of example.com.
+	Allow []string
+
+	// ReadOnlyWorkDir makes the buildlet's work directory read-only for
+	// the command, except for its GOCACHE and GOPLSCACHE directories.
+	ReadOnlyWorkDir bool
+}
+
+// SandboxNet is the network policy of a Sandbox.
+type SandboxNet string
+
+const (
+	// SandboxNetHost is the host's network. GO_DISABLE_OUTBOUND_NETWORK
+	// still applies to it.
+	SandboxNetHost SandboxNet = ""
+
+	// SandboxNetNone is no network at all, not even a loopback interface.
+	SandboxNetNone SandboxNet = "none"
+
+	// SandboxNetLoopback is a private loopback interface, so that the
+	// command can talk to servers it starts itself.
+	SandboxNetLoopback SandboxNet = "loopback"
+
+	// SandboxNetAllowlist is a private loopback interface and an HTTP
+	// proxy that connects only to the hosts in Sandbox.Allow. The proxy's
+	// URL is in the HTTP_PROXY and HTTPS_PROXY environment variables,
+	// which the go command and git respect. There's no DNS.
+	SandboxNetAllowlist SandboxNet = "allowlist"
+)
+
+// Valid reports whether n is a known network policy.
+func (n SandboxNet) Valid() bool {
+	switch n {
+	case SandboxNetHost, SandboxNetNone, SandboxNetLoopback, SandboxNetAllowlist:
+		return true
+	}
+	return false
+}
+
+// addSandboxForm adds the parameters describing sb, if non-nil,
+// to the form of an /exec or /exec-stream request.
+func addSandboxForm(form url.Values, sb *Sandbox) {
+	if sb == nil {
+		return
+	}
+	form.Set("sandbox", "true")
+	form.Set("sandboxNet", string(sb.Network))
+	form["sandboxAllow"] = sb.Allow
+	form.Set("sandboxRO", strconv.FormatBool(sb.ReadOnlyWorkDir))
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package buildlet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExecFormSandbox(t *testing.T) {
+	form := execForm("go/bin/go", ExecOpts{})
+	if form.Has("sandbox") {
+		t.Errorf("execForm without a sandbox has sandbox parameter %q", form.Get("sandbox"))
+	}
+	form = execForm("go/bin/go", ExecOpts{Sandbox: &Sandbox{
+		Network:         SandboxNetAllowlist,
+		Allow:           []string{"proxy.golang.org", "*.googlesource.com"},
+		ReadOnlyWorkDir: true,
+	}})
+	for k, want := range map[string][]string{
+		"sandbox":      {"true"},
+		"sandboxNet":   {"allowlist"},
+		"sandboxAllow": {"proxy.golang.org", "*.googlesource.com"},
+		"sandboxRO":    {"true"},
+	} {
+		if got := form[k]; !reflect.DeepEqual(got, want) {
+			t.Errorf("form[%q] = %q, want %q", k, got, want)
+		}
+	}
+}
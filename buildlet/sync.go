@@ -0,0 +1,346 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package buildlet
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// The /sync endpoint makes a directory on the buildlet match a manifest
+// of files and their SHA-1 digests, transferring only the file contents
+// that the buildlet doesn't already have anywhere in the directory.
+//
+// A sync takes one or two POST requests to /sync?dir=<dir>, where dir is
+// relative to the work directory. The first request carries the
+// JSON-encoded SyncManifest. If the buildlet already has the content of
+// every file in the manifest, it applies the manifest; otherwise it
+// changes nothing. Either way, it responds with a JSON-encoded SyncResult
+// listing the missing digests. If any were missing, the second request
+// carries a gzip-compressed tar file, as written by WriteSyncTGZ, holding
+// the manifest again and the missing contents, and the buildlet applies
+// the manifest.
+
+// SyncManifestName is the name of the tar entry holding the manifest
+// in the file written by WriteSyncTGZ.
+const SyncManifestName = "manifest.json"
+
+// A SyncFile is a regular file in a SyncManifest.
+type SyncFile struct {
+	// Path is the slash-separated path of the file,
+	// relative to the directory being synced.
+	Path string `json:"path"`
+
+	// Digest is the lowercase hex SHA-1 digest of the file's contents,
+	// as reported by DirEntry.Digest.
+	Digest string `json:"sha1"`
+
+	// Mode is the file's permission bits.
+	Mode os.FileMode `json:"mode"`
+}
+
+// A SyncManifest describes the desired contents of a directory for Sync.
+type SyncManifest struct {
+	// Files are the files to create or update. Files that already
+	// have the right contents are left alone, apart from their mode.
+	Files []SyncFile `json:"files"`
+
+	// Delete are slash-separated paths, relative to the directory,
+	// to remove along with anything beneath them. They must not be or
+	// contain any of Files. They are removed before any files are written.
+	Delete []string `json:"delete,omitempty"`
+}
+
+// SyncResult is the result of a sync.
+type SyncResult struct {
+	// Missing are the digests of the file contents that the
+	// buildlet doesn't have. If any are listed, the manifest
+	// hasn't been applied.
+	Missing []string `json:"missing,omitempty"`
+
+	// Written is the number of files written.
+	Written int `json:"written"`
+
+	// Deleted is the number of paths deleted.
+	Deleted int `json:"deleted"`
+
+	// Uploaded is the number of bytes of file contents
+	// sent to the buildlet, before compression.
+	Uploaded int64 `json:"uploaded,omitempty"`
+}
+
+// Sync makes the directory dir, relative to the work directory, match the
+// manifest m, like rsync: only the contents the buildlet doesn't already
+// have somewhere in dir are sent, and they're read with open.
+//
+// If open is nil, Sync only applies m if the buildlet already has all the
+// contents it needs; otherwise it changes nothing and returns a result
+// listing the missing digests.
+func (c *client) Sync(ctx context.Context, dir string, m *SyncManifest, open func(SyncFile) (io.ReadCloser, error)) (*SyncResult, error) {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.postSync(ctx, dir, "application/json", bytes.NewReader(body))
+	if err != nil || len(res.Missing) == 0 || open == nil {
+		return res, err
+	}
+
+	var n int64
+	res, err = c.postSync(ctx, dir, "application/gzip", syncTGZReader(m, res.Missing, open, &n))
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Missing) > 0 {
+		return nil, fmt.Errorf("buildlet: still missing %d file contents after upload", len(res.Missing))
+	}
+	res.Uploaded = n
+	return res, nil
+}
+
+// syncTGZReader returns a reader of the file written by WriteSyncTGZ,
+// which it writes as it's read. Once the reader returns io.EOF, *n
+// is the number of bytes of file contents written. Closing the reader
+// stops the writing.
+func syncTGZReader(m *SyncManifest, digests []string, open func(SyncFile) (io.ReadCloser, error), n *int64) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		var err error
+		*n, err = WriteSyncTGZ(pw, m, digests, open)
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+func (c *client) postSync(ctx context.Context, dir, contentType string, body io.Reader) (*SyncResult, error) {
+	req, err := http.NewRequest("POST", c.URL()+"/sync?dir="+url.QueryEscape(dir), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	res, err := c.do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		slurp, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10))
+		return nil, fmt.Errorf("%v; body: %s", res.Status, slurp)
+	}
+	sr := new(SyncResult)
+	if err := json.NewDecoder(res.Body).Decode(sr); err != nil {
+		return nil, fmt.Errorf("decoding /sync response: %v", err)
+	}
+	return sr, nil
+}
+
+// WriteSyncTGZ writes to w a gzip-compressed tar file holding the manifest
+// m and the file contents with the given digests, read with open from
+// files in m. It returns the number of bytes of file contents written.
+func WriteSyncTGZ(w io.Writer, m *SyncManifest, digests []string, open func(SyncFile) (io.ReadCloser, error)) (n int64, err error) {
+	byDigest := make(map[string]SyncFile)
+	for _, f := range m.Files {
+		if _, ok := byDigest[f.Digest]; !ok {
+			byDigest[f.Digest] = f
+		}
+	}
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+	manifest, err := json.Marshal(m)
+	if err != nil {
+		return 0, err
+	}
+	if err := writeTarFile(tw, SyncManifestName, manifest); err != nil {
+		return 0, err
+	}
+	for _, d := range digests {
+		f, ok := byDigest[d]
+		if !ok {
+			return n, fmt.Errorf("buildlet: digest %s isn't in the manifest", d)
+		}
+		m, err := writeSyncBlob(tw, f, open)
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return n, err
+	}
+	return n, zw.Close()
+}
+
+// writeSyncBlob writes the contents of f, read with open, to tw.
+// Contents whose size is known up front are copied without
+// buffering them.
+func writeSyncBlob(tw *tar.Writer, f SyncFile, open func(SyncFile) (io.ReadCloser, error)) (int64, error) {
+	rc, err := open(f)
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+	size := int64(-1)
+	switch r := rc.(type) {
+	case interface{ Size() int64 }:
+		size = r.Size()
+	case interface{ Stat() (fs.FileInfo, error) }:
+		if fi, err := r.Stat(); err == nil && fi.Mode().IsRegular() {
+			size = fi.Size()
+		}
+	}
+	if size < 0 {
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return 0, fmt.Errorf("reading %s: %w", f.Path, err)
+		}
+		if got := fmt.Sprintf("%x", sha1.Sum(data)); got != f.Digest {
+			return 0, fmt.Errorf("buildlet: %s changed during sync; digest is %s, want %s", f.Path, got, f.Digest)
+		}
+		return int64(len(data)), writeTarFile(tw, f.Digest, data)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: f.Digest, Mode: 0644, Size: size}); err != nil {
+		return 0, err
+	}
+	s1 := sha1.New()
+	n, err := io.Copy(io.MultiWriter(tw, s1), rc)
+	if err != nil {
+		return n, fmt.Errorf("reading %s: %w", f.Path, err)
+	}
+	if got := fmt.Sprintf("%x", s1.Sum(nil)); n != size || got != f.Digest {
+		return n, fmt.Errorf("buildlet: %s changed during sync; digest is %s, want %s", f.Path, got, f.Digest)
+	}
+	return n, nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: 0644,
+		Size: int64(len(data)),
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// SyncBlobs are the file contents read by ReadSyncTGZ,
+// which keeps them in a temporary file rather than in memory.
+type SyncBlobs struct {
+	f     *os.File
+	spans map[string]blobSpan // keyed by digest
+}
+
+// A blobSpan is where a file's contents are in SyncBlobs.f.
+type blobSpan struct {
+	off, size int64
+}
+
+// Has reports whether b holds the contents with the digest.
+func (b *SyncBlobs) Has(digest string) bool {
+	_, ok := b.spans[digest]
+	return ok
+}
+
+// Open returns a reader of the contents of f.
+// It fails with an error wrapping fs.ErrNotExist if b doesn't hold them.
+// The reader has a Size method, so WriteSyncTGZ doesn't buffer it.
+func (b *SyncBlobs) Open(f SyncFile) (io.ReadCloser, error) {
+	sp, ok := b.spans[f.Digest]
+	if !ok {
+		return nil, fmt.Errorf("blobs are missing the contents of %s: %w", f.Path, fs.ErrNotExist)
+	}
+	return sectionReadCloser{io.NewSectionReader(b.f, sp.off, sp.size)}, nil
+}
+
+// Close removes the temporary file holding the contents.
+func (b *SyncBlobs) Close() error {
+	err := b.f.Close()
+	os.Remove(b.f.Name())
+	return err
+}
+
+type sectionReadCloser struct {
+	*io.SectionReader
+}
+
+func (sectionReadCloser) Close() error { return nil }
+
+// ReadSyncTGZ reads a file written by WriteSyncTGZ, returning its manifest
+// and the file contents it holds, which it copies to a temporary file in
+// tmpDir, or the default directory for temporary files if tmpDir is empty.
+// It reads at most maxSize bytes of file contents.
+// The caller must close the returned SyncBlobs.
+func ReadSyncTGZ(r io.Reader, tmpDir string, maxSize int64) (_ *SyncManifest, _ *SyncBlobs, err error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	tf, err := os.CreateTemp(tmpDir, "sync-blobs-")
+	if err != nil {
+		return nil, nil, err
+	}
+	blobs := &SyncBlobs{f: tf, spans: make(map[string]blobSpan)}
+	defer func() {
+		if err != nil {
+			blobs.Close()
+		}
+	}()
+	tr := tar.NewReader(zr)
+	var m *SyncManifest
+	var off int64
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if h.Size > maxSize {
+			return nil, nil, fmt.Errorf("buildlet: sync file contents larger than %d bytes", maxSize)
+		}
+		maxSize -= h.Size
+		if m == nil {
+			if h.Name != SyncManifestName {
+				return nil, nil, fmt.Errorf("buildlet: first sync entry is %q, want %q", h.Name, SyncManifestName)
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, nil, err
+			}
+			m = new(SyncManifest)
+			if err := json.Unmarshal(data, m); err != nil {
+				return nil, nil, fmt.Errorf("decoding sync manifest: %v", err)
+			}
+			continue
+		}
+		s1 := sha1.New()
+		n, err := io.Copy(io.MultiWriter(tf, s1), tr)
+		if err != nil {
+			return nil, nil, err
+		}
+		if got := fmt.Sprintf("%x", s1.Sum(nil)); got != h.Name {
+			return nil, nil, fmt.Errorf("buildlet: sync entry %q has digest %s", h.Name, got)
+		}
+		blobs.spans[h.Name] = blobSpan{off, n}
+		off += n
+	}
+	if m == nil {
+		return nil, nil, errors.New("buildlet: sync file has no manifest")
+	}
+	return m, blobs, nil
+}
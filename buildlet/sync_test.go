@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package buildlet
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSyncTGZ(t *testing.T) {
+	contents := map[string]string{
+		"a.txt":     "hello",
+		"dir/b.txt": "world",
+		"dir/c.txt": "hello",
+	}
+	digest := func(s string) string { return fmt.Sprintf("%x", sha1.Sum([]byte(s))) }
+	m := &SyncManifest{Delete: []string{"old"}}
+	for _, p := range []string{"a.txt", "dir/b.txt", "dir/c.txt"} {
+		m.Files = append(m.Files, SyncFile{Path: p, Digest: digest(contents[p]), Mode: 0644})
+	}
+	open := func(f SyncFile) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(contents[f.Path])), nil
+	}
+
+	var buf bytes.Buffer
+	missing := []string{digest("hello"), digest("world")}
+	n, err := WriteSyncTGZ(&buf, m, missing, open)
+	if err != nil {
+		t.Fatalf("WriteSyncTGZ: %v", err)
+	}
+	if n != 10 {
+		t.Errorf("WriteSyncTGZ wrote %d bytes of contents, want 10", n)
+	}
+	data := buf.Bytes()
+	gotM, blobs, err := ReadSyncTGZ(bytes.NewReader(data), t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("ReadSyncTGZ: %v", err)
+	}
+	defer blobs.Close()
+	if !reflect.DeepEqual(gotM, m) {
+		t.Errorf("ReadSyncTGZ manifest = %+v, want %+v", gotM, m)
+	}
+	for _, f := range m.Files {
+		rc, err := blobs.Open(f)
+		if err != nil {
+			t.Fatalf("blobs.Open(%s): %v", f.Path, err)
+		}
+		got, _ := io.ReadAll(rc)
+		rc.Close()
+		if string(got) != contents[f.Path] {
+			t.Errorf("blobs.Open(%s) = %q, want %q", f.Path, got, contents[f.Path])
+		}
+	}
+	if _, err := blobs.Open(SyncFile{Path: "x", Digest: digest("x")}); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("blobs.Open of missing contents = %v, want fs.ErrNotExist", err)
+	}
+	if _, _, err := ReadSyncTGZ(bytes.NewReader(data), t.TempDir(), 5); err == nil {
+		t.Errorf("ReadSyncTGZ with too small a limit succeeded")
+	}
+
+	// Contents of a known size are copied without buffering them,
+	// and must still match their digests.
+	var buf2 bytes.Buffer
+	if _, err := WriteSyncTGZ(&buf2, m, missing, blobs.Open); err != nil {
+		t.Fatalf("WriteSyncTGZ from blobs: %v", err)
+	}
+	if !bytes.Equal(buf2.Bytes(), data) {
+		t.Errorf("WriteSyncTGZ from blobs wrote a different file")
+	}
+	badOpen := func(f SyncFile) (io.ReadCloser, error) {
+		return blobs.Open(SyncFile{Path: f.Path, Digest: digest("world")})
+	}
+	if _, err := WriteSyncTGZ(io.Discard, m, missing, badOpen); err == nil {
+		t.Errorf("WriteSyncTGZ of contents with the wrong digest succeeded")
+	}
+
+	// A file that changes while syncing is an error.
+	contents["a.txt"] = "changed"
+	if _, err := WriteSyncTGZ(io.Discard, m, missing, open); err == nil {
+		t.Errorf("WriteSyncTGZ of a changed file succeeded")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package buildlet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// TailOpts are options for Client.Tail.
+type TailOpts struct {
+	// Offset is the offset in the file at which to start.
+	// If negative, it's relative to the end of the file,
+	// so -1024 starts with the last 1 KiB.
+	Offset int64
+
+	// Follow, if true, continues to send bytes appended to the file,
+	// like tail -f, until the context is done. If the file doesn't
+	// exist yet, Tail waits for it to be created, and if it's
+	// truncated, Tail starts again at its beginning.
+	Follow bool
+}
+
+// Tail writes the contents of the file at path on the buildlet to w.
+//
+// The path may be an absolute or relative path using the buildlet's
+// native path separator, or a slash-separated relative path. If
+// relative, it is relative to the buildlet's work directory.
+//
+// If opts.Follow is set, Tail returns only when the context is done,
+// and it returns nil then.
+func (c *client) Tail(ctx context.Context, path string, opts TailOpts, w io.Writer) error {
+	form := url.Values{
+		"path":   {path},
+		"offset": {strconv.FormatInt(opts.Offset, 10)},
+		"follow": {strconv.FormatBool(opts.Follow)},
+	}
+	res, err := c.getStream(ctx, "/tail?"+form.Encode())
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, err = io.Copy(w, res.Body)
+	if opts.Follow && ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// A WatchEvent is a change to a file seen by Client.Watch.
+type WatchEvent struct {
+	// Op is what happened to the file:
+	// "create", "write", "chmod", or "remove".
+	Op string `json:"op"`
+
+	// Path is the slash-separated path of the file,
+	// relative to the watched directory.
+	Path string `json:"path"`
+
+	// Mode, Size, and ModTime describe the file after the change,
+	// and are zero for removed files.
+	Mode    os.FileMode `json:"mode,omitempty"`
+	Size    int64       `json:"size,omitempty"`
+	ModTime time.Time   `json:"modTime,omitempty"`
+}
+
+// String returns a description of the event, such as
+// "write go/src/log.txt (1024 bytes)".
+func (e WatchEvent) String() string {
+	switch {
+	case e.Op == "remove":
+		return fmt.Sprintf("%s %s", e.Op, e.Path)
+	case e.Mode.IsDir():
+		return fmt.Sprintf("%s %s/", e.Op, e.Path)
+	}
+	return fmt.Sprintf("%s %s (%d bytes)", e.Op, e.Path, e.Size)
+}
+
+// Watch calls fn for each change to the files in the directory dir on
+// the buildlet and its subdirectories, until the context is done.
+// The buildlet notices changes by polling, so changes made in quick
+// succession may be reported as one.
+//
+// The dir may be an absolute or relative path using the buildlet's
+// native path separator, or a slash-separated relative path. If
+// relative, it is relative to the buildlet's work directory.
+//
+// Watch returns nil once the context is done.
+func (c *client) Watch(ctx context.Context, dir string, fn func(WatchEvent)) error {
+	res, err := c.getStream(ctx, "/watch?dir="+url.QueryEscape(dir))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	dec := json.NewDecoder(res.Body)
+	for {
+		var e WatchEvent
+		if err := dec.Decode(&e); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, io.EOF) {
+				return errors.New("buildlet: watch ended unexpectedly")
+			}
+			return err
+		}
+		fn(e)
+	}
+}
+
+// getStream starts a GET request for a streaming response from the
+// buildlet, whose body the caller must close.
+func (c *client) getStream(ctx context.Context, pathAndQuery string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", c.URL()+pathAndQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The buildlet flushes the headers right away, like /exec.
+	res, err := c.doHeaderTimeout(req.WithContext(ctx), 20*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		slurp, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10))
+		res.Body.Close()
+		return nil, fmt.Errorf("%v; body: %s", res.Status, slurp)
+	}
+	return res, nil
+}
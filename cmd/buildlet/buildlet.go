@@ -15,7 +15,6 @@ package main // import "golang.org/x/build/cmd/buildlet"
 import (
 	"archive/tar"
 	"bytes"
-	"compress/gzip"
 	"context"
 	"crypto/sha1"
 	"crypto/tls"
@@ -37,6 +36,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"cloud.google.com/go/compute/metadata"
@@ -47,6 +47,7 @@ import (
 	"golang.org/x/build/buildlet"
 	"golang.org/x/build/internal/cloud"
 	"golang.org/x/build/internal/envutil"
+	"golang.org/x/build/internal/untar"
 	"golang.org/x/build/pargzip"
 )
 
@@ -62,6 +63,11 @@ var (
 	version          = flag.Bool("version", false, "print buildlet version and exit")
 	gomoteServerAddr = flag.String("gomote-server-addr", "gomotessh.golang.org:443", "Gomote server address and port")
 	swarmingBot      = flag.Bool("swarming-bot", false, "start the buildlet on a swarming bot")
+	reverseStreams   = flag.Bool("reverse-streams", true, "in reverse mode, offer to multiplex the connections from the coordinator or gomote server over the registration connection instead of dialing back for each one")
+	cacheDir         = flag.String("cachedir", "AUTO", "Directory in which to cache tar.gz files written to the work directory, across builds. Unlike the work directory, it isn't cleaned up. If AUTO, reverse buildlets use a directory in the user's cache directory, and other buildlets don't cache. If empty, nothing is cached.")
+	cacheSize        = flag.Int64("cachesize", 10<<30, "Maximum total size of the files in -cachedir, in bytes.")
+	untarMaxBytes    = flag.Int64("untar-max-bytes", 0, "If positive, the maximum total size of the files in a tar.gz written to the work directory, in bytes.")
+	untarMaxFiles    = flag.Int("untar-max-files", 0, "If positive, the maximum number of entries in a tar.gz written to the work directory.")
 )
 
 // Bump this whenever something notable happens, or when another
@@ -89,7 +95,13 @@ var (
 //	27: export GOPLSCACHE=$workdir/goplscache
 //	28: add support for gomote server
 //	29: fall back to /bin/sh when SHELL is unset
-const buildletVersion = 29
+//	30: add /exec-stream
+//	31: add /sync
+//	32: report resource usage of /exec commands in the Exec-Stats trailer
+//	33: add host-level artifact cache for /writetgz, and /cache
+//	34: add /tail and /watch
+//	35: add sandboxes for /exec and /exec-stream on Linux
+const buildletVersion = 35
 
 func defaultListenAddr() string {
 	if runtime.GOOS == "darwin" {
@@ -132,6 +144,9 @@ const (
 )
 
 func main() {
+	if v := os.Getenv(sandboxInitEnv); v != "" && sandboxInit != nil {
+		sandboxInit(v) // doesn't return
+	}
 	builderEnv := os.Getenv("GO_BUILDER_ENV")
 	defer teardownOnce()
 	onGCE := metadata.OnGCE()
@@ -233,6 +248,19 @@ func main() {
 		removeAllAndMkdir(processGoplsCacheEnv)
 	}
 
+	if *cacheDir == "AUTO" {
+		*cacheDir = defaultCacheDir(isReverse)
+	}
+	if *cacheDir != "" {
+		c, err := openTGZCache(*cacheDir, *cacheSize)
+		if err != nil {
+			log.Printf("not caching artifacts in %s: %v", *cacheDir, err)
+		} else {
+			artifactCache = c
+			log.Printf("caching artifacts in %s", *cacheDir)
+		}
+	}
+
 	http.HandleFunc("/", handleRoot)
 	http.HandleFunc("/debug/x", handleX)
 
@@ -247,12 +275,17 @@ func main() {
 	http.Handle("/writetgz", requireAuth(handleWriteTGZ))
 	http.Handle("/write", requireAuth(handleWrite))
 	http.Handle("/exec", requireAuth(handleExec))
+	http.Handle("/exec-stream", requireAuth(handleExecStream))
 	http.Handle("/halt", requireAuth(handleHalt))
 	http.Handle("/tgz", requireAuth(handleGetTGZ))
 	http.Handle("/removeall", requireAuth(handleRemoveAll))
 	http.Handle("/workdir", requireAuth(handleWorkDir))
 	http.Handle("/status", requireAuth(handleStatus))
 	http.Handle("/ls", requireAuth(handleLs))
+	http.Handle("/sync", requireAuth(handleSync))
+	http.Handle("/cache", requireAuth(handleCache))
+	http.Handle("/tail", requireAuth(handleTail))
+	http.Handle("/watch", requireAuth(handleWatch))
 	http.Handle("/connect-ssh", requireAuth(handleConnectSSH))
 	http.HandleFunc("/healthz", handleHealthz)
 
@@ -271,6 +304,18 @@ func main() {
 		srv := &http.Server{}
 		err = srv.Serve(ln)
 		log.Printf("http.Serve on reverse connection complete: %v", err)
+		for *swarmingBot && !halting.Load() {
+			// The connection was lost, or the gomote server
+			// restarted. Reconnect to keep serving the same
+			// gomote instance.
+			if ln, err = redialGomoteServer(); err != nil {
+				log.Printf("Unable to reconnect to the gomote server: %v", err)
+				break
+			}
+			log.Printf("Reconnected to the gomote server.")
+			err = srv.Serve(ln)
+			log.Printf("http.Serve on reverse connection complete: %v", err)
+		}
 		log.Printf("buildlet reverse mode exiting.")
 		if *haltEntireOS {
 			// The coordinator disconnects before doHalt has time to
@@ -571,7 +616,15 @@ func handleGetTGZ(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	zw := pargzip.NewWriter(w)
+	// Clients may ask for zstd or xz instead of gzip
+	// with the Accept header.
+	contentType := pargzip.NegotiateContentType(r.Header.Get("Accept"))
+	zw, err := pargzip.NewWriterFor(w, contentType)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
 	tw := tar.NewWriter(zw)
 	base := filepath.Join(*workDir, dir)
 	err = filepath.Walk(base, func(path string, fi os.FileInfo, err error) error {
@@ -655,9 +708,16 @@ func handleWriteTGZ(w http.ResponseWriter, r *http.Request) {
 
 	var tgz io.Reader
 	var urlStr string
+	var ue urlEntry    // validators of tgz's contents, if cacheable
+	var cacheable bool // tgz's contents can be cached
+	contentType := pargzip.GzipContentType
 	switch r.Method {
 	case "PUT":
 		tgz = r.Body
+		// The body may be compressed with zstd or xz instead.
+		if ct := r.Header.Get("Content-Type"); ct == pargzip.ZstdContentType || ct == pargzip.XzContentType {
+			contentType = ct
+		}
 		log.Printf("writetgz: untarring Request.Body into %s", baseDir)
 	case "POST":
 		urlStr = r.FormValue("url")
@@ -667,31 +727,50 @@ func handleWriteTGZ(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		t0 := time.Now()
-		res, err := http.Get(urlStr)
+		body, e, ok, cached, err := fetchTGZ(urlStr)
 		if err != nil {
 			log.Printf("writetgz: failed to fetch tgz URL %s: %v", urlStr, err)
 			http.Error(w, fmt.Sprintf("fetching URL %s: %v", urlStr, err), http.StatusInternalServerError)
 			return
 		}
-		defer res.Body.Close()
-		if res.StatusCode != http.StatusOK {
-			log.Printf("writetgz: failed to fetch tgz URL %s: status=%v", urlStr, res.Status)
-			http.Error(w, fmt.Sprintf("writetgz: fetching provided URL %q: %s", urlStr, res.Status), http.StatusInternalServerError)
-			return
+		defer body.Close()
+		tgz, ue, cacheable = body, e, ok
+		if cached {
+			log.Printf("writetgz: untarring cached %s (revalidated in %v) into %s", urlStr, time.Since(t0), baseDir)
+		} else {
+			log.Printf("writetgz: untarring %s (got headers in %v) into %s", urlStr, time.Since(t0), baseDir)
 		}
-		tgz = res.Body
-		log.Printf("writetgz: untarring %s (got headers in %v) into %s", urlStr, time.Since(t0), baseDir)
 	default:
 		log.Printf("writetgz: invalid method %q", r.Method)
 		http.Error(w, "requires PUT or POST method", http.StatusBadRequest)
 		return
 	}
+	var cw *cacheWriter // non-nil if tgz's contents are being cached
+	if cacheable {
+		var err error
+		if cw, err = artifactCache.create(); err != nil {
+			log.Printf("writetgz: not caching: %v", err)
+			cw = nil
+		} else {
+			defer cw.abort()
+			tgz = io.TeeReader(tgz, cw)
+		}
+	}
 
-	err := untar(tgz, baseDir)
+	err := extractTar(tgz, contentType, baseDir)
 	if err != nil {
 		http.Error(w, err.Error(), httpStatus(err))
 		return
 	}
+	if cw != nil {
+		// Read any padding after the end of the archive,
+		// so that the whole file is cached.
+		if _, err := io.Copy(io.Discard, tgz); err != nil {
+			log.Printf("writetgz: not caching: %v", err)
+		} else if err := cw.commit(urlStr, ue); err != nil {
+			log.Printf("writetgz: caching %s: %v", urlStr, err)
+		}
+	}
 	io.WriteString(w, "OK")
 }
 
@@ -762,118 +841,32 @@ func writeFile(r io.Reader, path string, mode os.FileMode) error {
 	return f.Close()
 }
 
-// untar reads the gzip-compressed tar file from r and writes it into dir.
-func untar(r io.Reader, dir string) (err error) {
-	t0 := time.Now()
-	nFiles := 0
-	madeDir := map[string]bool{}
-	defer func() {
-		td := time.Since(t0)
-		if err == nil {
-			log.Printf("extracted tarball into %s: %d files, %d dirs (%v)", dir, nFiles, len(madeDir), td)
-		} else {
-			log.Printf("error extracting tarball into %s after %d files, %d dirs, %v: %v", dir, nFiles, len(madeDir), td, err)
-		}
-	}()
-	zr, err := gzip.NewReader(r)
+// extractTar reads the tar file from r, compressed in the format with the
+// given content type (see pargzip.NewReaderFor), and writes it into dir.
+func extractTar(r io.Reader, contentType, dir string) error {
+	zr, err := pargzip.NewReaderFor(r, contentType)
 	if err != nil {
-		return badRequestf("requires gzip-compressed body: %w", err)
-	}
-	tr := tar.NewReader(zr)
-	loggedChtimesError := false
-	for {
-		f, err := tr.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			log.Printf("tar reading error: %v", err)
-			return badRequestf("tar error: %w", err)
-		}
-		if f.Typeflag == tar.TypeXGlobalHeader {
-			// golang.org/issue/22748: git archive exports
-			// a global header ('g') which after Go 1.9
-			// (for a bit?) contained an empty filename.
-			// Ignore it.
-			continue
-		}
-		rel, err := nativeRelPath(f.Name)
-		if err != nil {
-			return badRequestf("tar file contained invalid name %q: %v", f.Name, err)
-		}
-		abs := filepath.Join(dir, rel)
-
-		fi := f.FileInfo()
-		mode := fi.Mode()
-		switch {
-		case mode.IsRegular():
-			// Make the directory. This is redundant because it should
-			// already be made by a directory entry in the tar
-			// beforehand. Thus, don't check for errors; the next
-			// write will fail with the same error.
-			dir := filepath.Dir(abs)
-			if !madeDir[dir] {
-				if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
-					return err
-				}
-				madeDir[dir] = true
-			}
-			if runtime.GOOS == "darwin" && mode&0111 != 0 {
-				// See comment in writeFile.
-				err := os.Remove(abs)
-				if err != nil && !errors.Is(err, fs.ErrNotExist) {
-					return err
-				}
-			}
-			wf, err := os.OpenFile(abs, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode.Perm())
-			if err != nil {
-				return err
-			}
-			n, err := io.Copy(wf, tr)
-			if closeErr := wf.Close(); closeErr != nil && err == nil {
-				err = closeErr
-			}
-			if err != nil {
-				return fmt.Errorf("error writing to %s: %v", abs, err)
-			}
-			if n != f.Size {
-				return fmt.Errorf("only wrote %d bytes to %s; expected %d", n, abs, f.Size)
-			}
-			modTime := f.ModTime
-			if modTime.After(t0) {
-				// Clamp modtimes at system time. See
-				// golang.org/issue/19062 when clock on
-				// buildlet was behind the gitmirror server
-				// doing the git-archive.
-				modTime = t0
-			}
-			if !modTime.IsZero() {
-				if err := os.Chtimes(abs, modTime, modTime); err != nil && !loggedChtimesError {
-					// benign error. Gerrit doesn't even set the
-					// modtime in these, and we don't end up relying
-					// on it anywhere (the gomote push command relies
-					// on digests only), so this is a little pointless
-					// for now.
-					log.Printf("error changing modtime: %v (further Chtimes errors suppressed)", err)
-					loggedChtimesError = true // once is enough
-				}
-			}
-			nFiles++
-		case mode.IsDir():
-			if err := os.MkdirAll(abs, 0755); err != nil {
-				return err
-			}
-			madeDir[abs] = true
-		case mode&os.ModeSymlink != 0:
-			// TODO: ignore these for now. They were breaking x/build tests.
-			// Implement these if/when we ever have a test that needs them.
-			// But maybe we'd have to skip creating them on Windows for some builders
-			// without permissions.
-		default:
-			return badRequestf("tar file entry %s contained unsupported file type %v", f.Name, mode)
-		}
+		if contentType == pargzip.GzipContentType {
+			return badRequestf("requires gzip-compressed body: %w", err)
+		}
+		return badRequestf("decompressing body: %w", err)
+	}
+	defer zr.Close()
+	err = untar.Extract(zr, dir, &untar.Options{
+		MaxBytes: *untarMaxBytes,
+		MaxFiles: *untarMaxFiles,
+		// TODO: ignore symlinks for now. They were breaking x/build tests.
+		// Implement these if/when we ever have a test that needs them.
+		// But maybe we'd have to skip creating them on Windows for some builders
+		// without permissions.
+		Symlinks:  untar.LinkSkip,
+		HardLinks: untar.LinkContain,
+	})
+	var ue *untar.Error
+	if errors.As(err, &ue) && ue.Rejected() {
+		return httpError{http.StatusBadRequest, err}
 	}
-	return nil
+	return err
 }
 
 // Process-State is an HTTP Trailer set in the /exec handler to "ok"
@@ -895,46 +888,115 @@ func handleExec(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "HTTP/1.1 or higher required", http.StatusBadRequest)
 		return
 	}
-	// Create *workDir and any needed temporary subdirectories.
-	if !mkdirAllWorkdirOr500(w) {
+	if err := prepareExec(); err != nil {
+		http.Error(w, err.Error(), httpStatus(err))
+		return
+	}
+
+	w.Header().Set("Trailer", hdrProcessState+", "+hdrExecStats) // declare them so we can set them
+
+	debug, _ := strconv.ParseBool(r.FormValue("debug"))
+	cmd, cleanup, err := execCmd(r.Form, r.PostForm)
+	if err != nil {
+		http.Error(w, err.Error(), httpStatus(err))
 		return
 	}
+	defer cleanup()
+
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	cmdOutput := &countingWriter{w: flushWriter{w}}
+	cmd.Stdout = cmdOutput
+	cmd.Stderr = cmdOutput
+
+	log.Printf("[%p] Running %s with args %q and env %q in dir %s",
+		cmd, cmd.Path, cmd.Args, cmd.Env, cmd.Dir)
+
+	if debug {
+		fmt.Fprintf(cmdOutput, ":: Running %s with args %q and env %q in dir %s\n\n",
+			cmd.Path, cmd.Args, cmd.Env, cmd.Dir)
+	}
+
+	t0 := time.Now()
+	var stats buildlet.ExecStats
+	err = cmd.Start()
+	if err == nil {
+		measure := startExecStats(cmd)
+		go func() {
+			select {
+			case <-clientGone:
+				err := killProcessTree(cmd.Process)
+				if err != nil {
+					log.Printf("Kill failed: %v", err)
+				}
+			case <-handlerDone:
+				return
+			}
+		}()
+		err = cmd.Wait()
+		stats = measure()
+	}
+	stats.Wall = time.Since(t0)
+	stats.OutputBytes = cmdOutput.n.Load()
+	state := "ok"
+	if err != nil {
+		if ps := cmd.ProcessState; ps != nil {
+			state = ps.String()
+		} else {
+			state = err.Error()
+		}
+	}
+	w.Header().Set(hdrProcessState, state)
+	w.Header().Set(hdrExecStats, stats.String())
+	log.Printf("[%p] Run = %s, %v", cmd, state, stats)
+}
+
+// prepareExec creates *workDir and any needed temporary subdirectories
+// before running a command.
+func prepareExec() error {
+	if err := os.MkdirAll(*workDir, 0755); err != nil {
+		return err
+	}
 	for _, dir := range []string{processTmpDirEnv, processGoCacheEnv, processGoplsCacheEnv} {
 		if dir == "" {
 			continue
 		}
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+			return err
 		}
 	}
 	if err := checkAndroidEmulator(); err != nil {
-		http.Error(w, "android emulator not running: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("android emulator not running: %w", err)
 	}
+	return nil
+}
 
-	w.Header().Set("Trailer", hdrProcessState) // declare it so we can set it
+// execCmd returns the command described by the parameters of an
+// exec request: cmd, mode, and dir from form, and cmdArg, env, path,
+// and the sandbox parameters from postForm. Its standard input and
+// output are left unset.
+// The returned cleanup function must be called once the command finishes.
+func execCmd(form, postForm url.Values) (cmd *exec.Cmd, cleanup func(), err error) {
+	sysMode := form.Get("mode") == "sys"
 
-	sysMode := r.FormValue("mode") == "sys"
-	debug, _ := strconv.ParseBool(r.FormValue("debug"))
-
-	absCmd, err := absExecCmd(r.FormValue("cmd"), sysMode) // required
+	absCmd, err := absExecCmd(form.Get("cmd"), sysMode) // required
 	if err != nil {
-		http.Error(w, "invalid 'cmd' parameter: "+err.Error(), httpStatus(err))
-		return
+		return nil, nil, fmt.Errorf("invalid 'cmd' parameter: %w", err)
 	}
 
-	absDir, err := absExecDir(r.FormValue("dir"), sysMode, filepath.Dir(absCmd)) // optional
+	absDir, err := absExecDir(form.Get("dir"), sysMode, filepath.Dir(absCmd)) // optional
 	if err != nil {
-		http.Error(w, "invalid 'dir' parameter: "+err.Error(), httpStatus(err))
-		return
+		return nil, nil, fmt.Errorf("invalid 'dir' parameter: %w", err)
 	}
 
-	if f, ok := w.(http.Flusher); ok {
-		f.Flush()
+	sb, err := sandboxParams(postForm)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	postEnv := r.PostForm["env"]
+	postEnv := postForm["env"]
 
 	goarch := "amd64" // unless we find otherwise
 	if v := envutil.Get(runtime.GOOS, postEnv, "GOARCH"); v != "" {
@@ -954,60 +1016,28 @@ func handleExec(w http.ResponseWriter, r *http.Request) {
 	if v := processGoplsCacheEnv; v != "" {
 		env = append(env, "GOPLSCACHE="+v)
 	}
-	if path := r.PostForm["path"]; len(path) > 0 {
+	if path := postForm["path"]; len(path) > 0 {
 		if kv, ok := pathEnv(runtime.GOOS, env, path, *workDir); ok {
 			env = append(env, kv)
 		}
 	}
 	env = envutil.Dedup(runtime.GOOS, env)
 
-	var cmd *exec.Cmd
 	if needsBashWrapper(absCmd) {
 		cmd = exec.Command("bash", absCmd)
 	} else {
 		cmd = exec.Command(absCmd)
 	}
-	cmd.Args = append(cmd.Args, r.PostForm["cmdArg"]...)
+	cmd.Args = append(cmd.Args, postForm["cmdArg"]...)
 	cmd.Env = env
 	envutil.SetDir(cmd, absDir)
-	cmdOutput := flushWriter{w}
-	cmd.Stdout = cmdOutput
-	cmd.Stderr = cmdOutput
-
-	log.Printf("[%p] Running %s with args %q and env %q in dir %s",
-		cmd, cmd.Path, cmd.Args, cmd.Env, cmd.Dir)
-
-	if debug {
-		fmt.Fprintf(cmdOutput, ":: Running %s with args %q and env %q in dir %s\n\n",
-			cmd.Path, cmd.Args, cmd.Env, cmd.Dir)
-	}
-
-	t0 := time.Now()
-	err = cmd.Start()
-	if err == nil {
-		go func() {
-			select {
-			case <-clientGone:
-				err := killProcessTree(cmd.Process)
-				if err != nil {
-					log.Printf("Kill failed: %v", err)
-				}
-			case <-handlerDone:
-				return
-			}
-		}()
-		err = cmd.Wait()
-	}
-	state := "ok"
-	if err != nil {
-		if ps := cmd.ProcessState; ps != nil {
-			state = ps.String()
-		} else {
-			state = err.Error()
+	cleanup = func() {}
+	if sb != nil {
+		if cleanup, err = sandboxCommand(cmd, sb); err != nil {
+			return nil, nil, err
 		}
 	}
-	w.Header().Set(hdrProcessState, state)
-	log.Printf("[%p] Run = %s, after %v", cmd, state, time.Since(t0))
+	return cmd, cleanup, nil
 }
 
 // absExecCmd returns the native, absolute path corresponding to the "cmd"
@@ -1214,11 +1244,16 @@ func windowsPath(old string, goarch string) string {
 	return strings.Join(newPath, string(filepath.ListSeparator))
 }
 
+// halting is set once the buildlet was asked to halt, so that it
+// doesn't reconnect when its connection ends.
+var halting atomic.Bool
+
 func handleHalt(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
 		http.Error(w, "requires POST method", http.StatusBadRequest)
 		return
 	}
+	halting.Store(true)
 
 	// Do the halt in 1 second, to give the HTTP response time to
 	// complete.
@@ -0,0 +1,455 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// artifactCache, if non-nil, is the host-level cache of tar.gz files
+// fetched by /writetgz. It outlives the work directory, so builds on
+// long-lived hosts can reuse bootstrap toolchains and source tarballs
+// fetched by earlier builds.
+var artifactCache *tgzCache
+
+// defaultCacheDir returns the cache directory to use for the -cachedir
+// value AUTO: a directory in the user's cache directory for reverse
+// buildlets, whose hosts run many builds, and none otherwise.
+func defaultCacheDir(isReverse bool) string {
+	if !isReverse {
+		return ""
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		log.Printf("not caching artifacts: %v", err)
+		return ""
+	}
+	return filepath.Join(dir, "golang-buildlet")
+}
+
+// A tgzCache is a size-bounded directory of tar.gz files, named by the
+// SHA-256 digests of their contents, along with an index of the URLs
+// they were fetched from. When the files' total size exceeds the bound,
+// the least recently used files are evicted.
+//
+// The contents of a URL can change, so a cached file is only used after
+// the server confirms, with a conditional request, that the URL's
+// contents haven't changed. Files from servers that don't send an ETag
+// or Last-Modified header aren't cached.
+//
+// The directory holds:
+//
+//	blobs/<sha256>  a cached file
+//	index.json      a JSON map from URL to its urlEntry
+//	tmp/            files being written
+type tgzCache struct {
+	dir string
+	max int64 // maximum total size of blobs, in bytes
+
+	mu        sync.Mutex
+	entries   map[string]*cacheEntry // keyed by digest
+	urls      map[string]urlEntry
+	size      int64 // total size of entries
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+type cacheEntry struct {
+	size     int64
+	lastUsed time.Time
+}
+
+// A urlEntry records the cached contents of a URL and the validators
+// the server sent with them.
+type urlEntry struct {
+	SHA256       string `json:"sha256"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// newURLEntry returns the urlEntry for a response with header h.
+// It reports false if the response has no validators, and so can't
+// be cached.
+func newURLEntry(h http.Header) (urlEntry, bool) {
+	ue := urlEntry{ETag: h.Get("ETag"), LastModified: h.Get("Last-Modified")}
+	return ue, ue.ETag != "" || ue.LastModified != ""
+}
+
+// setConditional sets the headers of a request for the URL that make
+// the server reply 304 Not Modified if its contents haven't changed.
+func (ue urlEntry) setConditional(h http.Header) {
+	if ue.ETag != "" {
+		h.Set("If-None-Match", ue.ETag)
+	}
+	if ue.LastModified != "" {
+		h.Set("If-Modified-Since", ue.LastModified)
+	}
+}
+
+// openTGZCache opens the cache in dir, creating it if needed,
+// and evicts files until their total size is at most max bytes.
+func openTGZCache(dir string, max int64) (*tgzCache, error) {
+	c := &tgzCache{
+		dir:     dir,
+		max:     max,
+		entries: make(map[string]*cacheEntry),
+		urls:    make(map[string]urlEntry),
+	}
+	if err := os.RemoveAll(c.path("tmp")); err != nil {
+		return nil, err
+	}
+	for _, d := range []string{"blobs", "tmp"} {
+		if err := os.MkdirAll(c.path(d), 0755); err != nil {
+			return nil, err
+		}
+	}
+	des, err := os.ReadDir(c.path("blobs"))
+	if err != nil {
+		return nil, err
+	}
+	for _, de := range des {
+		fi, err := de.Info()
+		if err != nil || !fi.Mode().IsRegular() || !validSHA256(de.Name()) {
+			continue
+		}
+		c.entries[de.Name()] = &cacheEntry{size: fi.Size(), lastUsed: fi.ModTime()}
+		c.size += fi.Size()
+	}
+	if data, err := os.ReadFile(c.path("index.json")); err == nil {
+		if err := json.Unmarshal(data, &c.urls); err != nil {
+			log.Printf("cache: ignoring corrupt index: %v", err)
+			c.urls = make(map[string]urlEntry)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	used := make(map[string]bool)
+	for u, ue := range c.urls {
+		if c.entries[ue.SHA256] == nil {
+			delete(c.urls, u)
+		} else {
+			used[ue.SHA256] = true
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Files that no URL refers to can never be used again.
+	for d := range c.entries {
+		if !used[d] {
+			c.removeLocked(d)
+		}
+	}
+	c.evictLocked("")
+	return c, nil
+}
+
+func (c *tgzCache) path(elem ...string) string {
+	return filepath.Join(append([]string{c.dir}, elem...)...)
+}
+
+// cacheableURL reports whether the contents of u can be cached by URL.
+// URLs with query parameters, such as signed URLs, usually differ for
+// each request, so they're not worth caching.
+func cacheableURL(u string) bool {
+	pu, err := url.Parse(u)
+	return err == nil && (pu.Scheme == "http" || pu.Scheme == "https") && pu.RawQuery == ""
+}
+
+// open returns the cached contents of urlStr and their urlEntry, which
+// the caller must revalidate before using the file.
+// It reports false if there's no such file.
+func (c *tgzCache) open(urlStr string) (*os.File, urlEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ue, ok := c.urls[urlStr]
+	if !ok {
+		return nil, urlEntry{}, false
+	}
+	f, err := os.Open(c.path("blobs", ue.SHA256))
+	if err != nil {
+		log.Printf("cache: %v", err)
+		return nil, urlEntry{}, false
+	}
+	return f, ue, true
+}
+
+// hit records that the cached file with the given digest was used.
+func (c *tgzCache) hit(digest string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hits++
+	if e := c.entries[digest]; e != nil {
+		e.lastUsed = time.Now()
+		os.Chtimes(c.path("blobs", digest), e.lastUsed, e.lastUsed) // for eviction order after a restart
+	}
+}
+
+// miss records that the contents of a URL had to be fetched.
+func (c *tgzCache) miss() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.misses++
+}
+
+// fetchTGZ fetches the tar.gz file at urlStr. If the cache has a copy of
+// it that the server reports is unchanged, fetchTGZ returns the cached
+// file and reports true. Otherwise, if the response can be cached, it
+// returns the urlEntry for its contents, without their digest.
+func fetchTGZ(urlStr string) (tgz io.ReadCloser, ue urlEntry, cacheable, cached bool, err error) {
+	req, err := http.NewRequest("GET", urlStr, nil)
+	if err != nil {
+		return nil, urlEntry{}, false, false, err
+	}
+	useCache := artifactCache != nil && cacheableURL(urlStr)
+	var f *os.File
+	if useCache {
+		var old urlEntry
+		if f, old, cached = artifactCache.open(urlStr); cached {
+			old.setConditional(req.Header)
+			ue = old
+		}
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if f != nil {
+			f.Close()
+		}
+		return nil, urlEntry{}, false, false, err
+	}
+	if f != nil && res.StatusCode == http.StatusNotModified {
+		res.Body.Close()
+		artifactCache.hit(ue.SHA256)
+		return f, ue, false, true, nil
+	}
+	if f != nil {
+		f.Close()
+	}
+	if useCache {
+		artifactCache.miss()
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, urlEntry{}, false, false, errors.New(res.Status)
+	}
+	ue, cacheable = newURLEntry(res.Header)
+	return res.Body, ue, useCache && cacheable, false, nil
+}
+
+// A cacheWriter writes a new file to a tgzCache.
+type cacheWriter struct {
+	c *tgzCache
+	f *os.File
+	h hash.Hash
+	n int64
+}
+
+// create returns a cacheWriter for a new file in the cache.
+// The caller must call commit or abort.
+func (c *tgzCache) create() (*cacheWriter, error) {
+	f, err := os.CreateTemp(c.path("tmp"), "blob-")
+	if err != nil {
+		return nil, err
+	}
+	return &cacheWriter{c: c, f: f, h: sha256.New()}, nil
+}
+
+func (cw *cacheWriter) Write(p []byte) (int, error) {
+	n, err := cw.f.Write(p)
+	cw.h.Write(p[:n])
+	cw.n += int64(n)
+	return n, err
+}
+
+// abort discards the file being written, if it hasn't been committed.
+func (cw *cacheWriter) abort() {
+	if cw.f != nil {
+		cw.f.Close()
+		os.Remove(cw.f.Name())
+		cw.f = nil
+	}
+}
+
+// commit adds the file written to the cache, recording that it's the
+// contents of urlStr, which the server sent with the validators in ue.
+func (cw *cacheWriter) commit(urlStr string, ue urlEntry) error {
+	defer cw.abort()
+	digest := hex.EncodeToString(cw.h.Sum(nil))
+	if err := cw.f.Close(); err != nil {
+		return err
+	}
+	c := cw.c
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[digest] == nil {
+		if err := os.Rename(cw.f.Name(), c.path("blobs", digest)); err != nil {
+			return err
+		}
+		cw.f = nil
+		c.entries[digest] = &cacheEntry{size: cw.n}
+		c.size += cw.n
+	}
+	c.entries[digest].lastUsed = time.Now()
+	if old, ok := c.urls[urlStr]; ok && old.SHA256 != digest {
+		c.removeUnusedLocked(old.SHA256, urlStr)
+	}
+	ue.SHA256 = digest
+	c.urls[urlStr] = ue
+	c.evictLocked(digest)
+	return c.saveIndexLocked()
+}
+
+// removeUnusedLocked removes the file with the given digest if no URL
+// other than urlStr refers to it.
+func (c *tgzCache) removeUnusedLocked(digest, urlStr string) {
+	for u, ue := range c.urls {
+		if u != urlStr && ue.SHA256 == digest {
+			return
+		}
+	}
+	c.removeLocked(digest)
+}
+
+// removeLocked removes the file with the given digest and the URLs that
+// refer to it. It reports whether the file was removed.
+func (c *tgzCache) removeLocked(d string) bool {
+	if err := os.Remove(c.path("blobs", d)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		// On Windows, files being read can't be removed.
+		log.Printf("cache: removing %s: %v", d, err)
+		return false
+	}
+	c.size -= c.entries[d].size
+	delete(c.entries, d)
+	for u, ue := range c.urls {
+		if ue.SHA256 == d {
+			delete(c.urls, u)
+		}
+	}
+	return true
+}
+
+// evictLocked removes the least recently used files other than keep
+// until the cache is within its size bound.
+func (c *tgzCache) evictLocked(keep string) {
+	if c.size <= c.max {
+		return
+	}
+	var digests []string
+	for d := range c.entries {
+		if d != keep {
+			digests = append(digests, d)
+		}
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		return c.entries[digests[i]].lastUsed.Before(c.entries[digests[j]].lastUsed)
+	})
+	for _, d := range digests {
+		if c.size <= c.max {
+			break
+		}
+		if c.removeLocked(d) {
+			c.evictions++
+		}
+	}
+}
+
+func (c *tgzCache) saveIndexLocked() error {
+	data, err := json.Marshal(c.urls)
+	if err != nil {
+		return err
+	}
+	tmp := c.path("tmp", "index.json")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path("index.json"))
+}
+
+// cacheStatus is the status of the artifact cache, as served by /cache.
+type cacheStatus struct {
+	// Dir is the cache directory.
+	// It's empty if the buildlet doesn't cache files.
+	Dir string `json:"dir,omitempty"`
+
+	MaxBytes  int64 `json:"maxBytes"`  // bound on total size of the cached files
+	Bytes     int64 `json:"bytes"`     // total size of the cached files
+	Hits      int64 `json:"hits"`      // files used from the cache since the buildlet started
+	Misses    int64 `json:"misses"`    // files not found in the cache since the buildlet started
+	Evictions int64 `json:"evictions"` // files evicted since the buildlet started
+
+	// Entries are the cached files, most recently used first.
+	Entries []cacheEntryStatus `json:"entries,omitempty"`
+}
+
+// A cacheEntryStatus describes a file in the artifact cache.
+type cacheEntryStatus struct {
+	SHA256   string    `json:"sha256"`
+	URLs     []string  `json:"urls,omitempty"` // URLs the file was fetched from
+	Size     int64     `json:"size"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// status returns the cache's status.
+func (c *tgzCache) status() cacheStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st := cacheStatus{
+		Dir:       c.dir,
+		MaxBytes:  c.max,
+		Bytes:     c.size,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
+	urls := make(map[string][]string)
+	for u, ue := range c.urls {
+		urls[ue.SHA256] = append(urls[ue.SHA256], u)
+	}
+	for d, e := range c.entries {
+		sort.Strings(urls[d])
+		st.Entries = append(st.Entries, cacheEntryStatus{
+			SHA256:   d,
+			URLs:     urls[d],
+			Size:     e.size,
+			LastUsed: e.lastUsed,
+		})
+	}
+	sort.Slice(st.Entries, func(i, j int) bool {
+		return st.Entries[i].LastUsed.After(st.Entries[j].LastUsed)
+	})
+	return st
+}
+
+// handleCache serves the status of the artifact cache as JSON.
+func handleCache(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "requires GET method", http.StatusBadRequest)
+		return
+	}
+	var st cacheStatus
+	if artifactCache != nil {
+		st = artifactCache.status()
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(st)
+}
+
+// validSHA256 reports whether d looks like a lowercase hex SHA-256 digest.
+func validSHA256(d string) bool {
+	return len(d) == 2*sha256.Size && isLowerHex(d)
+}
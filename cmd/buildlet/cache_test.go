@@ -0,0 +1,170 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func addToCache(t *testing.T, c *tgzCache, urlStr, content string) {
+	t.Helper()
+	cw, err := c.create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(cw, content)
+	if err := cw.commit(urlStr, urlEntry{ETag: `"` + content + `"`}); err != nil {
+		t.Fatalf("commit(%q): %v", urlStr, err)
+	}
+}
+
+func readCache(c *tgzCache, urlStr string) (string, bool) {
+	f, _, ok := c.open(urlStr)
+	if !ok {
+		return "", false
+	}
+	defer f.Close()
+	data, _ := io.ReadAll(f)
+	return string(data), true
+}
+
+func TestTGZCache(t *testing.T) {
+	dir := t.TempDir()
+	c, err := openTGZCache(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addToCache(t, c, "https://example.com/a.tgz", "aaaa")
+	addToCache(t, c, "https://example.com/b.tgz", "bbbb")
+	if got, ok := readCache(c, "https://example.com/a.tgz"); !ok || got != "aaaa" {
+		t.Errorf("cached a.tgz = %q, %v; want %q", got, ok, "aaaa")
+	}
+	c.hit(c.urls["https://example.com/a.tgz"].SHA256)
+	addToCache(t, c, "https://example.com/c.tgz", "cccc")
+
+	// The cache holds at most 10 bytes, so b.tgz, the least recently
+	// used, was evicted.
+	if _, ok := readCache(c, "https://example.com/b.tgz"); ok {
+		t.Errorf("b.tgz wasn't evicted")
+	}
+	st := c.status()
+	if st.Bytes != 8 || len(st.Entries) != 2 || st.Evictions != 1 {
+		t.Errorf("status = %+v, want 2 entries of 8 bytes and 1 eviction", st)
+	}
+
+	// New contents of a URL replace the old ones.
+	addToCache(t, c, "https://example.com/c.tgz", "CCCC")
+	if got, ok := readCache(c, "https://example.com/c.tgz"); !ok || got != "CCCC" {
+		t.Errorf("cached c.tgz = %q, %v; want %q", got, ok, "CCCC")
+	}
+	if st := c.status(); st.Bytes != 8 {
+		t.Errorf("after replacing c.tgz, cache has %d bytes; want 8", st.Bytes)
+	}
+
+	// The cache persists.
+	c, err = openTGZCache(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := readCache(c, "https://example.com/a.tgz"); !ok || got != "aaaa" {
+		t.Errorf("after reopening, cached a.tgz = %q, %v; want %q", got, ok, "aaaa")
+	}
+	if ue := c.urls["https://example.com/a.tgz"]; ue.ETag != `"aaaa"` {
+		t.Errorf("after reopening, a.tgz has ETag %q; want %q", ue.ETag, `"aaaa"`)
+	}
+}
+
+func TestWriteTGZCache(t *testing.T) {
+	defer func(old string) { *workDir = old }(*workDir)
+	*workDir = t.TempDir()
+	defer func(old *tgzCache) { artifactCache = old }(artifactCache)
+	c, err := openTGZCache(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifactCache = c
+
+	makeTGZ := func(content string) []byte {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(zw)
+		tw.WriteHeader(&tar.Header{Name: "f.txt", Mode: 0644, Size: int64(len(content))})
+		io.WriteString(tw, content)
+		tw.Close()
+		zw.Close()
+		return buf.Bytes()
+	}
+	var (
+		content   = "v1"
+		validator = true // whether the server sends an ETag
+		gets      int    // requests for the full contents
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + content + `"`
+		if validator {
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+		}
+		gets++
+		w.Write(makeTGZ(content))
+	}))
+	defer srv.Close()
+
+	writeTGZ := func(dir, urlStr string) string {
+		t.Helper()
+		form := url.Values{"url": {urlStr}}
+		req := httptest.NewRequest("POST", "/writetgz?dir="+dir, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		handleWriteTGZ(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("POST /writetgz of %s = %d %s", urlStr, rec.Code, rec.Body)
+		}
+		got, err := os.ReadFile(filepath.Join(*workDir, dir, "f.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(got)
+	}
+
+	for i, tt := range []struct {
+		content   string
+		validator bool
+		wantGets  int
+	}{
+		{"v1", true, 1},  // fetched and cached
+		{"v1", true, 1},  // revalidated and used from the cache
+		{"v2", true, 2},  // changed, so fetched again
+		{"v2", true, 2},  // the new contents are cached
+		{"v3", false, 3}, // no validator, so fetched
+		{"v3", false, 4}, // and not cached
+	} {
+		content, validator = tt.content, tt.validator
+		dir := fmt.Sprintf("d%d", i)
+		if got := writeTGZ(dir, srv.URL+"/a.tgz"); got != tt.content {
+			t.Errorf("step %d: f.txt = %q; want %q", i, got, tt.content)
+		}
+		if gets != tt.wantGets {
+			t.Errorf("step %d: server sent the contents %d times; want %d", i, gets, tt.wantGets)
+		}
+	}
+	if st := c.status(); st.Hits != 2 || len(st.Entries) != 1 {
+		t.Errorf("status = %+v; want 2 hits and 1 entry", st)
+	}
+}
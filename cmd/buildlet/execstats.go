@@ -0,0 +1,48 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"os/exec"
+	"sync/atomic"
+
+	"golang.org/x/build/buildlet"
+)
+
+// hdrExecStats is an HTTP Trailer set in the /exec handler to the
+// command's resource usage, formatted by buildlet.ExecStats.String.
+const hdrExecStats = "Exec-Stats"
+
+// startExecStats is called after cmd has started. It returns a function
+// to call after cmd has been waited for, which returns cmd's CPU and
+// memory usage. It's replaced on platforms that can measure more than
+// CPU times.
+var startExecStats = func(cmd *exec.Cmd) func() buildlet.ExecStats {
+	return func() buildlet.ExecStats {
+		return processStateStats(cmd)
+	}
+}
+
+// processStateStats returns the CPU times in cmd.ProcessState, if any.
+func processStateStats(cmd *exec.Cmd) buildlet.ExecStats {
+	ps := cmd.ProcessState
+	if ps == nil {
+		return buildlet.ExecStats{}
+	}
+	return buildlet.ExecStats{User: ps.UserTime(), System: ps.SystemTime()}
+}
+
+// countingWriter is an io.Writer that counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n atomic.Int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n.Add(int64(n))
+	return n, err
+}
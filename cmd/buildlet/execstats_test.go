@@ -0,0 +1,39 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestExecStats(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skipf("no sh on %s", runtime.GOOS)
+	}
+	cmd := exec.Command("sh", "-c", "echo hello; echo oops >&2")
+	out := &countingWriter{w: io.Discard}
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	measure := startExecStats(cmd)
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	stats := measure()
+	if stats.MaxRSS <= 0 {
+		t.Errorf("MaxRSS = %d, want > 0", stats.MaxRSS)
+	}
+	if stats.User < 0 || stats.System < 0 {
+		t.Errorf("CPU times = %v, %v; want non-negative", stats.User, stats.System)
+	}
+	if n := out.n.Load(); n != int64(len("hello\noops\n")) {
+		t.Errorf("counted %d bytes of output, want %d", n, len("hello\noops\n"))
+	}
+}
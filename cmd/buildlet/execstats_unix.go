@@ -0,0 +1,40 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !plan9 && !windows
+
+package main
+
+import (
+	"os/exec"
+	"runtime"
+	"syscall"
+
+	"golang.org/x/build/buildlet"
+)
+
+func init() {
+	startExecStats = startExecStatsUnix
+}
+
+func startExecStatsUnix(cmd *exec.Cmd) func() buildlet.ExecStats {
+	return func() buildlet.ExecStats {
+		s := processStateStats(cmd)
+		if cmd.ProcessState == nil {
+			return s
+		}
+		ru, ok := cmd.ProcessState.SysUsage().(*syscall.Rusage)
+		if !ok {
+			return s
+		}
+		s.MaxRSS = int64(ru.Maxrss)
+		if runtime.GOOS != "darwin" && runtime.GOOS != "ios" {
+			s.MaxRSS *= 1024 // kilobytes elsewhere
+		}
+		// Linux counts 512-byte units; other systems count
+		// operations of varying size, which this underestimates.
+		s.WriteBytes = int64(ru.Oublock) * 512
+		return s
+	}
+}
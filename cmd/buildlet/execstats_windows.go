@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"log"
+	"os/exec"
+	"time"
+	"unsafe"
+
+	"golang.org/x/build/buildlet"
+	"golang.org/x/sys/windows"
+)
+
+func init() {
+	startExecStats = startExecStatsWindows
+}
+
+// jobBasicAndIOAccounting is JOBOBJECT_BASIC_AND_IO_ACCOUNTING_INFORMATION.
+type jobBasicAndIOAccounting struct {
+	TotalUserTime             int64 // in 100ns units
+	TotalKernelTime           int64
+	ThisPeriodTotalUserTime   int64
+	ThisPeriodTotalKernelTime int64
+	TotalPageFaultCount       uint32
+	TotalProcesses            uint32
+	ActiveProcesses           uint32
+	TotalTerminatedProcesses  uint32
+	IoInfo                    windows.IO_COUNTERS
+}
+
+// startExecStatsWindows measures the resource usage of cmd and its
+// descendants by putting cmd in a job object. Processes that cmd starts
+// before it's added to the job aren't counted.
+func startExecStatsWindows(cmd *exec.Cmd) func() buildlet.ExecStats {
+	job, err := newProcessJob(cmd.Process.Pid)
+	if err != nil {
+		log.Printf("measuring resource usage of pid %d: %v", cmd.Process.Pid, err)
+		return func() buildlet.ExecStats { return processStateStats(cmd) }
+	}
+	return func() buildlet.ExecStats {
+		defer windows.CloseHandle(job)
+		var acct jobBasicAndIOAccounting
+		if err := windows.QueryInformationJobObject(job, windows.JobObjectBasicAndIoAccountingInformation,
+			uintptr(unsafe.Pointer(&acct)), uint32(unsafe.Sizeof(acct)), nil); err != nil {
+			log.Printf("querying job accounting: %v", err)
+			return processStateStats(cmd)
+		}
+		var limits windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION
+		if err := windows.QueryInformationJobObject(job, windows.JobObjectExtendedLimitInformation,
+			uintptr(unsafe.Pointer(&limits)), uint32(unsafe.Sizeof(limits)), nil); err != nil {
+			log.Printf("querying job memory usage: %v", err)
+		}
+		return buildlet.ExecStats{
+			User:       time.Duration(acct.TotalUserTime) * 100,
+			System:     time.Duration(acct.TotalKernelTime) * 100,
+			MaxRSS:     int64(limits.PeakJobMemoryUsed),
+			WriteBytes: int64(acct.IoInfo.WriteTransferCount),
+		}
+	}
+}
+
+// newProcessJob returns a new job object holding the process pid.
+func newProcessJob(pid int) (windows.Handle, error) {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	p, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		windows.CloseHandle(job)
+		return 0, err
+	}
+	defer windows.CloseHandle(p)
+	if err := windows.AssignProcessToJobObject(job, p); err != nil {
+		windows.CloseHandle(job)
+		return 0, err
+	}
+	return job, nil
+}
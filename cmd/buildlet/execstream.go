@@ -0,0 +1,281 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/build/buildlet"
+	"golang.org/x/build/internal/envutil"
+)
+
+// handleExecStream serves the /exec-stream protocol,
+// described in golang.org/x/build/buildlet.
+func handleExecStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "requires POST method", http.StatusBadRequest)
+		return
+	}
+	if !strings.EqualFold(r.Header.Get("Upgrade"), buildlet.ExecStreamProtocol) {
+		http.Error(w, "requires Upgrade: "+buildlet.ExecStreamProtocol, http.StatusBadRequest)
+		return
+	}
+	if err := prepareExec(); err != nil {
+		http.Error(w, err.Error(), httpStatus(err))
+		return
+	}
+	// An upgrade request has no body, so all the parameters
+	// are in the URL query.
+	q := r.URL.Query()
+	cmd, cleanup, err := execCmd(q, q)
+	if err != nil {
+		http.Error(w, err.Error(), httpStatus(err))
+		return
+	}
+	defer cleanup()
+	debug, _ := strconv.ParseBool(q.Get("debug"))
+	var term *ptyRequest
+	if v, _ := strconv.ParseBool(q.Get("pty")); v {
+		if !ptySupported {
+			http.Error(w, "pseudo-terminals are not supported on "+runtime.GOOS, http.StatusNotImplemented)
+			return
+		}
+		rows, _ := strconv.ParseUint(q.Get("rows"), 10, 16)
+		cols, _ := strconv.ParseUint(q.Get("cols"), 10, 16)
+		term = &ptyRequest{
+			term: q.Get("term"),
+			size: buildlet.TermSize{Rows: uint16(rows), Cols: uint16(cols)},
+		}
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "conn can't hijack", http.StatusInternalServerError)
+		return
+	}
+	conn, bufrw, err := hj.Hijack()
+	if err != nil {
+		log.Printf("exec-stream hijack error: %v", err)
+		http.Error(w, "exec-stream hijack error: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer conn.Close()
+	fmt.Fprintf(conn, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: %s\r\nConnection: Upgrade\r\n\r\n", buildlet.ExecStreamProtocol)
+
+	log.Printf("[%p] Streaming %s with args %q and env %q in dir %s",
+		cmd, cmd.Path, cmd.Args, cmd.Env, cmd.Dir)
+	t0 := time.Now()
+	st := execStream(cmd, bufrw.Reader, conn, term, debug)
+	log.Printf("[%p] Stream = %s, after %v", cmd, st, time.Since(t0))
+}
+
+// stdinQueueLen is the number of standard input frames that
+// /exec-stream buffers for a command that isn't reading them. Once it's
+// full, no more frames are read from the client until the command reads
+// some input.
+const stdinQueueLen = 64
+
+// A ptyRequest holds the pseudo-terminal settings of an /exec-stream request.
+type ptyRequest struct {
+	term string
+	size buildlet.TermSize
+}
+
+// execStream runs cmd, exchanging /exec-stream frames with the client
+// over r and w, and returns the command's exit status after sending it.
+// If term is non-nil, cmd runs in a pseudo-terminal.
+func execStream(cmd *exec.Cmd, r io.Reader, w io.Writer, term *ptyRequest, debug bool) *buildlet.ExitStatus {
+	fw := &frameWriter{w: w}
+	stdout := fw.writer(buildlet.ExecFrameStdout)
+	stderr := fw.writer(buildlet.ExecFrameStderr)
+	if debug {
+		fmt.Fprintf(stdout, ":: Running %s with args %q and env %q in dir %s\n\n",
+			cmd.Path, cmd.Args, cmd.Env, cmd.Dir)
+	}
+
+	var (
+		stdin     io.WriteCloser
+		ptmx      *os.File
+		outCopied = make(chan struct{})
+		err       error
+	)
+	if term != nil {
+		if term.term != "" {
+			envutil.SetEnv(cmd, "TERM="+term.term)
+		}
+		ptmx, err = startPTY(cmd, term.size)
+		if err == nil {
+			stdin = ptmx
+			go func() {
+				io.Copy(stdout, ptmx)
+				close(outCopied)
+			}()
+		}
+	} else {
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		// Lead a process group, so that signals reach the processes
+		// that cmd starts too.
+		setProcessGroup(cmd)
+		stdin, err = cmd.StdinPipe()
+		if err == nil {
+			err = cmd.Start()
+		}
+		close(outCopied) // Wait waits for the output to be copied.
+	}
+	if err != nil {
+		st := &buildlet.ExitStatus{Code: -1, Error: err.Error()}
+		fw.exit(st)
+		return st
+	}
+
+	// Write standard input from its own goroutine, so that a command
+	// that doesn't read it doesn't hold up signals and resizes.
+	// An empty slice means the end of the input.
+	stdinc := make(chan []byte, stdinQueueLen)
+	go func() {
+		var werr error
+		for data := range stdinc {
+			if werr != nil {
+				continue // keep draining, so that the frame loop doesn't block
+			}
+			if len(data) > 0 {
+				_, werr = stdin.Write(data)
+			} else if ptmx != nil {
+				_, werr = stdin.Write([]byte{4}) // ^D, the terminal's EOF character
+			} else {
+				werr = stdin.Close()
+			}
+		}
+	}()
+
+	// Handle frames from the client until it goes away.
+	clientGone := make(chan struct{})
+	go func() {
+		defer close(clientGone)
+		defer close(stdinc)
+		for {
+			typ, data, err := buildlet.ReadExecFrame(r)
+			if err != nil {
+				return
+			}
+			switch typ {
+			case buildlet.ExecFrameStdin:
+				stdinc <- data
+			case buildlet.ExecFrameSignal:
+				sig := buildlet.Signal(data)
+				if !sig.Valid() {
+					log.Printf("[%p] ignoring unknown signal %q", cmd, sig)
+					continue
+				}
+				if err := signalProcess(cmd.Process, sig); err != nil {
+					log.Printf("[%p] signal %s failed: %v", cmd, sig, err)
+				}
+			case buildlet.ExecFrameResize:
+				var size buildlet.TermSize
+				if ptmx == nil || size.UnmarshalBinary(data) != nil {
+					continue
+				}
+				resizePTY(ptmx, size)
+			}
+		}
+	}()
+	waitDone := make(chan struct{})
+	defer close(waitDone)
+	go func() {
+		select {
+		case <-clientGone:
+			if err := killProcessGroup(cmd.Process); err != nil {
+				log.Printf("Kill failed: %v", err)
+			}
+		case <-waitDone:
+		}
+	}()
+
+	err = cmd.Wait()
+	if ptmx != nil {
+		// Output may still be buffered in the terminal.
+		select {
+		case <-outCopied:
+		case <-time.After(5 * time.Second):
+		}
+		ptmx.Close()
+	}
+	st := exitStatus(cmd.ProcessState, err)
+	fw.exit(st)
+	return st
+}
+
+// exitStatus returns the exit status of a process
+// that finished in state ps after Wait returned err.
+func exitStatus(ps *os.ProcessState, err error) *buildlet.ExitStatus {
+	if ps == nil {
+		return &buildlet.ExitStatus{Code: -1, Error: err.Error()}
+	}
+	st := &buildlet.ExitStatus{Code: ps.ExitCode()}
+	if st.Code == -1 {
+		st.Signal = exitSignal(ps)
+		if st.Signal == "" {
+			st.Error = ps.String()
+		}
+	}
+	var ee *exec.ExitError
+	if err != nil && !errors.As(err, &ee) && st.Error == "" {
+		st.Error = err.Error()
+	}
+	return st
+}
+
+// A frameWriter writes /exec-stream frames from several goroutines.
+type frameWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (fw *frameWriter) write(typ buildlet.ExecFrameType, p []byte) error {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	return buildlet.WriteExecFrame(fw.w, typ, p)
+}
+
+// writer returns an io.Writer that writes data as frames of type typ.
+func (fw *frameWriter) writer(typ buildlet.ExecFrameType) io.Writer {
+	return frameTypeWriter{fw, typ}
+}
+
+func (fw *frameWriter) exit(st *buildlet.ExitStatus) {
+	data, _ := json.Marshal(st)
+	fw.write(buildlet.ExecFrameExit, data)
+}
+
+type frameTypeWriter struct {
+	fw  *frameWriter
+	typ buildlet.ExecFrameType
+}
+
+func (w frameTypeWriter) Write(p []byte) (n int, err error) {
+	const chunk = 32 << 10
+	for len(p) > 0 {
+		m := min(len(p), chunk)
+		if err := w.fw.write(w.typ, p[:m]); err != nil {
+			return n, err
+		}
+		n += m
+		p = p[m:]
+	}
+	return n, nil
+}
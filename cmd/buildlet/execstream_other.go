@@ -0,0 +1,43 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build plan9 || windows
+
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"golang.org/x/build/buildlet"
+)
+
+const ptySupported = false
+
+func startPTY(cmd *exec.Cmd, size buildlet.TermSize) (*os.File, error) {
+	return nil, errors.New("pseudo-terminals are not supported on " + runtime.GOOS)
+}
+
+func resizePTY(f *os.File, size buildlet.TermSize) {}
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// signalProcess sends sig to p. Only interrupts can be delivered on Plan 9;
+// other signals, and all signals on Windows, kill the process tree.
+func signalProcess(p *os.Process, sig buildlet.Signal) error {
+	if runtime.GOOS == "plan9" && sig == buildlet.SignalInterrupt {
+		return p.Signal(os.Interrupt)
+	}
+	return killProcessTree(p)
+}
+
+func killProcessGroup(p *os.Process) error {
+	return killProcessTree(p)
+}
+
+func exitSignal(ps *os.ProcessState) string {
+	return ""
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"golang.org/x/build/buildlet"
+)
+
+func TestExecStream(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skipf("no sh on %s", runtime.GOOS)
+	}
+	cmd := exec.Command("sh", "-c", "cat; echo oops >&2; exit 3")
+	inr, inw := io.Pipe()
+	outr, outw := io.Pipe()
+	done := make(chan *buildlet.ExitStatus)
+	go func() {
+		st := execStream(cmd, inr, outw, nil, false)
+		outw.Close()
+		done <- st
+	}()
+
+	buildlet.WriteExecFrame(inw, buildlet.ExecFrameStdin, []byte("hello\n"))
+	buildlet.WriteExecFrame(inw, buildlet.ExecFrameStdin, nil)
+
+	var stdout, stderr string
+	var st buildlet.ExitStatus
+	for {
+		typ, data, err := buildlet.ReadExecFrame(outr)
+		if err != nil {
+			t.Fatalf("ReadExecFrame: %v", err)
+		}
+		if typ == buildlet.ExecFrameExit {
+			if err := json.Unmarshal(data, &st); err != nil {
+				t.Fatal(err)
+			}
+			break
+		}
+		switch typ {
+		case buildlet.ExecFrameStdout:
+			stdout += string(data)
+		case buildlet.ExecFrameStderr:
+			stderr += string(data)
+		default:
+			t.Fatalf("unexpected frame type %q", typ)
+		}
+	}
+	inw.Close()
+	if got := <-done; *got != st {
+		t.Errorf("execStream returned %+v, but sent %+v", got, st)
+	}
+	if stdout != "hello\n" || stderr != "oops\n" {
+		t.Errorf("stdout, stderr = %q, %q; want %q, %q", stdout, stderr, "hello\n", "oops\n")
+	}
+	if want := (buildlet.ExitStatus{Code: 3}); st != want {
+		t.Errorf("exit status = %+v, want %+v", st, want)
+	}
+}
+
+func TestExecStreamSignal(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skipf("no sh on %s", runtime.GOOS)
+	}
+	cmd := exec.Command("sh", "-c", "echo ready; exec sleep 60")
+	inr, inw := io.Pipe()
+	outr, outw := io.Pipe()
+	go func() {
+		execStream(cmd, inr, outw, nil, false)
+		outw.Close()
+	}()
+	defer inw.Close()
+
+	for {
+		typ, data, err := buildlet.ReadExecFrame(outr)
+		if err != nil {
+			t.Fatalf("ReadExecFrame: %v", err)
+		}
+		switch typ {
+		case buildlet.ExecFrameStdout:
+			if string(data) == "ready\n" {
+				buildlet.WriteExecFrame(inw, buildlet.ExecFrameSignal, []byte(buildlet.SignalTerminate))
+			}
+			continue
+		case buildlet.ExecFrameExit:
+		default:
+			t.Fatalf("unexpected frame type %q", typ)
+		}
+		var st buildlet.ExitStatus
+		if err := json.Unmarshal(data, &st); err != nil {
+			t.Fatal(err)
+		}
+		if st.Code != -1 || st.Signal != "terminated" {
+			t.Errorf("exit status = %+v, want killed by SIGTERM", st)
+		}
+		return
+	}
+}
+
+func TestExecStreamSignalUnreadStdin(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skipf("no sh on %s", runtime.GOOS)
+	}
+	// The shell doesn't exec sleep, so the signal must reach
+	// the process group, not just the shell.
+	cmd := exec.Command("sh", "-c", "echo ready; sleep 60; echo done")
+	inr, inw := io.Pipe()
+	outr, outw := io.Pipe()
+	done := make(chan *buildlet.ExitStatus, 1)
+	go func() {
+		done <- execStream(cmd, inr, outw, nil, false)
+		outw.Close()
+	}()
+	defer inw.Close()
+	go io.Copy(io.Discard, outr)
+
+	// More input than a pipe holds, which nothing reads.
+	input := make([]byte, 32<<10)
+	for range 10 {
+		buildlet.WriteExecFrame(inw, buildlet.ExecFrameStdin, input)
+	}
+	buildlet.WriteExecFrame(inw, buildlet.ExecFrameSignal, []byte(buildlet.SignalTerminate))
+	select {
+	case st := <-done:
+		if st.Code == 0 {
+			t.Errorf("exit status = %+v, want a failure after SIGTERM", st)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("signal not delivered to a command that doesn't read its input")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !plan9 && !windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/creack/pty"
+	"golang.org/x/build/buildlet"
+)
+
+const ptySupported = true
+
+// startPTY starts cmd in a new pseudo-terminal of the given size,
+// and returns the terminal's controlling side.
+func startPTY(cmd *exec.Cmd, size buildlet.TermSize) (*os.File, error) {
+	var ws *pty.Winsize
+	if size.Rows > 0 && size.Cols > 0 {
+		ws = &pty.Winsize{Rows: size.Rows, Cols: size.Cols}
+	}
+	return pty.StartWithSize(cmd, ws)
+}
+
+func resizePTY(f *os.File, size buildlet.TermSize) {
+	pty.Setsize(f, &pty.Winsize{Rows: size.Rows, Cols: size.Cols})
+}
+
+// setProcessGroup makes cmd lead a new process group when it starts.
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = new(syscall.SysProcAttr)
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// signalProcess sends sig to the process group that p leads.
+// A command in a pseudo-terminal leads its own session and process
+// group, and one without a terminal is started by setProcessGroup.
+func signalProcess(p *os.Process, sig buildlet.Signal) error {
+	var s syscall.Signal
+	switch sig {
+	case buildlet.SignalInterrupt:
+		s = syscall.SIGINT
+	case buildlet.SignalTerminate:
+		s = syscall.SIGTERM
+	case buildlet.SignalQuit:
+		s = syscall.SIGQUIT
+	}
+	return syscall.Kill(-p.Pid, s)
+}
+
+// killProcessGroup kills the process group that p leads,
+// if any of it is still running.
+func killProcessGroup(p *os.Process) error {
+	if err := syscall.Kill(-p.Pid, syscall.SIGKILL); err != syscall.ESRCH {
+		return err
+	}
+	return nil
+}
+
+// exitSignal returns the name of the signal that killed the process
+// that finished in state ps, or "" if it wasn't killed by a signal.
+func exitSignal(ps *os.ProcessState) string {
+	if ws, ok := ps.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return ws.Signal().String()
+	}
+	return ""
+}
@@ -126,6 +126,9 @@ func dialCoordinator() (net.Listener, error) {
 		req.Header.Set("X-Go-Builder-Hostname", *hostname)
 		req.Header.Set("X-Go-Builder-Version", strconv.Itoa(buildletVersion))
 		req.Header.Set("X-Revdial-Version", "2")
+		if *reverseStreams {
+			revdial.OfferStreams(req)
+		}
 		if err := req.Write(bufw); err != nil {
 			return nil, fmt.Errorf("coordinator /reverse request failed: %v", err)
 		}
@@ -146,7 +149,7 @@ func dialCoordinator() (net.Listener, error) {
 	}
 
 	log.Printf("Connected to coordinator; reverse dialing active")
-	ln := revdial.NewListener(conn, dial)
+	ln := revdial.NewListener(revdial.BufferedConn(conn, bufr), dial)
 	return ln, nil
 }
 
@@ -214,6 +217,9 @@ func dialGomoteServer() (net.Listener, error) {
 		req.Header.Set(rendezvous.HeaderID, os.Getenv("GOMOTEID"))
 		req.Header.Set(rendezvous.HeaderToken, mustSwarmingAuthToken(ctx))
 		req.Header.Set(rendezvous.HeaderHostname, *hostname)
+		if *reverseStreams {
+			revdial.OfferStreams(req)
+		}
 		if err := req.Write(bufw); err != nil {
 			return nil, fmt.Errorf("gomote server /reverse request failed: %v", err)
 		}
@@ -222,7 +228,7 @@ func dialGomoteServer() (net.Listener, error) {
 		}
 		location, err = revdial.ReadProtoSwitchOrRedirect(bufr, req)
 		if err != nil {
-			return nil, fmt.Errorf("gomote server registration failed: %v", err)
+			return nil, fmt.Errorf("gomote server registration failed: %w", err)
 		}
 		if location == "" {
 			success = true
@@ -234,10 +240,42 @@ func dialGomoteServer() (net.Listener, error) {
 	}
 
 	log.Printf("Connected to gomote server; reverse dialing active")
-	ln := revdial.NewListener(conn, dial)
+	ln := revdial.NewListener(revdial.BufferedConn(conn, bufr), dial)
 	return ln, nil
 }
 
+// reconnectTimeout is how long a swarming buildlet tries to reconnect to
+// the gomote server after losing its connection. It matches how long the
+// gomote server waits for buildlets to reconnect after a restart.
+const reconnectTimeout = 10 * time.Minute
+
+// redialGomoteServer dials the gomote server again after the connection
+// was lost, so that the instance keeps serving its gomote session. The
+// gomote server knows the buildlet by its GOMOTEID. It retries until it
+// succeeds, the gomote server rejects the instance, or reconnectTimeout
+// passes.
+func redialGomoteServer() (net.Listener, error) {
+	deadline := time.Now().Add(reconnectTimeout)
+	delay := time.Second
+	for {
+		time.Sleep(delay)
+		ln, err := dialGomoteServer()
+		if err == nil {
+			return ln, nil
+		}
+		var se *revdial.StatusError
+		if errors.As(err, &se) && se.StatusCode == http.StatusPreconditionFailed {
+			// The gomote server no longer expects this instance.
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+		delay = min(2*delay, 30*time.Second)
+		log.Printf("Reconnecting to the gomote server failed: %v; retrying in %v", err, delay)
+	}
+}
+
 var coordDialer = &net.Dialer{
 	Timeout:   10 * time.Second,
 	KeepAlive: 15 * time.Second,
@@ -129,9 +129,17 @@ func TestReverseDial(t *testing.T) {
 
 // TestReverseDialRedirect verifies that a revdial connection works with a 307
 // redirect to the endpoints. The coordinator will do this in dev mode.
+// The buildlet dials back for each connection, so the redirect of the
+// /revdial endpoint is used too.
 func TestReverseDialRedirect(t *testing.T) {
 	pool.SetBuilderMasterKey([]byte(devMasterKey))
 
+	oldReverseStreams := *reverseStreams
+	defer func() {
+		*reverseStreams = oldReverseStreams
+	}()
+	*reverseStreams = false
+
 	srv, ln, err := coordinatorServer()
 	if err != nil {
 		t.Fatalf("serveCoordinator got err %v want nil", err)
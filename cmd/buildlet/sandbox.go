@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"golang.org/x/build/buildlet"
+)
+
+// sandboxInitEnv is the environment variable through which the buildlet
+// passes a sandbox's configuration to the copy of itself that sets up
+// the sandbox and runs the command in it.
+const sandboxInitEnv = "GO_BUILDLET_SANDBOX_INIT"
+
+var (
+	// sandboxCommand, if non-nil, changes cmd to run in the sandbox sb.
+	// The returned cleanup function must be called once cmd finishes.
+	// It's set by platforms that support sandboxes.
+	sandboxCommand func(cmd *exec.Cmd, sb *buildlet.Sandbox) (cleanup func(), err error)
+
+	// sandboxInit, if non-nil, sets up a sandbox inside its namespaces
+	// and runs the command in it, as configured by the value of
+	// sandboxInitEnv. It doesn't return.
+	sandboxInit func(config string)
+)
+
+// sandboxParams returns the sandbox described by the parameters of an
+// /exec or /exec-stream request, or nil if there isn't one.
+func sandboxParams(form url.Values) (*buildlet.Sandbox, error) {
+	if v, _ := strconv.ParseBool(form.Get("sandbox")); !v {
+		return nil, nil
+	}
+	sb := &buildlet.Sandbox{
+		Network: buildlet.SandboxNet(form.Get("sandboxNet")),
+		Allow:   form["sandboxAllow"],
+	}
+	sb.ReadOnlyWorkDir, _ = strconv.ParseBool(form.Get("sandboxRO"))
+	if !sb.Network.Valid() {
+		return nil, badRequestf("invalid 'sandboxNet' parameter %q", sb.Network)
+	}
+	if len(sb.Allow) > 0 && sb.Network != buildlet.SandboxNetAllowlist {
+		return nil, badRequestf("'sandboxAllow' requires 'sandboxNet' %q", buildlet.SandboxNetAllowlist)
+	}
+	if sandboxCommand == nil {
+		return nil, httpError{http.StatusNotImplemented, fmt.Errorf("sandboxes are not supported on %s", runtime.GOOS)}
+	}
+	return sb, nil
+}
+
+// sandboxAllows reports whether the allowlist of a sandbox permits
+// connections to addr, of the form "host:port".
+func sandboxAllows(allow []string, addr string) bool {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, a := range allow {
+		ah, ap := a, ""
+		if h, p, err := net.SplitHostPort(a); err == nil {
+			ah, ap = h, p
+		}
+		if ap != "" && ap != port {
+			continue
+		}
+		ah = strings.ToLower(strings.Trim(ah, "[]"))
+		if suffix, ok := strings.CutPrefix(ah, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == ah {
+			return true
+		}
+	}
+	return false
+}
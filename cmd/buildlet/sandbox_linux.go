@@ -0,0 +1,460 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+// Sandboxes are built from Linux namespaces, without help from other
+// daemons. The buildlet runs a copy of itself in new user, mount, and
+// (unless the sandbox uses the host's network) network namespaces.
+// That copy, the sandbox's init process, is root in its user namespace,
+// so it can set up the mounts and network. It then starts the command
+// as the buildlet's user in a nested user and mount namespace, which
+// locks those mounts so that the command can't undo them, and exits
+// the way the command does.
+//
+// For an allowlisted network, init runs an HTTP proxy on the sandbox's
+// loopback interface. The proxy can't connect to anything itself, since
+// the sandbox has no other interfaces, so it asks the buildlet over an
+// inherited socket. The buildlet checks the allowlist, connects in the
+// host's network namespace, and sends back the connected socket.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/build/buildlet"
+	"golang.org/x/build/internal/envutil"
+	"golang.org/x/sys/unix"
+)
+
+func init() {
+	sandboxCommand = sandboxCommandLinux
+	sandboxInit = sandboxInitLinux
+}
+
+// sandboxConfig is the configuration passed to a sandbox's init process.
+type sandboxConfig struct {
+	Path     string   // command to run
+	Args     []string // its arguments, including the command name
+	UID, GID int      // the buildlet's user and group, to run it as
+
+	Network buildlet.SandboxNet
+
+	// TmpDirs are directories to replace with empty tmpfs mounts,
+	// in order, and Keep are directories to keep visible if they're
+	// in one of them, like a work directory in /tmp.
+	TmpDirs []string
+	Keep    []string
+
+	// ReadOnly, if non-empty, is a directory to make read-only,
+	// except for the directories in Writable under it.
+	ReadOnly string
+	Writable []string
+
+	// DialFD is the file descriptor of the socket over which init
+	// asks the buildlet to make connections, for an allowlisted network.
+	DialFD int
+}
+
+func sandboxCommandLinux(cmd *exec.Cmd, sb *buildlet.Sandbox) (cleanup func(), err error) {
+	cfg := sandboxConfig{
+		Path:    cmd.Path,
+		Args:    cmd.Args,
+		UID:     os.Getuid(),
+		GID:     os.Getgid(),
+		Network: sb.Network,
+		TmpDirs: []string{"/tmp"},
+		Keep:    []string{*workDir},
+	}
+	if processTmpDirEnv != "" {
+		cfg.TmpDirs = append(cfg.TmpDirs, processTmpDirEnv)
+	}
+	if sb.ReadOnlyWorkDir {
+		cfg.ReadOnly = *workDir
+		for _, dir := range []string{processGoCacheEnv, processGoplsCacheEnv} {
+			if dir != "" {
+				cfg.Writable = append(cfg.Writable, dir)
+			}
+		}
+	}
+	var local, remote *os.File
+	if sb.Network == buildlet.SandboxNetAllowlist {
+		fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_SEQPACKET|syscall.SOCK_CLOEXEC, 0)
+		if err != nil {
+			return nil, err
+		}
+		local = os.NewFile(uintptr(fds[0]), "sandbox-dial")
+		remote = os.NewFile(uintptr(fds[1]), "sandbox-dial")
+		cfg.DialFD = 3 + len(cmd.ExtraFiles)
+		cmd.ExtraFiles = append(cmd.ExtraFiles, remote)
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// /proc/self/exe works even if the buildlet binary
+	// has been replaced since it started.
+	cmd.Path = "/proc/self/exe"
+	cmd.Env = append(cmd.Env, sandboxInitEnv+"="+string(data))
+
+	// A new user namespace lets the buildlet create the others
+	// without privileges.
+	attr := &syscall.SysProcAttr{
+		Cloneflags:  syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS,
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: cfg.UID, Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: cfg.GID, Size: 1}},
+	}
+	if sb.Network != buildlet.SandboxNetHost {
+		attr.Cloneflags |= syscall.CLONE_NEWNET
+	}
+	cmd.SysProcAttr = attr
+
+	if local == nil {
+		return func() {}, nil
+	}
+	c, err := net.FileConn(local)
+	local.Close()
+	if err != nil {
+		remote.Close()
+		return nil, err
+	}
+	go serveSandboxDials(c.(*net.UnixConn), sb.Allow)
+	return func() {
+		remote.Close()
+		c.Close()
+	}, nil
+}
+
+// serveSandboxDials makes the connections requested by a sandbox's
+// init process over c, if allow permits them, until c is closed.
+// Each request is a message holding an address of the form "host:port".
+// The reply is a zero byte with the connected socket attached,
+// or an error message.
+func serveSandboxDials(c *net.UnixConn, allow []string) {
+	buf := make([]byte, 1024)
+	for {
+		n, _, _, _, err := c.ReadMsgUnix(buf, nil)
+		if err != nil || n == 0 {
+			return
+		}
+		addr := string(buf[:n])
+		reply, f := []byte{0}, (*os.File)(nil)
+		if !sandboxAllows(allow, addr) {
+			reply = []byte(fmt.Sprintf("connections to %s are not allowed in this sandbox", addr))
+		} else if conn, err := net.DialTimeout("tcp", addr, 30*time.Second); err != nil {
+			reply = []byte(err.Error())
+		} else {
+			f, err = conn.(*net.TCPConn).File()
+			conn.Close()
+			if err != nil {
+				reply = []byte(err.Error())
+			}
+		}
+		var oob []byte
+		if f != nil {
+			oob = syscall.UnixRights(int(f.Fd()))
+		}
+		_, _, err = c.WriteMsgUnix(reply, oob, nil)
+		if f != nil {
+			f.Close()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// sandboxInitLinux is the init process of a sandbox.
+func sandboxInitLinux(config string) {
+	log.SetPrefix("buildlet sandbox: ")
+	log.SetFlags(0)
+	os.Unsetenv(sandboxInitEnv)
+	var cfg sandboxConfig
+	if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	// The command's parent death signal is sent when the thread
+	// that started it exits, so keep this goroutine on one thread.
+	runtime.LockOSThread()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.mount(); err != nil {
+		log.Fatal(err)
+	}
+	env := os.Environ()
+	switch cfg.Network {
+	case buildlet.SandboxNetLoopback:
+		if err := loopbackUp(); err != nil {
+			log.Fatal(err)
+		}
+	case buildlet.SandboxNetAllowlist:
+		if err := loopbackUp(); err != nil {
+			log.Fatal(err)
+		}
+		proxyURL, err := startSandboxProxy(cfg.DialFD)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, k := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"} {
+			env = append(env, k+"="+proxyURL)
+		}
+		env = append(env, "NO_PROXY=", "no_proxy=")
+	}
+
+	cmd := &exec.Cmd{
+		Path:   cfg.Path,
+		Args:   cfg.Args,
+		Env:    envutil.Dedup(runtime.GOOS, env),
+		Dir:    wd, // in the new view of the file system
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+		SysProcAttr: &syscall.SysProcAttr{
+			Cloneflags:  syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS,
+			UidMappings: []syscall.SysProcIDMap{{ContainerID: cfg.UID, HostID: 0, Size: 1}},
+			GidMappings: []syscall.SysProcIDMap{{ContainerID: cfg.GID, HostID: 0, Size: 1}},
+			Pdeathsig:   syscall.SIGKILL,
+		},
+	}
+	if _, err := unix.IoctlGetTermios(0, unix.TCGETS); err == nil {
+		// Give the command the terminal, so that it gets the signals
+		// typed at it, while those sent to init are forwarded below.
+		cmd.SysProcAttr.Foreground = true
+		cmd.SysProcAttr.Ctty = 0
+	}
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGHUP)
+	if err := cmd.Start(); err != nil {
+		log.Fatal(err)
+	}
+	go func() {
+		for sig := range sigc {
+			cmd.Process.Signal(sig)
+		}
+	}()
+	cmd.Wait()
+
+	ws := cmd.ProcessState.Sys().(syscall.WaitStatus)
+	if ws.Signaled() {
+		// Die the same way, so the buildlet reports the signal.
+		signal.Reset(ws.Signal())
+		syscall.Kill(os.Getpid(), ws.Signal())
+		os.Exit(128 + int(ws.Signal()))
+	}
+	os.Exit(ws.ExitStatus())
+}
+
+// mount sets up the sandbox's view of the file system.
+func (cfg *sandboxConfig) mount() error {
+	// Keep the mounts below from propagating back to the host.
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("making mounts private: %v", err)
+	}
+	// Open the directories to keep before the tmpfs mounts hide them.
+	keep := make(map[string]*os.File)
+	for _, dir := range cfg.Keep {
+		f, err := os.Open(dir)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		keep[dir] = f
+	}
+	for _, dir := range cfg.TmpDirs {
+		if err := syscall.Mount("tmpfs", dir, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=1777"); err != nil {
+			return fmt.Errorf("mounting tmpfs on %s: %v", dir, err)
+		}
+		for k, f := range keep {
+			if !strings.HasPrefix(k, dir+"/") {
+				continue
+			}
+			if err := os.MkdirAll(k, 0755); err != nil {
+				return err
+			}
+			src := fmt.Sprintf("/proc/self/fd/%d", f.Fd())
+			if err := syscall.Mount(src, k, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+				return fmt.Errorf("binding %s: %v", k, err)
+			}
+		}
+	}
+	if cfg.ReadOnly == "" {
+		return nil
+	}
+	// Bind the directory to itself, with any tmpfs mounted under it,
+	// to get a mount whose flags can be changed.
+	if err := syscall.Mount(cfg.ReadOnly, cfg.ReadOnly, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("binding %s: %v", cfg.ReadOnly, err)
+	}
+	if err := remount(cfg.ReadOnly, syscall.MS_RDONLY); err != nil {
+		return err
+	}
+	for _, dir := range cfg.Writable {
+		if err := syscall.Mount(dir, dir, "", syscall.MS_BIND, ""); err != nil {
+			return fmt.Errorf("binding %s: %v", dir, err)
+		}
+		if err := remount(dir, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remount changes the flags of the bind mount at dir to flags,
+// keeping the flags that a user namespace may not clear.
+func remount(dir string, flags uintptr) error {
+	var st unix.Statfs_t
+	if err := unix.Statfs(dir, &st); err != nil {
+		return err
+	}
+	// The ST_ flags reported by statfs have the same values as
+	// the corresponding MS_ flags.
+	const locked = unix.MS_NOSUID | unix.MS_NODEV | unix.MS_NOEXEC | unix.MS_NOATIME | unix.MS_NODIRATIME | unix.MS_RELATIME
+	flags |= uintptr(st.Flags) & locked
+	if err := syscall.Mount("", dir, "", syscall.MS_REMOUNT|syscall.MS_BIND|flags, ""); err != nil {
+		return fmt.Errorf("remounting %s: %v", dir, err)
+	}
+	return nil
+}
+
+// loopbackUp brings up the loopback interface of the network namespace.
+func loopbackUp() error {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+	ifr, err := unix.NewIfreq("lo")
+	if err != nil {
+		return err
+	}
+	if err := unix.IoctlIfreq(fd, unix.SIOCGIFFLAGS, ifr); err != nil {
+		return fmt.Errorf("getting loopback flags: %v", err)
+	}
+	ifr.SetUint16(ifr.Uint16() | unix.IFF_UP)
+	if err := unix.IoctlIfreq(fd, unix.SIOCSIFFLAGS, ifr); err != nil {
+		return fmt.Errorf("bringing up loopback: %v", err)
+	}
+	return nil
+}
+
+// startSandboxProxy starts an HTTP proxy on the loopback interface that
+// makes its connections through the buildlet, and returns its URL.
+func startSandboxProxy(dialFD int) (string, error) {
+	f := os.NewFile(uintptr(dialFD), "sandbox-dial")
+	c, err := net.FileConn(f)
+	f.Close() // keep it from the command
+	if err != nil {
+		return "", fmt.Errorf("opening dial socket: %v", err)
+	}
+	d := &sandboxDialer{c: c.(*net.UnixConn)}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	p := &sandboxProxy{
+		dial: d.dial,
+		rp: &httputil.ReverseProxy{
+			Director:  func(*http.Request) {}, // requests to a proxy have absolute URLs
+			Transport: &http.Transport{DialContext: d.dial},
+		},
+	}
+	go http.Serve(ln, p)
+	return "http://" + ln.Addr().String(), nil
+}
+
+// A sandboxDialer makes connections by asking the buildlet,
+// using the protocol described at serveSandboxDials.
+type sandboxDialer struct {
+	mu sync.Mutex // one request at a time
+	c  *net.UnixConn
+}
+
+func (d *sandboxDialer) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.c.Write([]byte(addr)); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 1024)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	n, oobn, _, _, err := d.c.ReadMsgUnix(buf, oob)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, errors.New("buildlet closed dial socket")
+	}
+	if buf[0] != 0 {
+		return nil, errors.New(string(buf[:n]))
+	}
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(msgs) != 1 {
+		return nil, fmt.Errorf("malformed reply from buildlet for %s", addr)
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		return nil, fmt.Errorf("malformed reply from buildlet for %s", addr)
+	}
+	f := os.NewFile(uintptr(fds[0]), addr)
+	defer f.Close()
+	return net.FileConn(f)
+}
+
+// A sandboxProxy is an HTTP proxy that supports CONNECT, for HTTPS,
+// and plain HTTP requests.
+type sandboxProxy struct {
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+	rp   *httputil.ReverseProxy
+}
+
+func (p *sandboxProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "CONNECT" {
+		if !r.URL.IsAbs() {
+			http.Error(w, "this is a proxy; requests need absolute URLs", http.StatusBadRequest)
+			return
+		}
+		p.rp.ServeHTTP(w, r)
+		return
+	}
+	upstream, err := p.dial(r.Context(), "tcp", r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer upstream.Close()
+	conn, bufrw, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+		return
+	}
+	go func() {
+		io.Copy(upstream, bufrw)
+		if cw, ok := upstream.(interface{ CloseWrite() error }); ok {
+			cw.CloseWrite()
+		}
+	}()
+	io.Copy(conn, upstream)
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/build/buildlet"
+)
+
+const sandboxTestHelperEnv = "BUILDLET_SANDBOX_TEST_HELPER"
+
+func TestMain(m *testing.M) {
+	// The sandbox tests run the test binary as the sandbox's init
+	// process, and as the command in the sandbox.
+	if v := os.Getenv(sandboxInitEnv); v != "" {
+		sandboxInit(v)
+	}
+	if os.Getenv(sandboxTestHelperEnv) != "" {
+		sandboxTestHelper(os.Args[1:])
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// sandboxTestHelper performs each of the operations in args, of the
+// form "op=arg", and prints whether they succeeded.
+func sandboxTestHelper(args []string) {
+	for _, a := range args {
+		op, arg, _ := strings.Cut(a, "=")
+		var err error
+		switch op {
+		case "read":
+			_, err = os.ReadFile(arg)
+		case "write":
+			err = os.WriteFile(arg, []byte("hello"), 0644)
+		case "unmount":
+			err = syscall.Unmount(arg, syscall.MNT_DETACH)
+		case "dial":
+			var c net.Conn
+			if c, err = net.DialTimeout("tcp", arg, 5*time.Second); err == nil {
+				c.Close()
+			}
+		case "loopback":
+			var ln net.Listener
+			if ln, err = net.Listen("tcp", "127.0.0.1:0"); err == nil {
+				var c net.Conn
+				if c, err = net.Dial("tcp", ln.Addr().String()); err == nil {
+					c.Close()
+				}
+				ln.Close()
+			}
+		case "get":
+			// Use the proxy even for 127.0.0.1,
+			// which http.ProxyFromEnvironment doesn't.
+			proxyURL, _ := url.Parse(os.Getenv("HTTP_PROXY"))
+			c := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+			var res *http.Response
+			if res, err = c.Get(arg); err == nil {
+				res.Body.Close()
+				if res.StatusCode != http.StatusOK {
+					err = errors.New(res.Status)
+				}
+			}
+		case "exit":
+			code, _ := strconv.Atoi(arg)
+			os.Exit(code)
+		}
+		fmt.Printf("%s: %v\n", a, err == nil)
+	}
+}
+
+func TestSandbox(t *testing.T) {
+	dir := t.TempDir()
+	defer func(old string) { *workDir = old }(*workDir)
+	defer func(tmp, cache string) { processTmpDirEnv, processGoCacheEnv = tmp, cache }(processTmpDirEnv, processGoCacheEnv)
+	*workDir = dir
+	processTmpDirEnv = filepath.Join(dir, "tmp")
+	processGoCacheEnv = filepath.Join(dir, "gocache")
+	if err := prepareExec(); err != nil {
+		t.Fatal(err)
+	}
+	workFile := filepath.Join(dir, "file")
+	if err := os.WriteFile(workFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	hostTmp, err := os.CreateTemp("/tmp", "sandbox-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostTmp.Close()
+	defer os.Remove(hostTmp.Name())
+	sandboxTmp := hostTmp.Name() + ".sandbox"
+	defer os.Remove(sandboxTmp)
+
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ok.Close()
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer other.Close()
+	okAddr := ok.Listener.Addr().String()
+
+	// The test binary may be in /tmp, which the sandbox hides,
+	// so run a copy in the work directory.
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(self)
+	if err != nil {
+		t.Fatal(err)
+	}
+	helper := filepath.Join(dir, "helper")
+	if err := os.WriteFile(helper, data, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(t *testing.T, sb *buildlet.Sandbox, ops ...string) (string, error) {
+		cmd := exec.Command(helper, ops...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), sandboxTestHelperEnv+"=1")
+		cleanup, err := sandboxCommand(cmd, sb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cleanup()
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Start(); err != nil {
+			t.Skipf("can't create namespaces: %v", err)
+		}
+		err = cmd.Wait()
+		return out.String(), err
+	}
+	check := func(t *testing.T, sb *buildlet.Sandbox, want map[string]bool) {
+		var ops []string
+		for op := range want {
+			ops = append(ops, op)
+		}
+		out, err := run(t, sb, ops...)
+		if err != nil {
+			t.Fatalf("sandboxed command failed: %v\n%s", err, out)
+		}
+		for op, ok := range want {
+			if line := fmt.Sprintf("%s: %v\n", op, ok); !strings.Contains(out, line) {
+				t.Errorf("want %q in output:\n%s", line, out)
+			}
+		}
+	}
+
+	t.Run("files", func(t *testing.T) {
+		check(t, &buildlet.Sandbox{Network: buildlet.SandboxNetNone, ReadOnlyWorkDir: true}, map[string]bool{
+			"read=" + hostTmp.Name():                          false,
+			"write=" + sandboxTmp:                             true,
+			"read=" + workFile:                                true,
+			"write=" + filepath.Join(dir, "new"):              false,
+			"write=" + filepath.Join(dir, "tmp", "new"):       true,
+			"write=" + filepath.Join(dir, "gocache", "entry"): true,
+			"dial=" + okAddr:                                  false,
+			"unmount=/tmp":                                    false,
+		})
+		for _, name := range []string{sandboxTmp, filepath.Join(dir, "tmp", "new")} {
+			if _, err := os.Stat(name); err == nil {
+				t.Errorf("%s written in the sandbox is visible outside it", name)
+			}
+		}
+	})
+	t.Run("loopback", func(t *testing.T) {
+		check(t, &buildlet.Sandbox{Network: buildlet.SandboxNetLoopback}, map[string]bool{
+			"loopback":                           true,
+			"dial=" + okAddr:                     false,
+			"write=" + filepath.Join(dir, "new"): true,
+		})
+	})
+	t.Run("allowlist", func(t *testing.T) {
+		check(t, &buildlet.Sandbox{Network: buildlet.SandboxNetAllowlist, Allow: []string{okAddr}}, map[string]bool{
+			"get=" + ok.URL:    true,
+			"get=" + other.URL: false,
+			"dial=" + okAddr:   false,
+		})
+	})
+	t.Run("host", func(t *testing.T) {
+		check(t, &buildlet.Sandbox{}, map[string]bool{
+			"dial=" + okAddr:         true,
+			"read=" + hostTmp.Name(): false,
+		})
+	})
+	t.Run("exit", func(t *testing.T) {
+		_, err := run(t, &buildlet.Sandbox{Network: buildlet.SandboxNetNone}, "exit=3")
+		if ee, ok := err.(*exec.ExitError); !ok || ee.ExitCode() != 3 {
+			t.Errorf("sandboxed command exiting with 3 returned %v", err)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestSandboxAllows(t *testing.T) {
+	allow := []string{"proxy.golang.org", "*.googlesource.com:443", "[::1]", "Example.COM:8080"}
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"proxy.golang.org:443", true},
+		{"proxy.golang.org:80", true},
+		{"PROXY.golang.org.:443", true},
+		{"sum.golang.org:443", false},
+		{"go.googlesource.com:443", true},
+		{"go.googlesource.com:80", false},
+		{"googlesource.com:443", false},
+		{"evilgooglesource.com:443", false},
+		{"[::1]:22", true},
+		{"example.com:8080", true},
+		{"example.com:443", false},
+		{"proxy.golang.org", false}, // no port
+	}
+	for _, tt := range tests {
+		if got := sandboxAllows(allow, tt.addr); got != tt.want {
+			t.Errorf("sandboxAllows(%q) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestSandboxParams(t *testing.T) {
+	if sb, err := sandboxParams(url.Values{}); sb != nil || err != nil {
+		t.Errorf("sandboxParams with no sandbox = %+v, %v; want nil, nil", sb, err)
+	}
+	for _, form := range []url.Values{
+		{"sandbox": {"true"}, "sandboxNet": {"bogus"}},
+		{"sandbox": {"true"}, "sandboxNet": {"loopback"}, "sandboxAllow": {"golang.org"}},
+	} {
+		if _, err := sandboxParams(form); err == nil {
+			t.Errorf("sandboxParams(%v) succeeded, want error", form)
+		} else if httpStatus(err) != 400 {
+			t.Errorf("sandboxParams(%v) = %v, with status %d; want status 400", form, err, httpStatus(err))
+		}
+	}
+}
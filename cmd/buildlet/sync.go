@@ -0,0 +1,314 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"golang.org/x/build/buildlet"
+)
+
+// The limits on the size of /sync requests: of the manifest of a first
+// request, and of the file contents of a second one.
+const (
+	maxSyncManifestSize = 64 << 20
+	maxSyncBlobsSize    = 2 << 30
+)
+
+// handleSync serves the /sync protocol, described in golang.org/x/build/buildlet.
+func handleSync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "requires POST method", http.StatusBadRequest)
+		return
+	}
+	if !mkdirAllWorkdirOr500(w) {
+		return
+	}
+	baseDir := *workDir
+	if dir := r.URL.Query().Get("dir"); dir != "" {
+		dir, err := nativeRelPath(dir)
+		if err != nil {
+			http.Error(w, "invalid 'dir' parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		baseDir = filepath.Join(baseDir, dir)
+	}
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		http.Error(w, "mkdir of base: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	tmpDir, err := os.MkdirTemp("", "buildlet-sync-")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var m *buildlet.SyncManifest
+	blobs := make(map[string]string)
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch ct {
+	case "application/json":
+		m = new(buildlet.SyncManifest)
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxSyncManifestSize)).Decode(m); err != nil {
+			http.Error(w, "decoding manifest: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	case "application/gzip":
+		m, err = readSyncBlobs(r.Body, tmpDir, blobs, maxSyncBlobsSize)
+		if err != nil {
+			http.Error(w, err.Error(), httpStatus(err))
+			return
+		}
+	default:
+		http.Error(w, "requires application/json or application/gzip body", http.StatusBadRequest)
+		return
+	}
+
+	t0 := time.Now()
+	res, err := applySync(baseDir, m, blobs, tmpDir)
+	if err != nil {
+		log.Printf("sync of %s: %v", baseDir, err)
+		http.Error(w, err.Error(), httpStatus(err))
+		return
+	}
+	if len(res.Missing) > 0 {
+		log.Printf("sync of %s: missing %d of %d files' contents", baseDir, len(res.Missing), len(m.Files))
+	} else {
+		log.Printf("sync of %s: wrote %d files, deleted %d, with %d uploaded (%v)",
+			baseDir, res.Written, res.Deleted, len(blobs), time.Since(t0))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// readSyncBlobs reads the gzip-compressed tar file of a second /sync
+// request from r, writing each file content it holds to dir and
+// recording its location in blobs. It returns the manifest.
+// The manifest and the file contents may be at most maxSize bytes.
+func readSyncBlobs(r io.Reader, dir string, blobs map[string]string, maxSize int64) (*buildlet.SyncManifest, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, badRequestf("requires gzip-compressed body: %w", err)
+	}
+	tr := tar.NewReader(zr)
+	var m *buildlet.SyncManifest
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, badRequestf("tar error: %w", err)
+		}
+		if h.Size > maxSize {
+			return nil, badRequestf("file contents larger than %d bytes", maxSize)
+		}
+		maxSize -= h.Size
+		if m == nil {
+			if h.Name != buildlet.SyncManifestName {
+				return nil, badRequestf("first tar entry is %q, want %q", h.Name, buildlet.SyncManifestName)
+			}
+			m = new(buildlet.SyncManifest)
+			if err := json.NewDecoder(tr).Decode(m); err != nil {
+				return nil, badRequestf("decoding manifest: %w", err)
+			}
+			continue
+		}
+		if !validDigest(h.Name) {
+			return nil, badRequestf("tar entry %q isn't named by a digest", h.Name)
+		}
+		path := filepath.Join(dir, h.Name)
+		s1 := sha1.New()
+		if err := writeFile(io.TeeReader(tr, s1), path, 0644); err != nil {
+			return nil, err
+		}
+		if got := hex.EncodeToString(s1.Sum(nil)); got != h.Name {
+			return nil, badRequestf("tar entry %q has digest %s", h.Name, got)
+		}
+		blobs[h.Name] = path
+	}
+	if m == nil {
+		return nil, badRequestf("empty tar file")
+	}
+	return m, nil
+}
+
+// applySync applies the manifest m to baseDir, using the file contents
+// in blobs, a map from digest to file, and the contents of files already
+// in baseDir, including those about to be deleted. If some contents are
+// unavailable, it changes nothing and returns their digests in the result.
+// It uses tmpDir to save the contents of existing files that are about
+// to be overwritten.
+func applySync(baseDir string, m *buildlet.SyncManifest, blobs map[string]string, tmpDir string) (*buildlet.SyncResult, error) {
+	type target struct {
+		abs  string
+		file buildlet.SyncFile
+	}
+	var (
+		writes []target
+		chmods []target
+		have   = make(map[string]string) // digest -> existing file with that content
+		inSync = make(map[string]bool)   // native relative paths in m.Files and their parents
+	)
+	for _, f := range m.Files {
+		rel, err := nativeRelPath(f.Path)
+		if err != nil {
+			return nil, badRequestf("invalid path in manifest: %w", err)
+		}
+		if !validDigest(f.Digest) {
+			return nil, badRequestf("invalid digest %q for %s", f.Digest, f.Path)
+		}
+		if !f.Mode.IsRegular() {
+			return nil, badRequestf("invalid mode %v for %s", f.Mode, f.Path)
+		}
+		for p := filepath.Clean(rel); p != "."; p = filepath.Dir(p) {
+			inSync[p] = true
+		}
+		t := target{filepath.Join(baseDir, rel), f}
+		if fi, err := os.Lstat(t.abs); err == nil && fi.Mode().IsRegular() {
+			if d, err := fileSHA1(t.abs); err == nil {
+				if _, ok := have[d]; !ok {
+					have[d] = t.abs
+				}
+				if d == f.Digest {
+					if fi.Mode().Perm() != f.Mode.Perm() {
+						chmods = append(chmods, t)
+					}
+					continue
+				}
+			}
+		}
+		writes = append(writes, t)
+	}
+	var deletes []string
+	for _, p := range m.Delete {
+		rel, err := nativeRelPath(p)
+		if err != nil {
+			return nil, badRequestf("invalid path to delete: %w", err)
+		}
+		if inSync[filepath.Clean(rel)] {
+			return nil, badRequestf("%s is both synced and deleted", p)
+		}
+		abs := filepath.Join(baseDir, rel)
+		deletes = append(deletes, abs)
+
+		// Files being deleted may have been moved elsewhere,
+		// so their contents can be reused too.
+		filepath.WalkDir(abs, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return nil
+			}
+			if sum, err := fileSHA1(path); err == nil {
+				if _, ok := have[sum]; !ok {
+					have[sum] = path
+				}
+			}
+			return nil
+		})
+	}
+
+	res := new(buildlet.SyncResult)
+	missing := make(map[string]bool)
+	for _, t := range writes {
+		d := t.file.Digest
+		if blobs[d] == "" && have[d] == "" && !missing[d] {
+			missing[d] = true
+			res.Missing = append(res.Missing, d)
+		}
+	}
+	if len(res.Missing) > 0 {
+		return res, nil
+	}
+
+	// Save the contents to be copied from existing files,
+	// which deletions and writes could replace.
+	for _, t := range writes {
+		d := t.file.Digest
+		if blobs[d] != "" {
+			continue
+		}
+		saved := filepath.Join(tmpDir, d)
+		if err := copyFile(have[d], saved); err != nil {
+			return nil, err
+		}
+		blobs[d] = saved
+	}
+
+	for _, abs := range deletes {
+		if _, err := os.Lstat(abs); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err := removeAllIncludingReadonly(abs); err != nil {
+			return nil, err
+		}
+		res.Deleted++
+	}
+	for _, t := range writes {
+		if err := os.MkdirAll(filepath.Dir(t.abs), 0755); err != nil {
+			return nil, err
+		}
+		if err := copyFileMode(blobs[t.file.Digest], t.abs, t.file.Mode.Perm()); err != nil {
+			return nil, err
+		}
+		res.Written++
+	}
+	if runtime.GOOS != "windows" {
+		for _, t := range chmods {
+			if err := os.Chmod(t.abs, t.file.Mode.Perm()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return res, nil
+}
+
+func copyFile(src, dst string) error {
+	return copyFileMode(src, dst, 0644)
+}
+
+// copyFileMode copies the contents of src to dst, which gets mode.
+func copyFileMode(src, dst string, mode os.FileMode) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := writeFile(f, dst, mode); err != nil {
+		return fmt.Errorf("writing %s: %w", dst, err)
+	}
+	return nil
+}
+
+// validDigest reports whether d looks like a lowercase hex SHA-1 digest.
+func validDigest(d string) bool {
+	return len(d) == 2*sha1.Size && isLowerHex(d)
+}
+
+// isLowerHex reports whether s consists of lowercase hex digits.
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/build/buildlet"
+)
+
+func digest(s string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(s)))
+}
+
+func TestApplySync(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"a.txt":     "a",
+		"b.txt":     "b",
+		"old/x.txt": "x",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Swap a.txt and b.txt, copy the deleted old/x.txt to new/x.txt,
+	// and add c.txt, whose contents the buildlet lacks.
+	m := &buildlet.SyncManifest{
+		Files: []buildlet.SyncFile{
+			{Path: "a.txt", Digest: digest("b"), Mode: 0644},
+			{Path: "b.txt", Digest: digest("a"), Mode: 0644},
+			{Path: "new/x.txt", Digest: digest("x"), Mode: 0644},
+			{Path: "c.txt", Digest: digest("c"), Mode: 0644},
+		},
+		Delete: []string{"old"},
+	}
+	res, err := applySync(dir, m, map[string]string{}, t.TempDir())
+	if err != nil {
+		t.Fatalf("applySync without blobs: %v", err)
+	}
+	if want := []string{digest("c")}; !reflect.DeepEqual(res.Missing, want) {
+		t.Fatalf("applySync without blobs: missing %q, want %q", res.Missing, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old")); err != nil {
+		t.Fatalf("applySync with missing contents changed files: %v", err)
+	}
+
+	blobDir := t.TempDir()
+	blob := filepath.Join(blobDir, digest("c"))
+	if err := os.WriteFile(blob, []byte("c"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	res, err = applySync(dir, m, map[string]string{digest("c"): blob}, t.TempDir())
+	if err != nil {
+		t.Fatalf("applySync: %v", err)
+	}
+	if want := (buildlet.SyncResult{Written: 4, Deleted: 1}); !reflect.DeepEqual(*res, want) {
+		t.Errorf("applySync = %+v, want %+v", *res, want)
+	}
+	for name, want := range map[string]string{
+		"a.txt":     "b",
+		"b.txt":     "a",
+		"new/x.txt": "x",
+		"c.txt":     "c",
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || string(got) != want {
+			t.Errorf("after sync, %s = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old")); !os.IsNotExist(err) {
+		t.Errorf("after sync, old still exists")
+	}
+
+	// Syncing again changes nothing.
+	res, err = applySync(dir, m, map[string]string{}, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (buildlet.SyncResult{}); !reflect.DeepEqual(*res, want) {
+		t.Errorf("second applySync = %+v, want %+v", *res, want)
+	}
+}
+
+func TestApplySyncInvalid(t *testing.T) {
+	for _, m := range []*buildlet.SyncManifest{
+		{Files: []buildlet.SyncFile{{Path: "../x", Digest: digest("x"), Mode: 0644}}},
+		{Files: []buildlet.SyncFile{{Path: "x", Digest: "nope", Mode: 0644}}},
+		{Files: []buildlet.SyncFile{{Path: "x", Digest: digest("x"), Mode: os.ModeDir | 0755}}},
+		{Delete: []string{"/etc"}},
+		{Files: []buildlet.SyncFile{{Path: "x", Digest: digest("x"), Mode: 0644}}, Delete: []string{"x"}},
+		{Files: []buildlet.SyncFile{{Path: "x/y", Digest: digest("y"), Mode: 0644}}, Delete: []string{"x"}},
+	} {
+		if _, err := applySync(t.TempDir(), m, nil, t.TempDir()); err == nil || httpStatus(err) != 400 {
+			t.Errorf("applySync(%+v) = %v, want a bad request error", m, err)
+		}
+	}
+}
+
+func TestReadSyncBlobsLimit(t *testing.T) {
+	m := &buildlet.SyncManifest{Files: []buildlet.SyncFile{{Path: "big", Digest: digest("0123456789"), Mode: 0644}}}
+	var buf bytes.Buffer
+	open := func(buildlet.SyncFile) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("0123456789")), nil
+	}
+	if _, err := buildlet.WriteSyncTGZ(&buf, m, []string{digest("0123456789")}, open); err != nil {
+		t.Fatal(err)
+	}
+	blobs := make(map[string]string)
+	if _, err := readSyncBlobs(bytes.NewReader(buf.Bytes()), t.TempDir(), blobs, 1<<10); err != nil {
+		t.Fatalf("readSyncBlobs: %v", err)
+	}
+	if len(blobs) != 1 {
+		t.Errorf("readSyncBlobs read %d blobs; want 1", len(blobs))
+	}
+	// The manifest fits, but not the file contents too.
+	manifest, _ := json.Marshal(m)
+	limit := int64(len(manifest) + 5)
+	if _, err := readSyncBlobs(bytes.NewReader(buf.Bytes()), t.TempDir(), make(map[string]string), limit); err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("readSyncBlobs over the limit: %v; want an error", err)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"golang.org/x/build/buildlet"
+)
+
+// pollInterval is how often /tail and /watch check for changes.
+var pollInterval = 500 * time.Millisecond
+
+// absParamPath returns the native, absolute path corresponding to the
+// value v of the named parameter, which may be an absolute native path
+// or a path relative to the work directory.
+func absParamPath(param, v string) (string, error) {
+	if v == "" {
+		return "", badRequestf("missing '%s' parameter", param)
+	}
+	if filepath.IsAbs(v) {
+		return filepath.Clean(v), nil
+	}
+	rel, err := nativeRelPath(v)
+	if err != nil {
+		return "", badRequestf("invalid '%s' parameter: %w", param, err)
+	}
+	return filepath.Join(*workDir, rel), nil
+}
+
+// handleTail writes the contents of a file, optionally following
+// appends to it. See buildlet.Client.Tail.
+func handleTail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "requires GET method", http.StatusBadRequest)
+		return
+	}
+	path, err := absParamPath("path", r.FormValue("path"))
+	if err != nil {
+		http.Error(w, err.Error(), httpStatus(err))
+		return
+	}
+	offset, err := strconv.ParseInt(r.FormValue("offset"), 10, 64)
+	if err != nil && r.FormValue("offset") != "" {
+		http.Error(w, "invalid 'offset' parameter", http.StatusBadRequest)
+		return
+	}
+	follow, _ := strconv.ParseBool(r.FormValue("follow"))
+
+	f, err := os.Open(path)
+	if err != nil && (!follow || !errors.Is(err, fs.ErrNotExist)) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if f != nil {
+		defer f.Close()
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.(http.Flusher).Flush()
+	if err := tailFile(r.Context(), path, f, offset, follow, flushWriter{w}); err != nil {
+		log.Printf("tail of %s: %v", path, err)
+	}
+}
+
+// tailFile writes the contents of f, the open file at path, to w,
+// starting at offset (relative to the end if negative). If follow is
+// set, it then writes appended bytes until ctx is done. If f is nil,
+// it waits for the file to exist.
+func tailFile(ctx context.Context, path string, f *os.File, offset int64, follow bool, w io.Writer) error {
+	var pos int64 // offset of the next byte to write
+	start := func() error {
+		fi, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		pos = offset
+		if offset < 0 {
+			pos = max(fi.Size()+offset, 0)
+		}
+		return nil
+	}
+	if f != nil {
+		if err := start(); err != nil {
+			return err
+		}
+	}
+	buf := make([]byte, 32<<10)
+	for {
+		if f == nil {
+			var err error
+			if f, err = os.Open(path); err == nil {
+				defer f.Close()
+				if err := start(); err != nil {
+					return err
+				}
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+		if f != nil {
+			if fi, err := f.Stat(); err == nil && fi.Size() < pos {
+				pos = 0 // truncated
+			}
+			for {
+				n, err := f.ReadAt(buf, pos)
+				if n > 0 {
+					if _, err := w.Write(buf[:n]); err != nil {
+						return err
+					}
+					pos += int64(n)
+				}
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if !follow {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// handleWatch streams JSON-encoded buildlet.WatchEvents describing
+// changes to the files in a directory. See buildlet.Client.Watch.
+func handleWatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "requires GET method", http.StatusBadRequest)
+		return
+	}
+	dir, err := absParamPath("dir", r.FormValue("dir"))
+	if err != nil {
+		http.Error(w, err.Error(), httpStatus(err))
+		return
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		http.Error(w, "not a directory: "+dir, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.(http.Flusher).Flush()
+	enc := json.NewEncoder(flushWriter{w})
+	prev := snapshotDir(dir)
+	interval := pollInterval
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(interval):
+		}
+		t0 := time.Now()
+		cur := snapshotDir(dir)
+		events := diffSnapshots(prev, cur)
+		for _, e := range events {
+			if err := enc.Encode(e); err != nil {
+				return
+			}
+		}
+		prev = cur
+		interval = nextWatchInterval(interval, time.Since(t0), len(events) > 0)
+	}
+}
+
+// maxWatchIdleInterval bounds how long /watch backs off to while
+// nothing in its directory changes.
+const maxWatchIdleInterval = 8 * time.Second
+
+// nextWatchInterval returns how long /watch waits before walking its
+// directory again, given the previous wait, how long the last walk
+// took, and whether anything changed. It backs off while nothing
+// changes, and spends at most about a tenth of the time walking, so
+// watching a large tree such as a GOROOT doesn't keep a CPU busy.
+func nextWatchInterval(prev, walk time.Duration, changed bool) time.Duration {
+	d := pollInterval
+	if !changed {
+		d = max(d, min(2*prev, maxWatchIdleInterval))
+	}
+	return max(d, 10*walk)
+}
+
+// snapshotDir returns information about the files in dir and its
+// subdirectories, keyed by their slash-separated paths relative to dir.
+// Files that can't be read are left out.
+func snapshotDir(dir string) map[string]fs.FileInfo {
+	m := make(map[string]fs.FileInfo)
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == dir {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+		m[filepath.ToSlash(rel)] = fi
+		return nil
+	})
+	return m
+}
+
+// diffSnapshots returns events describing the changes from the
+// snapshot prev to cur, sorted by path.
+func diffSnapshots(prev, cur map[string]fs.FileInfo) []buildlet.WatchEvent {
+	var events []buildlet.WatchEvent
+	for p, fi := range cur {
+		op := ""
+		if old, ok := prev[p]; !ok || old.IsDir() != fi.IsDir() {
+			op = "create"
+		} else if !fi.IsDir() && (old.Size() != fi.Size() || !old.ModTime().Equal(fi.ModTime())) {
+			op = "write"
+		} else if old.Mode() != fi.Mode() {
+			op = "chmod"
+		}
+		if op != "" {
+			events = append(events, buildlet.WatchEvent{Op: op, Path: p, Mode: fi.Mode(), Size: fi.Size(), ModTime: fi.ModTime()})
+		}
+	}
+	for p := range prev {
+		if _, ok := cur[p]; !ok {
+			events = append(events, buildlet.WatchEvent{Op: "remove", Path: p})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+	return events
+}
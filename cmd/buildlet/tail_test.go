@@ -0,0 +1,137 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a strings.Builder safe for concurrent use.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+func TestTailFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	if err := os.WriteFile(path, []byte("hello, world\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var buf strings.Builder
+	if err := tailFile(context.Background(), path, f, -6, false, &buf); err != nil {
+		t.Fatalf("tailFile: %v", err)
+	}
+	if got, want := buf.String(), "world\n"; got != want {
+		t.Errorf("tailFile from offset -6 wrote %q, want %q", got, want)
+	}
+
+	// Follow a file that doesn't exist yet.
+	defer func(d time.Duration) { pollInterval = d }(pollInterval)
+	pollInterval = time.Millisecond
+	path = filepath.Join(t.TempDir(), "later.txt")
+	ctx, cancel := context.WithCancel(context.Background())
+	out := new(syncBuffer)
+	done := make(chan error)
+	go func() { done <- tailFile(ctx, path, nil, 0, true, out) }()
+	waitFor := func(want string) {
+		t.Helper()
+		for i := 0; out.String() != want; i++ {
+			if i == 5000 {
+				t.Fatalf("following file: got %q, want %q", out.String(), want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	os.WriteFile(path, []byte("one\n"), 0644)
+	waitFor("one\n")
+	af, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	af.WriteString("two\n")
+	af.Close()
+	waitFor("one\ntwo\n")
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("tailFile: %v", err)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("kept.txt", "x")
+	write("changed.txt", "x")
+	write("removed.txt", "x")
+	prev := snapshotDir(dir)
+
+	write("changed.txt", "xx")
+	os.Remove(filepath.Join(dir, "removed.txt"))
+	os.Mkdir(filepath.Join(dir, "sub"), 0755)
+	write("sub/new.txt", "x")
+	var got []string
+	for _, e := range diffSnapshots(prev, snapshotDir(dir)) {
+		got = append(got, e.String())
+	}
+	want := []string{
+		"write changed.txt (2 bytes)",
+		"remove removed.txt",
+		"create sub/",
+		"create sub/new.txt (1 bytes)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %q, want %q", got, want)
+	}
+}
+
+func TestNextWatchInterval(t *testing.T) {
+	defer func(d time.Duration) { pollInterval = d }(pollInterval)
+	pollInterval = 500 * time.Millisecond
+	for _, tt := range []struct {
+		prev, walk time.Duration
+		changed    bool
+		want       time.Duration
+	}{
+		{500 * time.Millisecond, time.Millisecond, true, 500 * time.Millisecond},
+		{500 * time.Millisecond, time.Millisecond, false, time.Second},
+		{4 * time.Second, time.Millisecond, true, 500 * time.Millisecond},
+		{8 * time.Second, time.Millisecond, false, 8 * time.Second},
+		// Slow walks are done less often, even if something changed.
+		{500 * time.Millisecond, 200 * time.Millisecond, true, 2 * time.Second},
+		{8 * time.Second, 2 * time.Second, false, 20 * time.Second},
+	} {
+		if got := nextWatchInterval(tt.prev, tt.walk, tt.changed); got != tt.want {
+			t.Errorf("nextWatchInterval(%v, %v, %v) = %v, want %v", tt.prev, tt.walk, tt.changed, got, tt.want)
+		}
+	}
+}
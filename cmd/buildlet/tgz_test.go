@@ -0,0 +1,138 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/build/buildlet"
+	"golang.org/x/build/pargzip"
+	"golang.org/x/build/tarutil"
+)
+
+func TestTGZFormats(t *testing.T) {
+	defer func(old string) { *workDir = old }(*workDir)
+	*workDir = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(*workDir, "src", "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(*workDir, "src", "sub", "f.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ct := range []string{pargzip.GzipContentType, pargzip.ZstdContentType} {
+		req := httptest.NewRequest("GET", "/tgz?dir=src", nil)
+		req.Header.Set("Accept", ct)
+		rec := httptest.NewRecorder()
+		handleGetTGZ(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /tgz with Accept %s = %d %q", ct, rec.Code, rec.Body)
+		}
+		if got := rec.Header().Get("Content-Type"); got != ct {
+			t.Errorf("GET /tgz with Accept %s has Content-Type %s", ct, got)
+		}
+
+		// Writing the archive back with its content type
+		// recreates the files.
+		dst := "dst-" + filepath.Base(ct)
+		req = httptest.NewRequest("PUT", "/writetgz?dir="+dst, rec.Body)
+		req.Header.Set("Content-Type", ct)
+		rec = httptest.NewRecorder()
+		handleWriteTGZ(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("PUT /writetgz with Content-Type %s = %d %q", ct, rec.Code, rec.Body)
+		}
+		got, err := os.ReadFile(filepath.Join(*workDir, dst, "sub", "f.txt"))
+		if err != nil || string(got) != "hello" {
+			t.Errorf("after PUT /writetgz with Content-Type %s, sub/f.txt = %q, %v; want %q", ct, got, err, "hello")
+		}
+	}
+}
+
+func TestTGZClientFormats(t *testing.T) {
+	defer func(old string) { *workDir = old }(*workDir)
+	*workDir = t.TempDir()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tgz", handleGetTGZ)
+	mux.HandleFunc("/writetgz", handleWriteTGZ)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc := buildlet.NewClient(u.Host, buildlet.NoKeyPair)
+	defer bc.Close()
+	ctx := context.Background()
+
+	for _, ct := range []string{pargzip.GzipContentType, pargzip.ZstdContentType, pargzip.XzContentType} {
+		t.Run(filepath.Base(ct), func(t *testing.T) {
+			if ct == pargzip.XzContentType {
+				if _, err := exec.LookPath("xz"); err != nil {
+					t.Skip("xz command not found")
+				}
+			}
+			const content = "hello"
+			var fl tarutil.FileList
+			fl.AddRegular(&tar.Header{Name: "sub/f.txt", Mode: 0644, Size: int64(len(content))}, int64(len(content)), strings.NewReader(content))
+			tz, err := fl.TarAs(ct)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer tz.Close()
+			dir := "dst-" + filepath.Base(ct)
+			if err := bc.PutTarAs(ctx, tz, dir, ct); err != nil {
+				t.Fatalf("PutTarAs(%s): %v", ct, err)
+			}
+
+			rc, streamType, err := bc.GetTarAs(ctx, dir, ct)
+			if err != nil {
+				t.Fatalf("GetTarAs(%s): %v", ct, err)
+			}
+			defer rc.Close()
+			if streamType != ct {
+				t.Errorf("GetTarAs(%s) returned a stream of type %s", ct, streamType)
+			}
+			zr, err := pargzip.NewReaderFor(rc, streamType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer zr.Close()
+			got := map[string]string{}
+			tr := tar.NewReader(zr)
+			for {
+				h, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if h.Typeflag != tar.TypeReg {
+					continue
+				}
+				b, err := io.ReadAll(tr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got[h.Name] = string(b)
+			}
+			if got["sub/f.txt"] != content {
+				t.Errorf("GetTarAs(%s) files = %q; want sub/f.txt with %q", ct, got, content)
+			}
+		})
+	}
+}
@@ -65,14 +65,16 @@ func newBuild(rev buildgo.BuilderRev, detail commitDetail) (*buildStatus, error)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
+	buildID := "B" + randHex(9)
 	return &buildStatus{
-		buildID:      "B" + randHex(9),
+		buildID:      buildID,
 		BuilderRev:   rev,
 		commitDetail: detail,
 		conf:         conf,
 		startTime:    time.Now(),
 		ctx:          ctx,
 		cancel:       cancel,
+		output:       livelog.NewLog(buildLogFS, buildID),
 	}, nil
 }
 
@@ -95,12 +97,13 @@ type buildStatus struct {
 
 	mu              sync.Mutex       // guards following
 	canceled        bool             // whether this build was forcefully canceled, so errors should be ignored
+	preempted       bool             // whether this build was canceled to make room for other work, and will be retried
 	schedItem       *queue.SchedItem // for the initial buildlet (ignoring helpers for now)
 	logURL          string           // if non-empty, permanent URL of log
 	bc              buildlet.Client  // nil initially, until pool returns one
 	done            time.Time        // finished running
 	succeeded       bool             // set when done
-	output          livelog.Buffer   // stdout and stderr
+	output          *livelog.Log     // stdout and stderr
 	events          []eventAndTime
 	useSnapshotMemo map[string]bool // memoized result of useSnapshotFor(rev), where the key is rev
 }
@@ -159,6 +162,17 @@ func (st *buildStatus) cancelBuild() {
 	}
 }
 
+// preempt cancels the build, because the scheduling policy needs its
+// buildlet for more important work.
+func (st *buildStatus) preempt() {
+	st.LogEventTime("preempted")
+	fmt.Fprintf(st, "\n\nBuild preempted by more important work; it will be retried.\n")
+	st.mu.Lock()
+	st.preempted = true
+	st.mu.Unlock()
+	st.cancelBuild()
+}
+
 func (st *buildStatus) setDone(succeeded bool) {
 	st.mu.Lock()
 	defer st.mu.Unlock()
@@ -370,6 +384,11 @@ func (st *buildStatus) getBuildlet() (buildlet.Client, error) {
 		Branch:     st.RevBranch,
 		User:       st.AuthorEmail,
 	}
+	if st.trySet == nil {
+		// Post-submit builds are retried by findWork, so they can
+		// give up their buildlets to more important work.
+		schedItem.OnPreempt = st.preempt
+	}
 	st.mu.Lock()
 	st.schedItem = schedItem
 	st.mu.Unlock()
@@ -742,6 +761,20 @@ func (st *buildStatus) writeGoSourceTo(bc buildlet.Client, rev, dir string) erro
 	if err != nil {
 		return err
 	}
+	if rs, ok := srcTar.(io.ReadSeeker); ok {
+		// Upload only the files the buildlet doesn't already have.
+		sp = st.CreateSpan("sync_go_src")
+		_, err := buildgo.SyncTGZ(st.ctx, bc, rs, dir)
+		if err == nil {
+			return sp.Done(nil)
+		}
+		// The buildlet may predate /sync.
+		sp.Done(err)
+		st.logf("syncing Go source to %s failed, writing the tarball instead: %v", bc.Name(), err)
+		if _, err := rs.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
 	sp = st.CreateSpan("write_go_src_tar")
 	if err := bc.PutTar(st.ctx, srcTar, dir); err != nil {
 		return sp.Done(fmt.Errorf("writing tarball from Gerrit: %v", err))
@@ -1897,6 +1930,7 @@ func (st *buildStatus) runTestsOnBuildlet(bc buildlet.Client, tis []*testItem, g
 	)
 	env = append(env, st.modulesEnv()...)
 
+	var stats buildlet.ExecStats
 	remoteErr, err := bc.Exec(ctx, "./go/bin/go", buildlet.ExecOpts{
 		// We set Dir to "." instead of the default ("go/bin") so when the dist tests
 		// try to run os/exec.Command("go", "test", ...), the LookPath of "go" doesn't
@@ -1909,8 +1943,12 @@ func (st *buildStatus) runTestsOnBuildlet(bc buildlet.Client, tis []*testItem, g
 		ExtraEnv: env,
 		Path:     []string{st.conf.FilePathJoin("$WORKDIR", "go", "bin"), "$PATH"},
 		Args:     args,
+		Stats:    &stats,
 	})
 	execDuration := time.Since(t0)
+	if stats != (buildlet.ExecStats{}) {
+		spanlog.Annotate(sp, stats.String())
+	}
 	sp.Done(err)
 	if err != nil {
 		bc.MarkBroken() // prevents reuse
@@ -2131,6 +2169,14 @@ func (st *buildStatus) logs() string {
 	return st.output.String()
 }
 
+// removeLog removes the chunks of the build's log from buildLogFS,
+// once the build's status is no longer served.
+func (st *buildStatus) removeLog() {
+	if err := st.output.Remove(); err != nil {
+		log.Printf("removing log of build %s: %v", st.buildID, err)
+	}
+}
+
 func (st *buildStatus) Write(p []byte) (n int, err error) {
 	return st.output.Write(p)
 }
@@ -2145,6 +2191,14 @@ func (st *buildStatus) repeatedCommunicationError(execErr error) error {
 	if execErr == nil {
 		return nil
 	}
+	st.mu.Lock()
+	preempted := st.preempted
+	st.mu.Unlock()
+	if preempted {
+		// The buildlet was taken away on purpose,
+		// and the build will be retried.
+		return nil
+	}
 	// For now, only do this for plan9, which is flaky (Issue 31261),
 	// but not for plan9-arm (Issue 52677)
 	if strings.HasPrefix(st.Name, "plan9-") && st.Name != "plan9-arm" && execErr == errBuildletsGone {
@@ -387,3 +387,15 @@ func TestGo120DistTestNames(t *testing.T) {
 		})
 	}
 }
+
+func TestRepeatedCommunicationError(t *testing.T) {
+	st := &buildStatus{BuilderRev: buildgo.BuilderRev{Name: "plan9-386"}}
+	if err := st.repeatedCommunicationError(errBuildletsGone); err == nil {
+		t.Errorf("repeatedCommunicationError(errBuildletsGone) = nil on plan9; want a terminal error")
+	}
+	// A preempted build lost its buildlet on purpose, and is retried.
+	st.preempted = true
+	if err := st.repeatedCommunicationError(errBuildletsGone); err != nil {
+		t.Errorf("repeatedCommunicationError(errBuildletsGone) of a preempted build = %v; want nil", err)
+	}
+}
@@ -25,10 +25,12 @@ import (
 	"fmt"
 	"html"
 	"io"
+	"io/fs"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
@@ -57,6 +59,7 @@ import (
 	"golang.org/x/build/internal/coordinator/pool/queue"
 	"golang.org/x/build/internal/coordinator/remote"
 	"golang.org/x/build/internal/coordinator/schedule"
+	"golang.org/x/build/internal/gcsfs"
 	"golang.org/x/build/internal/gomote"
 	gomoteprotos "golang.org/x/build/internal/gomote/protos"
 	"golang.org/x/build/internal/https"
@@ -117,6 +120,13 @@ var (
 	devEnableGCE  = flag.Bool("dev_gce", false, "Whether or not to enable the GCE pool when in dev mode. The pool is enabled by default in prod mode.")
 	devEnableEC2  = flag.Bool("dev_ec2", false, "Whether or not to enable the EC2 pool when in dev mode. The pool is enabled by default in prod mode.")
 	sshAddr       = flag.String("ssh_addr", ":2222", "Address the gomote SSH server should listen on")
+	schedPolicy   = flag.String("sched_policy", "", "If non-empty, the path of a JSON file with the scheduling policy: the priority classes, fair sharing, and preemption. See queue.Policy.")
+	gomoteQuotas  = gomote.RegisterQuotaFlags(flag.CommandLine)
+	buildLogStore = flag.String("build_log_store", "", "A file:// or gs:// URL under which the logs of builds spill in chunks while they're served at /temporarylogs. If empty, a directory under the system's temporary directory.")
+
+	devLocalBuildlet = flag.String("dev_local_buildlet", "", "In dev mode, the path of a buildlet binary to run as local processes or containers for every host type that isn't reverse, instead of using GCE or EC2.")
+	devLocalSocket   = flag.String("dev_local_container_socket", "", "In dev mode with -dev_local_buildlet, the Docker or Podman API socket to run container host types with, such as /var/run/docker.sock. If empty, container host types run as processes.")
+	devLocalImages   = flag.String("dev_local_image_prefix", "", "In dev mode with -dev_local_container_socket, the prefix added to each host type's container image name.")
 )
 
 // LOCK ORDER:
@@ -242,6 +252,18 @@ func main() {
 	}
 	log.Printf("coordinator version %q starting", Version)
 
+	if *schedPolicy != "" {
+		data, err := os.ReadFile(*schedPolicy)
+		if err != nil {
+			log.Fatalf("reading scheduling policy: %v", err)
+		}
+		p, err := queue.ParsePolicy(data)
+		if err != nil {
+			log.Fatal(err)
+		}
+		queue.SetPolicy(p)
+	}
+
 	sc := mustCreateSecretClientOnGCE()
 	if sc != nil {
 		defer sc.Close()
@@ -290,6 +312,11 @@ func main() {
 		defer ec2PoolClose()
 	}
 
+	if *mode == "dev" && *devLocalBuildlet != "" {
+		localPoolClose := mustCreateLocalBuildletPool()
+		defer localPoolClose()
+	}
+
 	if *mode == "dev" {
 		// Replace linux-amd64 with a config using a -localdev reverse
 		// buildlet so it is possible to run local builds by starting a
@@ -304,6 +331,10 @@ func main() {
 		}
 	}
 
+	if err := initBuildLogFS(context.Background()); err != nil {
+		log.Fatalf("initializing build log store: %v", err)
+	}
+
 	go pool.CoordinatorProcess().UpdateInstanceRecord()
 
 	switch *mode {
@@ -331,6 +362,7 @@ func main() {
 	} else {
 		mux.Handle("/metrics", ms)
 		defer ms.Stop()
+		pool.ReverseTrace = reverseTrace
 	}
 
 	dialOpts := []grpc.DialOption{
@@ -395,6 +427,7 @@ func main() {
 	gs := &gRPCServer{dashboardURL: "https://build.golang.org"}
 	setSessionPool(sp)
 	gomoteServer := gomote.New(sp, sched, sshCA, gomoteBucket, mustStorageClient())
+	gomoteServer.SetQuotas(*gomoteQuotas)
 	protos.RegisterCoordinatorServer(grpcServer, gs)
 	gomoteprotos.RegisterGomoteServiceServer(grpcServer, gomoteServer)
 	mux.HandleFunc("/", grpcHandlerFunc(grpcServer, handleStatus)) // Serve a status page at farmer.golang.org.
@@ -413,7 +446,7 @@ func main() {
 	if *mode == "dev" {
 		// TODO(crawshaw): do more in dev mode
 		gce.BuildletPool().SetEnabled(*devEnableGCE)
-		if *devEnableGCE || *devEnableEC2 {
+		if *devEnableGCE || *devEnableEC2 || *devLocalBuildlet != "" {
 			go findWorkLoop()
 		}
 	} else {
@@ -617,6 +650,7 @@ func markDone(work buildgo.BuilderRev) {
 	}
 	delete(status, work)
 	if len(statusDone) == maxStatusDone {
+		go statusDone[0].removeLog()
 		copy(statusDone, statusDone[1:])
 		statusDone = statusDone[:len(statusDone)-1]
 	}
@@ -870,6 +904,20 @@ func handleLogs(w http.ResponseWriter, r *http.Request) {
 	}
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if r.Header.Get("Range") != "" {
+		// Serve only the requested bytes of the log written so far,
+		// without the status header.
+		st.output.ServeRange(w, r)
+		return
+	}
+	// The "offset", "line" and "tail" parameters start the log
+	// elsewhere than at its beginning.
+	off, err := st.output.StartOffset(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	writeStatusHeader(w, st)
 
 	nostream := r.FormValue("nostream") != ""
@@ -877,7 +925,7 @@ func handleLogs(w http.ResponseWriter, r *http.Request) {
 		if nostream {
 			fmt.Fprintf(w, "\n\n(live streaming disabled; reload manually to see status)\n")
 		}
-		w.Write(st.output.Bytes())
+		io.Copy(w, io.NewSectionReader(st.output, off, st.output.Size()-off))
 		return
 	}
 
@@ -887,23 +935,7 @@ func handleLogs(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.(http.Flusher).Flush()
-
-	output := st.output.Reader()
-	go func() {
-		<-r.Context().Done()
-		output.Close()
-	}()
-	buf := make([]byte, 65536)
-	for {
-		n, err := output.Read(buf)
-		if _, err2 := w.Write(buf[:n]); err2 != nil {
-			return
-		}
-		w.(http.Flusher).Flush()
-		if err != nil {
-			break
-		}
-	}
+	st.output.Stream(w, r, off)
 }
 
 func writeStatusHeader(w http.ResponseWriter, st *buildStatus) {
@@ -2277,6 +2309,18 @@ func mustCreateEC2BuildletPool(sc *secret.Client, isRemoteBuildlet func(instName
 	return ec2Pool.Close
 }
 
+func mustCreateLocalBuildletPool() (close func()) {
+	var opts []pool.LocalOpt
+	if *devLocalSocket != "" {
+		opts = append(opts, pool.LocalContainerSocket(*devLocalSocket), pool.LocalImagePrefix(*devLocalImages))
+	}
+	localPool, err := pool.NewLocalBuildlet(*devLocalBuildlet, dashboard.Hosts, opts...)
+	if err != nil {
+		log.Fatalf("unable to create local buildlet pool: %s", err)
+	}
+	return localPool.Close
+}
+
 func mustRetrieveSSHCertificateAuthority() (privateKey []byte) {
 	privateKey, _, err := remote.SSHKeyPair()
 	if err != nil {
@@ -2285,6 +2329,35 @@ func mustRetrieveSSHCertificateAuthority() (privateKey []byte) {
 	return
 }
 
+// buildLogFS is the file system under which the logs of builds spill.
+// See the build_log_store flag.
+var buildLogFS fs.FS
+
+func initBuildLogFS(ctx context.Context) error {
+	u := *buildLogStore
+	if u == "" {
+		dir := filepath.Join(os.TempDir(), "coordinator-build-logs")
+		// Drop the logs of a previous run.
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		u = "file://" + filepath.ToSlash(dir)
+	}
+	var client *storage.Client
+	if strings.HasPrefix(u, "gs://") {
+		client = pool.NewGCEConfiguration().StorageClient()
+		if client == nil {
+			return fmt.Errorf("no storage client for %s", u)
+		}
+	}
+	fsys, err := gcsfs.FromURL(ctx, client, u)
+	if err != nil {
+		return err
+	}
+	buildLogFS = fsys
+	return nil
+}
+
 func mustStorageClient() *storage.Client {
 	if metadata.OnGCE() {
 		return pool.NewGCEConfiguration().StorageClient()
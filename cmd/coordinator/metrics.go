@@ -8,7 +8,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"time"
 
 	"github.com/gliderlabs/ssh"
@@ -16,17 +19,25 @@ import (
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
 	"golang.org/x/build/internal/coordinator/pool"
+	"golang.org/x/build/revdial/v2"
 )
 
 var (
 	kBuilderType        = tag.MustNewKey("go-build/coordinator/keys/builder_type")
 	kGomoteSSHSuccess   = tag.MustNewKey("go-build/coordinator/keys/gomote_ssh_success")
 	kHostType           = tag.MustNewKey("go-build/coordinator/host_type")
+	kCloseReason        = tag.MustNewKey("go-build/coordinator/keys/close_reason")
+	kDirection          = tag.MustNewKey("go-build/coordinator/keys/direction")
 	mGitHubAPIRemaining = stats.Int64("go-build/githubapi/remaining", "remaining GitHub API rate limit", stats.UnitDimensionless)
 	mGomoteCreateCount  = stats.Int64("go-build/coordinator/gomote_create_count", "counter for gomote create invocations", stats.UnitDimensionless)
 	mGomoteRDPCount     = stats.Int64("go-build/coordinator/gomote_rdp_count", "counter for gomote RDP invocations", stats.UnitDimensionless)
 	mGomoteSSHCount     = stats.Int64("go-build/coordinator/gomote_ssh_count", "counter for gomote SSH invocations", stats.UnitDimensionless)
 	mReverseBuildlets   = stats.Int64("go-build/coordinator/reverse_buildlets_count", "number of reverse buildlets", stats.UnitDimensionless)
+	mRevdialRTT         = stats.Float64("go-build/coordinator/revdial_rtt", "round-trip time of revdial control messages", stats.UnitMilliseconds)
+	mRevdialActive      = stats.Int64("go-build/coordinator/revdial_active_conns", "number of open revdial connections", stats.UnitDimensionless)
+	mRevdialPending     = stats.Int64("go-build/coordinator/revdial_pending_dials", "number of revdial dials waiting for a connection", stats.UnitDimensionless)
+	mRevdialConnBytes   = stats.Int64("go-build/coordinator/revdial_conn_bytes", "bytes transferred over a revdial connection", stats.UnitBytes)
+	mRevdialDisconnects = stats.Int64("go-build/coordinator/revdial_disconnects", "counter for disconnections of reverse buildlets", stats.UnitDimensionless)
 )
 
 // views should contain all measurements. All *view.View added to this
@@ -39,6 +50,41 @@ var views = []*view.View{
 		TagKeys:     []tag.Key{kHostType},
 		Aggregation: view.LastValue(),
 	},
+	{
+		Name:        "go-build/coordinator/revdial_rtt",
+		Description: "Round-trip time of revdial control messages to reverse buildlets",
+		Measure:     mRevdialRTT,
+		TagKeys:     []tag.Key{kHostType},
+		Aggregation: view.Distribution(1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000),
+	},
+	{
+		Name:        "go-build/coordinator/revdial_active_conns",
+		Description: "Number of open revdial connections to reverse buildlets",
+		Measure:     mRevdialActive,
+		TagKeys:     []tag.Key{kHostType},
+		Aggregation: view.LastValue(),
+	},
+	{
+		Name:        "go-build/coordinator/revdial_pending_dials",
+		Description: "Number of revdial dials to reverse buildlets waiting for a connection",
+		Measure:     mRevdialPending,
+		TagKeys:     []tag.Key{kHostType},
+		Aggregation: view.LastValue(),
+	},
+	{
+		Name:        "go-build/coordinator/revdial_conn_bytes",
+		Description: "Bytes transferred over each revdial connection to a reverse buildlet",
+		Measure:     mRevdialConnBytes,
+		TagKeys:     []tag.Key{kHostType, kDirection},
+		Aggregation: view.Distribution(1<<10, 1<<14, 1<<17, 1<<20, 1<<23, 1<<26, 1<<29),
+	},
+	{
+		Name:        "go-build/coordinator/revdial_disconnects",
+		Description: "Count of disconnections of reverse buildlets",
+		Measure:     mRevdialDisconnects,
+		TagKeys:     []tag.Key{kHostType, kCloseReason},
+		Aggregation: view.Count(),
+	},
 	{
 		Name:        "go-build/githubapi/remaining",
 		Description: "Remaining GitHub API rate limit",
@@ -79,11 +125,58 @@ func reportReverseCountMetrics() {
 				[]tag.Mutator{tag.Upsert(kHostType, hostType)},
 				mReverseBuildlets.M(int64(n)))
 		}
+		// 3. Write the revdial connection counts, grouped by hostType.
+		for hostType, st := range pool.ReversePool().RevdialStats() {
+			stats.RecordWithTags(context.Background(),
+				[]tag.Mutator{tag.Upsert(kHostType, hostType)},
+				mRevdialActive.M(int64(st.Active)),
+				mRevdialPending.M(int64(st.Pending)))
+		}
 
 		time.Sleep(5 * time.Minute)
 	}
 }
 
+// reverseTrace returns the revdial hooks that record the metrics of
+// the connections to reverse buildlets of hostType.
+func reverseTrace(hostType string) *revdial.Trace {
+	ctx, err := tag.New(context.Background(), tag.Upsert(kHostType, hostType))
+	if err != nil {
+		return nil
+	}
+	return &revdial.Trace{
+		Ping: func(rtt time.Duration) {
+			stats.Record(ctx, mRevdialRTT.M(float64(rtt)/float64(time.Millisecond)))
+		},
+		ConnClosed: func(cs revdial.ConnStats) {
+			stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(kDirection, "in")}, mRevdialConnBytes.M(cs.BytesRead))
+			stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(kDirection, "out")}, mRevdialConnBytes.M(cs.BytesWritten))
+		},
+		Closed: func(reason error) {
+			stats.RecordWithTags(ctx,
+				[]tag.Mutator{tag.Upsert(kCloseReason, closeReasonTag(reason))},
+				mRevdialDisconnects.M(1))
+		},
+	}
+}
+
+// closeReasonTag classifies the close reason of a revdial connection,
+// so the metric has few distinct tag values.
+func closeReasonTag(reason error) string {
+	switch {
+	case errors.Is(reason, revdial.ErrDialerClosed):
+		return "closed"
+	case errors.Is(reason, revdial.ErrKeepAliveTimeout):
+		return "keep-alive-timeout"
+	case errors.Is(reason, os.ErrDeadlineExceeded):
+		return "write-timeout"
+	case errors.Is(reason, io.EOF), errors.Is(reason, io.ErrUnexpectedEOF):
+		return "eof"
+	default:
+		return "error"
+	}
+}
+
 // recordBuildletCreate records information about gomote creates and sends them
 // to the configured metrics backend.
 func recordBuildletCreate(ctx context.Context, builderType string) {
@@ -571,6 +571,12 @@ func handleStatus(w http.ResponseWriter, r *http.Request) {
 	data.EC2PoolStatus = template.HTML(buf.String())
 	buf.Reset()
 
+	if lp := pool.LocalBuildletPool(); lp != nil {
+		lp.WriteHTMLStatus(&buf)
+		data.LocalPoolStatus = template.HTML(buf.String())
+		buf.Reset()
+	}
+
 	pool.ReversePool().WriteHTMLStatus(&buf)
 	data.ReversePoolStatus = template.HTML(buf.String())
 
@@ -636,6 +642,7 @@ type statusData struct {
 	Trybots           template.HTML
 	GCEPoolStatus     template.HTML // TODO: embed template
 	EC2PoolStatus     template.HTML // TODO: embed template
+	LocalPoolStatus   template.HTML // TODO: embed template; empty without a local pool
 	ReversePoolStatus template.HTML // TODO: embed template
 	GomoteInstances   template.HTML
 	SchedState        schedule.SchedulerState
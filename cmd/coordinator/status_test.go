@@ -131,7 +131,18 @@ func TestStatusSched(t *testing.T) {
 					Try:      schedule.SchedulerWaitingState{Count: 1},
 					Regular:  schedule.SchedulerWaitingState{Count: 3},
 				},
+				{
+					HostType:  "with-classes",
+					Total:     schedule.SchedulerWaitingState{Count: 2, Newest: time.Second, Oldest: 11 * time.Minute},
+					Try:       schedule.SchedulerWaitingState{Count: 2},
+					Preempted: 3,
+					Classes: []schedule.SchedulerClassState{
+						{Name: "try-go", SchedulerWaitingState: schedule.SchedulerWaitingState{Count: 1, Newest: 11 * time.Minute, Oldest: 11 * time.Minute}},
+						{Name: "try", SchedulerWaitingState: schedule.SchedulerWaitingState{Count: 1, Newest: time.Second, Oldest: time.Second}},
+					},
+				},
 			},
+			PreemptAfter: 10 * time.Minute,
 		},
 	}
 	buf := new(bytes.Buffer)
@@ -144,6 +155,9 @@ func TestStatusSched(t *testing.T) {
 		`<li>try: 1 \(oldest 5m0s, newest 2s\)</li>`,
 		`(?s)<li><b>gomote-and-try</b>: 6 waiting \(oldest 4m0s, newest 3s\)`, // checks for no ", progress"
 		`<li>gomote: 2 \(oldest 4m0s, newest 3s\)</li>`,
+		`<p>Policy: preempt after 10m0s</p>`,
+		`(?s)<li><b>with-classes</b>: 2 waiting \(oldest 11m0s, newest 1s\), 3 preempted`,
+		`<li>try-go: 1 \(oldest 11m0s, newest 11m0s\)</li>`,
 	}
 	for _, rx := range wantMatch {
 		matched, err := regexp.Match(rx, buf.Bytes())
@@ -41,6 +41,7 @@ var (
 	runBuild = flag.String("run-build", "", "optional builder name to run all.bash or make.bash for")
 	makeOnly = flag.Bool("make-only", false, "if a --run-build builder name is given, this controls whether make.bash or all.bash is run")
 	buildRev = flag.String("rev", "master", "if --run-build is specified, the git hash or branch name to build")
+	goroot   = flag.String("goroot", "", "if --run-build is specified, an optional local Go tree to sync to the buildlet and build instead of --rev")
 
 	useIAPTunnel = flag.Bool("use-iap-tunnel", true, "use an IAP tunnel to connect to GCE builders")
 
@@ -173,18 +174,29 @@ func main() {
 			}
 		}
 
-		// Push Go code
-		log.Printf("Pushing 'go' dir...")
-		goTarGz := "https://go.googlesource.com/go/+archive/" + *buildRev + ".tar.gz"
-		if err := bc.PutTarFromURL(ctx, goTarGz, "go"); err != nil {
-			bc.Close()
-			log.Fatalf("Putting go code: %v", err)
-		}
+		if *goroot != "" {
+			// Sync local Go code
+			log.Printf("Syncing %s to 'go' dir...", *goroot)
+			res, err := buildgo.SyncGoroot(ctx, bc, *goroot, "go")
+			if err != nil {
+				bc.Close()
+				log.Fatalf("Syncing go code: %v", err)
+			}
+			log.Printf("Synced: wrote %d files, deleted %d, uploaded %d bytes", res.Written, res.Deleted, res.Uploaded)
+		} else {
+			// Push Go code
+			log.Printf("Pushing 'go' dir...")
+			goTarGz := "https://go.googlesource.com/go/+archive/" + *buildRev + ".tar.gz"
+			if err := bc.PutTarFromURL(ctx, goTarGz, "go"); err != nil {
+				bc.Close()
+				log.Fatalf("Putting go code: %v", err)
+			}
 
-		// Push a synthetic VERSION file to prevent git usage:
-		if err := bc.PutTar(ctx, buildgo.VersionTgz(*buildRev), "go"); err != nil {
-			bc.Close()
-			log.Fatalf("Putting VERSION file: %v", err)
+			// Push a synthetic VERSION file to prevent git usage:
+			if err := bc.PutTar(ctx, buildgo.VersionTgz(*buildRev), "go"); err != nil {
+				bc.Close()
+				log.Fatalf("Putting VERSION file: %v", err)
+			}
 		}
 
 		script := bconf.AllScript()
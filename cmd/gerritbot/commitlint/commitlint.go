@@ -0,0 +1,124 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The commitlint command checks a commit message for the same common
+// mistakes that GerritBot reports on GitHub PRs.
+//
+// By default it checks the message of the HEAD commit in the current
+// git repository. With -fix, it prints the commit message with any
+// mechanical fixes applied, keeping its footers such as Change-Id.
+//
+// Usage:
+//
+//	commitlint [-repo=name] [-config=rules.json] [-fix] [commit | -]
+//
+// A commit of "-" reads the commit message from standard input.
+// The exit status is 1 if any problems were found.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"golang.org/x/build/cmd/gerritbot/internal/rules"
+)
+
+var (
+	repo       = flag.String("repo", "", "Gerrit repository name, like \"go\" or \"tools\"; if empty, derived from the URL of the origin remote")
+	configFile = flag.String("config", "", "if non-empty, a JSON file configuring the rules per repo")
+	fix        = flag.Bool("fix", false, "print the commit message with mechanical fixes applied, instead of the problems found")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: commitlint [flags] [commit | -]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("commitlint: ")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() > 1 {
+		usage()
+	}
+	commit := "HEAD"
+	if flag.NArg() == 1 {
+		commit = flag.Arg(0)
+	}
+
+	var cfg *rules.Config
+	if *configFile != "" {
+		data, err := os.ReadFile(*configFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if cfg, err = rules.ParseConfig(data); err != nil {
+			log.Fatal(err)
+		}
+	}
+	msg, err := commitMessage(commit)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *repo == "" {
+		if *repo, err = originRepo(); err != nil {
+			log.Fatalf("%v; use -repo", err)
+		}
+	}
+	change, footers, err := rules.ParseLocalCommitMessage(*repo, msg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *fix {
+		fixed, _ := cfg.Fix(change)
+		fmt.Print(fixed.Message(footers))
+		return
+	}
+	results := cfg.Check(change)
+	if len(results) == 0 {
+		return
+	}
+	for i, r := range results {
+		fixable := ""
+		if r.Fixable {
+			fixable = " (fixable with -fix)"
+		}
+		fmt.Printf("%d. %s%s\n", i+1, r.Finding, fixable)
+	}
+	os.Exit(1)
+}
+
+// commitMessage returns the message of the named commit,
+// or standard input if commit is "-".
+func commitMessage(commit string) (string, error) {
+	if commit == "-" {
+		b, err := io.ReadAll(os.Stdin)
+		return string(b), err
+	}
+	out, err := exec.Command("git", "log", "-1", "--format=%B", commit).Output()
+	if err != nil {
+		return "", fmt.Errorf("reading message of commit %s: %v", commit, err)
+	}
+	return string(out), nil
+}
+
+// originRepo returns the name of the repository that the origin remote
+// points to, such as "tools" for https://go.googlesource.com/tools.
+func originRepo() (string, error) {
+	out, err := exec.Command("git", "config", "remote.origin.url").Output()
+	if err != nil {
+		return "", fmt.Errorf("can't determine repo from origin remote: %v", err)
+	}
+	u := strings.TrimSuffix(strings.TrimSpace(string(out)), "/")
+	return strings.TrimSuffix(path.Base(u), ".git"), nil
+}
@@ -44,6 +44,7 @@ var (
 	gitcookiesFile  = flag.String("gitcookies-file", "", "if non-empty, write a git http cookiefile to this location using secret manager")
 	dryRun          = flag.Bool("dry-run", false, "print out mutating actions but don’t perform any")
 	singlePR        = flag.String("single-pr", "", "process only this PR, specified in GitHub shortlink format, e.g. golang/go#1")
+	rulesConfigFile = flag.String("rules-config", "", "if non-empty, a JSON file configuring the commit message rules per repo; see the rules package")
 )
 
 // TODO(amedee): set to this value until the SLO numbers are published
@@ -69,6 +70,15 @@ func main() {
 		log.Fatalf("gerritClient(): %v", err)
 	}
 	b := newBot(ghc, gc)
+	if *rulesConfigFile != "" {
+		data, err := os.ReadFile(*rulesConfigFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if b.rulesConfig, err = rules.ParseConfig(data); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	ctx := context.Background()
 	b.initCorpus(ctx)
@@ -228,6 +238,7 @@ func genProjectAllowlist() map[string]bool {
 type bot struct {
 	githubClient *github.Client
 	gerritClient *gerrit.Client
+	rulesConfig  *rules.Config // nil means the built-in rules settings
 
 	sync.RWMutex // Protects all fields below
 	corpus       *maintner.Corpus
@@ -241,6 +252,10 @@ type bot struct {
 
 	// Cache of Gerrit Account IDs to AccountInfo structs.
 	cachedGerritAccounts map[int]*gerrit.AccountInfo // 1234 -> Detailed Account Info
+
+	// Review summary comments posted on PRs, and the Gerrit meta commit
+	// they were last updated for.
+	reviewSummaries map[string]*reviewSummaryComment // GitHub owner/repo#n -> summary comment
 }
 
 func newBot(githubClient *github.Client, gerritClient *gerrit.Client) *bot {
@@ -250,6 +265,7 @@ func newBot(githubClient *github.Client, gerritClient *gerrit.Client) *bot {
 		importedPRs:          map[string]*maintner.GerritCL{},
 		pendingCLs:           map[string]string{},
 		cachedGerritAccounts: map[int]*gerrit.AccountInfo{},
+		reviewSummaries:      map[string]*reviewSummaryComment{},
 	}
 }
 
@@ -445,6 +461,9 @@ func (b *bot) processPullRequest(ctx context.Context, pr *github.PullRequest) er
 	if err := b.syncGerritCommentsToGitHub(ctx, pr, cl); err != nil {
 		return fmt.Errorf("syncGerritCommentsToGitHub: %v", err)
 	}
+	if err := b.syncReviewSummaryToGitHub(ctx, pr, cl); err != nil {
+		return fmt.Errorf("syncReviewSummaryToGitHub: %v", err)
+	}
 
 	if cmsg == cl.Commit.Msg && pr.GetDraft() == cl.WorkInProgress() {
 		log.Printf("Change https://go-review.googlesource.com/q/%s is up to date; nothing to do.",
@@ -486,6 +505,12 @@ func (b *bot) gerritMessageAuthorName(ctx context.Context, m *maintner.GerritMes
 	if err != nil {
 		return "", fmt.Errorf("gerritMessageAuthorID: %v", err)
 	}
+	return b.gerritAccountName(ctx, id)
+}
+
+// gerritAccountName returns the display name of the Gerrit account with the given ID.
+// b.RWMutex must be Lock'ed.
+func (b *bot) gerritAccountName(ctx context.Context, id int) (string, error) {
 	account := b.cachedGerritAccounts[id]
 	if account != nil {
 		return account.Name, nil
@@ -810,9 +835,17 @@ Please visit Gerrit at %s.
 		if err != nil {
 			return fmt.Errorf("failed to parse commit message for %s: %v", prShortLink(pr), err)
 		}
-		problems := rules.Check(change)
+		problems := b.rulesConfig.Check(change)
 		if len(problems) > 0 {
 			summary := rules.FormatResults(problems)
+			if fixed, ok := b.rulesConfig.Fix(change); ok {
+				// Suggest the mechanical fixes on the PR, since that's
+				// where the commit message needs to be edited.
+				fix := rules.FormatFix(change, fixed)
+				if err := b.postGitHubMessageNoDup(ctx, repo.GetOwner().GetLogin(), repo.GetName(), pr.GetNumber(), "", fix, nil); err != nil {
+					return fmt.Errorf("could not post suggested commit message to %s: %v", prShortLink(pr), err)
+				}
+			}
 			// If needed, summary contains advice for how to edit the commit message.
 			msg := fmt.Sprintf("I spotted some possible problems.\n\n"+
 				"These findings are based on simple heuristics. If a finding appears wrong, briefly reply here saying so. "+
@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-github/v48/github"
+	"golang.org/x/build/gerrit"
 	"golang.org/x/build/maintner"
 	"golang.org/x/build/repos"
 )
@@ -207,3 +208,71 @@ func TestFindChangeURLInAllRepos(t *testing.T) {
 		}
 	}
 }
+
+func TestTrybotStateFromVotes(t *testing.T) {
+	for _, tc := range []struct {
+		desc  string
+		votes map[string]map[string]int8
+		want  trybotState
+	}{
+		{"none", nil, trybotsNotRun},
+		{"queued", map[string]map[string]int8{"Commit-Queue": {"1@x": 1}}, trybotsRunning},
+		{"passed", map[string]map[string]int8{"Commit-Queue": {"1@x": 1}, "LUCI-TryBot-Result": {"2@x": 1}}, trybotsPassed},
+		{"failed", map[string]map[string]int8{"LUCI-TryBot-Result": {"2@x": 1, "3@x": -1}}, trybotsFailed},
+		{"legacy", map[string]map[string]int8{"TryBot-Result": {"2@x": -1}}, trybotsFailed},
+		{"luci preferred", map[string]map[string]int8{"TryBot-Result": {"2@x": -1}, "LUCI-TryBot-Result": {"3@x": 1}}, trybotsPassed},
+	} {
+		if got := trybotStateFromVotes(tc.votes); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestCountUnresolvedThreads(t *testing.T) {
+	yes, no := true, false
+	comments := map[string][]gerrit.CommentInfo{
+		"a.go": {
+			{ID: "1", Unresolved: &yes},
+			{ID: "2", InReplyTo: "1", Unresolved: &no}, // resolves thread 1
+			{ID: "3", Unresolved: &yes},
+		},
+		"b.go": {
+			{ID: "4", Unresolved: &no},
+			{ID: "5", InReplyTo: "4", Unresolved: &yes}, // reopens thread 4
+			{ID: "6"},
+		},
+	}
+	if got, want := countUnresolvedThreads(comments), 2; got != want {
+		t.Errorf("countUnresolvedThreads = %d, want %d", got, want)
+	}
+}
+
+func TestReviewSummaryString(t *testing.T) {
+	s := &reviewSummary{
+		project:  "go",
+		number:   1234,
+		patchSet: 3,
+		votes: []labelVotes{
+			{"Code-Review", []vote{{"Alice", 2}, {"Bob", 1}}},
+			{"Hold", []vote{{"Carol", 1}}},
+		},
+		trybots:    trybotsPassed,
+		unresolved: 1,
+	}
+	want := reviewSummaryMarker + `
+**Gerrit review status for [golang.org/cl/1234](https://go-review.googlesource.com/c/go/+/1234)** (patch set 3)
+
+| Label | Votes |
+| --- | --- |
+| Code-Review | +2 Alice, +1 Bob |
+| Hold | +1 Carol |
+
+TryBots: ✅ passed
+Unresolved comments: 1
+
+---
+_GerritBot updates this comment as the review progresses. Please reply on Gerrit, not here._`
+	if diff := cmp.Diff(want, s.String()); diff != "" {
+		t.Errorf("summary mismatch (-want +got):\n%s", diff)
+	}
+}
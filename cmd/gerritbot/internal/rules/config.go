@@ -0,0 +1,184 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Config configures the rules for each repo.
+//
+// A Config is usually loaded from a JSON file, like:
+//
+//	{
+//		"default": {"maxLineLength": 72},
+//		"repos": {
+//			"vscode-go": {"tracker": "own", "disable": ["body: might use markdown"]}
+//		}
+//	}
+//
+// The zero value, and a nil *Config, use the built-in settings for each repo.
+type Config struct {
+	// Default holds settings for all repos.
+	Default RepoConfig `json:"default"`
+
+	// Repos holds settings for individual repos, keyed by the repository
+	// name as reported by Gerrit. Settings here override those in Default.
+	Repos map[string]RepoConfig `json:"repos"`
+}
+
+// RepoConfig holds the settings for the rules for one repo.
+// Zero-valued fields leave the built-in setting in place.
+type RepoConfig struct {
+	// Disable lists the names of rules that should not run.
+	Disable []string `json:"disable,omitempty"`
+
+	// TitlePrefix is a regexp that the text before the first colon in the
+	// commit title, usually a package path, must match.
+	TitlePrefix string `json:"titlePrefix,omitempty"`
+
+	// Component is what to call the text before the first colon in the
+	// commit title, such as "package". Example is an example commit title.
+	Component string `json:"component,omitempty"`
+	Example   string `json:"example,omitempty"`
+
+	// MaxLineLength is the length at which lines in the commit message
+	// body should be wrapped. A line is reported as too long once it
+	// exceeds this by more than 2 characters.
+	MaxLineLength int `json:"maxLineLength,omitempty"`
+
+	// Tracker is the issue tracker the repo uses: "main" for golang/go,
+	// "own" for a repo-specific tracker, or "unknown".
+	Tracker string `json:"tracker,omitempty"`
+
+	// BugFooters are the words that may start a line referencing a bug,
+	// such as "Fixes" or "Updates".
+	BugFooters []string `json:"bugFooters,omitempty"`
+}
+
+// ParseConfig parses a JSON-encoded Config and checks that its settings are valid.
+func ParseConfig(data []byte) (*Config, error) {
+	cfg := new(Config)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("rules: ParseConfig: %v", err)
+	}
+	if err := cfg.Default.validate(); err != nil {
+		return nil, fmt.Errorf("rules: ParseConfig: default: %v", err)
+	}
+	for repo, rc := range cfg.Repos {
+		if err := rc.validate(); err != nil {
+			return nil, fmt.Errorf("rules: ParseConfig: repo %q: %v", repo, err)
+		}
+	}
+	return cfg, nil
+}
+
+func (rc *RepoConfig) validate() error {
+	if rc.TitlePrefix != "" {
+		if _, err := regexp.Compile(rc.TitlePrefix); err != nil {
+			return fmt.Errorf("bad titlePrefix: %v", err)
+		}
+	}
+	if rc.MaxLineLength < 0 {
+		return fmt.Errorf("negative maxLineLength %d", rc.MaxLineLength)
+	}
+	switch rc.Tracker {
+	case "", "main", "own", "unknown":
+	default:
+		return fmt.Errorf("unknown tracker %q; want main, own, or unknown", rc.Tracker)
+	}
+	for _, name := range rc.Disable {
+		if !knownRule(name) {
+			return fmt.Errorf("unknown rule %q in disable", name)
+		}
+	}
+	for _, w := range rc.BugFooters {
+		if w == "" || strings.ContainsAny(w, " \t\n") {
+			return fmt.Errorf("bad bugFooters word %q", w)
+		}
+	}
+	return nil
+}
+
+func knownRule(name string) bool {
+	for _, group := range ruleGroups {
+		for _, r := range group {
+			if r.name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// forRepo returns the complete settings for repo: the built-in
+// settings, overridden by c.Default, then by c.Repos[repo].
+func (c *Config) forRepo(repo string) *RepoConfig {
+	component, example := packageExample(repo)
+	rc := &RepoConfig{
+		TitlePrefix:   titlePrefixPattern(repo),
+		Component:     component,
+		Example:       example,
+		MaxLineLength: 76,
+		Tracker:       usesTracker(repo).String(),
+		BugFooters:    []string{"Fixes", "Updates", "For", "Closes", "Resolves"},
+	}
+	if c != nil {
+		rc.override(c.Default)
+		rc.override(c.Repos[repo])
+	}
+	return rc
+}
+
+// override replaces settings in rc with those set in o.
+// Disabled rules accumulate.
+func (rc *RepoConfig) override(o RepoConfig) {
+	rc.Disable = append(rc.Disable, o.Disable...)
+	if o.TitlePrefix != "" {
+		rc.TitlePrefix = o.TitlePrefix
+	}
+	if o.Component != "" {
+		rc.Component = o.Component
+	}
+	if o.Example != "" {
+		rc.Example = o.Example
+	}
+	if o.MaxLineLength != 0 {
+		rc.MaxLineLength = o.MaxLineLength
+	}
+	if o.Tracker != "" {
+		rc.Tracker = o.Tracker
+	}
+	if len(o.BugFooters) > 0 {
+		rc.BugFooters = o.BugFooters
+	}
+}
+
+// tracker returns the configured issue tracker.
+func (rc *RepoConfig) tracker() tracker {
+	switch rc.Tracker {
+	case "main":
+		return mainTracker
+	case "own":
+		return ownTracker
+	}
+	return unknownTracker
+}
+
+func (t tracker) String() string {
+	switch t {
+	case mainTracker:
+		return "main"
+	case ownTracker:
+		return "own"
+	}
+	return "unknown"
+}
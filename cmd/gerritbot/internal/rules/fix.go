@@ -0,0 +1,192 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// This file contains the fixes for rules that have a mechanical rewrite.
+// Each fix returns change unmodified if it isn't confident about a rewrite.
+
+// fixTitleColonSpacing normalizes the space around the first colon in the title.
+func fixTitleColonSpacing(change Change, cfg *RepoConfig) Change {
+	before, after, ok := strings.Cut(change.Title, ":")
+	before, after = strings.TrimRight(before, " "), strings.TrimLeft(after, " ")
+	if !ok || before == "" || after == "" || !match(cfg.TitlePrefix, before) {
+		return change
+	}
+	change.Title = before + ": " + after
+	return change
+}
+
+// fixTitleLowercaseWord lowercases a capitalized first word after the first colon
+// in the title, like "Improve". Words that might be acronyms or identifiers,
+// like "HTTP" or "ReadFile", are left alone.
+func fixTitleLowercaseWord(change Change, cfg *RepoConfig) Change {
+	before, after, ok := strings.Cut(change.Title, ":")
+	if !ok {
+		return change
+	}
+	m := capitalizedWordRE.FindStringSubmatchIndex(after)
+	if m == nil {
+		return change
+	}
+	i := m[2] // start of the word
+	r, size := utf8.DecodeRuneInString(after[i:])
+	change.Title = before + ":" + after[:i] + string(unicode.ToLower(r)) + after[i+size:]
+	return change
+}
+
+var capitalizedWordRE = regexp.MustCompile(`^ *([A-Z][a-z]+)\b`)
+
+// fixTitlePeriod removes a single period at the end of the title.
+// An ellipsis is left alone.
+func fixTitlePeriod(change Change, cfg *RepoConfig) Change {
+	if strings.HasSuffix(change.Title, "..") {
+		return change
+	}
+	change.Title = strings.TrimRight(strings.TrimSuffix(change.Title, "."), " ")
+	return change
+}
+
+// fixLongLines rewraps paragraphs of plain prose in the body that have long lines.
+// Paragraphs that are indented, look like lists, or contain URLs, paths, or bug
+// references are left alone.
+func fixLongLines(change Change, cfg *RepoConfig) Change {
+	paras := strings.Split(change.Body, "\n\n")
+	for i, para := range paras {
+		lines := strings.Split(para, "\n")
+		long := false
+		for _, line := range lines {
+			if len([]rune(line)) > cfg.MaxLineLength+2 {
+				long = true
+			}
+		}
+		if !long || !plainProse(lines, cfg) {
+			continue
+		}
+		paras[i] = wrap(strings.Fields(para), cfg.MaxLineLength)
+	}
+	change.Body = strings.Join(paras, "\n\n")
+	return change
+}
+
+// plainProse reports whether the lines of a paragraph can be safely rewrapped.
+func plainProse(lines []string, cfg *RepoConfig) bool {
+	if match(`^([-*+] |\d+[.)] )`, lines[0]) {
+		return false // Looks like a list.
+	}
+	for _, line := range lines {
+		if line == "" || line[0] == ' ' || line[0] == '\t' || mightBeWide(line) {
+			return false
+		}
+		if match(`^`+bugFooterPattern(cfg)+` `, line) {
+			return false
+		}
+	}
+	return true
+}
+
+// wrap joins words into lines no longer than width, except for words
+// that are longer than width on their own.
+func wrap(words []string, width int) string {
+	var b strings.Builder
+	n := 0 // length of current line
+	for _, w := range words {
+		l := len([]rune(w))
+		if n > 0 && n+1+l > width {
+			b.WriteString("\n")
+			n = 0
+		}
+		if n > 0 {
+			b.WriteString(" ")
+			n++
+		}
+		b.WriteString(w)
+		n += l
+	}
+	return b.String()
+}
+
+// fixSignedOffBy removes Signed-off-by lines from the body.
+func fixSignedOffBy(change Change, cfg *RepoConfig) Change {
+	var keep []string
+	for _, line := range splitLines(change.Body) {
+		if match(`(?i)^Signed-off-by: `, line) {
+			continue
+		}
+		keep = append(keep, line)
+	}
+	change.Body = strings.TrimRight(strings.Join(keep, "\n"), "\n")
+	return change
+}
+
+// bugLineRE matches a line that references a bug in a loose format,
+// like "fixes: #123." or "Updates golang/go#123".
+// The submatches are the leading word, the repo (if any), and the bug number.
+var bugLineRE = regexp.MustCompile(`^(?i:([a-z]+)):? +(?:golang/([a-z0-9-]+))?#(\d+)\.?$`)
+
+// fixBugFormat rewrites loosely formatted bug references in the body,
+// like "fixes: #123", into the format expected for the repo,
+// like "Fixes golang/go#123". References to other repos' trackers
+// are left alone.
+func fixBugFormat(change Change, cfg *RepoConfig) Change {
+	lines := splitLines(change.Body)
+	for i, line := range lines {
+		m := bugLineRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		word, repo, num := m[1], m[2], m[3]
+		var canonical string
+		for _, w := range cfg.BugFooters {
+			if strings.EqualFold(w, word) {
+				canonical = w
+			}
+		}
+		if canonical == "" {
+			continue
+		}
+		want := "go" // the repo whose tracker change.Repo uses
+		if cfg.tracker() == ownTracker {
+			want = change.Repo
+		}
+		if repo != "" && repo != want {
+			continue
+		}
+		if change.Repo == "go" {
+			lines[i] = fmt.Sprintf("%s #%s", canonical, num)
+		} else {
+			lines[i] = fmt.Sprintf("%s golang/%s#%s", canonical, want, num)
+		}
+	}
+	change.Body = strings.Join(lines, "\n")
+	return change
+}
+
+// fixBugAtEnd moves well-formed bug reference lines to the end of the body.
+func fixBugAtEnd(change Change, cfg *RepoConfig) Change {
+	re := regexp.MustCompile(`^` + bugFooterPattern(cfg) + ` ` + bugPattern(change.Repo, cfg) + `\.?$`)
+	var rest, bugs []string
+	for _, line := range splitLines(change.Body) {
+		if re.MatchString(line) {
+			bugs = append(bugs, line)
+			continue
+		}
+		rest = append(rest, line)
+	}
+	if len(bugs) == 0 {
+		return change
+	}
+	body := strings.TrimRight(strings.Join(rest, "\n"), "\n")
+	body = regexp.MustCompile(`\n{3,}`).ReplaceAllString(body, "\n\n")
+	change.Body = body + "\n\n" + strings.Join(bugs, "\n")
+	return change
+}
@@ -35,6 +35,13 @@
 // Rules currently err on the side of simplicity and avoiding false positives.
 // It is intended to be straightforward to add a new rule.
 //
+// Some settings used by the rules, such as the expected format of the package
+// at the start of the title, the maximum line length, and which issue tracker
+// a repo uses, can be changed per repo with a Config, usually loaded from a
+// JSON file with ParseConfig. Rules may also provide a fix, which is a
+// mechanical rewrite of the commit message that addresses the finding.
+// Config.Fix applies all such fixes.
+//
 // Rules can be arranged to trigger completely independently of one another,
 // or alternatively a set of rules can optionally be arranged in groups that
 // form a precedence based on order within the group, where at most one rule
@@ -50,6 +57,7 @@ package rules
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -63,7 +71,13 @@ type rule struct {
 	// f is the rule function that reports a single finding and optionally
 	// an auxiliary note, which for example could be advice on how to edit the commit message.
 	// Notes are deduplicated across different rules, and ignored for a given rule if there is no finding.
-	f func(change Change) (finding string, note string)
+	// The cfg argument holds the settings for change.Repo.
+	f func(change Change, cfg *RepoConfig) (finding string, note string)
+
+	// fix, if non-nil, returns change rewritten to address the finding reported by f.
+	// It is only called if f reports a finding, and should leave change
+	// unmodified if it has no confident rewrite.
+	fix func(change Change, cfg *RepoConfig) Change
 
 	// skip lists repos to skip for this rule.
 	skip []string
@@ -83,27 +97,14 @@ var ruleGroups = [][]rule{
 		{
 			name: "title: no package found",
 			skip: []string{"proposal"}, // We allow the proposal repo to have an irregular title.
-			f: func(change Change) (finding string, note string) {
-				component, example := packageExample(change.Repo)
-				finding = fmt.Sprintf("The commit title should start with the primary affected %s name followed by a colon, like \"%s\".", component, example)
+			f: func(change Change, cfg *RepoConfig) (finding string, note string) {
+				finding = fmt.Sprintf("The commit title should start with the primary affected %s name followed by a colon, like \"%s\".", cfg.Component, cfg.Example)
 				start, _, ok := strings.Cut(change.Title, ":")
 				if !ok {
 					// No colon.
 					return finding, commitMessageAdvice
 				}
-				var pattern string
-				switch change.Repo {
-				default:
-					// A single package starts with a lowercase ASCII and has no spaces prior to the colon.
-					pattern = `^[a-z][^ ]+$`
-				case "website":
-					// Allow leading underscore (e.g., "_content").
-					pattern = `^[a-z_][^ ]+$`
-				case "vscode-go":
-					// Allow leading uppercase and period (e.g., ".github/workflow").
-					pattern = `^[a-zA-Z.][^ ]+$`
-				}
-				if match(pattern, start) {
+				if match(cfg.TitlePrefix, start) {
 					return "", ""
 				}
 				if match(`^(README|DEBUG)`, start) {
@@ -117,27 +118,27 @@ var ruleGroups = [][]rule{
 				}
 				return finding, commitMessageAdvice
 			},
+			fix: fixTitleColonSpacing,
 		},
 		{
 			name: "title: no colon then single space after package",
 			skip: []string{"proposal"}, // We allow the proposal repo to have an irregular title.
-			f: func(change Change) (finding string, note string) {
-				component, example := packageExample(change.Repo)
-				finding = fmt.Sprintf("The %s in the commit title should be followed by a colon and single space, like \"%s\".", component, example)
+			f: func(change Change, cfg *RepoConfig) (finding string, note string) {
+				finding = fmt.Sprintf("The %s in the commit title should be followed by a colon and single space, like \"%s\".", cfg.Component, cfg.Example)
 				if !match(`[^ ]: [^ ]`, change.Title) {
 					return finding, commitMessageAdvice
 				}
 				return "", ""
 			},
+			fix: fixTitleColonSpacing,
 		},
 	},
 	{
 		{
 			name: "title: no lowercase word after a first colon",
 			skip: []string{"proposal"}, // We allow the proposal repo to have an irregular title.
-			f: func(change Change) (finding string, note string) {
-				component, _ := packageExample(change.Repo)
-				finding = fmt.Sprintf("The first word in the commit title after the %s should be a lowercase English word (usually a verb).", component)
+			f: func(change Change, cfg *RepoConfig) (finding string, note string) {
+				finding = fmt.Sprintf("The first word in the commit title after the %s should be a lowercase English word (usually a verb).", cfg.Component)
 				_, after, ok := strings.Cut(change.Title, ":")
 				if !ok {
 					// No colon. Someone who doesn't have any colon probably can use a reminder about what comes next.
@@ -149,18 +150,20 @@ var ruleGroups = [][]rule{
 				}
 				return "", ""
 			},
+			fix: fixTitleLowercaseWord,
 		},
 	},
 	{
 		{
 			name: "title: ends with period",
-			f: func(change Change) (finding string, note string) {
+			f: func(change Change, cfg *RepoConfig) (finding string, note string) {
 				finding = "The commit title should not end with a period."
 				if len(change.Title) > 0 && change.Title[len(change.Title)-1] == '.' {
 					return finding, commitMessageAdvice
 				}
 				return "", ""
 			},
+			fix: fixTitlePeriod,
 		},
 	},
 	{
@@ -168,7 +171,7 @@ var ruleGroups = [][]rule{
 		// The second rule is pickier.
 		{
 			name: "body: short",
-			f: func(change Change) (finding string, note string) {
+			f: func(change Change, cfg *RepoConfig) (finding string, note string) {
 				finding = "The commit message body is %s. " +
 					"That can be OK if the change is trivial like correcting spelling or fixing a broken link, " +
 					"but usually the description should provide context for the change and explain what it does in complete sentences."
@@ -186,7 +189,7 @@ var ruleGroups = [][]rule{
 		},
 		{
 			name: "body: no sentence candidates found",
-			f: func(change Change) (finding string, note string) {
+			f: func(change Change, cfg *RepoConfig) (finding string, note string) {
 				finding = "Are you describing the change in complete sentences with correct punctuation in the commit message body, including ending sentences with periods?"
 				if !mightBeTrivial(change) && !match("[a-zA-Z0-9\"'`)][.:?!)]( |\\n|$)", change.Body) {
 					// A complete English sentence usually ends with an alphanumeric immediately followed by a terminating punctuation.
@@ -201,8 +204,8 @@ var ruleGroups = [][]rule{
 	{
 		{
 			name: "body: long lines",
-			f: func(change Change) (finding string, note string) {
-				finding = "Lines in the commit message should be wrapped at ~76 characters unless needed for things like URLs or tables. You have a %d character line."
+			f: func(change Change, cfg *RepoConfig) (finding string, note string) {
+				finding = "Lines in the commit message should be wrapped at ~%d characters unless needed for things like URLs or tables. You have a %d character line."
 				// Check if something smells like a table, ASCII art, or benchstat output.
 				if match("----|____|[\u2500-\u257F]", change.Body) || match(` ± [0-9.]+%`, change.Body) {
 					// This might be something that is allowed to be wide.
@@ -214,8 +217,7 @@ var ruleGroups = [][]rule{
 				}
 				longest := 0
 				for _, line := range splitLines(change.Body) {
-					if match(`https?://|www\.|\.(com|org|dev)`, line) || matchCount(`[/\\]`, line) > 4 {
-						// Might be a long url or other path.
+					if mightBeWide(line) {
 						continue
 					}
 					l := len([]rune(line))
@@ -223,17 +225,18 @@ var ruleGroups = [][]rule{
 						longest = l
 					}
 				}
-				if longest > 78 { // Official guidance on wiki is "~76".
-					return fmt.Sprintf(finding, longest), commitMessageAdvice
+				if longest > cfg.MaxLineLength+2 { // Official guidance on wiki is "~76".
+					return fmt.Sprintf(finding, cfg.MaxLineLength, longest), commitMessageAdvice
 				}
 				return "", ""
 			},
+			fix: fixLongLines,
 		},
 	},
 	{
 		{
 			name: "body: might use markdown",
-			f: func(change Change) (finding string, note string) {
+			f: func(change Change, cfg *RepoConfig) (finding string, note string) {
 				finding = "Are you using markdown? Markdown should not be used to augment text in the commit message."
 				if match(`(?i)markdown`, change.Title) || match(`(?i)markdown`, change.Body) {
 					// Could be a markdown-related fix.
@@ -270,7 +273,7 @@ var ruleGroups = [][]rule{
 	{
 		{
 			name: "body: still contains PR instructions",
-			f: func(change Change) (finding string, note string) {
+			f: func(change Change, cfg *RepoConfig) (finding string, note string) {
 				finding = "Do you still have the GitHub PR instructions in your commit message text? The PR instructions should be deleted once you have applied them."
 				if strings.Contains(change.Body, "Delete these instructions once you have read and applied them") {
 					return finding, commitMessageAdvice
@@ -282,13 +285,14 @@ var ruleGroups = [][]rule{
 	{
 		{
 			name: "body: contains Signed-off-by",
-			f: func(change Change) (finding string, note string) {
+			f: func(change Change, cfg *RepoConfig) (finding string, note string) {
 				finding = "Please do not use 'Signed-off-by'. We instead rely on contributors signing CLAs."
 				if match(`(?mi)^Signed-off-by: `, change.Body) {
 					return finding, commitMessageAdvice
 				}
 				return "", ""
 			},
+			fix: fixSignedOffBy,
 		},
 	},
 	{
@@ -299,21 +303,21 @@ var ruleGroups = [][]rule{
 		{
 			name: "body: no bug reference candidate found",
 			skip: []string{"proposal"},
-			f: func(change Change) (finding string, note string) {
+			f: func(change Change, cfg *RepoConfig) (finding string, note string) {
 				finding = "You usually need to reference a bug number for all but trivial or cosmetic fixes. " +
 					"%s at the end of the commit message. Should you have a bug reference?"
 				if mightBeTrivial(change) {
 					return "", ""
 				}
 				var bugPattern string
-				switch usesTracker(change.Repo) {
+				switch cfg.tracker() {
 				case mainTracker:
 					bugPattern = `#\d{4}`
 				default:
 					bugPattern = `#\d{2}`
 				}
 				if !match(bugPattern, change.Body) {
-					return fmt.Sprintf(finding, bugExamples(change.Repo)), commitMessageAdvice
+					return fmt.Sprintf(finding, bugExamples(change.Repo, cfg)), commitMessageAdvice
 				}
 				return "", ""
 			},
@@ -321,32 +325,22 @@ var ruleGroups = [][]rule{
 		{
 			name: "body: bug format looks incorrect",
 			skip: []string{"proposal"},
-			f: func(change Change) (finding string, note string) {
+			f: func(change Change, cfg *RepoConfig) (finding string, note string) {
 				finding = "Do you have the right bug reference format? %s at the end of the commit message."
 				if mightBeTrivial(change) {
 					return "", ""
 				}
-				var bugPattern string
-				switch {
-				case change.Repo == "go":
-					bugPattern = `#\d{4,}`
-				case usesTracker(change.Repo) == mainTracker:
-					bugPattern = `golang/go#\d{4,}`
-				case usesTracker(change.Repo) == ownTracker:
-					bugPattern = fmt.Sprintf(`golang/%s#\d{2,}`, change.Repo)
-				default:
-					bugPattern = `golang/go#\d{4,}`
-				}
-				if !match(`(?m)^(Fixes|Updates|For|Closes|Resolves) `+bugPattern+`\.?$`, change.Body) {
-					return fmt.Sprintf(finding, bugExamples(change.Repo)), commitMessageAdvice
+				if !match(`(?m)^`+bugFooterPattern(cfg)+` `+bugPattern(change.Repo, cfg)+`\.?$`, change.Body) {
+					return fmt.Sprintf(finding, bugExamples(change.Repo, cfg)), commitMessageAdvice
 				}
 				return "", ""
 			},
+			fix: fixBugFormat,
 		},
 		{
 			name: "body: no bug reference candidate at end",
 			skip: []string{"proposal"},
-			f: func(change Change) (finding string, note string) {
+			f: func(change Change, cfg *RepoConfig) (finding string, note string) {
 				// If this rule is running, it means it passed the earlier bug-related rules
 				// in this group, so we know there is what looks like a well-formed bug.
 				finding = "It looks like you have a properly formated bug reference, but the convention is to " +
@@ -357,7 +351,7 @@ var ruleGroups = [][]rule{
 				}
 				var bugPattern string
 				switch {
-				case usesTracker(change.Repo) == mainTracker:
+				case cfg.tracker() == mainTracker:
 					bugPattern = `#\d{4}`
 				default:
 					bugPattern = `#\d{2}`
@@ -368,6 +362,7 @@ var ruleGroups = [][]rule{
 				}
 				return "", ""
 			},
+			fix: fixBugAtEnd,
 		},
 	},
 }
@@ -385,15 +380,40 @@ func mightBeTrivial(change Change) bool {
 	return match(`(?i)typo|spell|grammar|grammatical|comment|readme|document|\bdocs?\b|example|(fix|correct|broken|wrong).*(link|url)`, change.Title)
 }
 
+// bugPattern returns a regexp matching a well-formed bug reference for a repo,
+// such as "#12345" for the main repo or "golang/go#12345" for others.
+func bugPattern(repo string, cfg *RepoConfig) string {
+	switch {
+	case repo == "go":
+		return `#\d{4,}`
+	case cfg.tracker() == mainTracker:
+		return `golang/go#\d{4,}`
+	case cfg.tracker() == ownTracker:
+		return fmt.Sprintf(`golang/%s#\d{2,}`, regexp.QuoteMeta(repo))
+	default:
+		return `golang/go#\d{4,}`
+	}
+}
+
+// bugFooterPattern returns a regexp matching any of the words that may
+// start a bug reference line, like "Fixes" or "Updates".
+func bugFooterPattern(cfg *RepoConfig) string {
+	words := make([]string, len(cfg.BugFooters))
+	for i, w := range cfg.BugFooters {
+		words[i] = regexp.QuoteMeta(w)
+	}
+	return "(" + strings.Join(words, "|") + ")"
+}
+
 // bugExamples returns a snippet of text that includes example bug references
 // formatted as expected for a repo.
-func bugExamples(repo string) string {
+func bugExamples(repo string, cfg *RepoConfig) string {
 	switch {
 	case repo == "go":
 		return "For this repo, the format is usually 'Fixes #12345' or 'Updates #12345'"
-	case usesTracker(repo) == mainTracker:
+	case cfg.tracker() == mainTracker:
 		return fmt.Sprintf("For the %s repo, the format is usually 'Fixes golang/go#12345' or 'Updates golang/go#12345'", repo)
-	case usesTracker(repo) == ownTracker:
+	case cfg.tracker() == ownTracker:
 		return fmt.Sprintf("For the %s repo, the format is usually 'Fixes golang/%s#1234' or 'Updates golang/%s#1234'", repo, repo, repo)
 	default:
 		// We don't know how issues should be referenced for this repo, including this might be
@@ -416,6 +436,28 @@ func packageExample(repo string) (component string, example string) {
 	}
 }
 
+// titlePrefixPattern returns a regexp for the package or component that
+// starts a commit title for a given repo.
+func titlePrefixPattern(repo string) string {
+	switch repo {
+	default:
+		// A single package starts with a lowercase ASCII and has no spaces prior to the colon.
+		return `^[a-z][^ ]+$`
+	case "website":
+		// Allow leading underscore (e.g., "_content").
+		return `^[a-z_][^ ]+$`
+	case "vscode-go":
+		// Allow leading uppercase and period (e.g., ".github/workflow").
+		return `^[a-zA-Z.][^ ]+$`
+	}
+}
+
+// mightBeWide reports whether a line of a commit message body might
+// be a long URL or other path that is allowed to exceed the line length limit.
+func mightBeWide(line string) bool {
+	return match(`https?://|www\.|\.(com|org|dev)`, line) || matchCount(`[/\\]`, line) > 4
+}
+
 // tracker is the issue tracker used by a repo.
 type tracker int
 
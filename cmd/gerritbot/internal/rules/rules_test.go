@@ -574,6 +574,172 @@ func TestParseCommitMessage(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	good := `{
+		"default": {"maxLineLength": 72},
+		"repos": {"vscode-go": {"tracker": "main", "disable": ["body: might use markdown"]}}
+	}`
+	cfg, err := ParseConfig([]byte(good))
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	rc := cfg.forRepo("vscode-go")
+	if rc.MaxLineLength != 72 || rc.Tracker != "main" || rc.Component != "component" {
+		t.Errorf("forRepo(vscode-go) = %+v, want maxLineLength 72, main tracker, and built-in component", rc)
+	}
+
+	for _, bad := range []string{
+		`{"default": {"tracker": "other"}}`,
+		`{"default": {"titlePrefix": "("}}`,
+		`{"repos": {"go": {"disable": ["no such rule"]}}}`,
+		`{"repos": {"go": {"bugFooters": ["Fixes issue"]}}}`,
+		`{"unknownField": 1}`,
+	} {
+		if _, err := ParseConfig([]byte(bad)); err == nil {
+			t.Errorf("ParseConfig(%s) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestConfigCheck(t *testing.T) {
+	change := Change{
+		Repo:  "tools",
+		Title: "gopls: do something",
+		Body:  "This line is sixty-five characters long, which is fine by default.\n\nFixes #1234",
+	}
+	cfg := &Config{
+		Default: RepoConfig{MaxLineLength: 60},
+		Repos: map[string]RepoConfig{
+			"tools": {Tracker: "own", Disable: []string{"body: bug format looks incorrect"}},
+		},
+	}
+	var got []string
+	for _, r := range cfg.Check(change) {
+		got = append(got, r.Name)
+	}
+	want := []string{"body: long lines"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Check mismatch (-want +got):\n%s", diff)
+	}
+	if got := Check(change); len(got) != 1 || got[0].Name != "body: bug format looks incorrect" {
+		t.Errorf("Check with built-in settings = %v, want only a bug format finding", got)
+	}
+}
+
+func TestFix(t *testing.T) {
+	tests := []struct {
+		name  string
+		repo  string // if empty string, treated as "go" repo
+		title string
+		body  string
+		want  Change
+	}{
+		{
+			name:  "title",
+			title: "net/http :Improve things.",
+			body:  goodCommitBody,
+			want:  Change{Title: "net/http: improve things", Body: goodCommitBody},
+		},
+		{
+			name:  "acronym",
+			title: "net/http: HTTP/2 things",
+			body:  goodCommitBody,
+			want:  Change{Title: "net/http: HTTP/2 things", Body: goodCommitBody},
+		},
+		{
+			name:  "bug format and position",
+			repo:  "tools",
+			title: goodCommitTitle,
+			body:  "A sentence.\n\nfixes: #1234.\n\nAnother sentence.\n\nSigned-off-by: Gopher <gopher@example.com>",
+			want:  Change{Repo: "tools", Title: goodCommitTitle, Body: "A sentence.\n\nAnother sentence.\n\nFixes golang/go#1234"},
+		},
+		{
+			name:  "own tracker",
+			repo:  "vscode-go",
+			title: "src/goMain: do a thing",
+			body:  "A sentence.\n\nUpdates #123",
+			want:  Change{Repo: "vscode-go", Title: "src/goMain: do a thing", Body: "A sentence.\n\nUpdates golang/vscode-go#123"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := "go"
+			if tt.repo != "" {
+				repo = tt.repo
+			}
+			tt.want.Repo = repo
+			change := Change{Repo: repo, Title: tt.title, Body: tt.body}
+			got, _ := (*Config)(nil).Fix(change)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Fix mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFixLongLines(t *testing.T) {
+	prose := strings.Repeat("word ", 20) + "end.\n" + strings.Repeat("word ", 10) + "end."
+	code := "    indented " + strings.Repeat("code ", 20)
+	change := Change{Repo: "go", Title: goodCommitTitle, Body: prose + "\n\n" + code + "\n\nFixes #1234"}
+	got, ok := (*Config)(nil).Fix(change)
+	if !ok {
+		t.Fatalf("Fix reported no changes")
+	}
+	paras := strings.Split(got.Body, "\n\n")
+	if len(paras) != 3 {
+		t.Fatalf("Fix changed the number of paragraphs: %q", got.Body)
+	}
+	if diff := cmp.Diff(strings.Fields(prose), strings.Fields(paras[0])); diff != "" {
+		t.Errorf("Fix changed the words of the prose paragraph (-want +got):\n%s", diff)
+	}
+	for _, line := range splitLines(paras[0]) {
+		if len(line) > 76 {
+			t.Errorf("line %q is longer than 76 characters", line)
+		}
+	}
+	if paras[1] != code {
+		t.Errorf("Fix rewrapped an indented paragraph: %q", paras[1])
+	}
+}
+
+func TestParseLocalCommitMessage(t *testing.T) {
+	for _, tt := range []struct {
+		text        string
+		want        Change
+		wantFooters string
+	}{
+		{"net/http: title only\n", Change{Repo: "go", Title: "net/http: title only"}, ""},
+		{"net/http: title\n\nBody.\n", Change{Repo: "go", Title: "net/http: title", Body: "Body."}, ""},
+		{"net/http: title\n\nBody.\n\nChange-Id: I1234\n", Change{Repo: "go", Title: "net/http: title", Body: "Body."}, "Change-Id: I1234"},
+		{"net/http: title\n\nChange-Id: I1234\nReviewed-by: Gopher <gopher@golang.org>\n\n", Change{Repo: "go", Title: "net/http: title"}, "Change-Id: I1234\nReviewed-by: Gopher <gopher@golang.org>"},
+	} {
+		got, footers, err := ParseLocalCommitMessage("go", tt.text)
+		if err != nil {
+			t.Errorf("ParseLocalCommitMessage(%q): %v", tt.text, err)
+			continue
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("ParseLocalCommitMessage(%q) mismatch (-want +got):\n%s", tt.text, diff)
+		}
+		if footers != tt.wantFooters {
+			t.Errorf("ParseLocalCommitMessage(%q) footers = %q; want %q", tt.text, footers, tt.wantFooters)
+		}
+	}
+}
+
+func TestFixKeepsFooters(t *testing.T) {
+	text := "net/http :Improve things.\n\nBody.\n\nChange-Id: I1234\nReviewed-by: Gopher <gopher@golang.org>\n"
+	change, footers, err := ParseLocalCommitMessage("go", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixed, _ := (*Config)(nil).Fix(change)
+	want := "net/http: improve things\n\nBody.\n\nChange-Id: I1234\nReviewed-by: Gopher <gopher@golang.org>\n"
+	if got := fixed.Message(footers); got != want {
+		t.Errorf("fixed message = %q; want %q", got, want)
+	}
+}
+
 // commitMessage helps us create valid commit messages while testing.
 func commitMessage(title, body, footers string) string {
 	return title + "\n\n" + body + "\n\n" + footers
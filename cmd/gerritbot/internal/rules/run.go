@@ -28,15 +28,53 @@ type Change struct {
 	// spot check for something that looks like test files in the changed file list.
 }
 
+// String returns the commit message title and body of change, without footers.
+func (change Change) String() string {
+	if change.Body == "" {
+		return change.Title + "\n"
+	}
+	return change.Title + "\n\n" + change.Body + "\n"
+}
+
+// Message returns the commit message of change, with the footers,
+// as returned by ParseLocalCommitMessage, appended.
+func (change Change) Message(footers string) string {
+	if footers == "" {
+		return change.String()
+	}
+	return change.String() + "\n" + footers + "\n"
+}
+
+// ParseCommitMessage parses the commit message of a Gerrit CL, which
+// must have a title, a blank line, and footers such as Change-Id.
 func ParseCommitMessage(repo string, text string) (Change, error) {
+	change, _, err := parseCommitMessage(repo, text, true)
+	return change, err
+}
+
+// ParseLocalCommitMessage is like ParseCommitMessage, but for a local
+// commit that might not have been sent to Gerrit yet, so the body
+// and footers are optional. It also returns the footer lines, if any,
+// so that they can be kept when the message is rewritten.
+func ParseLocalCommitMessage(repo string, text string) (change Change, footers string, err error) {
+	return parseCommitMessage(repo, text, false)
+}
+
+func parseCommitMessage(repo string, text string, fromGerrit bool) (Change, string, error) {
 	change := Change{Repo: repo}
 	lines := splitLines(text)
+	if !fromGerrit {
+		// Pad a title-only message so it has the same shape as one from Gerrit.
+		for len(lines) < 3 {
+			lines = append(lines, "")
+		}
+	}
 	if len(lines) < 3 {
-		return Change{}, fmt.Errorf("rules: ParseCommitMessage: short commit message: %q", text)
+		return Change{}, "", fmt.Errorf("rules: ParseCommitMessage: short commit message: %q", text)
 	}
 	change.Title = lines[0]
 	if lines[1] != "" {
-		return Change{}, fmt.Errorf("rules: ParseCommitMessage: second line is not blank in commit message: %q", text)
+		return Change{}, "", fmt.Errorf("rules: ParseCommitMessage: second line is not blank in commit message: %q", text)
 	}
 
 	// Find the body.
@@ -62,14 +100,24 @@ func ParseCommitMessage(repo string, text string) (Change, error) {
 			body = body[:i]
 			break
 		}
-		return Change{}, fmt.Errorf("rules: ParseCommitMessage: found non-footer line at end of commit message. line: %q, commit message: %q", body[i], text)
+		if !fromGerrit && !sawFooter {
+			break // The whole remainder is the body.
+		}
+		return Change{}, "", fmt.Errorf("rules: ParseCommitMessage: found non-footer line at end of commit message. line: %q, commit message: %q", body[i], text)
 	}
-	if !sawFooter {
-		return Change{}, fmt.Errorf("rules: ParseCommitMessage: did not find any footers preceded by blank line for commit message: %q", text)
+	if !sawFooter && fromGerrit {
+		return Change{}, "", fmt.Errorf("rules: ParseCommitMessage: did not find any footers preceded by blank line for commit message: %q", text)
 	}
 	change.Body = strings.Join(body, "\n")
+	if !fromGerrit {
+		change.Body = strings.TrimRight(change.Body, "\n")
+	}
+	var footers string
+	if sawFooter {
+		footers = strings.Trim(strings.Join(lines[2+len(body):], "\n"), "\n")
+	}
 
-	return change, nil
+	return change, footers, nil
 }
 
 // Result contains the result of a single rule check against a Change.
@@ -77,21 +125,31 @@ type Result struct {
 	Name    string
 	Finding string
 	Note    string
+
+	// Fixable reports whether Config.Fix can address the finding.
+	Fixable bool
 }
 
-// Check runs the defined rules against one Change.
+// Check runs the defined rules against one Change, using the built-in settings.
 func Check(change Change) (results []Result) {
+	return (*Config)(nil).Check(change)
+}
+
+// Check runs the defined rules against one Change, using the settings in c.
+func (c *Config) Check(change Change) (results []Result) {
+	cfg := c.forRepo(change.Repo)
 	for _, group := range ruleGroups {
 		for _, rule := range group {
-			if slices.Contains(rule.skip, change.Repo) || len(rule.only) > 0 && !slices.Contains(rule.only, change.Repo) {
+			if !rule.enabled(change.Repo, cfg) {
 				continue
 			}
-			finding, advice := rule.f(change)
+			finding, advice := rule.f(change, cfg)
 			if finding != "" {
 				results = append(results, Result{
 					Name:    rule.name,
 					Finding: finding,
 					Note:    advice,
+					Fixable: rule.fix != nil,
 				})
 				break // Only report the first finding per rule group.
 			}
@@ -100,6 +158,47 @@ func Check(change Change) (results []Result) {
 	return results
 }
 
+// Fix returns change with fixes applied for the findings that Check
+// would report, and reports whether any fix modified it.
+// Findings without a fix are left for the author to address.
+func (c *Config) Fix(change Change) (fixed Change, ok bool) {
+	cfg := c.forRepo(change.Repo)
+	fixed = change
+	// Fixing one finding can uncover another in the same rule group,
+	// so repeat until there is nothing left to fix.
+	for i := 0; i < 5; i++ {
+		changed := false
+		for _, group := range ruleGroups {
+			for _, rule := range group {
+				if !rule.enabled(fixed.Repo, cfg) {
+					continue
+				}
+				if finding, _ := rule.f(fixed, cfg); finding == "" {
+					continue
+				}
+				if rule.fix != nil {
+					if f := rule.fix(fixed, cfg); f != fixed {
+						fixed, changed = f, true
+					}
+				}
+				break // Only the first finding per rule group is reported.
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return fixed, fixed != change
+}
+
+// enabled reports whether r should run for repo with the settings in cfg.
+func (r rule) enabled(repo string, cfg *RepoConfig) bool {
+	if slices.Contains(r.skip, repo) || len(r.only) > 0 && !slices.Contains(r.only, repo) {
+		return false
+	}
+	return !slices.Contains(cfg.Disable, r.name)
+}
+
 // FormatResults returns a string ready to be placed in a CL comment,
 // formatted as simple markdown.
 func FormatResults(results []Result) string {
@@ -120,6 +219,35 @@ func FormatResults(results []Result) string {
 	return b.String()
 }
 
+// FormatFix returns a string ready to be placed in a PR or CL comment,
+// formatted as simple markdown, that suggests the title and body of fixed
+// as replacements for those of orig. It returns the empty string if
+// nothing was fixed.
+func FormatFix(orig, fixed Change) string {
+	if orig == fixed {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Some problems in the commit message can be fixed mechanically.")
+	if orig.Title != fixed.Title {
+		fmt.Fprintf(&b, " Suggested title:\n\n%s", codeBlock(fixed.Title))
+	}
+	if orig.Body != fixed.Body {
+		fmt.Fprintf(&b, "\nSuggested description:\n\n%s", codeBlock(fixed.Body))
+	}
+	return b.String()
+}
+
+// codeBlock returns s as a fenced markdown code block,
+// using a fence longer than any run of backticks in s.
+func codeBlock(s string) string {
+	fence := "```"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	return fence + "\n" + s + "\n" + fence + "\n"
+}
+
 // formatAdvice returns a deduplicated string containing all the advice in results.
 func formatAdvice(results []Result) string {
 	var s []string
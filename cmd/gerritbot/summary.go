@@ -0,0 +1,275 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v48/github"
+	"golang.org/x/build/gerrit"
+	"golang.org/x/build/maintner"
+)
+
+// reviewSummaryMarker identifies the review summary comment on a PR.
+// It is invisible in the rendered comment.
+const reviewSummaryMarker = "<!-- gerritbot:review-summary -->"
+
+// A reviewSummaryComment is a review summary comment posted on a PR.
+type reviewSummaryComment struct {
+	id   int64  // GitHub comment ID
+	body string // last body posted
+	meta string // hash of the Gerrit meta commit the body was built from
+}
+
+// A reviewSummary is the state of a Gerrit review, as mirrored to GitHub.
+type reviewSummary struct {
+	project    string
+	number     int32
+	patchSet   int32
+	votes      []labelVotes
+	trybots    trybotState
+	unresolved int // number of unresolved comment threads
+}
+
+// labelVotes are the votes on a single Gerrit label.
+type labelVotes struct {
+	label string
+	votes []vote // sorted by descending value, then name
+}
+
+type vote struct {
+	name  string
+	value int8
+}
+
+type trybotState int
+
+const (
+	trybotsNotRun trybotState = iota
+	trybotsRunning
+	trybotsPassed
+	trybotsFailed
+)
+
+func (s trybotState) String() string {
+	switch s {
+	case trybotsRunning:
+		return "⏳ running"
+	case trybotsPassed:
+		return "✅ passed"
+	case trybotsFailed:
+		return "❌ failed"
+	}
+	return "not run"
+}
+
+// trybotResultLabels are the Gerrit labels that hold trybot results,
+// in order of preference.
+var trybotResultLabels = []string{"LUCI-TryBot-Result", "TryBot-Result"}
+
+// trybotStateFromVotes reports the trybot outcome implied by the
+// label votes on a CL, as returned by maintner.GerritMeta.LabelVotes.
+func trybotStateFromVotes(votes map[string]map[string]int8) trybotState {
+	for _, label := range trybotResultLabels {
+		var passed, failed bool
+		for _, v := range votes[label] {
+			switch {
+			case v < 0:
+				failed = true
+			case v > 0:
+				passed = true
+			}
+		}
+		if failed {
+			return trybotsFailed
+		}
+		if passed {
+			return trybotsPassed
+		}
+	}
+	for _, label := range []string{"Commit-Queue", "Run-TryBot"} {
+		for _, v := range votes[label] {
+			if v > 0 {
+				return trybotsRunning
+			}
+		}
+	}
+	return trybotsNotRun
+}
+
+// countUnresolvedThreads returns the number of unresolved comment threads
+// in comments, as returned by gerrit.Client.ListChangeComments.
+// A thread is unresolved if its last comment is.
+func countUnresolvedThreads(comments map[string][]gerrit.CommentInfo) int {
+	replied := make(map[string]bool)
+	for _, cs := range comments {
+		for _, c := range cs {
+			if c.InReplyTo != "" {
+				replied[c.InReplyTo] = true
+			}
+		}
+	}
+	var n int
+	for _, cs := range comments {
+		for _, c := range cs {
+			if !replied[c.ID] && c.Unresolved != nil && *c.Unresolved {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// reviewSummaryForCL builds the review summary for cl.
+// b.RWMutex must be Lock'ed.
+func (b *bot) reviewSummaryForCL(ctx context.Context, cl *maintner.GerritCL) (*reviewSummary, error) {
+	votes, err := cl.Meta.LabelVotes()
+	if err != nil {
+		return nil, fmt.Errorf("LabelVotes: %v", err)
+	}
+	s := &reviewSummary{
+		project:  cl.Project.Project(),
+		number:   cl.Number,
+		patchSet: cl.Version,
+		trybots:  trybotStateFromVotes(votes),
+	}
+	for label, byVoter := range votes {
+		lv := labelVotes{label: label}
+		for email, value := range byVoter {
+			if value == 0 {
+				continue
+			}
+			name := email
+			if id, err := strconv.Atoi(strings.Split(email, "@")[0]); err == nil {
+				if n, err := b.gerritAccountName(ctx, id); err == nil && n != "" {
+					name = n
+				}
+			}
+			lv.votes = append(lv.votes, vote{name: name, value: value})
+		}
+		if len(lv.votes) == 0 {
+			continue
+		}
+		sort.Slice(lv.votes, func(i, j int) bool {
+			if lv.votes[i].value != lv.votes[j].value {
+				return lv.votes[i].value > lv.votes[j].value
+			}
+			return lv.votes[i].name < lv.votes[j].name
+		})
+		s.votes = append(s.votes, lv)
+	}
+	sort.Slice(s.votes, func(i, j int) bool { return s.votes[i].label < s.votes[j].label })
+
+	comments, err := b.gerritClient.ListChangeComments(ctx, cl.ChangeID())
+	if err != nil {
+		return nil, fmt.Errorf("b.gerritClient.ListChangeComments: %v", err)
+	}
+	s.unresolved = countUnresolvedThreads(comments)
+	return s, nil
+}
+
+// String returns the body of the GitHub comment for s.
+func (s *reviewSummary) String() string {
+	var buf strings.Builder
+	fmt.Fprintln(&buf, reviewSummaryMarker)
+	fmt.Fprintf(&buf, "**Gerrit review status for [golang.org/cl/%d](https://go-review.googlesource.com/c/%s/+/%d)** (patch set %d)\n\n",
+		s.number, s.project, s.number, s.patchSet)
+	if len(s.votes) == 0 {
+		fmt.Fprintf(&buf, "No votes yet.\n")
+	} else {
+		fmt.Fprintf(&buf, "| Label | Votes |\n| --- | --- |\n")
+		for _, lv := range s.votes {
+			var vs []string
+			for _, v := range lv.votes {
+				vs = append(vs, fmt.Sprintf("%+d %s", v.value, v.name))
+			}
+			fmt.Fprintf(&buf, "| %s | %s |\n", lv.label, strings.Join(vs, ", "))
+		}
+	}
+	fmt.Fprintf(&buf, "\nTryBots: %v\n", s.trybots)
+	fmt.Fprintf(&buf, "Unresolved comments: %d\n", s.unresolved)
+	fmt.Fprintf(&buf, "\n---\n_GerritBot updates this comment as the review progresses. Please reply on Gerrit, not here._")
+	return buf.String()
+}
+
+// syncReviewSummaryToGitHub posts or updates a single comment on pr
+// summarizing the review of cl: votes, trybot outcome, and the number
+// of unresolved comments.
+// b.RWMutex must be Lock'ed.
+func (b *bot) syncReviewSummaryToGitHub(ctx context.Context, pr *github.PullRequest, cl *maintner.GerritCL) error {
+	shortLink := prShortLink(pr)
+	meta := cl.Meta.Commit.Hash.String()
+	prev := b.reviewSummaries[shortLink]
+	if prev != nil && prev.meta == meta {
+		return nil
+	}
+	s, err := b.reviewSummaryForCL(ctx, cl)
+	if err != nil {
+		return err
+	}
+	body := s.String()
+
+	repo := pr.GetBase().GetRepo()
+	org, name := repo.GetOwner().GetLogin(), repo.GetName()
+	if prev == nil {
+		prev = b.findReviewSummaryComment(org, name, pr.GetNumber())
+	}
+	if prev != nil && prev.body == body {
+		prev.meta = meta
+		b.reviewSummaries[shortLink] = prev
+		return nil
+	}
+	if *dryRun {
+		log.Printf("[dry run] would update review summary on %v: %q", shortLink, body)
+		return nil
+	}
+	if prev == nil {
+		ic, resp, err := b.githubClient.Issues.CreateComment(ctx, org, name, pr.GetNumber(), &github.IssueComment{
+			Body: github.String(body),
+		})
+		if err != nil {
+			return err
+		}
+		logGitHubRateLimits(resp)
+		b.reviewSummaries[shortLink] = &reviewSummaryComment{id: ic.GetID(), body: body, meta: meta}
+		return nil
+	}
+	_, resp, err := b.githubClient.Issues.EditComment(ctx, org, name, prev.id, &github.IssueComment{
+		Body: github.String(body),
+	})
+	if err != nil {
+		return err
+	}
+	logGitHubRateLimits(resp)
+	b.reviewSummaries[shortLink] = &reviewSummaryComment{id: prev.id, body: body, meta: meta}
+	return nil
+}
+
+// findReviewSummaryComment returns the review summary comment on the
+// given PR from the maintner corpus, or nil if there isn't one.
+// b.RWMutex must be Lock'ed.
+func (b *bot) findReviewSummaryComment(org, repo string, number int) *reviewSummaryComment {
+	gr := b.corpus.GitHub().Repo(org, repo)
+	if gr == nil {
+		return nil
+	}
+	gi := gr.Issue(int32(number))
+	if gi == nil {
+		return nil
+	}
+	var found *reviewSummaryComment
+	gi.ForeachComment(func(c *maintner.GitHubComment) error {
+		if strings.HasPrefix(c.Body, reviewSummaryMarker) {
+			found = &reviewSummaryComment{id: c.ID, body: c.Body}
+		}
+		return nil
+	})
+	return found
+}
@@ -7,6 +7,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -96,6 +97,7 @@ func create(args []string) error {
 	fs.Usage = func() {
 		log := usageLogger
 		log.Print("create usage: gomote create [create-opts] <type>")
+		log.Print("       gomote create [create-opts] -from-snapshot <snapshot-id> [type]")
 		log.Print()
 		log.Print("If there's a valid group specified, new instances are")
 		log.Print("automatically added to the group. If the group in")
@@ -151,6 +153,7 @@ func create(args []string) error {
 	fs.BoolVar(&cfg.setup, "setup", false, "set up the instance by pushing GOROOT and building the Go toolchain")
 	fs.StringVar(&cfg.newGroup, "new-group", "", "also create a new group and add the new instances to it")
 	fs.BoolVar(&cfg.useGolangbuild, "use-golangbuild", true, "disable the installation of build dependencies installed by golangbuild")
+	fs.StringVar(&cfg.fromSnapshot, "from-snapshot", "", "restore the snapshot with this ID, saved by 'gomote snapshot', onto the new instances; the type defaults to the snapshot's")
 
 	fs.Parse(args)
 	if listBuilders {
@@ -163,11 +166,24 @@ func create(args []string) error {
 		}
 		return nil
 	}
-	if fs.NArg() != 1 {
+	ctx := context.Background()
+	var builderType string
+	switch {
+	case fs.NArg() == 1:
+		builderType = fs.Arg(0)
+	case fs.NArg() == 0 && cfg.fromSnapshot != "":
+		var err error
+		builderType, err = snapshotBuilderType(ctx, cfg.fromSnapshot)
+		if err != nil {
+			return err
+		}
+	default:
 		fs.Usage()
 	}
-	builderType := fs.Arg(0)
-	_, _, err := createInstances(context.Background(), builderType, &cfg)
+	if cfg.setup && cfg.fromSnapshot != "" {
+		return errors.New("-setup and -from-snapshot can't be used together")
+	}
+	_, _, err := createInstances(ctx, builderType, &cfg)
 	return err
 }
 
@@ -177,6 +193,37 @@ type createConfig struct {
 	setup          bool
 	newGroup       string
 	useGolangbuild bool
+	fromSnapshot   string
+}
+
+// createInstance creates the nth instance of builderType requested
+// by a command, and returns its name.
+func createInstance(ctx context.Context, client protos.GomoteServiceClient, builderType string, n int, cfg *createConfig) (string, error) {
+	start := time.Now()
+	var exp []string
+	if !cfg.useGolangbuild {
+		exp = append(exp, "disable-golang-build")
+	}
+	stream, err := client.CreateInstance(ctx, &protos.CreateInstanceRequest{BuilderType: builderType, ExperimentOption: exp})
+	if err != nil {
+		return "", fmt.Errorf("failed to create buildlet: %w", err)
+	}
+	var inst string
+	for {
+		update, err := stream.Recv()
+		switch {
+		case err == io.EOF:
+			return inst, nil
+		case err != nil:
+			return "", fmt.Errorf("failed to create buildlet (%d): %w", n, err)
+		case update.GetStatus() == protos.CreateInstanceResponse_QUEUED && cfg.printStatus:
+			log.Printf("%s (%d) queued after %v, because %s; %d requests ahead of you\n", builderType, n, time.Since(start).Round(time.Second), update.GetQueueReason(), update.GetWaitersAhead())
+		case update.GetStatus() != protos.CreateInstanceResponse_COMPLETE && cfg.printStatus:
+			log.Printf("still creating %s (%d) after %v; %d requests ahead of you\n", builderType, n, time.Since(start).Round(time.Second), update.GetWaitersAhead())
+		case update.GetStatus() == protos.CreateInstanceResponse_COMPLETE:
+			inst = update.GetInstance().GetGomoteId()
+		}
+	}
 }
 
 func createInstances(ctx context.Context, builderType string, cfg *createConfig) ([]string, *groupData, error) {
@@ -205,29 +252,9 @@ func createInstances(ctx context.Context, builderType string, cfg *createConfig)
 	for i := 0; i < cfg.count; i++ {
 		i := i
 		eg.Go(func() error {
-			start := time.Now()
-			var exp []string
-			if !cfg.useGolangbuild {
-				exp = append(exp, "disable-golang-build")
-			}
-			stream, err := client.CreateInstance(ctx, &protos.CreateInstanceRequest{BuilderType: builderType, ExperimentOption: exp})
+			inst, err := createInstance(ctx, client, builderType, i+1, cfg)
 			if err != nil {
-				return fmt.Errorf("failed to create buildlet: %w", err)
-			}
-			var inst string
-		updateLoop:
-			for {
-				update, err := stream.Recv()
-				switch {
-				case err == io.EOF:
-					break updateLoop
-				case err != nil:
-					return fmt.Errorf("failed to create buildlet (%d): %w", i+1, err)
-				case update.GetStatus() != protos.CreateInstanceResponse_COMPLETE && cfg.printStatus:
-					log.Printf("still creating %s (%d) after %v; %d requests ahead of you\n", builderType, i+1, time.Since(start).Round(time.Second), update.GetWaitersAhead())
-				case update.GetStatus() == protos.CreateInstanceResponse_COMPLETE:
-					inst = update.GetInstance().GetGomoteId()
-				}
+				return err
 			}
 			fmt.Println(inst)
 
@@ -240,6 +267,13 @@ func createInstances(ctx context.Context, builderType string, cfg *createConfig)
 				group.Instances = append(group.Instances, inst)
 				groupMu.Unlock()
 			}
+			if cfg.fromSnapshot != "" {
+				log.Printf("Restoring snapshot %s onto %q...\n", cfg.fromSnapshot, inst)
+				if _, err := client.RestoreSnapshot(ctx, &protos.RestoreSnapshotRequest{GomoteId: inst, SnapshotId: cfg.fromSnapshot}); err != nil {
+					return fmt.Errorf("unable to restore snapshot %s onto %s: %w", cfg.fromSnapshot, inst, err)
+				}
+				return nil
+			}
 			if !cfg.setup {
 				return nil
 			}
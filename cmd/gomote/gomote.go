@@ -38,7 +38,11 @@ To list the subcommands, run "gomote" without arguments:
 	  rdp        RDP (Remote Desktop Protocol) to a Windows buildlet
 	  repro      reproduce a build by LUCI build ID
 	  run        run a command on a buildlet
+	  snapshot   save a buildlet's work directory for restoring with create -from-snapshot
 	  ssh        ssh to a buildlet
+	  tail       print the end of a file on a buildlet, or follow it
+	  up         create, provision, and run commands on the instances in a session file
+	  usage      list the instance-hours used by your buildlets
 
 To list all the builder types available, run "create" with no arguments:
 
@@ -63,6 +67,9 @@ The "gomote run" command has many of its own flags:
 	  -e value
 	        Environment variable KEY=value. The -e flag may be repeated
 	        multiple times to add multiple things to the environment.
+	  -failfast
+	        When running on a group, stop the command on every instance
+	        once it fails on one.
 	  -path string
 	        Comma-separated list of ExecOpts.Path elements. The special
 	        string 'EMPTY' means to run without any $PATH. The empty
@@ -70,8 +77,51 @@ The "gomote run" command has many of its own flags:
 	        following expansions apply: the string '$PATH' expands to
 	        the current PATH element(s), the substring '$WORKDIR'
 	        expands to the buildlet's temp workdir.
+	  -sandbox string
+	        Run the command in a sandbox with a private /tmp and the
+	        given network: 'host', 'none', 'loopback', or 'allowlist'.
+	        Linux instances only.
+	  -sandbox-allow string
+	        Comma-separated list of hosts, as host or host:port, that a
+	        sandboxed command may connect to through an HTTP proxy. A
+	        host like *.example.com matches subdomains. Implies
+	        -sandbox=allowlist.
+	  -sandbox-ro
+	        Make the work directory read-only for the command, except
+	        for GOCACHE. Implies -sandbox=host if -sandbox isn't set.
+	  -stdin
+	        Stream standard input to the command, keep its standard
+	        error separate, and forward interrupts to it. Requires a
+	        single instance.
+	  -stats
+	        Print the command's wall time, CPU time, peak memory use,
+	        and bytes written once it finishes, if the instance reports
+	        them.
+	  -stress int
+	        Run the command repeatedly on every instance until it has
+	        failed this many times in total, saving the output of each
+	        failure.
 	  -system
 	        run inside the system, and not inside the workdir; this is implicit if cmd starts with '/'
+	  -tty
+	        Like -stdin, but run the command in a pseudo-terminal. Not
+	        supported on Windows or Plan 9 instances.
+
+With -stdin or -tty, gomote exits with the command's exit code.
+For example, to get an interactive shell:
+
+	$ gomote run -tty user-username-linux-amd64-0 /bin/bash
+
+To check that tests don't write to the source tree or use the network
+other than the module proxy:
+
+	$ gomote run -sandbox-ro -sandbox-allow proxy.golang.org user-username-linux-amd64-0 go/bin/go test ./...
+
+To watch a log file being written on an instance, and, separately,
+the files being changed in a directory:
+
+	$ gomote tail -f user-username-linux-amd64-0 go/src/test.log
+	$ gomote tail -watch user-username-linux-amd64-0 go/src
 
 # Debugging buildlets directly
 
@@ -115,12 +165,74 @@ As this example demonstrates, groups are useful even if the group
 contains only a single instance: it can dramatically shorten most gomote
 commands.
 
+# Sessions
+
+The up command does the work of create, push, put, puttar, and run,
+for a set of instances described by a YAML session file:
+
+	group: repro
+	instances:
+	  - builder: gotip-linux-amd64
+	    count: 2
+	  - builder: gotip-windows-amd64
+	push: true            # push $GOROOT, or 'goroot: /path/to/goroot'
+	bootstrap: true       # put the bootstrap toolchain in place
+	files:                # put local files
+	  - src: testdata/input.txt
+	    dst: go/src/runtime/testdata/input.txt
+	    mode: "0644"
+	tars:                 # extract a tar.gz from a url, a local src, or a Go rev
+	  - url: https://example.com/data.tar.gz
+	    dir: data
+	setup:                # run commands in order, optionally on some builders only
+	  - cmd: go/src/make.bash
+	    builders: ["*-linux-*"]
+	  - cmd: go/src/make.bat
+	    builders: ["*-windows-*"]
+	run:                  # then run the command whose results matter
+	  cmd: go/bin/go
+	  args: [test, -run=TestFlaky, -count=10, runtime]
+	  env: [GODEBUG=gctrace=1]
+
+Running
+
+	$ gomote up -f repro.yaml
+
+creates the instances in the group (named after the file if the group
+isn't set), reusing any of the right builder types already in it, and
+then provisions them and runs the commands on all of them in parallel.
+Each instance's output goes to its own file. Once all are done, up
+prints a summary of which instances failed, at which step, and how,
+and writes it to summary.json next to the output. With -destroy, it
+then destroys the instances and the group.
+
+# Snapshots
+
+Setting up an instance can take many minutes, and that work is lost when
+the instance expires. The snapshot command saves an instance's work
+directory, along with its builder type and the Go revision in $GOROOT,
+and create restores it onto new instances:
+
+	$ gomote snapshot -m "after make.bash" user-username-linux-amd64-0
+	0b0e2f2c-9d2e-4f37-8a4e-5f0b6f1d2a3c
+	$ gomote create -from-snapshot 0b0e2f2c-9d2e-4f37-8a4e-5f0b6f1d2a3c
+
+The gomote server keeps each snapshot for 7 days by default, which the
+-ttl flag can change up to 30 days, and limits how many snapshots each
+user may have. "gomote snapshot -list" lists your snapshots, and
+"gomote snapshot -rm" deletes one.
+
 # Tips and tricks
 
   - The create command accepts the -setup flag which also pushes a GOROOT
     and runs the appropriate equivalent of "make.bash" for the instance.
   - The create command accepts the -count flag for creating several
     instances at once.
+  - The gomote server may limit how many instances each user, and each
+    builder type, may have at once. Requests beyond the limits wait in a
+    queue, where users with fewer instances go first, and create prints
+    why it's waiting. The usage command lists the instance-hours you've
+    used.
   - The run command accepts the -collect flag for automatically writing
     the output from the command to a file in $PWD, as well as a copy of
     the full file tree from the instance. This command is useful for
@@ -133,6 +245,15 @@ commands.
   - The run command always streams output to a temporary file regardless
     of any additional flags to avoid losing output due to terminal
     scrollback. It always prints the location of the file.
+  - When the run command runs on more than one instance, it prints a
+    table of each instance's exit status and run time, and marks the
+    instances whose output differs from the most common output, once
+    timings and addresses are ignored. It writes the differences to an
+    <instance>.diff file next to the output.
+  - The run command accepts the -failfast flag for stopping the command
+    on every instance as soon as it fails on one, and the -stress flag
+    for running the command on every instance until it has failed some
+    number of times in total.
 
 Using some of these tricks, it's straightforward to hammer at some test
 to reproduce a rare failure, like so:
@@ -141,6 +262,10 @@ to reproduce a rare failure, like so:
 	$ GOROOT=/path/to/goroot gomote create -setup -count=10 linux-amd64
 	$ gomote run -until='unexpected return pc' -collect go/bin/go run -run="MyFlakyTest" -count=100 runtime
 
+or to collect the output of the first 5 failures across the group:
+
+	$ gomote run -stress=5 go/bin/go test -run="MyFlakyTest" -count=100 runtime
+
 # Legacy Infrastructure
 
 Setting the GOMOTEDISABLELUCI environmental variable equal to true will set the gomote client to communicate with
@@ -229,7 +354,11 @@ func registerCommands() {
 	registerCommand("rdp", "Unimplimented: RDP (Remote Desktop Protocol) to a Windows buildlet", rdp)
 	registerCommand("rm", "delete files or directories", rm)
 	registerCommand("run", "run a command on a buildlet", run)
+	registerCommand("snapshot", "save a buildlet's work directory for restoring with create -from-snapshot", snapshot)
 	registerCommand("ssh", "ssh to a buildlet", ssh)
+	registerCommand("tail", "print the end of a file on a buildlet, or follow it", tail)
+	registerCommand("up", "create, provision, and run commands on the instances in a session file", up)
+	registerCommand("usage", "list the instance-hours used by your buildlets", listUsage)
 }
 
 var (
@@ -5,10 +5,8 @@
 package main
 
 import (
-	"archive/tar"
 	"bufio"
 	"bytes"
-	"compress/gzip"
 	"context"
 	"crypto/sha1"
 	"errors"
@@ -225,25 +223,19 @@ func doPush(ctx context.Context, name, goroot string, dryRun, detailedProgress b
 			toDel = append(toDel, rel)
 		}
 	}
+	sort.Strings(toDel)
 	if len(toDel) > 0 {
-		withGo := make([]string, len(toDel)) // with the "go/" prefix
-		for i, v := range toDel {
-			withGo[i] = "go/" + v
-		}
-		sort.Strings(withGo)
 		if dryRun {
-			logf("(Dry-run) Would have deleted remote files: %q", withGo)
+			logf("(Dry-run) Would have deleted remote files in go/: %q", toDel)
 		} else {
-			logf("Deleting remote files: %q", withGo)
-			if _, err := client.RemoveFiles(ctx, &protos.RemoveFilesRequest{
-				GomoteId: name,
-				Paths:    withGo,
-			}); err != nil {
-				return fmt.Errorf("failed to delete remote unwanted files: %w", err)
-			}
+			logf("Deleting remote files in go/: %q", toDel)
 		}
 	}
-	var toSend []string
+
+	// Sync a manifest of all the local files: the instance already has
+	// most of their contents, so only the rest need to be uploaded.
+	m := &buildlet.SyncManifest{Delete: toDel}
+	changed := 0
 	notHave := 0
 	const maxNotHavePrint = 5
 	for rel, inf := range local {
@@ -256,17 +248,18 @@ func doPush(ctx context.Context, name, goroot string, dryRun, detailedProgress b
 			}
 			continue
 		}
+		m.Files = append(m.Files, buildlet.SyncFile{Path: rel, Digest: inf.sha1, Mode: inf.fi.Mode().Perm()})
 		rem, ok := remote[rel]
 		if !ok {
 			if notHave++; notHave <= maxNotHavePrint {
 				logf("Remote doesn't have %q", rel)
 			}
-			toSend = append(toSend, rel)
+			changed++
 			continue
 		}
 		if rem.Digest() != inf.sha1 {
 			logf("Remote's %s digest is %q; want %q", rel, rem.Digest(), inf.sha1)
-			toSend = append(toSend, rel)
+			changed++
 		}
 	}
 	if notHave > maxNotHavePrint {
@@ -275,37 +268,81 @@ func doPush(ctx context.Context, name, goroot string, dryRun, detailedProgress b
 	_, localHasVersion := local["VERSION"]
 	if _, remoteHasVersion := remote["VERSION"]; !remoteHasVersion && !localHasVersion {
 		logf("Remote lacks a VERSION file; sending a fake one")
-		toSend = append(toSend, "VERSION")
+		m.Files = append(m.Files, buildlet.SyncFile{
+			Path:   "VERSION",
+			Digest: fmt.Sprintf("%x", sha1.Sum([]byte(fakeVersion))),
+			Mode:   0644,
+		})
+		changed++
 	}
-	if len(toSend) > 0 {
-		sort.Strings(toSend)
-		tgz, err := generateDeltaTgz(goroot, toSend)
-		if err != nil {
-			return err
-		}
-		logf("Uploading %d new/changed files; %d byte .tar.gz", len(toSend), tgz.Len())
-		if dryRun {
-			logf("(Dry-run mode; not doing anything.")
-			return nil
-		}
-		resp, err := client.UploadFile(ctx, &protos.UploadFileRequest{})
+	if changed == 0 && len(toDel) == 0 {
+		return nil
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+	if dryRun {
+		logf("(Dry-run) Would have synced %d new/changed files; not doing anything.", changed)
+		return nil
+	}
+
+	req := &protos.SyncFilesRequest{
+		GomoteId:  name,
+		Directory: "go",
+		Delete:    m.Delete,
+	}
+	for _, f := range m.Files {
+		req.Files = append(req.Files, &protos.SyncFile{Path: f.Path, Digest: f.Digest, Mode: uint32(f.Mode)})
+	}
+	resp, err := client.SyncFiles(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed syncing files to buildlet: %w", err)
+	}
+	if missing := resp.GetMissingDigests(); len(missing) > 0 {
+		upload, err := client.UploadFile(ctx, &protos.UploadFileRequest{})
 		if err != nil {
 			return fmt.Errorf("unable to request credentials for a file upload: %w", err)
 		}
-		if err := uploadToGCS(ctx, resp.GetFields(), tgz, resp.GetObjectName(), resp.GetUrl()); err != nil {
+		// Write the .tar.gz file as it's uploaded, rather than holding it in memory.
+		pr, pw := io.Pipe()
+		tgz := &countingWriter{w: pw}
+		go func() {
+			_, err := buildlet.WriteSyncTGZ(tgz, m, missing, func(f buildlet.SyncFile) (io.ReadCloser, error) {
+				return openPushFile(goroot, f.Path)
+			})
+			pw.CloseWithError(err)
+		}()
+		err = uploadToGCS(ctx, upload.GetFields(), pr, upload.GetObjectName(), upload.GetUrl())
+		pr.Close()
+		if err != nil {
 			return fmt.Errorf("unable to upload file to GCS: %w", err)
 		}
-		if _, err := client.WriteTGZFromURL(ctx, &protos.WriteTGZFromURLRequest{
-			GomoteId:  name,
-			Url:       fmt.Sprintf("%s%s", resp.GetUrl(), resp.GetObjectName()),
-			Directory: "go",
-		}); err != nil {
-			return fmt.Errorf("failed writing tarball to buildlet: %w", err)
+		logf("Uploaded %d new file contents for %d new/changed files; %d byte .tar.gz", len(missing), changed, tgz.n)
+		req.BlobsUrl = upload.GetUrl() + upload.GetObjectName()
+		resp, err = client.SyncFiles(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed syncing files to buildlet: %w", err)
 		}
 	}
+	logf("Wrote %d files and deleted %d", resp.GetWritten(), resp.GetDeleted())
 	return nil
 }
 
+// fakeVersion is the contents of the VERSION file pushed
+// when neither the local GOROOT nor the instance has one.
+//
+// TODO(bradfitz): a dummy VERSION file's contents to make things
+// happy. Notably it starts with "devel ". Do we care about it
+// being accurate beyond that?
+const fakeVersion = "devel gomote.XXXXX"
+
+// openPushFile opens the file at the forward-slash separated path
+// file in goroot to push it, or a fake VERSION file if there's none.
+func openPushFile(goroot, file string) (io.ReadCloser, error) {
+	if file == "VERSION" && !localFileExists(filepath.Join(goroot, file)) {
+		return io.NopCloser(strings.NewReader(fakeVersion)), nil
+	}
+	return os.Open(filepath.Join(goroot, filepath.FromSlash(file)))
+}
+
 func isGoToolDistGenerated(path string) bool {
 	switch path {
 	case "src/cmd/cgo/zdefaultcc.go",
@@ -336,65 +373,6 @@ func isEditorBackup(path string) bool {
 	return false
 }
 
-// file is forward-slash separated
-func generateDeltaTgz(goroot string, files []string) (*bytes.Buffer, error) {
-	var buf bytes.Buffer
-	zw := gzip.NewWriter(&buf)
-	tw := tar.NewWriter(zw)
-	for _, file := range files {
-		// Special.
-		if file == "VERSION" && !localFileExists(filepath.Join(goroot, file)) {
-			// TODO(bradfitz): a dummy VERSION file's contents to make things
-			// happy. Notably it starts with "devel ". Do we care about it
-			// being accurate beyond that?
-			version := "devel gomote.XXXXX"
-			if err := tw.WriteHeader(&tar.Header{
-				Name: "VERSION",
-				Mode: 0644,
-				Size: int64(len(version)),
-			}); err != nil {
-				return nil, err
-			}
-			if _, err := io.WriteString(tw, version); err != nil {
-				return nil, err
-			}
-			continue
-		}
-		f, err := os.Open(filepath.Join(goroot, file))
-		if err != nil {
-			return nil, err
-		}
-		fi, err := f.Stat()
-		if err != nil {
-			f.Close()
-			return nil, err
-		}
-		header, err := tar.FileInfoHeader(fi, "")
-		if err != nil {
-			f.Close()
-			return nil, err
-		}
-		header.Name = file // forward slash
-		if err := tw.WriteHeader(header); err != nil {
-			f.Close()
-			return nil, err
-		}
-		if _, err := io.CopyN(tw, f, header.Size); err != nil {
-			f.Close()
-			return nil, fmt.Errorf("error copying contents of %s: %w", file, err)
-		}
-		f.Close()
-	}
-	if err := tw.Close(); err != nil {
-		return nil, err
-	}
-	if err := zw.Close(); err != nil {
-		return nil, err
-	}
-
-	return &buf, nil
-}
-
 func fileSHA1(path string) (string, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -469,3 +447,15 @@ func gitIgnored(goroot string, absToRel map[string]string) []string {
 	}
 	return ignored
 }
+
+// A countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
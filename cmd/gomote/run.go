@@ -17,7 +17,9 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
+	"golang.org/x/build/internal/diff"
 	"golang.org/x/build/internal/gomote/protos"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc/codes"
@@ -69,19 +71,47 @@ func run(args []string) error {
 	var untilPattern string
 	fs.StringVar(&untilPattern, "until", "", "Run command repeatedly until the output matches the provided regexp.")
 
+	var stdin bool
+	fs.BoolVar(&stdin, "stdin", false, "Stream standard input to the command, keep its standard error separate, and forward interrupts to it. Requires a single instance.")
+	var tty bool
+	fs.BoolVar(&tty, "tty", false, "Like -stdin, but run the command in a pseudo-terminal. Not supported on Windows or Plan 9 instances.")
+
+	var failFast bool
+	fs.BoolVar(&failFast, "failfast", false, "When running on a group, stop the command on every instance once it fails on one.")
+	var stress int
+	fs.IntVar(&stress, "stress", 0, "Run the command repeatedly on every instance until it has failed this many times in total, saving the output of each failure.")
+
+	var stats bool
+	fs.BoolVar(&stats, "stats", false, "Print the command's wall time, CPU time, peak memory use, and bytes written once it finishes, if the instance reports them.")
+
+	var sandboxNet, sandboxAllow string
+	var sandboxRO bool
+	fs.StringVar(&sandboxNet, "sandbox", "", "Run the command in a sandbox with a private /tmp and the given network: 'host', 'none', 'loopback', or 'allowlist'. Linux instances only.")
+	fs.StringVar(&sandboxAllow, "sandbox-allow", "", "Comma-separated list of hosts, as host or host:port, that a sandboxed command may connect to through an HTTP proxy. A host like *.example.com matches subdomains. Implies -sandbox=allowlist.")
+	fs.BoolVar(&sandboxRO, "sandbox-ro", false, "Make the work directory read-only for the command, except for GOCACHE. Implies -sandbox=host if -sandbox isn't set.")
+
 	fs.Parse(args)
 	if fs.NArg() == 0 {
 		fs.Usage()
 	}
+	sandbox, err := sandboxFlag(sandboxNet, sandboxAllow, sandboxRO)
+	if err != nil {
+		return err
+	}
 
 	var until *regexp.Regexp
-	var err error
 	if untilPattern != "" {
 		until, err = regexp.Compile(untilPattern)
 		if err != nil {
 			return fmt.Errorf("bad regexp %q for 'until': %w", untilPattern, err)
 		}
 	}
+	if stress < 0 {
+		return fmt.Errorf("invalid -stress %d; must be positive", stress)
+	}
+	if stress > 0 && until != nil {
+		return errors.New("-stress and -until can't be used together")
+	}
 
 	var cmd string
 	var cmdArgs []string
@@ -120,6 +150,33 @@ func run(args []string) error {
 		pathOpt = strings.Split(path, ",")
 	}
 
+	if stdin || tty {
+		if len(runSet) != 1 {
+			return errors.New("-stdin and -tty require a single instance, not a group")
+		}
+		if until != nil || collect || stats || failFast || stress > 0 {
+			return errors.New("-until, -collect, -stats, -failfast, and -stress can't be used with -stdin or -tty")
+		}
+		st, err := doStreamRun(ctx, runSet[0], cmd, cmdArgs, tty,
+			runDir(dir),
+			runBuilderEnv(builderEnv),
+			runEnv(env),
+			runPath(pathOpt),
+			runSystem(sys),
+			runDebug(debug),
+			runFirewall(firewall),
+			runSandbox(sandbox),
+		)
+		if err != nil {
+			return err
+		}
+		if code := exitCode(st); code != 0 {
+			log.Printf("Command %q failed on %q: %s\n", cmd, runSet[0], exitStatusString(st))
+			os.Exit(code)
+		}
+		return nil
+	}
+
 	// Create temporary directory for output.
 	// This is useful even if we don't have multiple gomotes running, since
 	// it's easy to accidentally lose the output.
@@ -136,11 +193,22 @@ func run(args []string) error {
 		}
 	}
 
-	var cmdsFailedMu sync.Mutex
-	var cmdsFailed []*cmdFailedError
+	var (
+		mu         sync.Mutex
+		cmdsFailed []*cmdFailedError
+		failures   int // across all instances
+	)
+	results := make([]*groupRunResult, len(runSet))
+	// Compare the outputs of single runs. The last of several
+	// stress runs may have been stopped part way through.
+	compared := len(runSet) > 1 && stress == 0
 	eg, ctx := errgroup.WithContext(context.Background())
-	for _, inst := range runSet {
-		inst := inst
+	// stop cancels the runs on the other instances, for -failfast and -stress.
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+	for i, inst := range runSet {
+		res := &groupRunResult{inst: inst}
+		results[i] = res
 		if len(runSet) > 1 {
 			// There's more than one instance running the command, so let's
 			// be explicit about that.
@@ -161,16 +229,23 @@ func run(args []string) error {
 			outputs := []io.Writer{outf}
 			// If this is the only command running, print to stdout too, for convenience and
 			// backwards compatibility.
-			if len(runSet) == 1 {
+			if len(runSet) == 1 && stress == 0 {
 				outputs = append(outputs, os.Stdout)
 			}
-			// Give ourselves the output too so that we can match against it.
+			// Give ourselves the output too so that we can match against it,
+			// and the end of it to compare with the other instances.
 			var outBuf bytes.Buffer
 			if until != nil {
 				outputs = append(outputs, &outBuf)
 			}
+			tail := newTailBuffer(maxComparedOutput)
+			if compared {
+				outputs = append(outputs, tail)
+			}
 			var ce *cmdFailedError
 			for {
+				var st *protos.ExecStats
+				start := time.Now()
 				err := doRun(
 					ctx,
 					inst,
@@ -184,42 +259,87 @@ func run(args []string) error {
 					runDebug(debug),
 					runFirewall(firewall),
 					runWriters(outputs...),
+					runStats(&st),
+					runSandbox(sandbox),
 				)
 				// If it's just that the command failed, don't exit just yet, and don't return
 				// an error to the errgroup because we want the other commands to keep going.
+				ce = nil
 				if err != nil {
 					var ok bool
 					ce, ok = err.(*cmdFailedError)
 					if !ok {
-						return err
+						if ctx.Err() == nil {
+							return err
+						}
+						// Stopped because of a failure elsewhere, or because another
+						// instance returned an error, which eg.Wait reports. Stress
+						// runs are expected to stop this way.
+						res.canceled = stress == 0
+						break
 					}
 				}
-				if until == nil || until.Match(outBuf.Bytes()) {
+				res.runs++
+				res.duration += time.Since(start)
+				if stats && st != nil {
+					log.Printf("Resource usage on %q: %s\n", inst, execStatsString(st))
+				}
+				if ce != nil {
+					// Write out the error.
+					_, err := io.MultiWriter(outputs...).Write([]byte(ce.Error() + "\n"))
+					if err != nil {
+						log.Printf("failed to write error to output: %v", err)
+					}
+					res.failures++
+					res.result = failureString(ce.err)
+					mu.Lock()
+					failures++
+					n := failures
+					mu.Unlock()
+					if stress > 0 {
+						name := filepath.Join(outDir, fmt.Sprintf("%s.failure-%d.stdout", inst, res.failures))
+						if err := copyOutput(name, outf); err != nil {
+							return err
+						}
+						log.Printf("Failure %d of %d on %q, wrote output to %q.\n", n, stress, inst, name)
+					}
+					if failFast || (stress > 0 && n >= stress) {
+						stop()
+					}
+				}
+				if stress > 0 {
+					if ctx.Err() != nil {
+						break
+					}
+				} else if until == nil || until.Match(outBuf.Bytes()) {
 					break
 				}
-				// Reset the output file and our buffer for the next run.
+				// Reset the output file and our buffers for the next run.
 				outBuf.Reset()
+				tail.Reset()
+				if _, err := outf.Seek(0, io.SeekStart); err != nil {
+					return fmt.Errorf("failed to rewind output file %q: %w", outf.Name(), err)
+				}
 				if err := outf.Truncate(0); err != nil {
 					return fmt.Errorf("failed to truncate output file %q: %w", outf.Name(), err)
 				}
 
-				log.Printf("No match found on %q, running again...\n", inst)
+				if until != nil {
+					log.Printf("No match found on %q, running again...\n", inst)
+				}
 			}
-			if until != nil {
+			if until != nil && !res.canceled {
 				log.Printf("Match found on %q.\n", inst)
 			}
-			if ce != nil {
-				// N.B. If err this wasn't a cmdFailedError
-				cmdsFailedMu.Lock()
-				cmdsFailed = append(cmdsFailed, ce)
-				cmdsFailedMu.Unlock()
-				// Write out the error.
-				_, err := io.MultiWriter(outputs...).Write([]byte(ce.Error() + "\n"))
-				if err != nil {
-					log.Printf("failed to write error to output: %v", err)
-				}
+			res.output = normalizeOutput(tail.Bytes())
+			if res.failures > 0 {
+				mu.Lock()
+				cmdsFailed = append(cmdsFailed, &cmdFailedError{inst: inst, cmd: cmd, err: errors.New(res.result)})
+				mu.Unlock()
 			}
 			if collect {
+				// Use a fresh context: the tarball is still useful after -failfast.
+				ctx := context.Background()
 				f, err := os.Create(fmt.Sprintf("%s.tar.gz", inst))
 				if err != nil {
 					log.Printf("failed to create file to write instance tarball: %v", err)
@@ -238,11 +358,19 @@ func run(args []string) error {
 	if err := eg.Wait(); err != nil {
 		return err
 	}
+	if len(runSet) > 1 || stress > 0 {
+		if compared && groupOutputs(results) > 1 {
+			if err := writeOutputDiffs(outDir, results); err != nil {
+				return err
+			}
+		}
+		printRunSummary(os.Stdout, results, compared)
+	}
 	// Handle failed commands separately so that we can let all the instances finish
 	// running. We still want to handle them, though, because we want to make sure
 	// we exit with a non-zero exit code to reflect the command failure.
 	for _, ce := range cmdsFailed {
-		log.Printf("Command %q failed on %q: %v\n", ce.cmd, ce.inst, err)
+		log.Printf("Command %q failed on %q: %v\n", ce.cmd, ce.inst, ce.err)
 	}
 	if len(cmdsFailed) > 0 {
 		return errors.New("one or more commands failed")
@@ -250,6 +378,44 @@ func run(args []string) error {
 	return nil
 }
 
+// copyOutput copies the output written to f so far to a new file name.
+func copyOutput(name string, f *os.File) error {
+	out, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, io.NewSectionReader(f, 0, 1<<62)); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// writeOutputDiffs writes, for each instance in results whose output
+// differs from the most common, a diff of the two to <instance>.diff in
+// outDir.
+func writeOutputDiffs(outDir string, results []*groupRunResult) error {
+	var common *groupRunResult
+	for _, r := range results {
+		if r.outGroup == 0 {
+			common = r
+			break
+		}
+	}
+	for _, r := range results {
+		if r.outGroup <= 0 {
+			continue
+		}
+		name := filepath.Join(outDir, r.inst+".diff")
+		d := diff.Diff(common.inst, common.output, r.inst, r.output)
+		if err := os.WriteFile(name, d, 0644); err != nil {
+			return err
+		}
+		log.Printf("Output from %q differs from %q, wrote diff to %q.\n", r.inst, common.inst, name)
+	}
+	return nil
+}
+
 func doRun(ctx context.Context, inst, cmd string, cmdArgs []string, opts ...runOpt) error {
 	cfg := &runCfg{
 		req: protos.ExecuteCommandRequest{
@@ -287,9 +453,19 @@ func doRun(ctx context.Context, inst, cmd string, cmdArgs []string, opts ...runO
 			return fmt.Errorf("unable to execute %s: %w", cmd, err)
 		}
 		fmt.Fprint(outWriter, string(update.GetOutput()))
+		if st := update.GetStats(); st != nil && cfg.stats != nil {
+			*cfg.stats = st
+		}
 	}
 }
 
+// execStatsString formats the resource usage of a command.
+func execStatsString(st *protos.ExecStats) string {
+	return fmt.Sprintf("wall %v, user %v, sys %v, max RSS %.1f MiB, wrote %.1f MiB, output %d bytes",
+		time.Duration(st.GetWallNanos()), time.Duration(st.GetUserNanos()), time.Duration(st.GetSystemNanos()),
+		float64(st.GetMaxRssBytes())/(1<<20), float64(st.GetWriteBytes())/(1<<20), st.GetOutputBytes())
+}
+
 type cmdFailedError struct {
 	inst, cmd string
 	err       error
@@ -306,6 +482,7 @@ func (e *cmdFailedError) Unwrap() error {
 type runCfg struct {
 	outputs []io.Writer
 	req     protos.ExecuteCommandRequest
+	stats   **protos.ExecStats
 }
 
 type runOpt func(*runCfg)
@@ -357,3 +534,43 @@ func runWriters(writers ...io.Writer) runOpt {
 		r.outputs = writers
 	}
 }
+
+// runStats sets *st to the command's resource usage, if it's reported.
+func runStats(st **protos.ExecStats) runOpt {
+	return func(r *runCfg) {
+		r.stats = st
+	}
+}
+
+// runSandbox runs the command in sb, if it's non-nil.
+func runSandbox(sb *protos.Sandbox) runOpt {
+	return func(r *runCfg) {
+		r.req.Sandbox = sb
+	}
+}
+
+// sandboxFlag returns the sandbox described by the -sandbox,
+// -sandbox-allow, and -sandbox-ro flags, or nil if they're unset.
+func sandboxFlag(network, allow string, readOnly bool) (*protos.Sandbox, error) {
+	if network == "" && allow == "" && !readOnly {
+		return nil, nil
+	}
+	sb := &protos.Sandbox{ReadOnlyWorkDir: readOnly}
+	if allow != "" {
+		sb.Allow = strings.Split(allow, ",")
+		if network == "" {
+			network = "allowlist"
+		}
+	}
+	switch network {
+	case "", "host":
+	case "none", "loopback", "allowlist":
+		sb.Network = network
+	default:
+		return nil, fmt.Errorf("invalid -sandbox network %q; want host, none, loopback, or allowlist", network)
+	}
+	if len(sb.Allow) > 0 && sb.Network != "allowlist" {
+		return nil, errors.New("-sandbox-allow requires -sandbox=allowlist")
+	}
+	return sb, nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+// A groupRunResult is the outcome of running a command
+// on one instance in a group.
+type groupRunResult struct {
+	inst     string
+	runs     int           // number of completed runs
+	failures int           // number of failed runs
+	duration time.Duration // total time spent in completed runs
+	canceled bool          // last run stopped by -failfast
+	result   string        // failure of the last failed run, if any
+	output   []byte        // normalized end of the output of the last run
+	outGroup int           // index of the output group, or -1 if not compared
+}
+
+// failureString returns the reason a command failed, as reported by
+// the gomote server in err.
+func failureString(err error) string {
+	msg := err.Error()
+	if s, ok := status.FromError(err); ok {
+		msg = s.Message()
+	}
+	return strings.TrimPrefix(msg, "unable to execute command: ")
+}
+
+var (
+	durationRE = regexp.MustCompile(`\b[0-9]+(\.[0-9]+)?(ns|µs|us|ms|s|m|h)\b`)
+	addressRE  = regexp.MustCompile(`\b0x[0-9a-f]{6,}\b`)
+)
+
+// normalizeOutput returns out with timings and addresses replaced by
+// placeholders, so that otherwise equal outputs compare equal.
+func normalizeOutput(out []byte) []byte {
+	out = durationRE.ReplaceAll(out, []byte("<dur>"))
+	return addressRE.ReplaceAll(out, []byte("<addr>"))
+}
+
+// maxComparedOutput is how much of the end of each instance's output
+// is kept to compare with the other instances.
+const maxComparedOutput = 1 << 20
+
+// A tailBuffer is an io.Writer that keeps the last max bytes written to it.
+type tailBuffer struct {
+	max int
+	buf []byte
+}
+
+func newTailBuffer(max int) *tailBuffer {
+	return &tailBuffer{max: max}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) > b.max {
+		p = p[len(p)-b.max:]
+	}
+	if len(b.buf)+len(p) > 2*b.max {
+		// Drop all but the bytes still needed, at most once
+		// every max bytes written.
+		keep := b.max - len(p)
+		b.buf = append(b.buf[:0], b.buf[len(b.buf)-keep:]...)
+	}
+	b.buf = append(b.buf, p...)
+	return n, nil
+}
+
+// Bytes returns the last max bytes written.
+func (b *tailBuffer) Bytes() []byte {
+	if len(b.buf) > b.max {
+		return b.buf[len(b.buf)-b.max:]
+	}
+	return b.buf
+}
+
+// Reset discards the bytes written.
+func (b *tailBuffer) Reset() {
+	b.buf = b.buf[:0]
+}
+
+// groupOutputs sets the outGroup of each result that ran to completion
+// so that results with the same output share a group, with group 0 the
+// most common output, and returns the number of groups.
+func groupOutputs(results []*groupRunResult) int {
+	byOutput := make(map[string][]*groupRunResult)
+	var outputs []string // in order of first appearance
+	for _, r := range results {
+		r.outGroup = -1
+		if r.canceled || r.runs == 0 {
+			continue
+		}
+		out := string(r.output)
+		if _, ok := byOutput[out]; !ok {
+			outputs = append(outputs, out)
+		}
+		byOutput[out] = append(byOutput[out], r)
+	}
+	sort.SliceStable(outputs, func(i, j int) bool {
+		return len(byOutput[outputs[i]]) > len(byOutput[outputs[j]])
+	})
+	for i, out := range outputs {
+		for _, r := range byOutput[out] {
+			r.outGroup = i
+		}
+	}
+	return len(outputs)
+}
+
+// outGroupName returns the name of output group i: A, B, ..., Z, AA, AB, ...
+func outGroupName(i int) string {
+	if i < 0 {
+		return "-"
+	}
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// printRunSummary prints a table of results to w. If the outputs were
+// compared, the OUTPUT column names each instance's output group,
+// and instances whose output differs from the most common are marked.
+func printRunSummary(w io.Writer, results []*groupRunResult, compared bool) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "INSTANCE\tRUNS\tFAILURES\tTIME\tOUTPUT\tRESULT\n")
+	for _, r := range results {
+		output := "-"
+		if compared {
+			output = outGroupName(r.outGroup)
+			if r.outGroup > 0 {
+				output += " (differs)"
+			}
+		}
+		result := "ok"
+		switch {
+		case r.canceled:
+			result = "canceled"
+		case r.failures > 0:
+			result = "FAIL: " + r.result
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%v\t%s\t%s\n", r.inst, r.runs, r.failures, r.duration.Round(time.Millisecond), output, result)
+	}
+	tw.Flush()
+}
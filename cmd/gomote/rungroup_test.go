@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNormalizeOutput(t *testing.T) {
+	got := string(normalizeOutput([]byte("ok  \truntime\t12.345s\n--- FAIL: TestX (0.01s)\npc=0xc000123456 sp=0x1\n")))
+	want := "ok  \truntime\t<dur>\n--- FAIL: TestX (<dur>)\npc=<addr> sp=0x1\n"
+	if got != want {
+		t.Errorf("normalizeOutput = %q, want %q", got, want)
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := newTailBuffer(4)
+	var all strings.Builder
+	for _, s := range []string{"ab", "c", "defgh", "", "i", "jk", "lmnop", "q"} {
+		if n, err := b.Write([]byte(s)); n != len(s) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v", s, n, err)
+		}
+		all.WriteString(s)
+		want := all.String()
+		if len(want) > 4 {
+			want = want[len(want)-4:]
+		}
+		if got := string(b.Bytes()); got != want {
+			t.Errorf("after writing %q, Bytes = %q, want %q", all.String(), got, want)
+		}
+		if len(b.buf) > 2*4 {
+			t.Errorf("after writing %q, buffer holds %d bytes", all.String(), len(b.buf))
+		}
+	}
+	b.Reset()
+	if got := b.Bytes(); len(got) != 0 {
+		t.Errorf("after Reset, Bytes = %q", got)
+	}
+}
+
+func TestGroupOutputs(t *testing.T) {
+	results := []*groupRunResult{
+		{inst: "a", runs: 1, output: []byte("x")},
+		{inst: "b", runs: 1, output: []byte("y")},
+		{inst: "c", runs: 1, output: []byte("y")},
+		{inst: "d", canceled: true, output: []byte("partial")},
+		{inst: "e", runs: 1, output: []byte("z")},
+	}
+	if n := groupOutputs(results); n != 3 {
+		t.Errorf("groupOutputs returned %d groups, want 3", n)
+	}
+	want := map[string]int{"a": 1, "b": 0, "c": 0, "d": -1, "e": 2}
+	for _, r := range results {
+		if r.outGroup != want[r.inst] {
+			t.Errorf("instance %q in output group %d, want %d", r.inst, r.outGroup, want[r.inst])
+		}
+	}
+}
+
+func TestOutGroupName(t *testing.T) {
+	for i, want := range map[int]string{-1: "-", 0: "A", 1: "B", 25: "Z", 26: "AA", 27: "AB", 52: "BA"} {
+		if got := outGroupName(i); got != want {
+			t.Errorf("outGroupName(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestPrintRunSummary(t *testing.T) {
+	err := status.Error(codes.Aborted, "unable to execute command: command execution failed: exit status 2")
+	results := []*groupRunResult{
+		{inst: "a", runs: 1},
+		{inst: "b", runs: 1, failures: 1, result: failureString(err), outGroup: 1},
+		{inst: "c", canceled: true, outGroup: -1},
+	}
+	var buf bytes.Buffer
+	printRunSummary(&buf, results, true)
+	out := buf.String()
+	for _, want := range []string{"FAIL: command execution failed: exit status 2", "B (differs)", "canceled"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary doesn't contain %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+
+	"golang.org/x/build/internal/gomote/protos"
+	"golang.org/x/term"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// doStreamRun runs cmd on inst with StreamCommand, streaming this process's
+// standard input to it and forwarding interrupts. Its standard output and
+// error are written to this process's own. If tty is set, the command runs
+// in a pseudo-terminal and the local terminal is put in raw mode meanwhile.
+func doStreamRun(ctx context.Context, inst, cmd string, cmdArgs []string, tty bool, opts ...runOpt) (*protos.ExitStatus, error) {
+	cfg := &runCfg{
+		req: protos.ExecuteCommandRequest{
+			AppendEnvironment: []string{},
+			Args:              cmdArgs,
+			Command:           cmd,
+			Path:              []string{},
+			GomoteId:          inst,
+		},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if !cfg.req.SystemLevel {
+		cfg.req.SystemLevel = strings.HasPrefix(cmd, "/")
+	}
+	start := &protos.StreamCommandStart{Command: &cfg.req}
+	fd := int(os.Stdin.Fd())
+	if tty {
+		if !term.IsTerminal(fd) {
+			return nil, errors.New("-tty requires standard input to be a terminal")
+		}
+		start.Pty = true
+		start.Term = os.Getenv("TERM")
+		if cols, rows, err := term.GetSize(fd); err == nil {
+			start.Size = &protos.TerminalSize{Rows: uint32(rows), Cols: uint32(cols)}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := gomoteServerClient(ctx).StreamCommand(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute %s: %w", cmd, err)
+	}
+	// Requests are sent from several goroutines.
+	var mu sync.Mutex
+	send := func(req *protos.StreamCommandRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return stream.Send(req)
+	}
+	if err := send(&protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_Start{Start: start}}); err != nil {
+		return nil, fmt.Errorf("unable to execute %s: %w", cmd, err)
+	}
+
+	if tty {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return nil, fmt.Errorf("putting terminal in raw mode: %w", err)
+		}
+		defer term.Restore(fd, state)
+		stopResize := notifyResize(func() {
+			if cols, rows, err := term.GetSize(fd); err == nil {
+				send(&protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_Resize{
+					Resize: &protos.TerminalSize{Rows: uint32(rows), Cols: uint32(cols)},
+				}})
+			}
+		})
+		defer stopResize()
+	}
+
+	// In raw mode, interrupts typed at the terminal reach the remote
+	// terminal as input, so this only handles signals sent to gomote itself.
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, forwardedSignals...)
+	defer signal.Stop(sigc)
+	go func() {
+		for {
+			select {
+			case sig := <-sigc:
+				if name := signalName(sig); name != "" {
+					send(&protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_Signal{Signal: name}})
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		buf := make([]byte, 32<<10)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if n > 0 {
+				req := &protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_Stdin{Stdin: append([]byte(nil), buf[:n]...)}}
+				if send(req) != nil {
+					return
+				}
+			}
+			if err != nil {
+				send(&protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_CloseStdin{CloseStdin: true}})
+				return
+			}
+		}
+	}()
+
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return nil, fmt.Errorf("unable to execute %s: stream ended without an exit status", cmd)
+		}
+		if err != nil {
+			if status.Code(err) == codes.Aborted {
+				return nil, &cmdFailedError{inst: inst, cmd: cmd, err: err}
+			}
+			return nil, fmt.Errorf("unable to execute %s: %w", cmd, err)
+		}
+		switch r := res.GetResponse().(type) {
+		case *protos.StreamCommandResponse_Stdout:
+			os.Stdout.Write(r.Stdout)
+		case *protos.StreamCommandResponse_Stderr:
+			os.Stderr.Write(r.Stderr)
+		case *protos.StreamCommandResponse_ExitStatus:
+			return r.ExitStatus, nil
+		}
+	}
+}
+
+// exitCode returns the exit code for gomote to exit with after
+// running a command that finished with status st: the command's own
+// exit code if it has one, 1 if it failed otherwise, or 0 on success.
+func exitCode(st *protos.ExitStatus) int {
+	switch {
+	case st.GetCode() > 0:
+		return int(st.GetCode())
+	case st.GetCode() != 0 || st.GetSignal() != "" || st.GetError() != "":
+		return 1
+	}
+	return 0
+}
+
+func exitStatusString(st *protos.ExitStatus) string {
+	switch {
+	case st.GetError() != "":
+		return st.GetError()
+	case st.GetSignal() != "":
+		return "signal: " + st.GetSignal()
+	}
+	return fmt.Sprintf("exit status %d", st.GetCode())
+}
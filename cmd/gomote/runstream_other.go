@@ -0,0 +1,25 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build plan9 || windows
+
+package main
+
+import "os"
+
+// forwardedSignals are the signals gomote run -stdin forwards to the command.
+var forwardedSignals = []os.Signal{os.Interrupt}
+
+// signalName returns the StreamCommand name of sig.
+func signalName(sig os.Signal) string {
+	if sig == os.Interrupt {
+		return "INT"
+	}
+	return ""
+}
+
+// notifyResize does nothing: there's no resize notification here.
+func notifyResize(f func()) (stop func()) {
+	return func() {}
+}
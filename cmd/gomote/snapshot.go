@@ -0,0 +1,165 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/build/internal/gomote/protos"
+	"golang.org/x/sync/errgroup"
+)
+
+func snapshot(args []string) error {
+	fs := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	fs.Usage = func() {
+		log := usageLogger
+		log.Print("snapshot usage: gomote snapshot [snapshot-opts] [instance]")
+		log.Print("       gomote snapshot -list")
+		log.Print("       gomote snapshot -rm <snapshot-id>")
+		log.Print("")
+		log.Print("Saves the work directory of an instance as a snapshot, which")
+		log.Print("'gomote create -from-snapshot' restores onto a new instance of")
+		log.Print("the same builder type. Snapshots expire after a while.")
+		log.Print("")
+		log.Print("Instance name is optional if a group is selected, in which case")
+		log.Print("each instance in the group is saved as a separate snapshot.")
+		fs.PrintDefaults()
+		os.Exit(1)
+	}
+	var dir, rev, desc string
+	fs.StringVar(&dir, "dir", "", "relative directory from the instance's work dir to save (default is the whole work dir)")
+	fs.StringVar(&rev, "rev", "", "Go revision in the snapshot, for reference (default is the HEAD of $GOROOT, if set)")
+	fs.StringVar(&desc, "m", "", "description of the snapshot")
+	var ttl time.Duration
+	fs.DurationVar(&ttl, "ttl", 0, "how long to keep the snapshot (default is the server's, usually 7 days)")
+	var list bool
+	fs.BoolVar(&list, "list", false, "list your snapshots")
+	var rmID string
+	fs.StringVar(&rmID, "rm", "", "delete the snapshot with this ID")
+
+	fs.Parse(args)
+
+	ctx := context.Background()
+	switch {
+	case list:
+		if fs.NArg() != 0 {
+			fs.Usage()
+		}
+		snaps, err := listSnapshots(ctx)
+		if err != nil {
+			return err
+		}
+		printSnapshots(os.Stdout, snaps)
+		return nil
+	case rmID != "":
+		if fs.NArg() != 0 {
+			fs.Usage()
+		}
+		client := gomoteServerClient(ctx)
+		if _, err := client.DeleteSnapshot(ctx, &protos.DeleteSnapshotRequest{SnapshotId: rmID}); err != nil {
+			return fmt.Errorf("unable to delete snapshot %s: %w", rmID, err)
+		}
+		return nil
+	}
+
+	var snapSet []string
+	if fs.NArg() == 1 {
+		snapSet = []string{fs.Arg(0)}
+	} else if fs.NArg() == 0 && activeGroup != nil {
+		snapSet = append(snapSet, activeGroup.Instances...)
+	} else {
+		fs.Usage()
+	}
+	if rev == "" {
+		rev = gorootRevision()
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, inst := range snapSet {
+		eg.Go(func() error {
+			log.Printf("Saving snapshot of %q...\n", inst)
+			client := gomoteServerClient(ctx)
+			resp, err := client.CreateSnapshot(ctx, &protos.CreateSnapshotRequest{
+				GomoteId:    inst,
+				Directory:   dir,
+				GoRevision:  rev,
+				Description: desc,
+				TtlSeconds:  int64(ttl / time.Second),
+			})
+			if err != nil {
+				return fmt.Errorf("unable to save snapshot of %s: %w", inst, err)
+			}
+			snap := resp.GetSnapshot()
+			log.Printf("Saved %q as snapshot %s, which expires %s.\n", inst, snap.GetSnapshotId(), time.Unix(snap.GetExpires(), 0).Format(time.DateTime))
+			fmt.Println(snap.GetSnapshotId())
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// gorootRevision returns the commit checked out in $GOROOT,
+// or the empty string if it can't tell.
+func gorootRevision() string {
+	goroot := os.Getenv("GOROOT")
+	if goroot == "" {
+		return ""
+	}
+	out, err := exec.Command("git", "-C", goroot, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func listSnapshots(ctx context.Context) ([]*protos.Snapshot, error) {
+	client := gomoteServerClient(ctx)
+	resp, err := client.ListSnapshots(ctx, &protos.ListSnapshotsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list snapshots: %w", err)
+	}
+	return resp.GetSnapshots(), nil
+}
+
+// printSnapshots prints a table of snaps to w.
+func printSnapshots(w io.Writer, snaps []*protos.Snapshot) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID\tBUILDER\tREVISION\tDIR\tSIZE\tEXPIRES\tDESCRIPTION\n")
+	for _, s := range snaps {
+		rev, dir := s.GetGoRevision(), s.GetDirectory()
+		if len(rev) > 12 {
+			rev = rev[:12]
+		}
+		if dir == "" {
+			dir = "."
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.1f MiB\t%s\t%s\n", s.GetSnapshotId(), s.GetBuilderType(), rev, dir,
+			float64(s.GetSizeBytes())/(1<<20), time.Unix(s.GetExpires(), 0).Format(time.DateTime), s.GetDescription())
+	}
+	tw.Flush()
+}
+
+// snapshotBuilderType returns the builder type of the snapshot with the given ID.
+func snapshotBuilderType(ctx context.Context, id string) (string, error) {
+	snaps, err := listSnapshots(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, s := range snaps {
+		if s.GetSnapshotId() == id {
+			return s.GetBuilderType(), nil
+		}
+	}
+	return "", fmt.Errorf("no snapshot %s; see 'gomote snapshot -list'", id)
+}
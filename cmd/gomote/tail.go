@@ -0,0 +1,95 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"golang.org/x/build/internal/gomote/protos"
+)
+
+func tail(args []string) error {
+	fs := flag.NewFlagSet("tail", flag.ContinueOnError)
+	fs.Usage = func() {
+		log := usageLogger
+		log.Print("tail usage: gomote tail [tail-opts] <instance> <file>")
+		log.Print("       gomote tail -watch <instance> <dir>")
+		log.Print("")
+		log.Print("Relative paths are relative to the instance's work directory.")
+		fs.PrintDefaults()
+		os.Exit(1)
+	}
+	var follow bool
+	fs.BoolVar(&follow, "f", false, "keep printing data appended to the file, waiting for it to be created if needed, until interrupted")
+	var bytes int64
+	fs.Int64Var(&bytes, "c", 16<<10, "print the last `n` bytes of the file; 0 means the whole file")
+	var watch bool
+	fs.BoolVar(&watch, "watch", false, "instead of printing a file, print changes to the files in a directory until interrupted")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+	}
+	inst, path := fs.Arg(0), fs.Arg(1)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	client := gomoteServerClient(ctx)
+	if watch {
+		stream, err := client.WatchDirectory(ctx, &protos.WatchDirectoryRequest{
+			GomoteId:  inst,
+			Directory: path,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to watch %s: %w", path, err)
+		}
+		for {
+			e, err := stream.Recv()
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("unable to watch %s: %w", path, err)
+			}
+			desc := e.GetPath()
+			switch {
+			case e.GetOp() == "remove":
+			case os.FileMode(e.GetMode()).IsDir():
+				desc += "/"
+			default:
+				desc += fmt.Sprintf(" (%d bytes)", e.GetSize())
+			}
+			fmt.Printf("%s %-6s %s\n", time.Now().Format(time.TimeOnly), e.GetOp(), desc)
+		}
+	}
+
+	stream, err := client.TailFile(ctx, &protos.TailFileRequest{
+		GomoteId: inst,
+		Path:     path,
+		Offset:   -bytes,
+		Follow:   follow,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to tail %s: %w", path, err)
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("unable to tail %s: %w", path, err)
+		}
+		os.Stdout.Write(resp.GetData())
+	}
+}
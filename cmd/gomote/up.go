@@ -0,0 +1,510 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/build/internal/gomote/protos"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v2"
+)
+
+// A session describes a set of instances, how to provision them,
+// and a command to run on them, as read by "gomote up" from a
+// YAML file like:
+//
+//	group: repro
+//	instances:
+//	  - builder: gotip-linux-amd64
+//	    count: 2
+//	  - builder: gotip-windows-amd64
+//	push: true
+//	files:
+//	  - src: testdata/input.txt
+//	    dst: go/src/runtime/testdata/input.txt
+//	setup:
+//	  - cmd: go/src/make.bash
+//	    builders: ["*-linux-*"]
+//	  - cmd: go/src/make.bat
+//	    builders: ["*-windows-*"]
+//	run:
+//	  cmd: go/bin/go
+//	  args: [test, -run=TestFlaky, -count=10, runtime]
+type session struct {
+	// Group is the name of the group holding the instances.
+	// It defaults to the file's name without its extension.
+	Group string `yaml:"group"`
+
+	// Instances are the builder types to create and how many of each.
+	// Instances already in the group are reused.
+	Instances []sessionInstances `yaml:"instances"`
+
+	// Push, if true, pushes the local GOROOT ($GOROOT or the one
+	// 'go env GOROOT' reports) to each instance, like "gomote push".
+	// GOROOT, if set, is a GOROOT to push instead.
+	Push   bool   `yaml:"push"`
+	GOROOT string `yaml:"goroot"`
+
+	// Bootstrap, if true, puts the bootstrap toolchain on each instance,
+	// like "gomote putbootstrap".
+	Bootstrap bool `yaml:"bootstrap"`
+
+	// Files are local files to put on each instance, like "gomote put".
+	Files []sessionFile `yaml:"files"`
+
+	// Tars are tar.gz files to extract on each instance,
+	// like "gomote puttar".
+	Tars []sessionTar `yaml:"tars"`
+
+	// Setup are commands to run on each instance, in order,
+	// before Run.
+	Setup []sessionCommand `yaml:"setup"`
+
+	// Run is the command whose results are summarized.
+	Run *sessionCommand `yaml:"run"`
+}
+
+type sessionInstances struct {
+	Builder string `yaml:"builder"`
+	Count   int    `yaml:"count"` // default 1
+}
+
+type sessionFile struct {
+	Src  string `yaml:"src"`
+	Dst  string `yaml:"dst"`  // default: base name of Src
+	Mode string `yaml:"mode"` // octal; default: Src's mode
+}
+
+// A sessionTar is a tar.gz file to extract into Dir, from exactly
+// one of a URL, a local file, or a Go repository revision.
+type sessionTar struct {
+	URL string `yaml:"url"`
+	Src string `yaml:"src"`
+	Rev string `yaml:"rev"`
+	Dir string `yaml:"dir"`
+}
+
+type sessionCommand struct {
+	Cmd    string   `yaml:"cmd"`
+	Args   []string `yaml:"args"`
+	Env    []string `yaml:"env"`
+	Dir    string   `yaml:"dir"`
+	System bool     `yaml:"system"`
+
+	// Builders, if non-empty, are path.Match patterns for the
+	// builder types the command runs on, like "*-windows-*".
+	Builders []string `yaml:"builders"`
+}
+
+// appliesTo reports whether c runs on instances of builderType.
+func (c *sessionCommand) appliesTo(builderType string) bool {
+	if len(c.Builders) == 0 {
+		return true
+	}
+	for _, pat := range c.Builders {
+		if ok, _ := path.Match(pat, builderType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *sessionCommand) String() string {
+	return strings.Join(append([]string{c.Cmd}, c.Args...), " ")
+}
+
+// parseSession parses and checks the session in data,
+// read from the file named name.
+func parseSession(name string, data []byte) (*session, error) {
+	s := new(session)
+	if err := yaml.UnmarshalStrict(data, s); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	if s.Group == "" {
+		s.Group = strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	}
+	if len(s.Instances) == 0 {
+		return nil, fmt.Errorf("%s: no instances", name)
+	}
+	for i := range s.Instances {
+		in := &s.Instances[i]
+		if in.Builder == "" {
+			return nil, fmt.Errorf("%s: instance %d has no builder", name, i+1)
+		}
+		if in.Count == 0 {
+			in.Count = 1
+		}
+		if in.Count < 0 {
+			return nil, fmt.Errorf("%s: negative count for %s", name, in.Builder)
+		}
+	}
+	for i := range s.Files {
+		f := &s.Files[i]
+		if f.Src == "" {
+			return nil, fmt.Errorf("%s: file %d has no src", name, i+1)
+		}
+		if f.Dst == "" {
+			f.Dst = filepath.Base(f.Src)
+		}
+		if _, err := parseFileMode(f.Mode); err != nil {
+			return nil, fmt.Errorf("%s: file %s: %v", name, f.Src, err)
+		}
+	}
+	for i, t := range s.Tars {
+		n := 0
+		for _, v := range []string{t.URL, t.Src, t.Rev} {
+			if v != "" {
+				n++
+			}
+		}
+		if n != 1 {
+			return nil, fmt.Errorf("%s: tar %d needs exactly one of url, src, and rev", name, i+1)
+		}
+	}
+	cmds := s.Setup
+	if s.Run != nil {
+		cmds = append(cmds[:len(cmds):len(cmds)], *s.Run)
+	}
+	for _, c := range cmds {
+		if c.Cmd == "" {
+			return nil, fmt.Errorf("%s: command with no cmd", name)
+		}
+		for _, pat := range c.Builders {
+			if _, err := path.Match(pat, ""); err != nil {
+				return nil, fmt.Errorf("%s: bad builder pattern %q", name, pat)
+			}
+		}
+	}
+	return s, nil
+}
+
+// parseFileMode parses an octal file mode with only permission bits,
+// returning 0 if s is empty.
+func parseFileMode(s string) (os.FileMode, error) {
+	if s == "" {
+		return 0, nil
+	}
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode&^uint64(os.ModePerm) != 0 {
+		return 0, fmt.Errorf("bad mode %q", s)
+	}
+	return os.FileMode(mode), nil
+}
+
+// An upResult is the outcome of a session on one instance.
+type upResult struct {
+	Instance string        `json:"instance"`
+	Builder  string        `json:"builder"`
+	Step     string        `json:"step,omitempty"`  // the step that failed
+	Error    string        `json:"error,omitempty"` // how it failed
+	Duration time.Duration `json:"duration"`
+	Output   string        `json:"output"` // file holding the output of its commands
+}
+
+func up(args []string) error {
+	fs := flag.NewFlagSet("up", flag.ContinueOnError)
+	fs.Usage = func() {
+		log := usageLogger
+		log.Print("up usage: gomote up [up-opts] -f <session.yaml>")
+		log.Print()
+		log.Print("Up creates the instances described by a session file, or reuses")
+		log.Print("those already in its group, provisions them, and runs the session's")
+		log.Print("commands on them in parallel. It writes each instance's output to")
+		log.Print("a file and prints a summary of the results. See 'go doc")
+		log.Print("golang.org/x/build/cmd/gomote' for the file's format.")
+		log.Print()
+		log.Print("Flags:")
+		fs.PrintDefaults()
+		os.Exit(1)
+	}
+	var file, outDir string
+	fs.StringVar(&file, "f", "", "session file to read")
+	fs.StringVar(&outDir, "o", "", "directory to write output and summary.json to (default is a new temporary directory)")
+	var destroyAfter bool
+	fs.BoolVar(&destroyAfter, "destroy", false, "destroy the instances and the group once done")
+	fs.Parse(args)
+	if file == "" || fs.NArg() != 0 {
+		fs.Usage()
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	s, err := parseSession(file, data)
+	if err != nil {
+		return err
+	}
+	goroot := s.GOROOT
+	if s.Push && goroot == "" {
+		if goroot, err = getGOROOT(); err != nil {
+			return err
+		}
+	}
+	if outDir == "" {
+		if outDir, err = os.MkdirTemp("", "gomote"); err != nil {
+			return err
+		}
+	} else if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	builders, g, err := upInstances(ctx, s)
+	if err != nil {
+		return err
+	}
+	log.Printf("Using %d instances in group %q.\n", len(builders), g.Name)
+
+	var mu sync.Mutex
+	var results []upResult
+	var wg sync.WaitGroup
+	for inst, builder := range builders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := upRun(ctx, s, inst, builder, goroot, outDir)
+			mu.Lock()
+			results = append(results, r)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	sort.Slice(results, func(i, j int) bool { return results[i].Instance < results[j].Instance })
+
+	summary, err := json.MarshalIndent(results, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "summary.json"), summary, 0644); err != nil {
+		return err
+	}
+	failed := printUpSummary(os.Stdout, results)
+	log.Printf("Wrote output and summary.json to %q.\n", outDir)
+
+	if destroyAfter {
+		if err := destroyGroupInstances(ctx, g); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d instances failed", failed, len(results))
+	}
+	return nil
+}
+
+// upInstances returns the instances of session s, keyed by name, with
+// their builder types, creating any needed, and the group holding them.
+func upInstances(ctx context.Context, s *session) (map[string]string, *groupData, error) {
+	g, err := loadGroup(s.Group)
+	if err != nil {
+		if g, err = doCreateGroup(s.Group); err != nil {
+			return nil, nil, err
+		}
+	}
+	client := gomoteServerClient(ctx)
+	resp, err := client.ListInstances(ctx, &protos.ListInstancesRequest{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list instances: %w", err)
+	}
+	existing := make(map[string][]string) // builder type -> instances in g
+	for _, inst := range resp.GetInstances() {
+		if g.has(inst.GetGomoteId()) {
+			existing[inst.GetBuilderType()] = append(existing[inst.GetBuilderType()], inst.GetGomoteId())
+		}
+	}
+
+	builders := make(map[string]string)
+	var mu sync.Mutex
+	eg, ctx := errgroup.WithContext(ctx)
+	cfg := &createConfig{printStatus: true, useGolangbuild: true}
+	for _, in := range s.Instances {
+		have := existing[in.Builder]
+		sort.Strings(have)
+		for i := 0; i < in.Count; i++ {
+			if i < len(have) {
+				builders[have[i]] = in.Builder
+				continue
+			}
+			eg.Go(func() error {
+				inst, err := createInstance(ctx, client, in.Builder, i+1, cfg)
+				if err != nil {
+					return err
+				}
+				log.Printf("Created %q.\n", inst)
+				mu.Lock()
+				defer mu.Unlock()
+				builders[inst] = in.Builder
+				g.Instances = append(g.Instances, inst)
+				return nil
+			})
+		}
+	}
+	err = eg.Wait()
+	// Record the instances created, even if creating others failed.
+	if serr := storeGroup(g); err == nil {
+		err = serr
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return builders, g, nil
+}
+
+// upRun provisions instance inst, of type builder, as described by
+// session s, and runs its commands, writing their output to a file
+// in outDir.
+func upRun(ctx context.Context, s *session, inst, builder, goroot, outDir string) upResult {
+	start := time.Now()
+	r := upResult{
+		Instance: inst,
+		Builder:  builder,
+		Output:   filepath.Join(outDir, inst+".stdout"),
+	}
+	step, err := upSteps(ctx, s, inst, builder, goroot, r.Output)
+	r.Duration = time.Since(start).Round(time.Second)
+	if err != nil {
+		r.Step, r.Error = step, err.Error()
+		log.Printf("%s: %s failed: %v\n", inst, step, err)
+	} else {
+		log.Printf("%s: done\n", inst)
+	}
+	return r
+}
+
+// upSteps performs the steps of upRun, returning the name
+// of the step that failed, if any.
+func upSteps(ctx context.Context, s *session, inst, builder, goroot, output string) (step string, err error) {
+	outf, err := os.Create(output)
+	if err != nil {
+		return "output", err
+	}
+	defer outf.Close()
+
+	if goroot != "" {
+		if err := doPush(ctx, inst, goroot, false, false); err != nil {
+			return "push", err
+		}
+	}
+	if s.Bootstrap {
+		if err := doPutBootstrap(ctx, inst); err != nil {
+			return "bootstrap", err
+		}
+	}
+	for _, f := range s.Files {
+		if err := upPutFile(ctx, inst, f); err != nil {
+			return "put " + f.Src, err
+		}
+	}
+	for _, t := range s.Tars {
+		if err := upPutTar(ctx, inst, t); err != nil {
+			return "puttar", err
+		}
+	}
+	run := func(c *sessionCommand) error {
+		if !c.appliesTo(builder) {
+			return nil
+		}
+		log.Printf("%s: running %s\n", inst, c)
+		fmt.Fprintf(outf, "$ %s\n", c)
+		return doRun(ctx, inst, c.Cmd, c.Args,
+			runDir(c.Dir),
+			runEnv(c.Env),
+			runSystem(c.System),
+			runWriters(outf),
+		)
+	}
+	for i := range s.Setup {
+		if err := run(&s.Setup[i]); err != nil {
+			return "setup " + s.Setup[i].Cmd, err
+		}
+	}
+	if s.Run != nil {
+		if err := run(s.Run); err != nil {
+			return "run", err
+		}
+	}
+	return "", nil
+}
+
+func upPutFile(ctx context.Context, inst string, sf sessionFile) error {
+	f, err := os.Open(sf.Src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	mode, _ := parseFileMode(sf.Mode) // checked by parseSession
+	if mode == 0 {
+		fi, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		mode = fi.Mode()
+	}
+	return doPutFile(ctx, inst, f, sf.Dst, mode)
+}
+
+func upPutTar(ctx context.Context, inst string, t sessionTar) error {
+	switch {
+	case t.URL != "":
+		return doPutTarURL(ctx, inst, t.Dir, t.URL)
+	case t.Rev != "":
+		return doPutTarGoRev(ctx, inst, t.Dir, t.Rev)
+	}
+	f, err := os.Open(t.Src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return doPutTar(ctx, inst, t.Dir, f)
+}
+
+// printUpSummary prints a table of results to w,
+// and returns the number of instances that failed.
+func printUpSummary(w io.Writer, results []upResult) (failed int) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "INSTANCE\tBUILDER\tTIME\tRESULT\n")
+	for _, r := range results {
+		result := "ok"
+		if r.Error != "" {
+			result = fmt.Sprintf("FAIL in %s: %s", r.Step, r.Error)
+			failed++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%v\t%s\n", r.Instance, r.Builder, r.Duration, result)
+	}
+	tw.Flush()
+	return failed
+}
+
+// destroyGroupInstances destroys the instances in g, and then g.
+func destroyGroupInstances(ctx context.Context, g *groupData) error {
+	client := gomoteServerClient(ctx)
+	var errs []error
+	for _, inst := range g.Instances {
+		log.Printf("Destroying %s\n", inst)
+		if _, err := client.DestroyInstance(ctx, &protos.DestroyInstanceRequest{GomoteId: inst}); err != nil {
+			errs = append(errs, fmt.Errorf("unable to destroy %s: %w", inst, err))
+		}
+	}
+	if len(errs) == 0 {
+		errs = append(errs, deleteGroup(g.Name))
+	}
+	return errors.Join(errs...)
+}
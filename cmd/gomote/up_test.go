@@ -0,0 +1,93 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseSession(t *testing.T) {
+	const data = `
+instances:
+  - builder: gotip-linux-amd64
+    count: 2
+  - builder: gotip-windows-amd64
+files:
+  - src: testdata/input.txt
+    mode: 0755
+tars:
+  - rev: abc123
+    dir: go
+setup:
+  - cmd: go/src/make.bash
+    builders: ["*-linux-*"]
+run:
+  cmd: go/bin/go
+  args: [test, runtime]
+`
+	s, err := parseSession("dir/repro.yaml", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Group != "repro" {
+		t.Errorf("Group = %q, want %q", s.Group, "repro")
+	}
+	if len(s.Instances) != 2 || s.Instances[0].Count != 2 || s.Instances[1].Count != 1 {
+		t.Errorf("Instances = %+v, want counts 2 and 1", s.Instances)
+	}
+	if f := s.Files[0]; f.Dst != "input.txt" {
+		t.Errorf("file Dst = %q, want %q", f.Dst, "input.txt")
+	}
+	if m, err := parseFileMode(s.Files[0].Mode); err != nil || m != 0755 {
+		t.Errorf("file mode = %v, %v; want 0755", m, err)
+	}
+	if got, want := s.Run.String(), "go/bin/go test runtime"; got != want {
+		t.Errorf("Run = %q, want %q", got, want)
+	}
+	setup := s.Setup[0]
+	if !setup.appliesTo("gotip-linux-amd64") || setup.appliesTo("gotip-windows-amd64") {
+		t.Errorf("setup command %v applies to the wrong builders", setup.Builders)
+	}
+	if !s.Run.appliesTo("gotip-windows-amd64") {
+		t.Errorf("run command without builders doesn't apply to all builders")
+	}
+}
+
+func TestParseSessionErrors(t *testing.T) {
+	for _, data := range []string{
+		"instances: []",
+		"instances: [{count: 2}]",
+		"instances: [{builder: b}]\nbogus: true",
+		"instances: [{builder: b}]\nfiles: [{dst: x}]",
+		"instances: [{builder: b}]\nfiles: [{src: x, mode: 040755}]",
+		"instances: [{builder: b}]\ntars: [{url: u, rev: r}]",
+		"instances: [{builder: b}]\nrun: {args: [x]}",
+		"instances: [{builder: b}]\nsetup: [{cmd: x, builders: ['[']}]",
+	} {
+		if _, err := parseSession("s.yaml", []byte(data)); err == nil {
+			t.Errorf("parseSession(%q) succeeded, want error", data)
+		}
+	}
+}
+
+func TestPrintUpSummary(t *testing.T) {
+	var buf bytes.Buffer
+	failed := printUpSummary(&buf, []upResult{
+		{Instance: "a", Builder: "linux"},
+		{Instance: "b", Builder: "windows", Step: "run", Error: "exit status 1"},
+	})
+	if failed != 1 {
+		t.Errorf("printUpSummary returned %d failed, want 1", failed)
+	}
+	if out := buf.String(); !strings.Contains(out, "FAIL in run: exit status 1") {
+		t.Errorf("summary doesn't report the failure:\n%s", out)
+	}
+	if testing.Verbose() {
+		os.Stdout.Write(buf.Bytes())
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"golang.org/x/build/internal/gomote/protos"
+)
+
+func listUsage(args []string) error {
+	fs := flag.NewFlagSet("usage", flag.ContinueOnError)
+	fs.Usage = func() {
+		usageLogger.Print("usage usage: gomote usage [-all]")
+		usageLogger.Print("")
+		usageLogger.Print("Lists the instance-hours used by your instances since the")
+		usageLogger.Print("gomote server started, by builder type.")
+		fs.PrintDefaults()
+		os.Exit(1)
+	}
+	var all bool
+	fs.BoolVar(&all, "all", false, "list the usage of all users; only privileged users may do this")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+	}
+	ctx := context.Background()
+	client := gomoteServerClient(ctx)
+	resp, err := client.ListUsage(ctx, &protos.ListUsageRequest{AllUsers: all})
+	if err != nil {
+		return fmt.Errorf("unable to list usage: %w", err)
+	}
+	printUsage(os.Stdout, resp)
+	return nil
+}
+
+// printUsage prints a table of the usage in resp to w.
+func printUsage(w io.Writer, resp *protos.ListUsageResponse) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "USER\tBUILDER\tINSTANCES\tACTIVE\tINSTANCE-HOURS\n")
+	for _, u := range resp.GetUsage() {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.1f\n", u.GetUser(), u.GetBuilderType(), u.GetInstances(), u.GetActiveInstances(), float64(u.GetInstanceSeconds())/3600)
+	}
+	tw.Flush()
+	if n := resp.GetMaxInstancesPerUser(); n > 0 {
+		fmt.Fprintf(w, "\nEach user may have %d instances at once.\n", n)
+	}
+}
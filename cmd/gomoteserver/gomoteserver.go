@@ -21,6 +21,7 @@ import (
 	"go.chromium.org/luci/hardcoded/chromeinfra"
 	"go.chromium.org/luci/swarming/client/swarming"
 	"golang.org/x/build/buildenv"
+	"golang.org/x/build/buildlet"
 	"golang.org/x/build/internal/access"
 	"golang.org/x/build/internal/coordinator/pool"
 	"golang.org/x/build/internal/coordinator/remote"
@@ -39,6 +40,10 @@ var (
 	sshAddr      = flag.String("ssh_addr", ":2222", "Address the gomote SSH server should listen on")
 	buildEnvName = flag.String("env", "", "The build environment configuration to use. Not required if running in dev mode locally or prod mode on GCE.")
 	mode         = flag.String("mode", "", "Valid modes are 'dev', 'prod', or '' for auto-detect. dev means localhost development, not be confused with staging on go-dashboard-dev, which is still the 'prod' mode.")
+
+	quotas = gomote.RegisterQuotaFlags(flag.CommandLine)
+
+	registrationFile = flag.String("registration_file", "", "If non-empty, the file in which to persist the registrations of swarming buildlets, so that they can reconnect to their instances after a restart. Otherwise, they are only kept in memory.")
 )
 
 var Version string // set by linker -X
@@ -82,11 +87,26 @@ func main() {
 		opts = append(opts, grpc.StreamInterceptor(access.RequireIAPAuthStreamInterceptor(access.IAPSkipAudienceValidation)))
 	}
 	grpcServer := grpc.NewServer(opts...)
-	rdv := rendezvous.New(ctx)
+	// The gomote server restores the sessions of buildlets that
+	// reconnect, but needs the rendezvous to be created.
+	var gomoteServer *gomote.SwarmingServer
+	rdvOpts := []rendezvous.Option{rendezvous.OptionReconnect(func(ctx context.Context, reg *rendezvous.Registration, bc buildlet.Client) (buildlet.Client, error) {
+		return gomoteServer.ReconnectInstance(ctx, reg, bc)
+	})}
+	if *registrationFile != "" {
+		store, err := rendezvous.NewFileStore(*registrationFile)
+		if err != nil {
+			log.Fatalf("unable to open registration store: %s", err)
+		}
+		rdvOpts = append(rdvOpts, rendezvous.OptionStore(store))
+	}
+	rdv := rendezvous.New(ctx, rdvOpts...)
+	sp.SetOnDestroy(func(name string) { rdv.DeregisterSession(ctx, name) })
 	gomoteServer, err := gomote.NewSwarming(sp, sshCA, gomoteBucket, mustStorageClient(), rdv, mustSwarmingClient(ctx), mustBuildersClient(ctx))
 	if err != nil {
 		log.Fatalf("unable to create gomote server: %s", err)
 	}
+	gomoteServer.SetQuotas(*quotas)
 	gomotepb.RegisterGomoteServiceServer(grpcServer, gomoteServer)
 
 	mux := http.NewServeMux()
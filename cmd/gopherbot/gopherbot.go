@@ -60,7 +60,12 @@ var (
 	// token file with a colon in between the email and password.
 	gerritTokenFile = flag.String("gerrit-token-file", filepath.Join(os.Getenv("HOME"), "keys", "gerrit-gobot"), `File to load Gerrit token from. File should be of form <git-email>:<token>`)
 
-	onlyRun = flag.String("only-run", "", "if non-empty, the name of a task to run. Mostly for debugging, but tasks (like 'kicktrain') may choose to only run in explicit mode")
+	onlyRun = flag.String("only-run", "", "if non-empty, the name of a task to run, or a comma-separated list of task names. Mostly for debugging, but tasks (like 'kicktrain') may choose to only run in explicit mode")
+
+	replayDir    = flag.String("replay-dir", "", `if non-empty, run tasks once against a corpus loaded from the maintner mutation logs (*.mutlog) in this directory, and report the GitHub and Gerrit actions that would have been taken instead of performing them. The value "godata" means the local cache of maintner.golang.org's log.`)
+	replayOffset = flag.Int64("replay-offset", 0, "in replay mode, if positive, the byte offset into the concatenated mutation logs at which to stop loading the corpus")
+	replayTime   = flag.String("replay-time", "", "in replay mode, the RFC 3339 time that tasks treat as the current time; if empty, the time of the most recent issue or CL update in the corpus")
+	replayOut    = flag.String("replay-out", "", "in replay mode, if non-empty, the file to write the recorded actions to as JSON")
 )
 
 func init() {
@@ -238,27 +243,9 @@ func main() {
 	}
 	ctx := context.Background()
 
-	ghV3, ghV4, err := getGitHubClients(ctx, sc)
-	if err != nil {
-		log.Fatal(err)
-	}
-	gerrit, err := getGerritClient(ctx, sc)
-	if err != nil {
-		log.Fatal(err)
-	}
-	mc, err := getMaintnerClient(ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	var goRepo = maintner.GitHubRepoID{Owner: "golang", Repo: "go"}
 	var vscode = maintner.GitHubRepoID{Owner: "golang", Repo: "vscode-go"}
 	bot := &gopherbot{
-		ghc:    ghV3,
-		ghV4:   ghV4,
-		gerrit: gerrit,
-		mc:     mc,
-		is:     ghV3.Issues,
 		deletedChanges: map[gerritChange]bool{
 			{"crypto", 35958}:  true,
 			{"scratch", 71730}: true,
@@ -384,6 +371,32 @@ func main() {
 	for n := int32(55359); n <= 55828; n++ {
 		bot.deletedIssues[githubIssue{goRepo, n}] = true
 	}
+
+	mc, err := getMaintnerClient(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	bot.mc = mc
+
+	if *replayDir != "" {
+		if err := bot.replay(ctx); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	ghV3, ghV4, err := getGitHubClients(ctx, sc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	gerrit, err := getGerritClient(ctx, sc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	bot.ghV4 = ghV4
+	bot.gerrit = gerrit
+	bot.is = ghV3.Issues
+	bot.issues = ghV3.Issues
 	bot.initCorpus()
 
 	for {
@@ -425,13 +438,23 @@ func main() {
 }
 
 type gopherbot struct {
-	ghc    *github.Client
 	ghV4   *githubv4.Client
-	gerrit *gerrit.Client
+	gerrit gerritService
 	mc     apipb.MaintnerServiceClient
 	corpus *maintner.Corpus
 	gorepo *maintner.GitHubRepo
 	is     issuesService
+	issues githubIssuesService
+
+	// clock, if non-nil, is used in place of time.Now when
+	// deciding whether issues and CLs are old enough to act on.
+	// It is set in replay mode so tasks see the corpus as of its
+	// last mutation.
+	clock func() time.Time
+
+	// rec, if non-nil, records the actions that gopherbot would
+	// have taken instead of performing them. See replay.go.
+	rec *recorder
 
 	knownContributors map[string]bool
 
@@ -529,9 +552,12 @@ func (b *gopherbot) initCorpus() {
 func (b *gopherbot) doTasks(ctx context.Context) []error {
 	var errs []error
 	for _, task := range tasks {
-		if *onlyRun != "" && task.name != *onlyRun {
+		if *onlyRun != "" && !slices.Contains(strings.Split(*onlyRun, ","), task.name) {
 			continue
 		}
+		if b.rec != nil {
+			b.rec.setTask(task.name)
+		}
 		err := task.fn(b, ctx)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("%s: %v", task.name, err))
@@ -547,6 +573,46 @@ type issuesService interface {
 	RemoveLabelForIssue(ctx context.Context, owner string, repo string, number int, label string) (*github.Response, error)
 }
 
+// githubIssuesService represents the remaining portions of github.IssuesService
+// that gopherbot uses. It is overridden by a recorder in replay mode.
+type githubIssuesService interface {
+	Get(ctx context.Context, owner string, repo string, number int) (*github.Issue, *github.Response, error)
+	ListByRepo(ctx context.Context, owner string, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
+	ListComments(ctx context.Context, owner string, repo string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error)
+	Create(ctx context.Context, owner string, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+	Edit(ctx context.Context, owner string, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+	CreateComment(ctx context.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	Lock(ctx context.Context, owner string, repo string, number int, opts *github.LockIssueOptions) (*github.Response, error)
+}
+
+// gerritService represents portions of gerrit.Client that gopherbot uses.
+// It is overridden by a recorder in replay mode.
+type gerritService interface {
+	QueryChanges(ctx context.Context, q string, opts ...gerrit.QueryChangesOpt) ([]*gerrit.ChangeInfo, error)
+	GetChange(ctx context.Context, changeID string, opts ...gerrit.QueryChangesOpt) (*gerrit.ChangeInfo, error)
+	GetChangeDetail(ctx context.Context, changeID string, opts ...gerrit.QueryChangesOpt) (*gerrit.ChangeInfo, error)
+	ListFiles(ctx context.Context, changeID, revision string) (map[string]*gerrit.FileInfo, error)
+	ListReviewers(ctx context.Context, changeID string) ([]gerrit.ReviewerInfo, error)
+	GetGroupMembers(ctx context.Context, groupID string) ([]gerrit.AccountInfo, error)
+	GetMergeable(ctx context.Context, changeID, revision string) (gerrit.MergeableInfo, error)
+	GetRevisionActions(ctx context.Context, changeID, revision string) (map[string]*gerrit.ActionInfo, error)
+	GetRelatedChanges(ctx context.Context, changeID, revision string) (*gerrit.RelatedChangesInfo, error)
+	SetReview(ctx context.Context, changeID, revision string, review gerrit.ReviewInput) error
+	AddHashtags(ctx context.Context, changeID string, tags ...string) ([]string, error)
+	RemoveHashtags(ctx context.Context, changeID string, tags ...string) ([]string, error)
+	DeleteTopic(ctx context.Context, changeID string) error
+	AbandonChange(ctx context.Context, changeID string, message ...string) error
+	SubmitChange(ctx context.Context, changeID string) (gerrit.ChangeInfo, error)
+}
+
+// now returns the current time, or the replay time in replay mode.
+func (b *gopherbot) now() time.Time {
+	if b.clock != nil {
+		return b.clock()
+	}
+	return time.Now()
+}
+
 func (b *gopherbot) addLabel(ctx context.Context, repoID maintner.GitHubRepoID, gi *maintner.GitHubIssue, label string) error {
 	return b.addLabels(ctx, repoID, gi, []string{label})
 }
@@ -646,7 +712,7 @@ func (b *gopherbot) setMilestone(ctx context.Context, repoID maintner.GitHubRepo
 	if *dryRun {
 		return nil
 	}
-	_, resp, err := b.ghc.Issues.Edit(ctx, repoID.Owner, repoID.Repo, int(gi.Number), &github.IssueRequest{
+	_, resp, err := b.issues.Edit(ctx, repoID.Owner, repoID.Repo, int(gi.Number), &github.IssueRequest{
 		Milestone: github.Int(m.Number),
 	})
 	if err != nil && resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone) {
@@ -683,7 +749,7 @@ func (b *gopherbot) addGitHubComment(ctx context.Context, repo *maintner.GitHubR
 	if !since.IsZero() {
 		opt.Since = &since
 	}
-	ics, resp, err := b.ghc.Issues.ListComments(ctx, repo.ID().Owner, repo.ID().Repo, int(issueNum), opt)
+	ics, resp, err := b.issues.ListComments(ctx, repo.ID().Owner, repo.ID().Repo, int(issueNum), opt)
 	if err != nil {
 		// TODO(golang/go#40640) - This issue was transferred or otherwise is gone. We should permanently skip it. This
 		// is a temporary fix to keep gopherbot working.
@@ -704,7 +770,7 @@ func (b *gopherbot) addGitHubComment(ctx context.Context, repo *maintner.GitHubR
 		log.Printf("[dry-run] would add comment to github.com/%s/issues/%d: %v", repo.ID(), issueNum, msg)
 		return nil
 	}
-	_, resp, createError := b.ghc.Issues.CreateComment(ctx, repo.ID().Owner, repo.ID().Repo, int(issueNum), &github.IssueComment{
+	_, resp, createError := b.issues.CreateComment(ctx, repo.ID().Owner, repo.ID().Repo, int(issueNum), &github.IssueComment{
 		Body: github.String(msg),
 	})
 	if createError != nil && resp != nil && resp.StatusCode == http.StatusUnprocessableEntity {
@@ -713,7 +779,7 @@ func (b *gopherbot) addGitHubComment(ctx context.Context, repo *maintner.GitHubR
 		// empty list, so the error check from ListComments doesn't catch it. (The deleted
 		// issue 55403 is an example of such a case.) So check again with the Get endpoint,
 		// which seems to return 404 more reliably in such cases at least as of 2022-10-11.
-		if _, resp, err := b.ghc.Issues.Get(ctx, repo.ID().Owner, repo.ID().Repo, int(issueNum)); err != nil &&
+		if _, resp, err := b.issues.Get(ctx, repo.ID().Owner, repo.ID().Repo, int(issueNum)); err != nil &&
 			resp != nil && resp.StatusCode == http.StatusNotFound {
 			log.Printf("addGitHubComment: Issue %v#%v returned a 404 after posting comment failed with 422. Skipping. See go.dev/issue/30184.", repo.ID(), issueNum)
 			b.deletedIssues[githubIssue{repo.ID(), issueNum}] = true
@@ -741,7 +807,7 @@ func (b *gopherbot) createGitHubIssue(ctx context.Context, title, msg string, la
 		return dup, nil
 	}
 	// See if there is a dup issue from when gopherbot last got its data from maintner.
-	is, _, err := b.ghc.Issues.ListByRepo(ctx, "golang", "go", &github.IssueListByRepoOptions{
+	is, _, err := b.issues.ListByRepo(ctx, "golang", "go", &github.IssueListByRepoOptions{
 		State:       "all",
 		ListOptions: github.ListOptions{PerPage: 100},
 		Since:       baseEvent,
@@ -759,7 +825,7 @@ func (b *gopherbot) createGitHubIssue(ctx context.Context, title, msg string, la
 		log.Printf("[dry-run] would create issue with title %s and labels %v\n%s", title, labels, msg)
 		return 4242, nil
 	}
-	i, _, err := b.ghc.Issues.Create(ctx, "golang", "go", &github.IssueRequest{
+	i, _, err := b.issues.Create(ctx, "golang", "go", &github.IssueRequest{
 		Title:  github.String(title),
 		Body:   github.String(msg),
 		Labels: &labels,
@@ -787,7 +853,7 @@ func (b *gopherbot) closeGitHubIssue(ctx context.Context, repoID maintner.GitHub
 		log.Printf("[dry-run] would close go.dev/issue/%v%s", number, suffix)
 		return nil
 	}
-	_, _, err := b.ghc.Issues.Edit(ctx, repoID.Owner, repoID.Repo, int(number), &github.IssueRequest{
+	_, _, err := b.issues.Edit(ctx, repoID.Owner, repoID.Repo, int(number), &github.IssueRequest{
 		State:       github.String("closed"),
 		StateReason: reason,
 	})
@@ -930,7 +996,7 @@ func (b *gopherbot) getOffKickTrain(ctx context.Context) error {
 // causes the updated time to bump, which means the bot wouldn't try to lock it
 // again for another year.
 func (b *gopherbot) freezeOldIssues(ctx context.Context) error {
-	tooOld := time.Now().Add(-365 * 24 * time.Hour)
+	tooOld := b.now().Add(-365 * 24 * time.Hour)
 	return b.corpus.GitHub().ForeachRepo(func(repo *maintner.GitHubRepo) error {
 		if !gardenIssues(repo) {
 			return nil
@@ -946,7 +1012,7 @@ func (b *gopherbot) freezeOldIssues(ctx context.Context) error {
 			if *dryRun {
 				return nil
 			}
-			_, err := b.ghc.Issues.Lock(ctx, repo.ID().Owner, repo.ID().Repo, int(gi.Number), nil)
+			_, err := b.issues.Lock(ctx, repo.ID().Owner, repo.ID().Repo, int(gi.Number), nil)
 			if ge, ok := err.(*github.ErrorResponse); ok && ge.Response.StatusCode == http.StatusNotFound {
 				// An issue can become 404 on GitHub due to being deleted or transferred. See go.dev/issue/30182.
 				b.deletedIssues[githubIssue{repo.ID(), gi.Number}] = true
@@ -1088,7 +1154,7 @@ func (b *gopherbot) setVSCodeGoMilestones(ctx context.Context) error {
 			return nil
 		}
 		// Work-around golang/go#40640 by only milestoning new issues.
-		if time.Since(gi.Created) > 24*time.Hour {
+		if b.now().Sub(gi.Created) > 24*time.Hour {
 			return nil
 		}
 		return b.setMilestone(ctx, vscode.ID(), gi, vscodeUntriaged)
@@ -1251,7 +1317,7 @@ func (b *gopherbot) handleTelemetryIssues(ctx context.Context) error {
 
 func (b *gopherbot) closeStaleWaitingForInfo(ctx context.Context) error {
 	const waitingForInfo = "WaitingForInfo"
-	now := time.Now()
+	now := b.now()
 	return b.corpus.GitHub().ForeachRepo(func(repo *maintner.GitHubRepo) error {
 		if !gardenIssues(repo) {
 			return nil
@@ -1325,7 +1391,7 @@ func (b *gopherbot) closeStaleWaitingForInfo(ctx context.Context) error {
 // cl2issue writes "Change https://go.dev/cl/NNNN mentions this issue"
 // and the change summary on GitHub when a new Gerrit change references a GitHub issue.
 func (b *gopherbot) cl2issue(ctx context.Context) error {
-	monthAgo := time.Now().Add(-30 * 24 * time.Hour)
+	monthAgo := b.now().Add(-30 * 24 * time.Hour)
 	return b.corpus.Gerrit().ForeachProjectUnsorted(func(gp *maintner.GerritProject) error {
 		if gp.Server() != "go.googlesource.com" {
 			return nil
@@ -1670,6 +1736,10 @@ func (b *gopherbot) topicToHashtag(ctx context.Context) error {
 // upstream data and, if so, returns f(). If it's out of date, it does
 // nothing more and returns nil.
 func (b *gopherbot) onLatestCL(ctx context.Context, cl *maintner.GerritCL, f func() error) error {
+	if b.rec != nil {
+		// In replay mode, the corpus is the only view of Gerrit there is.
+		return f()
+	}
 	ci, err := b.gerrit.GetChangeDetail(ctx, fmt.Sprint(cl.Number), gerrit.QueryChangesOpt{Fields: []string{"MESSAGES"}})
 	if err != nil {
 		return err
@@ -1873,7 +1943,7 @@ func (b *gopherbot) closeCherryPickIssues(ctx context.Context) error {
 		cherryPickIssues[gi.Number] = gi
 		return nil
 	})
-	monthAgo := time.Now().Add(-30 * 24 * time.Hour)
+	monthAgo := b.now().Add(-30 * 24 * time.Hour)
 	return b.corpus.Gerrit().ForeachProjectUnsorted(func(gp *maintner.GerritProject) error {
 		if gp.Server() != "go.googlesource.com" {
 			return nil
@@ -1920,7 +1990,7 @@ func (b *gopherbot) closeLUCIConfigIssues(ctx context.Context) error {
 	if buildProject == nil {
 		return fmt.Errorf("no go.googlesource.com/build Gerrit project in corpus")
 	}
-	monthAgo := time.Now().Add(-30 * 24 * time.Hour)
+	monthAgo := b.now().Add(-30 * 24 * time.Hour)
 	return buildProject.ForeachCLUnsorted(func(cl *maintner.GerritCL) error {
 		if cl.Commit.CommitTime.Before(monthAgo) {
 			// If the CL was last updated over a month ago, assume (as an
@@ -2172,7 +2242,7 @@ func (b *gopherbot) assignReviewersToCLs(ctx context.Context) error {
 			return nil
 		}
 		return gp.ForeachOpenCL(func(cl *maintner.GerritCL) error {
-			if cl.Private || cl.WorkInProgress() || time.Since(cl.Created) < 10*time.Minute {
+			if cl.Private || cl.WorkInProgress() || b.now().Sub(cl.Created) < 10*time.Minute {
 				return nil
 			}
 			if assignReviewersOptOut[cl.Owner().Email()] {
@@ -2314,7 +2384,7 @@ func (b *gopherbot) abandonScratchReviews(ctx context.Context) error {
 	if scratchProject == nil {
 		return fmt.Errorf("no go.googlesource.com/scratch Gerrit project in corpus")
 	}
-	tooOld := time.Now().Add(-24 * time.Hour * 7)
+	tooOld := b.now().Add(-24 * time.Hour * 7)
 	return scratchProject.ForeachOpenCL(func(cl *maintner.GerritCL) error {
 		if b.deletedChanges[gerritChange{scratchProject.Project(), cl.Number}] || !cl.Meta.Commit.CommitTime.Before(tooOld) {
 			return nil
@@ -2354,7 +2424,7 @@ func (b *gopherbot) whoNeedsAccess(ctx context.Context) error {
 		level[ai.NumericID] = 2
 	}
 
-	quarterAgo := time.Now().Add(-90 * 24 * time.Hour)
+	quarterAgo := b.now().Add(-90 * 24 * time.Hour)
 	missing := map[string]int{} // "only level N: $WHO" -> number of CLs for that user
 	err = b.corpus.Gerrit().ForeachProjectUnsorted(func(gp *maintner.GerritProject) error {
 		if gp.Server() != "go.googlesource.com" {
@@ -0,0 +1,411 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/go-github/v48/github"
+	"golang.org/x/build/gerrit"
+	"golang.org/x/build/maintner"
+	"golang.org/x/build/maintner/godata"
+	"golang.org/x/build/maintner/maintpb"
+	"golang.org/x/build/maintner/reclog"
+	"google.golang.org/protobuf/proto"
+)
+
+// replay runs the selected tasks once against a corpus loaded from the
+// mutation logs in *replayDir, up to *replayOffset, and reports the
+// GitHub and Gerrit actions that they would have taken.
+//
+// Writes go to a recorder instead of GitHub and Gerrit. Reads that
+// would normally consult GitHub or Gerrit to double-check the corpus
+// return nothing, so the decisions reflect only what the corpus knew
+// at that point in the log.
+func (b *gopherbot) replay(ctx context.Context) error {
+	if *dryRun {
+		return errors.New("-replay-dir and -dry-run are mutually exclusive")
+	}
+	dir := *replayDir
+	if dir == "godata" {
+		dir = godata.Dir()
+	}
+	corpus := new(maintner.Corpus)
+	if err := corpus.Initialize(ctx, &replaySource{dir: dir, limit: *replayOffset}); err != nil {
+		return err
+	}
+	repo := corpus.GitHub().Repo("golang", "go")
+	if repo == nil {
+		return errors.New("failed to find Go repo in corpus")
+	}
+	b.corpus = corpus
+	b.gorepo = repo
+
+	now := corpusTime(corpus)
+	if *replayTime != "" {
+		t, err := time.Parse(time.RFC3339, *replayTime)
+		if err != nil {
+			return fmt.Errorf("bad -replay-time: %v", err)
+		}
+		now = t
+	}
+	if now.IsZero() {
+		return errors.New("corpus has no issues or CLs; can't determine replay time")
+	}
+	log.Printf("replaying gopherbot tasks as of %v", now.Format(time.RFC3339))
+	b.clock = func() time.Time { return now }
+
+	b.rec = new(recorder)
+	b.is = recordingGitHub{b.rec}
+	b.issues = recordingGitHub{b.rec}
+	b.gerrit = recordingGerrit{b.rec}
+
+	for _, err := range b.doTasks(ctx) {
+		log.Print(err)
+	}
+
+	b.rec.writeSummary(os.Stdout)
+	if *replayOut != "" {
+		data, err := json.MarshalIndent(b.rec.actions, "", "\t")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*replayOut, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// corpusTime returns the time of the most recent GitHub issue or
+// Gerrit CL update in the corpus.
+func corpusTime(c *maintner.Corpus) time.Time {
+	var t time.Time
+	c.GitHub().ForeachRepo(func(repo *maintner.GitHubRepo) error {
+		return repo.ForeachIssue(func(gi *maintner.GitHubIssue) error {
+			if gi.Updated.After(t) {
+				t = gi.Updated
+			}
+			return nil
+		})
+	})
+	c.Gerrit().ForeachProjectUnsorted(func(gp *maintner.GerritProject) error {
+		return gp.ForeachCLUnsorted(func(cl *maintner.GerritCL) error {
+			if ct := cl.Meta.Commit.CommitTime; ct.After(t) {
+				t = ct
+			}
+			return nil
+		})
+	})
+	return t
+}
+
+// replaySource is a maintner.MutationSource that reads the mutation
+// logs in a directory, in lexical order of their file names, stopping
+// at a byte offset into their concatenation.
+//
+// Both maintner.DiskMutationLogger directories and the network
+// mutation source's cache directory (godata.Dir) can be read.
+type replaySource struct {
+	dir   string
+	limit int64 // if positive, stop at the first record at or past this offset
+
+	mu   sync.Mutex
+	done bool // true after first GetMutations
+}
+
+func (s *replaySource) GetMutations(ctx context.Context) <-chan maintner.MutationStreamEvent {
+	s.mu.Lock()
+	wasDone := s.done
+	s.done = true
+	s.mu.Unlock()
+	if wasDone {
+		// A replayed corpus is never updated.
+		return nil
+	}
+
+	ch := make(chan maintner.MutationStreamEvent, 50)
+	go func() {
+		err := s.foreachMutation(func(m *maintpb.Mutation) error {
+			select {
+			case ch <- maintner.MutationStreamEvent{Mutation: m}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		final := maintner.MutationStreamEvent{Err: err}
+		if err == nil {
+			final.End = true
+		}
+		select {
+		case ch <- final:
+		case <-ctx.Done():
+		}
+	}()
+	return ch
+}
+
+var errReachedLimit = errors.New("reached replay offset")
+
+func (s *replaySource) foreachMutation(fn func(*maintpb.Mutation) error) error {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.mutlog"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no mutation logs found in %s", s.dir)
+	}
+	sort.Strings(files)
+	var base int64
+	for _, file := range files {
+		size, err := foreachFileMutation(file, func(off int64, m *maintpb.Mutation) error {
+			if s.limit > 0 && base+off >= s.limit {
+				return errReachedLimit
+			}
+			return fn(m)
+		})
+		if err == errReachedLimit {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error in %s: %v", file, err)
+		}
+		base += size
+	}
+	return nil
+}
+
+// foreachFileMutation calls fn for each mutation in the named log file
+// along with its offset in the file, and returns the file's size.
+func foreachFileMutation(file string, fn func(off int64, m *maintpb.Mutation) error) (size int64, err error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	err = reclog.ForeachRecord(f, 0, func(off int64, hdr, rec []byte) error {
+		m := new(maintpb.Mutation)
+		if err := proto.Unmarshal(rec, m); err != nil {
+			return err
+		}
+		return fn(off, m)
+	})
+	return fi.Size(), err
+}
+
+// An action is a GitHub or Gerrit write recorded in replay mode.
+type action struct {
+	Task   string // name of the gopherbot task
+	Kind   string // the API method, such as "AddLabelsToIssue" or "SetReview"
+	Target string // the issue or CL, such as "golang/go#123" or "go.dev/cl/456"
+	Detail string `json:",omitempty"`
+}
+
+// A recorder records the actions gopherbot would have taken.
+type recorder struct {
+	mu      sync.Mutex
+	task    string
+	actions []action
+}
+
+func (r *recorder) setTask(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.task = name
+}
+
+func (r *recorder) record(kind, target, detail string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.actions = append(r.actions, action{Task: r.task, Kind: kind, Target: target, Detail: detail})
+}
+
+// writeSummary writes the recorded actions, grouped by task,
+// followed by a count of actions per task.
+func (r *recorder) writeSummary(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[string]int)
+	var order []string
+	for _, a := range r.actions {
+		if counts[a.Task] == 0 {
+			order = append(order, a.Task)
+			fmt.Fprintf(w, "%s:\n", a.Task)
+		}
+		counts[a.Task]++
+		fmt.Fprintf(w, "\t%s %s", a.Kind, a.Target)
+		if a.Detail != "" {
+			fmt.Fprintf(w, " %s", strings.Join(strings.Fields(a.Detail), " "))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "\n%d actions:\n", len(r.actions))
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, task := range order {
+		fmt.Fprintf(tw, "\t%s\t%d\n", task, counts[task])
+	}
+	tw.Flush()
+}
+
+// recordingGitHub implements issuesService and githubIssuesService
+// by recording writes and returning empty results for reads.
+type recordingGitHub struct{ r *recorder }
+
+func issueTarget(owner, repo string, number int) string {
+	return fmt.Sprintf("%s/%s#%d", owner, repo, number)
+}
+
+func (g recordingGitHub) ListLabelsByIssue(ctx context.Context, owner string, repo string, number int, opt *github.ListOptions) ([]*github.Label, *github.Response, error) {
+	return nil, nil, nil
+}
+
+func (g recordingGitHub) AddLabelsToIssue(ctx context.Context, owner string, repo string, number int, labels []string) ([]*github.Label, *github.Response, error) {
+	g.r.record("AddLabelsToIssue", issueTarget(owner, repo, number), fmt.Sprintf("%q", labels))
+	return nil, nil, nil
+}
+
+func (g recordingGitHub) RemoveLabelForIssue(ctx context.Context, owner string, repo string, number int, label string) (*github.Response, error) {
+	g.r.record("RemoveLabelForIssue", issueTarget(owner, repo, number), fmt.Sprintf("%q", label))
+	return nil, nil
+}
+
+func (g recordingGitHub) Get(ctx context.Context, owner string, repo string, number int) (*github.Issue, *github.Response, error) {
+	return &github.Issue{Number: github.Int(number)}, nil, nil
+}
+
+func (g recordingGitHub) ListByRepo(ctx context.Context, owner string, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+	return nil, nil, nil
+}
+
+func (g recordingGitHub) ListComments(ctx context.Context, owner string, repo string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+	return nil, nil, nil
+}
+
+func (g recordingGitHub) Create(ctx context.Context, owner string, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	g.r.record("Create", owner+"/"+repo, fmt.Sprintf("%q labels=%q", issue.GetTitle(), issue.GetLabels()))
+	return &github.Issue{}, nil, nil
+}
+
+func (g recordingGitHub) Edit(ctx context.Context, owner string, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	var detail []string
+	if issue.State != nil {
+		detail = append(detail, "state="+issue.GetState())
+	}
+	if issue.StateReason != nil {
+		detail = append(detail, "reason="+issue.GetStateReason())
+	}
+	if issue.Milestone != nil {
+		detail = append(detail, fmt.Sprintf("milestone=%d", issue.GetMilestone()))
+	}
+	g.r.record("Edit", issueTarget(owner, repo, number), strings.Join(detail, " "))
+	return &github.Issue{Number: github.Int(number)}, nil, nil
+}
+
+func (g recordingGitHub) CreateComment(ctx context.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	g.r.record("CreateComment", issueTarget(owner, repo, number), fmt.Sprintf("%q", comment.GetBody()))
+	return comment, nil, nil
+}
+
+func (g recordingGitHub) Lock(ctx context.Context, owner string, repo string, number int, opts *github.LockIssueOptions) (*github.Response, error) {
+	g.r.record("Lock", issueTarget(owner, repo, number), "")
+	return nil, nil
+}
+
+// recordingGerrit implements gerritService by recording writes and
+// returning empty results for reads.
+type recordingGerrit struct{ r *recorder }
+
+func changeTarget(changeID string) string {
+	return "go.dev/cl/" + changeID
+}
+
+func (g recordingGerrit) QueryChanges(ctx context.Context, q string, opts ...gerrit.QueryChangesOpt) ([]*gerrit.ChangeInfo, error) {
+	return nil, nil
+}
+
+func (g recordingGerrit) GetChange(ctx context.Context, changeID string, opts ...gerrit.QueryChangesOpt) (*gerrit.ChangeInfo, error) {
+	return new(gerrit.ChangeInfo), nil
+}
+
+func (g recordingGerrit) GetChangeDetail(ctx context.Context, changeID string, opts ...gerrit.QueryChangesOpt) (*gerrit.ChangeInfo, error) {
+	return new(gerrit.ChangeInfo), nil
+}
+
+func (g recordingGerrit) ListFiles(ctx context.Context, changeID, revision string) (map[string]*gerrit.FileInfo, error) {
+	return nil, nil
+}
+
+func (g recordingGerrit) ListReviewers(ctx context.Context, changeID string) ([]gerrit.ReviewerInfo, error) {
+	return nil, nil
+}
+
+func (g recordingGerrit) GetGroupMembers(ctx context.Context, groupID string) ([]gerrit.AccountInfo, error) {
+	return nil, nil
+}
+
+func (g recordingGerrit) GetMergeable(ctx context.Context, changeID, revision string) (gerrit.MergeableInfo, error) {
+	return gerrit.MergeableInfo{}, nil
+}
+
+func (g recordingGerrit) GetRevisionActions(ctx context.Context, changeID, revision string) (map[string]*gerrit.ActionInfo, error) {
+	return nil, nil
+}
+
+func (g recordingGerrit) GetRelatedChanges(ctx context.Context, changeID, revision string) (*gerrit.RelatedChangesInfo, error) {
+	return new(gerrit.RelatedChangesInfo), nil
+}
+
+func (g recordingGerrit) SetReview(ctx context.Context, changeID, revision string, review gerrit.ReviewInput) error {
+	var detail []string
+	if review.Message != "" {
+		detail = append(detail, fmt.Sprintf("message=%q", review.Message))
+	}
+	for _, r := range review.Reviewers {
+		detail = append(detail, "reviewer="+r.Reviewer)
+	}
+	g.r.record("SetReview", changeTarget(changeID), strings.Join(detail, " "))
+	return nil
+}
+
+func (g recordingGerrit) AddHashtags(ctx context.Context, changeID string, tags ...string) ([]string, error) {
+	g.r.record("AddHashtags", changeTarget(changeID), fmt.Sprintf("%q", tags))
+	return tags, nil
+}
+
+func (g recordingGerrit) RemoveHashtags(ctx context.Context, changeID string, tags ...string) ([]string, error) {
+	g.r.record("RemoveHashtags", changeTarget(changeID), fmt.Sprintf("%q", tags))
+	return nil, nil
+}
+
+func (g recordingGerrit) DeleteTopic(ctx context.Context, changeID string) error {
+	g.r.record("DeleteTopic", changeTarget(changeID), "")
+	return nil
+}
+
+func (g recordingGerrit) AbandonChange(ctx context.Context, changeID string, message ...string) error {
+	g.r.record("AbandonChange", changeTarget(changeID), fmt.Sprintf("%q", message))
+	return nil
+}
+
+func (g recordingGerrit) SubmitChange(ctx context.Context, changeID string) (gerrit.ChangeInfo, error) {
+	g.r.record("SubmitChange", changeTarget(changeID), "")
+	return gerrit.ChangeInfo{}, nil
+}
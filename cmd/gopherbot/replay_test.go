@@ -0,0 +1,88 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v48/github"
+	"golang.org/x/build/maintner/maintpb"
+	"golang.org/x/build/maintner/reclog"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReplaySourceLimit(t *testing.T) {
+	dir := t.TempDir()
+	var offsets []int64 // offset of each record in the concatenated logs
+	var base int64
+	for i, file := range []string{"maintner-2024-01-01.mutlog", "maintner-2024-01-02.mutlog"} {
+		path := filepath.Join(dir, file)
+		var size int64
+		for j := 0; j < 2; j++ {
+			offsets = append(offsets, base+size)
+			data, err := proto.Marshal(&maintpb.Mutation{Github: &maintpb.GithubMutation{Owner: "golang", Repo: "go", Labels: []*maintpb.GithubLabel{{Id: int64(i*2 + j)}}}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := reclog.AppendRecordToFile(path, data); err != nil {
+				t.Fatal(err)
+			}
+			fi, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			size = fi.Size()
+		}
+		base += size
+	}
+
+	for _, tc := range []struct {
+		limit int64
+		want  int
+	}{
+		{0, 4},
+		{offsets[1], 1},
+		{offsets[1] + 1, 2},
+		{offsets[2], 2},
+		{offsets[3] + 1, 4},
+	} {
+		src := &replaySource{dir: dir, limit: tc.limit}
+		var got int
+		if err := src.foreachMutation(func(*maintpb.Mutation) error {
+			got++
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("limit %d: got %d mutations, want %d", tc.limit, got, tc.want)
+		}
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	rec := new(recorder)
+	b := &gopherbot{rec: rec, is: recordingGitHub{rec}, issues: recordingGitHub{rec}, gerrit: recordingGerrit{rec}}
+	ctx := context.Background()
+
+	rec.setTask("a")
+	b.issues.Lock(ctx, "golang", "go", 1, nil)
+	rec.setTask("b")
+	b.issues.Edit(ctx, "golang", "go", 2, &github.IssueRequest{Milestone: github.Int(6)})
+	b.gerrit.AbandonChange(ctx, "3", "too old")
+
+	want := []action{
+		{Task: "a", Kind: "Lock", Target: "golang/go#1"},
+		{Task: "b", Kind: "Edit", Target: "golang/go#2", Detail: "milestone=6"},
+		{Task: "b", Kind: "AbandonChange", Target: "go.dev/cl/3", Detail: `["too old"]`},
+	}
+	if diff := cmp.Diff(want, rec.actions); diff != "" {
+		t.Errorf("recorded actions differ: (-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+
+// The schedsim command replays a recorded workload of buildlet requests
+// against the coordinator's scheduler and quotas on a virtual clock, and
+// reports how long the requests waited and how much quota they used.
+//
+// Usage:
+//
+//	schedsim [-policy=policy.json] workload.json...
+//
+// The workload is the JSON encoding of a simulate.Workload, and the
+// policy is in the format of the coordinator's -sched_policy flag.
+// Each workload is simulated separately.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"golang.org/x/build/internal/coordinator/pool/queue"
+	"golang.org/x/build/internal/coordinator/simulate"
+)
+
+var policyFile = flag.String("policy", "", "If non-empty, the path of a JSON file with the scheduling policy to simulate, as for the coordinator's -sched_policy flag. The default is the coordinator's default policy.")
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("schedsim: ")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: schedsim [-policy=policy.json] workload.json...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	policy := queue.DefaultPolicy()
+	if *policyFile != "" {
+		data, err := os.ReadFile(*policyFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if policy, err = queue.ParsePolicy(data); err != nil {
+			log.Fatal(err)
+		}
+	}
+	for i, file := range flag.Args() {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		w, err := simulate.ParseWorkload(data)
+		if err != nil {
+			log.Fatalf("%s: %v", file, err)
+		}
+		report, err := simulate.Run(w, policy)
+		if err != nil {
+			log.Fatalf("%s: %v", file, err)
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		if flag.NArg() > 1 {
+			fmt.Printf("%s:\n", file)
+		}
+		if err := report.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 
 	"golang.org/x/build/internal/https"
 )
@@ -20,6 +21,7 @@ var (
 	staticDir   = flag.String("static-dir", "./static/", "location of static directory relative to binary location")
 	templateDir = flag.String("template-dir", "./templates/", "location of templates directory relative to binary location")
 	reload      = flag.Bool("reload", false, "reload content on each page load")
+	ownersRepos = flag.String("owners-repos", "", "comma-separated list of go.googlesource.com repos whose OWNERS files supplement the built-in owners table")
 )
 
 func init() {
@@ -39,6 +41,9 @@ func main() {
 		log.Fatalf("Could not init corpus: %v", err)
 	}
 	go s.corpusUpdateLoop(ctx)
+	if *ownersRepos != "" {
+		go ownersUpdateLoop(ctx, strings.Split(*ownersRepos, ","))
+	}
 
 	log.Fatalln(https.ListenAndServe(ctx, s))
 }
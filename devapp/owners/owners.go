@@ -75,6 +75,7 @@ type Response struct {
 // directory within that repo and returns the deepest Entry match in the file
 // hierarchy for the given resource.
 func match(path string) *Entry {
+	entries, _ := currentEntries()
 	var deepestPath string
 	for p := range entries {
 		if hasPathPrefix(path, p) && len(p) > len(deepestPath) {
@@ -84,6 +85,14 @@ func match(path string) *Entry {
 	return entries[deepestPath]
 }
 
+// Match returns the deepest Entry in the file hierarchy for path,
+// which consists of the repo name and the full path of a file or
+// directory within that repo. It returns nil if there is no match.
+// The returned Entry must not be modified.
+func Match(path string) *Entry {
+	return match(path)
+}
+
 // hasPathPrefix reports whether the slash-separated path s
 // begins with the elements in prefix.
 //
@@ -132,7 +141,7 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 
 		var resp Response
 		if req.Payload.All {
-			resp.Payload.Entries = entries
+			resp.Payload.Entries, _ = currentEntries()
 		} else {
 			resp.Payload.Entries = make(map[string]*Entry)
 			for _, p := range req.Payload.Paths {
@@ -140,7 +149,7 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 		// resp.Payload.Entries must not be mutated because it contains
-		// references to the global "entries" value.
+		// references to the entries being served.
 
 		var buf bytes.Buffer
 		if err := json.NewEncoder(&buf).Encode(resp); err != nil {
@@ -252,41 +261,54 @@ type ownerData struct {
 func serveIndex(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
-	indexCache.once.Do(func() {
-		paths, err := formatEntries(entries)
-		if err != nil {
-			indexCache.err = err
-			return
-		}
-
-		archOses, err := formatEntries(archOses)
-		if err != nil {
-			indexCache.err = err
-			return
-		}
-
-		displayEntries := ownerData{paths, archOses}
-
-		var buf bytes.Buffer
-		indexCache.err = indexTmpl.Execute(&buf, displayEntries)
-		indexCache.html = buf.Bytes()
-	})
-	if indexCache.err != nil {
-		log.Printf("unable to serve index page HTML: %v", indexCache.err)
+	html, err := indexHTML()
+	if err != nil {
+		log.Printf("unable to serve index page HTML: %v", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
-	w.Write(indexCache.html)
+	w.Write(html)
+}
+
+// indexHTML returns the owners index page HTML for the entries being served.
+func indexHTML() ([]byte, error) {
+	entries, gen := currentEntries()
+	indexCache.Lock()
+	defer indexCache.Unlock()
+	if indexCache.valid && indexCache.gen == gen {
+		return indexCache.html, indexCache.err
+	}
+	indexCache.html, indexCache.err = renderIndex(entries)
+	indexCache.gen, indexCache.valid = gen, true
+	return indexCache.html, indexCache.err
+}
+
+func renderIndex(entries map[string]*Entry) ([]byte, error) {
+	paths, err := formatEntries(entries)
+	if err != nil {
+		return nil, err
+	}
+	archOses, err := formatEntries(archOses)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := indexTmpl.Execute(&buf, ownerData{paths, archOses}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 // indexCache is a cache of the owners index page HTML.
 //
-// As long as the owners are defined at package initialization time
-// and not modified at runtime, the HTML doesn't change per request.
+// The HTML only changes when the entries being served do,
+// which is tracked by their generation.
 var indexCache struct {
-	once sync.Once
-	html []byte // Page HTML rendered by indexTmpl.
-	err  error
+	sync.Mutex
+	valid bool
+	gen   int    // Generation of the entries html was rendered from.
+	html  []byte // Page HTML rendered by indexTmpl.
+	err   error
 }
 
 var indexTmpl = template.Must(template.New("index").Funcs(template.FuncMap{
@@ -340,7 +362,7 @@ body {
 </style>
 <header class="header">
 	<p>Reviews are automatically assigned to primary owners.</p>
-	<p>Alter these entries at
+	<p>Alter these entries in the OWNERS file of a directory, or at
 	<a href="https://go.googlesource.com/build/+/master/devapp/owners"
 		target="_blank" rel="noopener">golang.org/x/build/devapp/owners</a></p>
 </header>
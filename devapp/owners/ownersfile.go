@@ -0,0 +1,279 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package owners
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+)
+
+// ParseOwnersFile parses the contents of an OWNERS file.
+//
+// An OWNERS file lists the owners of the directory that contains it,
+// and of its subdirectories that don't have an OWNERS file of their own.
+// Blank lines and lines starting with '#' are ignored. Other lines
+// have the form
+//
+//	primary: @username @org/team ...
+//	secondary: @username ...
+//
+// and may be repeated. The leading '@' is optional. There must be at least
+// one primary owner, and every owner must be known to the
+// golang.org/x/build/internal/gophers package.
+func ParseOwnersFile(data []byte) (*Entry, error) {
+	e := new(Entry)
+	var errs []error
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; sc.Scan(); lineNum++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			errs = append(errs, fmt.Errorf("line %d: missing colon", lineNum))
+			continue
+		}
+		var list *[]Owner
+		switch strings.TrimSpace(key) {
+		case "primary":
+			list = &e.Primary
+		case "secondary":
+			list = &e.Secondary
+		default:
+			errs = append(errs, fmt.Errorf("line %d: unknown key %q; want primary or secondary", lineNum, key))
+			continue
+		}
+		for _, name := range strings.Fields(value) {
+			o, err := lookupOwner(strings.TrimPrefix(name, "@"))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("line %d: %v", lineNum, err))
+				continue
+			}
+			*list = append(*list, o)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(errs) == 0 && len(e.Primary) == 0 {
+		errs = append(errs, errors.New("no primary owners"))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return e, nil
+}
+
+// gitilesURL is the Gitiles server that LoadRepos reads OWNERS files from.
+var gitilesURL = "https://go.googlesource.com"
+
+// loaded holds what LoadRepos last loaded from each repo, so that
+// it only fetches the OWNERS files that changed.
+var loaded struct {
+	sync.Mutex
+	repos map[string]*repoFiles // repo name -> its OWNERS files
+}
+
+// repoFiles are the OWNERS files of a repo at a commit.
+type repoFiles struct {
+	commit string
+	files  map[string]ownersFile // path -> file
+}
+
+// An ownersFile is a parsed OWNERS file.
+type ownersFile struct {
+	id    string // git blob ID of the contents
+	entry *Entry // nil if the file is invalid
+	err   error
+}
+
+// LoadRepos loads the OWNERS files from the master branch of each
+// of the named repos on go.googlesource.com, and starts serving their
+// entries in place of the built-in table's entries for the same paths.
+//
+// It reads the repos through Gitiles. A repo whose master branch hasn't
+// moved since the last call isn't read again, and only the OWNERS files
+// that changed are fetched.
+//
+// A repo that can't be read keeps the entries it was last loaded with.
+// Invalid OWNERS files are skipped. Both are reported in the returned error.
+func LoadRepos(ctx context.Context, names []string) error {
+	loaded.Lock()
+	defer loaded.Unlock()
+	if loaded.repos == nil {
+		loaded.repos = make(map[string]*repoFiles)
+	}
+	var errs []error
+	for _, name := range names {
+		rf, err := loadRepo(ctx, name, loaded.repos[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+			continue
+		}
+		if rf == loaded.repos[name] {
+			continue // unchanged
+		}
+		loaded.repos[name] = rf
+		m := make(map[string]*Entry)
+		for p, f := range rf.files {
+			if f.err != nil {
+				errs = append(errs, fmt.Errorf("%s/%s: %w", name, p, f.err))
+				continue
+			}
+			key := name
+			if dir := path.Dir(p); dir != "." {
+				key += "/" + dir
+			}
+			m[key] = f.entry
+		}
+		setRepoEntries(name, m)
+	}
+	return errors.Join(errs...)
+}
+
+// loadRepo reads the OWNERS files of the master branch of repo,
+// reusing those in old that haven't changed. It returns old itself
+// if the branch hasn't moved.
+func loadRepo(ctx context.Context, repo string, old *repoFiles) (*repoFiles, error) {
+	var commit struct {
+		Commit string
+	}
+	if err := gitilesJSON(ctx, "/"+repo+"/+/refs/heads/master", url.Values{}, &commit); err != nil {
+		return nil, err
+	}
+	if commit.Commit == "" {
+		return nil, errors.New("no commit for master branch")
+	}
+	if old != nil && old.commit == commit.Commit {
+		return old, nil
+	}
+	var tree struct {
+		Entries []struct {
+			Type string
+			ID   string
+			Name string // the path, in a recursive listing
+		}
+	}
+	if err := gitilesJSON(ctx, "/"+repo+"/+/"+commit.Commit+"/", url.Values{"recursive": {"1"}}, &tree); err != nil {
+		return nil, err
+	}
+	rf := &repoFiles{commit: commit.Commit, files: make(map[string]ownersFile)}
+	for _, e := range tree.Entries {
+		if e.Type != "blob" || path.Base(e.Name) != "OWNERS" || skipPath(path.Dir(e.Name)) {
+			continue
+		}
+		if old != nil {
+			if f, ok := old.files[e.Name]; ok && f.id == e.ID {
+				rf.files[e.Name] = f
+				continue
+			}
+		}
+		data, err := gitilesGet(ctx, "/"+repo+"/+/"+commit.Commit+"/"+e.Name, url.Values{"format": {"TEXT"}})
+		if err != nil {
+			return nil, err
+		}
+		data, err = base64.StdEncoding.DecodeString(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", e.Name, err)
+		}
+		f := ownersFile{id: e.ID}
+		f.entry, f.err = ParseOwnersFile(data)
+		rf.files[e.Name] = f
+	}
+	return rf, nil
+}
+
+// skipPath reports whether OWNERS files in the directory dir
+// are ignored, because it or a parent is testdata or hidden.
+func skipPath(dir string) bool {
+	for _, elem := range strings.Split(dir, "/") {
+		if elem == "testdata" || (strings.HasPrefix(elem, ".") && elem != ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// gitilesJSON fetches a Gitiles page in JSON format into v.
+func gitilesJSON(ctx context.Context, urlPath string, query url.Values, v any) error {
+	query.Set("format", "JSON")
+	data, err := gitilesGet(ctx, urlPath, query)
+	if err != nil {
+		return err
+	}
+	// Gitiles prefixes JSON responses to prevent XSSI.
+	data = bytes.TrimPrefix(data, []byte(")]}'"))
+	return json.Unmarshal(data, v)
+}
+
+// gitilesGet fetches a Gitiles page.
+func gitilesGet(ctx context.Context, urlPath string, query url.Values) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", gitilesURL+urlPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", urlPath, res.Status)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, 64<<20))
+}
+
+// served holds the entries loaded from OWNERS files, and the result
+// of merging them with the built-in table.
+var served struct {
+	sync.Mutex
+	fromFiles map[string]map[string]*Entry // repo name -> entries
+	merged    map[string]*Entry            // nil means the built-in table
+	gen       int                          // incremented when merged changes
+}
+
+// setRepoEntries replaces the entries loaded from OWNERS files in repo.
+func setRepoEntries(repo string, m map[string]*Entry) {
+	served.Lock()
+	defer served.Unlock()
+	if served.fromFiles == nil {
+		served.fromFiles = make(map[string]map[string]*Entry)
+	}
+	served.fromFiles[repo] = m
+	merged := make(map[string]*Entry, len(entries))
+	for p, e := range entries {
+		merged[p] = e
+	}
+	for _, m := range served.fromFiles {
+		for p, e := range m {
+			merged[p] = e
+		}
+	}
+	served.merged = merged
+	served.gen++
+}
+
+// currentEntries returns the entries being served, and their generation.
+// The returned map must not be modified.
+func currentEntries() (map[string]*Entry, int) {
+	served.Lock()
+	defer served.Unlock()
+	if served.merged == nil {
+		return entries, served.gen
+	}
+	return served.merged, served.gen
+}
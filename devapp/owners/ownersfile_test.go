@@ -0,0 +1,167 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package owners
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseOwnersFile(t *testing.T) {
+	testCases := []struct {
+		in      string
+		want    *Entry
+		wantErr string
+	}{
+		{
+			in: "# Owners of net/http.\nprimary: @neild golang/security\n\nsecondary: @bradfitz\n",
+			want: &Entry{
+				Primary:   []Owner{neild, securityTeam},
+				Secondary: []Owner{bradfitz},
+			},
+		},
+		{
+			in:   "primary: rsc\nprimary: @adonovan\n",
+			want: &Entry{Primary: []Owner{rsc, adonovan}},
+		},
+		{in: "secondary: @rsc\n", wantErr: "no primary owners"},
+		{in: "primary @rsc\n", wantErr: "line 1: missing colon"},
+		{in: "primary: @rsc\ntertiary: @iant\n", wantErr: `line 2: unknown key "tertiary"`},
+		{in: "primary: @no-such-gopher-here\n", wantErr: "no-such-gopher-here does not exist"},
+	}
+	for _, tc := range testCases {
+		got, err := ParseOwnersFile([]byte(tc.in))
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("ParseOwnersFile(%q): got error %v, want %q", tc.in, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseOwnersFile(%q): %v", tc.in, err)
+			continue
+		}
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("ParseOwnersFile(%q): (-got +want)\n%s", tc.in, diff)
+		}
+	}
+}
+
+func TestSetRepoEntries(t *testing.T) {
+	defer func() {
+		served.Lock()
+		served.fromFiles, served.merged = nil, nil
+		served.Unlock()
+	}()
+
+	_, gen := currentEntries()
+	fromFile := &Entry{Primary: []Owner{rsc}}
+	setRepoEntries("go", map[string]*Entry{
+		"go/src/archive/tar": fromFile,
+		"go/src/new/package": fromFile,
+	})
+	if _, g := currentEntries(); g == gen {
+		t.Errorf("generation did not change after setRepoEntries")
+	}
+	for _, p := range []string{"go/src/archive/tar/reader.go", "go/src/new/package/x.go"} {
+		if got := match(p); got != fromFile {
+			t.Errorf("match(%q) = %v, want entry from OWNERS file", p, got)
+		}
+	}
+	// Entries without an OWNERS file still come from the table.
+	if got, want := match("go/src/archive/zip/a.go"), entries["go/src/archive/zip"]; got != want {
+		t.Errorf("match(go/src/archive/zip/a.go) = %v, want table entry %v", got, want)
+	}
+	html, err := indexHTML()
+	if err != nil {
+		t.Fatalf("indexHTML: %v", err)
+	}
+	if !strings.Contains(string(html), "new/package") {
+		t.Errorf("index page doesn't include entry loaded from OWNERS file")
+	}
+}
+
+func TestLoadRepos(t *testing.T) {
+	commit := "c1"
+	files := map[string]string{
+		"OWNERS":                "primary: @adonovan\n",
+		"gopls/OWNERS":          "primary: @findleyr\n",
+		"gopls/testdata/OWNERS": "primary: @rsc\n",
+		".github/OWNERS":        "primary: @rsc\n",
+		"go/ast/ast.go":         "package ast\n",
+	}
+	var fetched []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch p := r.URL.Path; {
+		case p == "/tools/+/refs/heads/master":
+			fmt.Fprintf(w, ")]}'\n{\"commit\": %q}", commit)
+		case p == "/tools/+/"+commit+"/" && r.FormValue("recursive") == "1":
+			var entries []string
+			for name, data := range files {
+				entries = append(entries, fmt.Sprintf(`{"type": "blob", "id": "%x", "name": %q}`, sha1.Sum([]byte(data)), name))
+			}
+			fmt.Fprintf(w, ")]}'\n{\"entries\": [%s]}", strings.Join(entries, ","))
+		case strings.HasPrefix(p, "/tools/+/"+commit+"/") && r.FormValue("format") == "TEXT":
+			name := strings.TrimPrefix(p, "/tools/+/"+commit+"/")
+			fetched = append(fetched, name)
+			io.WriteString(w, base64.StdEncoding.EncodeToString([]byte(files[name])))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	defer func(old string) {
+		gitilesURL = old
+		loaded.Lock()
+		loaded.repos = nil
+		loaded.Unlock()
+		served.Lock()
+		served.fromFiles, served.merged = nil, nil
+		served.Unlock()
+	}(gitilesURL)
+	gitilesURL = srv.URL
+
+	ctx := context.Background()
+	if err := LoadRepos(ctx, []string{"tools"}); err != nil {
+		t.Fatalf("LoadRepos: %v", err)
+	}
+	sort.Strings(fetched)
+	if want := []string{"OWNERS", "gopls/OWNERS"}; !slices.Equal(fetched, want) {
+		t.Errorf("first load fetched %q; want %q", fetched, want)
+	}
+	if got := match("tools/gopls/doc.go"); got == nil || !slices.Equal(got.Primary, []Owner{findleyr}) {
+		t.Errorf("match(tools/gopls/doc.go) = %v; want findleyr from the OWNERS file", got)
+	}
+
+	// An unchanged branch isn't read again.
+	fetched = nil
+	if err := LoadRepos(ctx, []string{"tools"}); err != nil || len(fetched) != 0 {
+		t.Errorf("reload of unchanged branch fetched %q, %v; want nothing", fetched, err)
+	}
+
+	// Only changed OWNERS files are fetched.
+	commit = "c2"
+	files["gopls/OWNERS"] = "primary: @rsc\n"
+	if err := LoadRepos(ctx, []string{"tools"}); err != nil {
+		t.Fatalf("LoadRepos: %v", err)
+	}
+	if want := []string{"gopls/OWNERS"}; !slices.Equal(fetched, want) {
+		t.Errorf("reload fetched %q; want %q", fetched, want)
+	}
+	if got := match("tools/gopls/doc.go"); got == nil || !slices.Equal(got.Primary, []Owner{rsc}) {
+		t.Errorf("match(tools/gopls/doc.go) = %v after reload; want rsc", got)
+	}
+}
@@ -5,15 +5,27 @@
 package owners
 
 import (
+	"fmt"
+
 	"golang.org/x/build/internal/gophers"
 )
 
 func gh(githubUsername string) Owner {
+	o, err := lookupOwner(githubUsername)
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
+// lookupOwner returns the Owner for a GitHub user or team name,
+// which must be known to the golang.org/x/build/internal/gophers package.
+func lookupOwner(githubUsername string) (Owner, error) {
 	p := gophers.GetPerson("@" + githubUsername)
 	if p == nil {
-		panic("person with GitHub username " + githubUsername + " does not exist in the golang.org/x/build/internal/gophers package")
+		return Owner{}, fmt.Errorf("person with GitHub username %s does not exist in the golang.org/x/build/internal/gophers package", githubUsername)
 	}
-	return Owner{GitHubUsername: githubUsername, GerritEmail: p.Gerrit}
+	return Owner{GitHubUsername: githubUsername, GerritEmail: p.Gerrit}, nil
 }
 
 // archOsTeam returns the *Entry for an architecture or OS team at github
@@ -93,6 +105,10 @@ var (
 // entries is a map of <repo name>/<path>, <domain>, or <branch> to Owner
 // entries. For <repo name>/<path>, there is an implicit prefix of
 // go.googlesource.com. This map should not be modified at runtime.
+//
+// Entries are being migrated to OWNERS files in each repo (see LoadRepos).
+// An entry loaded from an OWNERS file takes precedence over the entry for
+// the same path here.
 var entries = map[string]*Entry{
 	// Go standard library.
 	"go/src/archive/tar": {
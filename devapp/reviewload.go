@@ -0,0 +1,326 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/build/devapp/owners"
+	"golang.org/x/build/internal/gophers"
+	"golang.org/x/build/maintner"
+)
+
+// reviewLoadWindow is how far back CLs and review activity are
+// considered for the review latency and reviewer activity numbers.
+const reviewLoadWindow = 90 * 24 * time.Hour
+
+type reviewLoadData struct {
+	Generated time.Time         `json:"generated"`
+	Projects  []*projectLatency `json:"projects"`
+	Reviewers []*reviewerLoad   `json:"reviewers"`
+	Areas     []*uncoveredArea  `json:"uncoveredAreas"`
+
+	// dirty is set if this data needs to be updated due to a corpus change.
+	dirty bool
+}
+
+// A projectLatency summarizes the time to first review for the CLs
+// created in a project during the review load window.
+type projectLatency struct {
+	Project    string  `json:"project"`
+	Reviewed   int     `json:"reviewed"`   // CLs that got a first review
+	Unreviewed int     `json:"unreviewed"` // open CLs still waiting for a first review
+	MedianDays float64 `json:"medianDays"` // median time to first review
+	P90Days    float64 `json:"p90Days"`    // 90th percentile time to first review
+}
+
+// A reviewerLoad is the queue of open CLs a reviewer is assigned to.
+type reviewerLoad struct {
+	Name     string `json:"name"`
+	GerritID string `json:"gerritID"`
+	Assigned int    `json:"assigned"` // open CLs with them as a reviewer
+	// Waiting is the number of assigned CLs whose current patch set
+	// they haven't commented on, the oldest of which has been waiting
+	// for OldestDays.
+	Waiting    int     `json:"waiting"`
+	OldestDays float64 `json:"oldestDays"`
+}
+
+// An uncoveredArea is a directory with open CLs but no active reviewer:
+// either it has no owners, or none of its owners reviewed any CL during
+// the review load window.
+type uncoveredArea struct {
+	Path    string `json:"path"` // <repo name>/<path>, like in devapp/owners
+	NoOwner bool   `json:"noOwner"`
+	OpenCLs int    `json:"openCLs"`
+}
+
+// handleReviewLoad serves dev.golang.org/reviewload.
+func (s *server) handleReviewLoad(t *template.Template, w http.ResponseWriter, r *http.Request) {
+	s.ensureReviewLoadData()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	s.cMu.RLock()
+	defer s.cMu.RUnlock()
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, &s.data.reviewLoad); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if _, err := io.Copy(w, &buf); err != nil {
+		log.Printf("io.Copy(w, %+v) = %v", buf, err)
+		return
+	}
+}
+
+// handleReviewLoadJSON serves the data behind dev.golang.org/reviewload as JSON.
+func (s *server) handleReviewLoadJSON(w http.ResponseWriter, r *http.Request) {
+	s.ensureReviewLoadData()
+	w.Header().Set("Content-Type", "application/json")
+	s.cMu.RLock()
+	defer s.cMu.RUnlock()
+	if err := json.NewEncoder(w).Encode(&s.data.reviewLoad); err != nil {
+		log.Printf("Encode(%+v) = %v", s.data.reviewLoad, err)
+		return
+	}
+}
+
+func (s *server) ensureReviewLoadData() {
+	s.cMu.RLock()
+	dirty := s.data.reviewLoad.dirty
+	s.cMu.RUnlock()
+	if dirty {
+		s.updateReviewLoadData()
+	}
+}
+
+// A projectCL is a CL along with the name of its project.
+type projectCL struct {
+	project string
+	cl      *maintner.GerritCL
+}
+
+func (s *server) updateReviewLoadData() {
+	log.Println("Updating review load data ...")
+	s.cMu.Lock()
+	defer s.cMu.Unlock()
+	now := time.Now()
+	var cls []projectCL
+	s.corpus.Gerrit().ForeachProjectUnsorted(filterProjects(func(p *maintner.GerritProject) error {
+		p.ForeachCLUnsorted(withoutDeletedCLs(p, func(cl *maintner.GerritCL) error {
+			if cl.Status == "new" || cl.Created.After(now.Add(-reviewLoadWindow)) {
+				cls = append(cls, projectCL{p.Project(), cl})
+			}
+			return nil
+		}))
+		return nil
+	}))
+	s.data.reviewLoad = computeReviewLoad(cls, now, owners.Match)
+}
+
+// computeReviewLoad computes the review load data at time now from cls,
+// which must include every open CL and every CL created during the
+// review load window. match returns the owners of a path.
+func computeReviewLoad(cls []projectCL, now time.Time, match func(path string) *owners.Entry) reviewLoadData {
+	windowStart := now.Add(-reviewLoadWindow)
+
+	// Gerrit emails of the people who reviewed a CL during the window.
+	active := make(map[string]bool)
+	for _, pc := range cls {
+		owner := ownerGerritID(pc.cl)
+		for _, m := range pc.cl.Messages {
+			if !m.Date.After(windowStart) || !isReviewer(m.Author, owner) {
+				continue
+			}
+			if p := gophers.GetPerson(m.Author.Email()); p != nil && p.Gerrit != "" {
+				active[p.Gerrit] = true
+			}
+		}
+	}
+
+	latencies := make(map[string][]time.Duration)
+	unreviewed := make(map[string]int)
+	reviewers := make(map[string]*reviewerLoad)
+	areaCLs := make(map[string]int)
+	for _, pc := range cls {
+		cl := pc.cl
+		open := cl.Status == "new" && !cl.WorkInProgress()
+		if cl.Created.After(windowStart) {
+			if d, ok := timeToFirstReview(cl); ok {
+				latencies[pc.project] = append(latencies[pc.project], d)
+			} else if open {
+				unreviewed[pc.project]++
+			}
+		}
+		if !open {
+			continue
+		}
+
+		ids, _ := reviewerFields(cl)
+		for id := range ids {
+			if id == ownerGerritID(cl) {
+				continue
+			}
+			rl := reviewers[id]
+			if rl == nil {
+				rl = &reviewerLoad{Name: id, GerritID: id}
+				if p := gophers.GetPerson(id); p != nil && p.Name != "" {
+					rl.Name = p.Name
+				}
+				reviewers[id] = rl
+			}
+			rl.Assigned++
+			if since, ok := waitingOnReviewer(cl, id); ok {
+				rl.Waiting++
+				rl.OldestDays = max(rl.OldestDays, days(now.Sub(since)))
+			}
+		}
+
+		dirs := make(map[string]bool)
+		if cl.Commit != nil {
+			for _, f := range cl.Commit.Files {
+				dirs[path.Join(pc.project, path.Dir(f.File))] = true
+			}
+		}
+		for dir := range dirs {
+			areaCLs[dir]++
+		}
+	}
+
+	data := reviewLoadData{Generated: now}
+	for project, ds := range latencies {
+		slices.Sort(ds)
+		data.Projects = append(data.Projects, &projectLatency{
+			Project:    project,
+			Reviewed:   len(ds),
+			Unreviewed: unreviewed[project],
+			MedianDays: days(percentile(ds, 50)),
+			P90Days:    days(percentile(ds, 90)),
+		})
+	}
+	for project, n := range unreviewed {
+		if _, ok := latencies[project]; !ok {
+			data.Projects = append(data.Projects, &projectLatency{Project: project, Unreviewed: n})
+		}
+	}
+	slices.SortFunc(data.Projects, func(a, b *projectLatency) int {
+		return strings.Compare(a.Project, b.Project)
+	})
+
+	for _, rl := range reviewers {
+		data.Reviewers = append(data.Reviewers, rl)
+	}
+	slices.SortFunc(data.Reviewers, func(a, b *reviewerLoad) int {
+		if a.Waiting != b.Waiting {
+			return b.Waiting - a.Waiting
+		}
+		if a.Assigned != b.Assigned {
+			return b.Assigned - a.Assigned
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for dir, n := range areaCLs {
+		e := match(dir)
+		if e != nil && hasActiveOwner(e, active) {
+			continue
+		}
+		data.Areas = append(data.Areas, &uncoveredArea{Path: dir, NoOwner: e == nil, OpenCLs: n})
+	}
+	slices.SortFunc(data.Areas, func(a, b *uncoveredArea) int {
+		if a.OpenCLs != b.OpenCLs {
+			return b.OpenCLs - a.OpenCLs
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	return data
+}
+
+// ownerGerritID returns the Gerrit ID of the owner of cl,
+// in the same form as message authors, or "" if unknown.
+func ownerGerritID(cl *maintner.GerritCL) string {
+	if len(cl.Metas) == 0 || cl.Metas[0].Commit == nil || cl.Metas[0].Commit.Author == nil {
+		return ""
+	}
+	return cl.Metas[0].Commit.Author.Email()
+}
+
+// isReviewer reports whether a message by author counts as review
+// on a CL owned by owner: it's by a human other than the owner.
+func isReviewer(author *maintner.GitPerson, owner string) bool {
+	if author == nil {
+		return false
+	}
+	id := author.Email()
+	if id == owner || id == gobotID || id == gerritbotID {
+		return false
+	}
+	p := gophers.GetPerson(id)
+	return p == nil || !p.Bot
+}
+
+// timeToFirstReview returns how long after its creation cl got its
+// first message from a reviewer. ok is false if it hasn't got one.
+func timeToFirstReview(cl *maintner.GerritCL) (d time.Duration, ok bool) {
+	owner := ownerGerritID(cl)
+	for _, m := range cl.Messages {
+		if isReviewer(m.Author, owner) {
+			return m.Date.Sub(cl.Created), true
+		}
+	}
+	return 0, false
+}
+
+// waitingOnReviewer reports whether the reviewer with the given Gerrit ID
+// hasn't yet commented on the current patch set of cl, and if so,
+// since when the patch set has been waiting.
+func waitingOnReviewer(cl *maintner.GerritCL, id string) (since time.Time, waiting bool) {
+	since = cl.Created
+	found := false
+	for _, m := range cl.Messages {
+		if m.Version != cl.Version {
+			continue
+		}
+		if !found {
+			since, found = m.Date, true
+		}
+		if m.Author != nil && m.Author.Email() == id {
+			return time.Time{}, false
+		}
+	}
+	return since, true
+}
+
+// hasActiveOwner reports whether any owner in e reviewed a CL recently,
+// according to active, the Gerrit emails of recent reviewers.
+// Teams are assumed to be active.
+func hasActiveOwner(e *owners.Entry, active map[string]bool) bool {
+	for _, o := range slices.Concat(e.Primary, e.Secondary) {
+		if strings.Contains(o.GitHubUsername, "/") || active[o.GerritEmail] {
+			return true
+		}
+	}
+	return false
+}
+
+// percentile returns the p'th percentile of ds, which must be sorted.
+func percentile(ds []time.Duration, p int) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	return ds[(len(ds)-1)*p/100]
+}
+
+func days(d time.Duration) float64 {
+	return d.Hours() / 24
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/build/devapp/owners"
+	"golang.org/x/build/maintner"
+	"golang.org/x/build/maintner/maintpb"
+)
+
+func TestComputeReviewLoad(t *testing.T) {
+	const (
+		author = "1000@62eb7196-b449-3ce5-99f1-c037f21e1705"
+		rsc    = "5056@62eb7196-b449-3ce5-99f1-c037f21e1705" // Russ Cox in internal/gophers
+		other  = "2000@62eb7196-b449-3ce5-99f1-c037f21e1705"
+	)
+	person := func(id string) *maintner.GitPerson {
+		return &maintner.GitPerson{Str: "Gerrit User <" + id + ">"}
+	}
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	newCL := func(created time.Time, version int32, files []string, reviewers []string, msgs ...*maintner.GerritMessage) *maintner.GerritCL {
+		meta := "Patch-set: 1\n"
+		for _, r := range reviewers {
+			meta += "Reviewer: Gerrit User <" + r + ">\n"
+		}
+		commit := &maintner.GitCommit{}
+		for _, f := range files {
+			commit.Files = append(commit.Files, &maintpb.GitDiffTreeFile{File: f})
+		}
+		return &maintner.GerritCL{
+			Created: created,
+			Status:  "new",
+			Version: version,
+			Commit:  commit,
+			Metas: []*maintner.GerritMeta{
+				{Commit: &maintner.GitCommit{Author: person(author), Msg: meta}},
+			},
+			Messages: msgs,
+		}
+	}
+	msg := func(id string, version int32, date time.Time) *maintner.GerritMessage {
+		return &maintner.GerritMessage{Author: person(id), Version: version, Date: date}
+	}
+
+	cls := []projectCL{
+		// Reviewed by rsc after 2 days; rsc commented on the current patch set.
+		{"go", newCL(now.Add(-10*day), 1, []string{"src/net/http/server.go"}, []string{rsc},
+			msg(author, 1, now.Add(-10*day)),
+			msg(rsc, 1, now.Add(-8*day)),
+		)},
+		// Reviewed by other after 4 days; patch set 2 waiting on rsc for 3 days.
+		{"go", newCL(now.Add(-10*day), 2, []string{"src/unowned/x.go"}, []string{rsc, other},
+			msg(author, 1, now.Add(-10*day)),
+			msg(other, 1, now.Add(-6*day)),
+			msg(author, 2, now.Add(-3*day)),
+		)},
+		// Not yet reviewed; the owner's own messages don't count.
+		{"tools", newCL(now.Add(-1*day), 1, []string{"gopls/main.go"}, nil,
+			msg(author, 1, now.Add(-1*day)),
+		)},
+	}
+	entries := map[string]*owners.Entry{
+		"go/src/net/http": {Primary: []owners.Owner{{GitHubUsername: "rsc", GerritEmail: "rsc@golang.org"}}},
+		"tools/gopls":     {Primary: []owners.Owner{{GitHubUsername: "nobody", GerritEmail: "nobody@golang.org"}}},
+	}
+	match := func(path string) *owners.Entry { return entries[path] }
+
+	got := computeReviewLoad(cls, now, match)
+	want := reviewLoadData{
+		Generated: now,
+		Projects: []*projectLatency{
+			{Project: "go", Reviewed: 2, MedianDays: 2, P90Days: 2},
+			{Project: "tools", Unreviewed: 1},
+		},
+		Reviewers: []*reviewerLoad{
+			{Name: "Russ Cox", GerritID: rsc, Assigned: 2, Waiting: 1, OldestDays: 3},
+			{Name: other, GerritID: other, Assigned: 1, Waiting: 1, OldestDays: 3},
+		},
+		Areas: []*uncoveredArea{
+			{Path: "go/src/unowned", NoOwner: true, OpenCLs: 1},
+			{Path: "tools/gopls", OpenCLs: 1},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(reviewLoadData{})); diff != "" {
+		t.Errorf("computeReviewLoad mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	ds := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	for _, tc := range []struct {
+		p    int
+		want time.Duration
+	}{{0, 1}, {50, 5}, {90, 9}, {100, 10}} {
+		if got := percentile(ds, tc.p); got != tc.want {
+			t.Errorf("percentile(ds, %d) = %v, want %v", tc.p, got, tc.want)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile(nil, 50) = %v, want 0", got)
+	}
+}
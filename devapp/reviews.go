@@ -217,13 +217,14 @@ func (s *server) updateReviewsData() error {
 	return nil
 }
 
+// Gerrit IDs of bots that post messages on CLs.
+const (
+	gobotID     = "5976@62eb7196-b449-3ce5-99f1-c037f21e1705"
+	gerritbotID = "12446@62eb7196-b449-3ce5-99f1-c037f21e1705"
+)
+
 // hasHumanComments reports whether cl has any comments from a human on it.
 func hasHumanComments(cl *maintner.GerritCL) bool {
-	const (
-		gobotID     = "5976@62eb7196-b449-3ce5-99f1-c037f21e1705"
-		gerritbotID = "12446@62eb7196-b449-3ce5-99f1-c037f21e1705"
-	)
-
 	for _, m := range cl.Messages {
 		if email := m.Author.Email(); email != gobotID && email != gerritbotID {
 			return true
@@ -236,8 +237,27 @@ func hasHumanComments(cl *maintner.GerritCL) bool {
 // the reviewer and cc fields of a Gerrit change.
 func searchTermsFromReviewerFields(cl *maintner.GerritCL) []string {
 	var searchTerms []string
-	reviewers := make(map[string]bool)
-	ccs := make(map[string]bool)
+	reviewers, ccs := reviewerFields(cl)
+	for r := range reviewers {
+		if p := gophers.GetPerson(r); p != nil && p.Gerrit != cl.Owner().Email() {
+			searchTerms = append(searchTerms, "involves:"+p.Gerrit)
+			searchTerms = append(searchTerms, "reviewer:"+p.Gerrit)
+		}
+	}
+	for r := range ccs {
+		if p := gophers.GetPerson(r); p != nil && p.Gerrit != cl.Owner().Email() {
+			searchTerms = append(searchTerms, "involves:"+p.Gerrit)
+			searchTerms = append(searchTerms, "cc:"+p.Gerrit)
+		}
+	}
+	return searchTerms
+}
+
+// reviewerFields returns the Gerrit IDs of the current reviewers
+// and CCs of a Gerrit change.
+func reviewerFields(cl *maintner.GerritCL) (reviewers, ccs map[string]bool) {
+	reviewers = make(map[string]bool)
+	ccs = make(map[string]bool)
 	for _, m := range cl.Metas {
 		if !strings.Contains(m.Commit.Msg, "Reviewer:") &&
 			!strings.Contains(m.Commit.Msg, "CC:") &&
@@ -264,17 +284,5 @@ func searchTermsFromReviewerFields(cl *maintner.GerritCL) []string {
 			return nil
 		})
 	}
-	for r := range reviewers {
-		if p := gophers.GetPerson(r); p != nil && p.Gerrit != cl.Owner().Email() {
-			searchTerms = append(searchTerms, "involves:"+p.Gerrit)
-			searchTerms = append(searchTerms, "reviewer:"+p.Gerrit)
-		}
-	}
-	for r := range ccs {
-		if p := gophers.GetPerson(r); p != nil && p.Gerrit != cl.Owner().Email() {
-			searchTerms = append(searchTerms, "involves:"+p.Gerrit)
-			searchTerms = append(searchTerms, "cc:"+p.Gerrit)
-		}
-	}
-	return searchTerms
+	return reviewers, ccs
 }
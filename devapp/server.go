@@ -50,9 +50,10 @@ type issueData struct {
 }
 
 type pageData struct {
-	release releaseData
-	reviews reviewsData
-	stats   statsData
+	release    releaseData
+	reviews    reviewsData
+	reviewLoad reviewLoadData
+	stats      statsData
 }
 
 func newServer(mux *http.ServeMux, staticDir, templateDir string, reloadTmpls bool) *server {
@@ -68,6 +69,8 @@ func newServer(mux *http.ServeMux, staticDir, templateDir string, reloadTmpls bo
 	s.mux.HandleFunc("/release", s.withTemplate("/release.tmpl", s.handleRelease))
 	s.mux.HandleFunc("/reviews", s.withTemplate("/reviews.tmpl", s.handleReviews))
 	s.mux.HandleFunc("/stats", s.withTemplate("/stats.tmpl", s.handleStats))
+	s.mux.HandleFunc("/reviewload", s.withTemplate("/reviewload.tmpl", s.handleReviewLoad))
+	s.mux.HandleFunc("/_/reviewload", s.handleReviewLoadJSON)
 	s.mux.HandleFunc("/dir/", handleDirRedirect)
 	s.mux.HandleFunc("/owners", owners.Handler)
 	s.mux.Handle("/owners/", http.RedirectHandler("/owners", http.StatusPermanentRedirect)) // TODO: remove after clients updated to use URL without trailing slash
@@ -120,6 +123,7 @@ func (s *server) corpusUpdateLoop(ctx context.Context) {
 		s.cMu.Lock()
 		s.data.release.dirty = true
 		s.data.reviews.dirty = true
+		s.data.reviewLoad.dirty = true
 		s.data.stats.dirty = true
 		s.cMu.Unlock()
 		err := s.corpus.UpdateWithLocker(ctx, &s.cMu)
@@ -143,6 +147,24 @@ func (s *server) corpusUpdateLoop(ctx context.Context) {
 	}
 }
 
+// ownersUpdateLoop periodically loads the OWNERS files in repos
+// until ctx’s Done channel is closed.
+func ownersUpdateLoop(ctx context.Context, repos []string) {
+	t := time.NewTicker(30 * time.Minute)
+	defer t.Stop()
+	for {
+		log.Println("Loading OWNERS files ...")
+		if err := owners.LoadRepos(ctx, repos); err != nil {
+			log.Printf("owners.LoadRepos: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
 const (
 	issuesURLBase = "https://golang.org/issue/"
 
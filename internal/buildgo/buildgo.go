@@ -139,13 +139,18 @@ func (gb GoBuilder) RunMake(ctx context.Context, bc buildlet.Client, w io.Writer
 		env = append(env, "GOROOT_BOOTSTRAP=")
 		makePath = []string{"$WORKDIR/go1.4/go/bin", "$PATH"}
 	}
+	var stats buildlet.ExecStats
 	remoteErr, err = bc.Exec(ctx, path.Join(gb.Goroot, gb.Conf.MakeScript()), buildlet.ExecOpts{
 		Output:   w,
 		ExtraEnv: env,
 		Debug:    true,
 		Args:     makeArgs,
 		Path:     makePath,
+		Stats:    &stats,
 	})
+	if stats != (buildlet.ExecStats{}) {
+		spanlog.Annotate(makeSpan, stats.String())
+	}
 	if err != nil {
 		makeSpan.Done(err)
 		return nil, err
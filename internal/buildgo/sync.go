@@ -0,0 +1,215 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package buildgo
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/build/buildlet"
+)
+
+// SyncGoroot makes the directory dir on the buildlet, relative to its work
+// directory, match the Go tree in the local directory goroot, uploading
+// only the contents of the files the buildlet doesn't already have.
+// Files in dir that aren't in goroot are deleted, except in bin and pkg,
+// which hold build output and aren't synced.
+func SyncGoroot(ctx context.Context, bc buildlet.RemoteClient, goroot, dir string) (*buildlet.SyncResult, error) {
+	m := new(buildlet.SyncManifest)
+	local := make(map[string]bool) // slash-separated paths relative to goroot
+	err := filepath.WalkDir(goroot, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(goroot, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		switch rel {
+		case ".":
+			return nil
+		case ".git", "bin", "pkg":
+			// .git is a file in `git worktree` checkouts.
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		local[rel] = true
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		digest, err := fileSHA1(p)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, buildlet.SyncFile{Path: rel, Digest: digest, Mode: fi.Mode().Perm()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("enumerating local files in %s: %w", goroot, err)
+	}
+
+	// Find the remote files to delete, if dir already exists.
+	exists := false
+	err = bc.ListDir(ctx, path.Dir(dir), buildlet.ListDirOpts{}, func(de buildlet.DirEntry) {
+		if strings.TrimSuffix(de.Name(), "/") == path.Base(dir) {
+			exists = true
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing buildlet's files: %w", err)
+	}
+	if exists {
+		deleted := make(map[string]bool)
+		err := bc.ListDir(ctx, dir, buildlet.ListDirOpts{Recursive: true, Skip: []string{"bin", "pkg"}}, func(de buildlet.DirEntry) {
+			rel := strings.TrimSuffix(de.Name(), "/")
+			switch {
+			case deleted[path.Dir(rel)]:
+				deleted[rel] = true // deleted along with its parent
+			case !local[rel]:
+				deleted[rel] = true
+				m.Delete = append(m.Delete, rel)
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing buildlet's files in %s: %w", dir, err)
+		}
+	}
+
+	return bc.Sync(ctx, dir, m, func(f buildlet.SyncFile) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(goroot, filepath.FromSlash(f.Path)))
+	})
+}
+
+// SyncTGZ writes the contents of the gzip-compressed tar file tgz to the
+// directory dir on the buildlet, relative to its work directory, like
+// PutTar, but uploads only the contents of the files the buildlet
+// doesn't already have. Unlike SyncGoroot, it deletes no files.
+// The tar file may only hold regular files and directories.
+func SyncTGZ(ctx context.Context, bc buildlet.RemoteClient, tgz io.ReadSeeker, dir string) (*buildlet.SyncResult, error) {
+	m := new(buildlet.SyncManifest)
+	err := readTGZ(tgz, func(h *tar.Header, tr *tar.Reader) error {
+		s1 := sha1.New()
+		if _, err := io.Copy(s1, tr); err != nil {
+			return err
+		}
+		m.Files = append(m.Files, buildlet.SyncFile{Path: h.Name, Digest: fmt.Sprintf("%x", s1.Sum(nil)), Mode: h.FileInfo().Mode().Perm()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The buildlet asks for the missing contents in the order of the
+	// manifest, so they're read in a second pass over tgz.
+	var next func() (*tar.Header, *tar.Reader, error)
+	open := func(f buildlet.SyncFile) (io.ReadCloser, error) {
+		for restarted := false; ; {
+			if next == nil {
+				if _, err := tgz.Seek(0, io.SeekStart); err != nil {
+					return nil, err
+				}
+				zr, err := gzip.NewReader(tgz)
+				if err != nil {
+					return nil, err
+				}
+				tr := tar.NewReader(zr)
+				next = func() (*tar.Header, *tar.Reader, error) {
+					h, err := nextFile(tr)
+					return h, tr, err
+				}
+			}
+			h, tr, err := next()
+			if err == io.EOF {
+				if restarted {
+					return nil, fmt.Errorf("%s is no longer in the tar file", f.Path)
+				}
+				next, restarted = nil, true
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if h.Name == f.Path {
+				return io.NopCloser(tr), nil
+			}
+		}
+	}
+	return bc.Sync(ctx, dir, m, open)
+}
+
+// readTGZ calls f for each regular file in the gzip-compressed tar file r,
+// with its header, whose Name is cleaned, and the tar.Reader of its contents.
+func readTGZ(r io.Reader, f func(*tar.Header, *tar.Reader) error) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(zr)
+	for {
+		h, err := nextFile(tr)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := f(h, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// nextFile returns the header of the next regular file in tr, skipping
+// directories, with its Name cleaned.
+func nextFile(tr *tar.Reader) (*tar.Header, error) {
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			return nil, err
+		}
+		switch h.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+		default:
+			return nil, fmt.Errorf("tar entry %q isn't a regular file or directory", h.Name)
+		}
+		name := path.Clean(h.Name)
+		if !fs.ValidPath(name) {
+			return nil, fmt.Errorf("invalid tar entry name %q", h.Name)
+		}
+		h.Name = name
+		return h, nil
+	}
+}
+
+func fileSHA1(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	s1 := sha1.New()
+	if _, err := io.Copy(s1, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", s1.Sum(nil)), nil
+}
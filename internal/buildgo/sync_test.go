@@ -0,0 +1,75 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package buildgo
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	"golang.org/x/build/buildlet"
+)
+
+// syncClient is a buildlet client that records the files it syncs,
+// asking for the contents of those in missing in the given order.
+type syncClient struct {
+	buildlet.RemoteClient
+	missing []string // paths
+	got     map[string]string
+}
+
+func (c *syncClient) Sync(ctx context.Context, dir string, m *buildlet.SyncManifest, open func(buildlet.SyncFile) (io.ReadCloser, error)) (*buildlet.SyncResult, error) {
+	c.got = make(map[string]string)
+	for _, p := range c.missing {
+		for _, f := range m.Files {
+			if f.Path != p {
+				continue
+			}
+			rc, err := open(f)
+			if err != nil {
+				return nil, err
+			}
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			c.got[p] = string(data)
+		}
+	}
+	return &buildlet.SyncResult{Written: len(m.Files)}, nil
+}
+
+func TestSyncTGZ(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	files := map[string]string{
+		"src/a.go": "package a",
+		"src/b.go": "package b",
+		"VERSION":  "go1.99",
+	}
+	tw.WriteHeader(&tar.Header{Name: "src/", Typeflag: tar.TypeDir, Mode: 0755})
+	for _, name := range []string{"VERSION", "src/a.go", "src/b.go"} {
+		tw.WriteHeader(&tar.Header{Name: "./" + name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(files[name]))})
+		io.WriteString(tw, files[name])
+	}
+	tw.Close()
+	zw.Close()
+
+	// The contents are read in another order than the tar file's.
+	bc := &syncClient{missing: []string{"src/b.go", "src/a.go", "VERSION"}}
+	if _, err := SyncTGZ(context.Background(), bc, bytes.NewReader(buf.Bytes()), "go"); err != nil {
+		t.Fatalf("SyncTGZ: %v", err)
+	}
+	for name, want := range files {
+		if got := bc.got[name]; got != want {
+			t.Errorf("synced %s = %q; want %q", name, got, want)
+		}
+	}
+}
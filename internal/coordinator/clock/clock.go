@@ -0,0 +1,179 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package clock provides the time used by the coordinator's buildlet
+// scheduling code, so that a simulation can replace it with a virtual
+// clock.
+package clock
+
+import (
+	"container/heap"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A Clock tells the time and runs functions after a duration.
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// A Timer is a pending call of a function. Stop prevents the call, and
+// reports whether it did so.
+type Timer interface {
+	Stop() bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+type holder struct{ Clock }
+
+var current atomic.Pointer[holder]
+
+func init() {
+	current.Store(&holder{realClock{}})
+}
+
+// Set sets the clock used by Now, Since and AfterFunc, and returns a
+// function that restores the previous clock. A nil c sets the real clock.
+func Set(c Clock) (restore func()) {
+	if c == nil {
+		c = realClock{}
+	}
+	old := current.Swap(&holder{c})
+	return func() { current.Store(old) }
+}
+
+// Now returns the current time.
+func Now() time.Time { return current.Load().Now() }
+
+// Since returns the time elapsed since t.
+func Since(t time.Time) time.Duration { return Now().Sub(t) }
+
+// AfterFunc calls f in its own goroutine after d has elapsed, unless the
+// returned Timer is stopped first.
+func AfterFunc(d time.Duration, f func()) Timer { return current.Load().AfterFunc(d, f) }
+
+// Virtual is a clock whose time only changes when AdvanceTo is called.
+type Virtual struct {
+	mu     sync.Mutex
+	now    time.Time
+	seq    int
+	timers timerHeap
+}
+
+// NewVirtual returns a virtual clock set to now.
+func NewVirtual(now time.Time) *Virtual {
+	return &Virtual{now: now}
+}
+
+// Now returns the virtual time.
+func (v *Virtual) Now() time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.now
+}
+
+// AfterFunc arranges for AdvanceTo to call f once the virtual time has
+// advanced by d. Unlike time.AfterFunc, f is called in the goroutine
+// that calls AdvanceTo.
+func (v *Virtual) AfterFunc(d time.Duration, f func()) Timer {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.seq++
+	t := &virtualTimer{v: v, when: v.now.Add(d), seq: v.seq, f: f}
+	heap.Push(&v.timers, t)
+	return t
+}
+
+// Next returns the time at which the next pending timer expires, and
+// false if there are no pending timers.
+func (v *Virtual) Next() (time.Time, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.timers) == 0 {
+		return time.Time{}, false
+	}
+	return v.timers[0].when, true
+}
+
+// AdvanceTo sets the virtual time to t, unless it is already later, and
+// calls the functions of the timers that expire by then in the order in
+// which they expire. Timers that expire at the same time run in the
+// order in which they were created.
+func (v *Virtual) AdvanceTo(t time.Time) {
+	for {
+		v.mu.Lock()
+		if len(v.timers) == 0 || v.timers[0].when.After(t) {
+			if t.After(v.now) {
+				v.now = t
+			}
+			v.mu.Unlock()
+			return
+		}
+		timer := heap.Pop(&v.timers).(*virtualTimer)
+		if timer.when.After(v.now) {
+			v.now = timer.when
+		}
+		v.mu.Unlock()
+		timer.f()
+	}
+}
+
+type virtualTimer struct {
+	v     *Virtual
+	when  time.Time
+	seq   int
+	f     func()
+	index int // in the heap, or -1 once expired or stopped
+}
+
+func (t *virtualTimer) Stop() bool {
+	t.v.mu.Lock()
+	defer t.v.mu.Unlock()
+	if t.index < 0 {
+		return false
+	}
+	heap.Remove(&t.v.timers, t.index)
+	return true
+}
+
+// A timerHeap implements heap.Interface, ordering timers by expiry.
+type timerHeap []*virtualTimer
+
+func (h timerHeap) Len() int { return len(h) }
+
+func (h timerHeap) Less(i, j int) bool {
+	if !h[i].when.Equal(h[j].when) {
+		return h[i].when.Before(h[j].when)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h timerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *timerHeap) Push(x interface{}) {
+	t := x.(*virtualTimer)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *timerHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1
+	*h = old[:n-1]
+	return t
+}
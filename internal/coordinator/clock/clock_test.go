@@ -0,0 +1,49 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVirtual(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	v := NewVirtual(start)
+	defer Set(v)()
+
+	var got []string
+	fired := func(name string) func() {
+		return func() { got = append(got, name+"@"+Since(start).String()) }
+	}
+	AfterFunc(2*time.Second, fired("b"))
+	AfterFunc(time.Second, func() {
+		fired("a")()
+		AfterFunc(0, fired("a2"))
+	})
+	stopped := AfterFunc(time.Second, fired("stopped"))
+	AfterFunc(2*time.Second, fired("c"))
+	AfterFunc(time.Minute, fired("late"))
+	if !stopped.Stop() {
+		t.Errorf("Stop() = false; want true")
+	}
+	if next, ok := v.Next(); !ok || !next.Equal(start.Add(time.Second)) {
+		t.Errorf("Next() = %v, %t; want %v, true", next, ok, start.Add(time.Second))
+	}
+
+	v.AdvanceTo(start.Add(10 * time.Second))
+	want := []string{"a@1s", "a2@1s", "b@2s", "c@2s"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("timers mismatch (-want +got):\n%s", diff)
+	}
+	if got := Since(start); got != 10*time.Second {
+		t.Errorf("Since(start) = %v; want 10s", got)
+	}
+	if stopped.Stop() {
+		t.Errorf("second Stop() = true; want false")
+	}
+}
@@ -586,14 +586,6 @@ func (p *GCEBuildlet) queueForMachineType(mt string) *queue.Quota {
 	}
 }
 
-// returnQuota adjusts the dead-reckoning of our quota usage by
-// one instance and cpu CPUs.
-func (p *GCEBuildlet) returnQuota(hconf *dashboard.HostConfig) {
-	machineType := hconf.MachineType()
-	p.queueForMachineType(hconf.MachineType()).ReturnQuota(GCENumCPU(machineType))
-	p.instQueue.ReturnQuota(1)
-}
-
 func (p *GCEBuildlet) setInstanceUsed(instName string, used bool) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
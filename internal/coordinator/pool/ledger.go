@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"golang.org/x/build/internal/cloud"
+	"golang.org/x/build/internal/coordinator/clock"
 	"golang.org/x/build/internal/coordinator/pool/queue"
 )
 
@@ -129,7 +130,7 @@ func (l *ledger) UpdateReservation(instName, instID string) error {
 	if !ok {
 		return fmt.Errorf("unable to update reservation: instance not found %s", instName)
 	}
-	e.createdAt = time.Now()
+	e.createdAt = clock.Now()
 	e.instanceID = instID
 	return nil
 }
@@ -0,0 +1,611 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+
+package pool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/build/buildlet"
+	"golang.org/x/build/dashboard"
+	"golang.org/x/build/internal/cloud"
+	"golang.org/x/build/internal/coordinator/pool/queue"
+)
+
+var _ Buildlet = (*LocalBuildlet)(nil)
+
+// localBuildlet is the package level local buildlet pool. It is nil
+// unless NewLocalBuildlet has been called.
+//
+// TODO(golang.org/issues/38337) remove once a package level variable is no longer
+// required by the main package.
+var localBuildlet *LocalBuildlet
+
+// LocalBuildletPool retrieves the package level LocalBuildlet pool set by the
+// constructor, or nil if there is none.
+//
+// TODO(golang.org/issues/38337) remove once a package level variable is no longer
+// required by the main package.
+func LocalBuildletPool() *LocalBuildlet {
+	return localBuildlet
+}
+
+const (
+	// localContainerLabel is the label set on every container created
+	// by a LocalBuildlet pool, so that containers left behind by an
+	// earlier coordinator can be found and removed.
+	localContainerLabel = "org.golang.build.pool"
+	// localContainerPort is the port the buildlet listens on in a container.
+	localContainerPort = "80/tcp"
+	// localContainerBinary is where the buildlet binary is mounted in a container.
+	localContainerBinary = "/usr/local/bin/localbuildlet"
+	// localStartTimeout is how long a local buildlet has to start serving.
+	localStartTimeout = time.Minute
+)
+
+// LocalOpt is optional configuration for the local buildlet pool.
+type LocalOpt func(*LocalBuildlet)
+
+// LocalContainerSocket configures the pool to run host types with a
+// ContainerImage as containers, using the Docker Engine API served on
+// the unix socket at path. Podman serves the same API with
+// 'podman system service'.
+func LocalContainerSocket(path string) LocalOpt {
+	return func(lb *LocalBuildlet) {
+		lb.containers = newContainerRuntime(path)
+	}
+}
+
+// LocalImagePrefix sets the prefix added to each host type's
+// ContainerImage to name the image to run, such as "gcr.io/<PROJ>/".
+// The default is no prefix, which runs locally tagged images.
+func LocalImagePrefix(prefix string) LocalOpt {
+	return func(lb *LocalBuildlet) {
+		lb.imagePrefix = prefix
+	}
+}
+
+// LocalBuildletLimit sets the number of local buildlets that may run
+// at once. The default is the number of CPUs on the machine.
+func LocalBuildletLimit(n int) LocalOpt {
+	return func(lb *LocalBuildlet) {
+		lb.ledger.SetCPULimit(int64(n))
+	}
+}
+
+// LocalBuildlet manages a pool of buildlets that run on the same machine
+// as the coordinator, either as processes or as containers. It makes it
+// possible to run the coordinator without any cloud resources, such as
+// in integration tests.
+type LocalBuildlet struct {
+	// binary is the absolute path of the buildlet binary to run.
+	binary string
+	// dir holds the work directory and log of each process buildlet.
+	dir string
+	// containers runs container host types, or is nil if they are
+	// run as processes.
+	containers *containerRuntime
+	// imagePrefix is added to each ContainerImage to name the image to run.
+	imagePrefix string
+	// hosts provides the host configuration for all hosts. It is passed in to facilitate
+	// testing.
+	hosts map[string]*dashboard.HostConfig
+	// ledger tracks buildlets and their resource allocations. Each
+	// buildlet costs one CPU.
+	ledger *ledger
+
+	mu    sync.Mutex
+	insts map[string]*localInstance // keyed by instance name
+}
+
+// localInstance is a running local buildlet.
+type localInstance struct {
+	// cmd is the buildlet process, if it runs as a process.
+	cmd *exec.Cmd
+	// exited is closed once the process exits.
+	exited chan struct{}
+	// dir holds the process's work directory and log.
+	dir string
+	// containerID is the ID of the buildlet's container, if it runs in one.
+	containerID string
+}
+
+// NewLocalBuildlet creates a new pool that runs the buildlet binary at
+// path for the hosts. Host types with a ContainerImage run in containers
+// if the pool is configured with LocalContainerSocket, and all other
+// host types run as processes, which is only possible for host types of
+// the local GOOS and GOARCH. The pool also sets the package level pool
+// returned by LocalBuildletPool, which ForHost uses for every host type
+// that isn't reverse.
+func NewLocalBuildlet(binary string, hosts map[string]*dashboard.HostConfig, opts ...LocalOpt) (*LocalBuildlet, error) {
+	binary, err := filepath.Abs(binary)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create local pool: %w", err)
+	}
+	if _, err := os.Stat(binary); err != nil {
+		return nil, fmt.Errorf("unable to create local pool: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "localbuildlet-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create local pool: %w", err)
+	}
+	lb := &LocalBuildlet{
+		binary: binary,
+		dir:    dir,
+		hosts:  hosts,
+		ledger: newLedger(),
+		insts:  make(map[string]*localInstance),
+	}
+	var types []*cloud.InstanceType
+	for hostType, hconf := range hosts {
+		if !hconf.IsReverse {
+			types = append(types, &cloud.InstanceType{Type: hostType, CPU: 1})
+		}
+	}
+	lb.ledger.UpdateInstanceTypes(types)
+	lb.ledger.SetCPULimit(int64(runtime.NumCPU()))
+	for _, opt := range opts {
+		opt(lb)
+	}
+
+	if lb.containers != nil {
+		// Containers that outlive the coordinator that created them
+		// are never used again.
+		lb.destroyOldContainers()
+	}
+
+	// TODO(golang.org/issues/38337) remove once a package level variable is no longer
+	// required by the main package.
+	localBuildlet = lb
+	return lb, nil
+}
+
+// GetBuildlet starts a local buildlet and returns a client for it.
+func (lb *LocalBuildlet) GetBuildlet(ctx context.Context, hostType string, lg Logger, si *queue.SchedItem) (buildlet.Client, error) {
+	hconf, ok := lb.hosts[hostType]
+	if !ok {
+		return nil, fmt.Errorf("local pool: unknown host type %q", hostType)
+	}
+	// Containers run the local buildlet binary too, so they
+	// must be of the local architecture as well.
+	inContainer := hconf.IsContainer() && lb.containers != nil
+	if !isLocalArch(hconf.HostArch) {
+		return nil, fmt.Errorf("local pool: cannot run host type %q of arch %s on %s/%s", hostType, hconf.HostArch, runtime.GOOS, runtime.GOARCH)
+	}
+	instName := instanceName(hostType, 7)
+
+	qsp := lg.CreateSpan("awaiting_local_quota")
+	err := lb.ledger.ReserveResources(ctx, instName, hostType, si)
+	qsp.Done(err)
+	if err != nil {
+		return nil, err
+	}
+
+	sp := lg.CreateSpan("create_local_buildlet", instName)
+	var (
+		inst   *localInstance
+		ipPort string
+		desc   string
+	)
+	if inContainer {
+		log.Printf("Creating local container %q for %s", instName, hostType)
+		inst, ipPort, err = lb.startContainer(ctx, instName, hconf)
+		desc = "Local container"
+	} else {
+		log.Printf("Creating local process %q for %s", instName, hostType)
+		inst, ipPort, err = lb.startProcess(instName)
+		desc = "Local process"
+	}
+	if err != nil {
+		sp.Done(err)
+		lb.ledger.Remove(instName)
+		return nil, err
+	}
+	lb.mu.Lock()
+	lb.insts[instName] = inst
+	lb.mu.Unlock()
+	lb.ledger.UpdateReservation(instName, inst.id())
+
+	err = waitLocalBuildlet(ctx, ipPort, inst.exited)
+	sp.Done(err)
+	if err != nil {
+		log.Printf("local buildlet %q failed to start: %v", instName, err)
+		lb.buildletDone(instName)
+		return nil, err
+	}
+	bc := buildlet.NewClient(ipPort, buildlet.NoKeyPair)
+	bc.SetDescription(fmt.Sprintf("%s: %s", desc, instName))
+	bc.SetOnHeartbeatFailure(func() {
+		lb.buildletDone(instName)
+	})
+	bc.SetInstanceName(instName)
+	return bc, nil
+}
+
+// isLocalArch reports whether a host of hostArch, such as "linux-amd64"
+// or "linux-arm-7", can run binaries built for this machine.
+func isLocalArch(hostArch string) bool {
+	local := runtime.GOOS + "-" + runtime.GOARCH
+	return hostArch == local || strings.HasPrefix(hostArch, local+"-")
+}
+
+// id returns the ID the ledger records for inst.
+func (inst *localInstance) id() string {
+	if inst.cmd != nil {
+		return strconv.Itoa(inst.cmd.Process.Pid)
+	}
+	return inst.containerID
+}
+
+// startProcess starts a buildlet process named instName, and returns
+// it along with the address it listens on.
+func (lb *LocalBuildlet) startProcess(instName string) (*localInstance, string, error) {
+	ipPort, err := freeLocalAddr()
+	if err != nil {
+		return nil, "", err
+	}
+	dir, err := os.MkdirTemp(lb.dir, instName+"-")
+	if err != nil {
+		return nil, "", err
+	}
+	logFile, err := os.Create(filepath.Join(dir, "buildlet.log"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, "", err
+	}
+	defer logFile.Close()
+	workDir := filepath.Join(dir, "work")
+	if err := os.Mkdir(workDir, 0755); err != nil {
+		os.RemoveAll(dir)
+		return nil, "", err
+	}
+	cmd := exec.Command(lb.binary, "-listen="+ipPort, "-halt=false", "-workdir="+workDir)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(dir)
+		return nil, "", fmt.Errorf("unable to start buildlet: %w", err)
+	}
+	inst := &localInstance{cmd: cmd, exited: make(chan struct{}), dir: dir}
+	go func() {
+		cmd.Wait()
+		close(inst.exited)
+	}()
+	return inst, ipPort, nil
+}
+
+// freeLocalAddr returns a localhost address with a port that is
+// currently free.
+func freeLocalAddr() (string, error) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return "", fmt.Errorf("unable to find a free port: %w", err)
+	}
+	defer ln.Close()
+	return "localhost:" + strconv.Itoa(ln.Addr().(*net.TCPAddr).Port), nil
+}
+
+// startContainer starts a buildlet container named instName, and returns
+// it along with the address the buildlet can be reached at.
+func (lb *LocalBuildlet) startContainer(ctx context.Context, instName string, hconf *dashboard.HostConfig) (*localInstance, string, error) {
+	id, err := lb.containers.create(ctx, instName, &containerConfig{
+		Image:      lb.imagePrefix + hconf.ContainerImage,
+		Entrypoint: []string{localContainerBinary},
+		Cmd:        []string{"-listen=:80", "-halt=false", "-workdir=/workdir"},
+		Labels:     map[string]string{localContainerLabel: "local"},
+		ExposedPorts: map[string]struct{}{
+			localContainerPort: {},
+		},
+		HostConfig: containerHostConfig{
+			Binds: []string{lb.binary + ":" + localContainerBinary + ":ro"},
+			PortBindings: map[string][]containerPortBinding{
+				localContainerPort: {{HostIP: "127.0.0.1"}},
+			},
+			Privileged: hconf.NestedVirt,
+		},
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to create container: %w", err)
+	}
+	inst := &localInstance{containerID: id}
+	if err := lb.containers.start(ctx, id); err != nil {
+		lb.containers.remove(context.Background(), id)
+		return nil, "", fmt.Errorf("unable to start container: %w", err)
+	}
+	port, err := lb.containers.hostPort(ctx, id, localContainerPort)
+	if err != nil {
+		lb.containers.remove(context.Background(), id)
+		return nil, "", fmt.Errorf("unable to find container port: %w", err)
+	}
+	return inst, net.JoinHostPort("127.0.0.1", port), nil
+}
+
+// waitLocalBuildlet waits until the buildlet at ipPort serves requests.
+// It gives up early if exited is closed.
+func waitLocalBuildlet(ctx context.Context, ipPort string, exited <-chan struct{}) error {
+	ctx, cancel := context.WithTimeout(ctx, localStartTimeout)
+	defer cancel()
+	cl := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+ipPort+"/", nil)
+		if err != nil {
+			return err
+		}
+		res, err := cl.Do(req)
+		if err == nil {
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+			if res.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("buildlet at %s did not start: %w", ipPort, ctx.Err())
+		case <-exited:
+			return errors.New("buildlet exited before it started serving")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func (lb *LocalBuildlet) QuotaStats() map[string]*queue.QuotaStats {
+	return map[string]*queue.QuotaStats{
+		"local-buildlets": lb.ledger.cpuQueue.ToExported(),
+	}
+}
+
+// String gives a report of capacity usage for the local buildlet pool.
+func (lb *LocalBuildlet) String() string {
+	return fmt.Sprintf("Local pool capacity: %s", lb.capacityString())
+}
+
+// capacityString() gives a report of capacity usage.
+func (lb *LocalBuildlet) capacityString() string {
+	r := lb.ledger.Resources()
+	return fmt.Sprintf("%d/%d buildlets", r.CPUUsed, r.CPULimit)
+}
+
+// WriteHTMLStatus writes the status of the local buildlet pool to an io.Writer.
+func (lb *LocalBuildlet) WriteHTMLStatus(w io.Writer) {
+	fmt.Fprintf(w, "<b>Local pool</b> capacity: %s", lb.capacityString())
+
+	active := lb.ledger.ResourceTime()
+	if len(active) > 0 {
+		fmt.Fprintf(w, "<ul>")
+		for _, inst := range active {
+			fmt.Fprintf(w, "<li>%v, %s</li>\n", html.EscapeString(inst.Name), friendlyDuration(time.Since(inst.Creation)))
+		}
+		fmt.Fprintf(w, "</ul>")
+	}
+}
+
+// buildletDone stops the local buildlet named instName, deletes its
+// files or container, and removes it from the ledger.
+func (lb *LocalBuildlet) buildletDone(instName string) {
+	lb.mu.Lock()
+	inst, ok := lb.insts[instName]
+	delete(lb.insts, instName)
+	lb.mu.Unlock()
+	if !ok {
+		return
+	}
+	lb.stop(inst)
+	lb.ledger.Remove(instName)
+}
+
+// stop stops inst and deletes its files or container.
+func (lb *LocalBuildlet) stop(inst *localInstance) {
+	if inst.cmd != nil {
+		inst.cmd.Process.Kill()
+		<-inst.exited
+		if err := os.RemoveAll(inst.dir); err != nil {
+			log.Printf("local buildlet directory %s deletion failed: %s", inst.dir, err)
+		}
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := lb.containers.remove(ctx, inst.containerID); err != nil {
+		log.Printf("local container %s deletion failed: %s", inst.containerID, err)
+	}
+}
+
+// Close stops all of the LocalBuildlet pool's buildlets.
+func (lb *LocalBuildlet) Close() {
+	lb.mu.Lock()
+	var names []string
+	for name := range lb.insts {
+		names = append(names, name)
+	}
+	lb.mu.Unlock()
+	for _, name := range names {
+		lb.buildletDone(name)
+	}
+	os.RemoveAll(lb.dir)
+}
+
+// destroyOldContainers deletes the containers created by earlier local pools.
+func (lb *LocalBuildlet) destroyOldContainers() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ids, err := lb.containers.list(ctx, localContainerLabel)
+	if err != nil {
+		log.Printf("failed to query for local containers: %s", err)
+		return
+	}
+	for _, id := range ids {
+		log.Printf("deleting old local container %q", id)
+		if err := lb.containers.remove(ctx, id); err != nil {
+			log.Printf("failed deleting local container %q: %s", id, err)
+		}
+	}
+}
+
+// containerRuntime is a client for the subset of the Docker Engine API
+// that the local pool uses, served on a unix socket by Docker or Podman.
+type containerRuntime struct {
+	httpClient *http.Client
+}
+
+func newContainerRuntime(socket string) *containerRuntime {
+	return &containerRuntime{
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+	}
+}
+
+// containerConfig is the body of a request to create a container.
+type containerConfig struct {
+	Image        string
+	Entrypoint   []string
+	Cmd          []string
+	Labels       map[string]string
+	ExposedPorts map[string]struct{}
+	HostConfig   containerHostConfig
+}
+
+type containerHostConfig struct {
+	Binds        []string
+	PortBindings map[string][]containerPortBinding
+	Privileged   bool
+}
+
+type containerPortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string
+}
+
+// do sends a request with the JSON encoding of body, if it isn't nil,
+// and decodes the JSON response into resp, if it isn't nil.
+func (r *containerRuntime) do(ctx context.Context, method, path string, body, resp any) error {
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(b)
+	}
+	// The host is ignored, since the transport dials the socket.
+	req, err := http.NewRequestWithContext(ctx, method, "http://localhost"+path, rd)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		var msg struct{ Message string }
+		json.NewDecoder(res.Body).Decode(&msg)
+		return &containerError{status: res.StatusCode, msg: msg.Message}
+	}
+	if resp == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(resp)
+}
+
+// containerError is an error response from the container runtime.
+type containerError struct {
+	status int
+	msg    string
+}
+
+func (e *containerError) Error() string {
+	return fmt.Sprintf("container runtime returned %d: %s", e.status, e.msg)
+}
+
+func (r *containerRuntime) create(ctx context.Context, name string, config *containerConfig) (string, error) {
+	var resp struct {
+		ID string `json:"Id"`
+	}
+	if err := r.do(ctx, http.MethodPost, "/containers/create?name="+url.QueryEscape(name), config, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+func (r *containerRuntime) start(ctx context.Context, id string) error {
+	return r.do(ctx, http.MethodPost, "/containers/"+id+"/start", nil, nil)
+}
+
+// hostPort returns the host port that the container's port is published on.
+func (r *containerRuntime) hostPort(ctx context.Context, id, port string) (string, error) {
+	var resp struct {
+		NetworkSettings struct {
+			Ports map[string][]containerPortBinding
+		}
+	}
+	if err := r.do(ctx, http.MethodGet, "/containers/"+id+"/json", nil, &resp); err != nil {
+		return "", err
+	}
+	for _, b := range resp.NetworkSettings.Ports[port] {
+		if b.HostPort != "" {
+			return b.HostPort, nil
+		}
+	}
+	return "", fmt.Errorf("port %s of container %s is not published", port, id)
+}
+
+// remove stops and deletes a container. It is not an error if the
+// container doesn't exist.
+func (r *containerRuntime) remove(ctx context.Context, id string) error {
+	err := r.do(ctx, http.MethodDelete, "/containers/"+id+"?force=true", nil, nil)
+	if ce := (*containerError)(nil); errors.As(err, &ce) && ce.status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// list returns the IDs of all containers with the label.
+func (r *containerRuntime) list(ctx context.Context, label string) ([]string, error) {
+	filters, err := json.Marshal(map[string][]string{"label": {label}})
+	if err != nil {
+		return nil, err
+	}
+	var resp []struct {
+		ID string `json:"Id"`
+	}
+	if err := r.do(ctx, http.MethodGet, "/containers/json?all=true&filters="+url.QueryEscape(string(filters)), nil, &resp); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(resp))
+	for _, c := range resp {
+		ids = append(ids, c.ID)
+	}
+	return ids, nil
+}
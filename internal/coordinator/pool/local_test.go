@@ -0,0 +1,290 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/build/dashboard"
+	"golang.org/x/build/internal/coordinator/pool/queue"
+)
+
+func TestMain(m *testing.M) {
+	switch os.Getenv("GO_LOCAL_POOL_TEST_BUILDLET") {
+	case "serve":
+		fakeBuildletMain()
+	case "exit":
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeBuildletMain serves enough of the buildlet's API for the local
+// pool tests, on the address given by the -listen flag.
+func fakeBuildletMain() {
+	var addr string
+	for _, arg := range os.Args[1:] {
+		if v, ok := strings.CutPrefix(arg, "-listen="); ok {
+			addr = v
+		}
+	}
+	http.Handle("/", fakeBuildletHandler(func() {
+		time.AfterFunc(100*time.Millisecond, func() { os.Exit(0) })
+	}))
+	fmt.Fprintln(os.Stderr, http.ListenAndServe(addr, nil))
+	os.Exit(1)
+}
+
+func fakeBuildletHandler(halt func()) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/status":
+			fmt.Fprintf(w, `{"Version": 35}`)
+		case "/halt":
+			halt()
+		}
+	})
+}
+
+func newTestLocalBuildlet(t *testing.T, hosts map[string]*dashboard.HostConfig, opts ...LocalOpt) *LocalBuildlet {
+	lb, err := NewLocalBuildlet(os.Args[0], hosts, opts...)
+	if err != nil {
+		t.Fatalf("NewLocalBuildlet() = %v", err)
+	}
+	t.Cleanup(func() {
+		lb.Close()
+		localBuildlet = nil
+	})
+	return lb
+}
+
+// waitForCapacity waits until the pool's capacity report is want.
+func waitForCapacity(t *testing.T, lb *LocalBuildlet, want string) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for lb.capacityString() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("capacity = %q; want %q", lb.capacityString(), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLocalBuildletProcess(t *testing.T) {
+	t.Setenv("GO_LOCAL_POOL_TEST_BUILDLET", "serve")
+	host := "host-type-x"
+	lb := newTestLocalBuildlet(t, map[string]*dashboard.HostConfig{
+		host: {HostType: host, HostArch: runtime.GOOS + "-" + runtime.GOARCH},
+	}, LocalBuildletLimit(1))
+	if got := ForHost(&dashboard.HostConfig{HostType: host}); got != lb {
+		t.Errorf("ForHost() = %v; want the local pool", got)
+	}
+
+	ctx := context.Background()
+	// The container would run the local buildlet binary,
+	// which can't run on another architecture.
+	if _, err := lb.GetBuildlet(ctx, "host-other", noopEventTimeLogger{}, new(queue.SchedItem)); err == nil {
+		t.Errorf("LocalBuildlet.GetBuildlet(ctx, %q) = _, nil; want error for another architecture", "host-other")
+	}
+	bc, err := lb.GetBuildlet(ctx, host, noopEventTimeLogger{}, new(queue.SchedItem))
+	if err != nil {
+		t.Fatalf("LocalBuildlet.GetBuildlet(ctx, %q) = _, %s; want no error", host, err)
+	}
+	if _, err := bc.Status(ctx); err != nil {
+		t.Fatalf("Status() = %v", err)
+	}
+	waitForCapacity(t, lb, "1/1 buildlets")
+	dirs, _ := filepath.Glob(filepath.Join(lb.dir, "*", "work"))
+	if len(dirs) != 1 {
+		t.Errorf("buildlet work directories = %q; want one", dirs)
+	}
+
+	// The pool is full.
+	ctx2, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, err := lb.GetBuildlet(ctx2, host, noopEventTimeLogger{}, new(queue.SchedItem)); err == nil {
+		t.Errorf("GetBuildlet() on a full pool succeeded; want error")
+	}
+
+	bc.Close()
+	waitForCapacity(t, lb, "0/1 buildlets")
+	if dirs, _ := filepath.Glob(filepath.Join(lb.dir, "*")); len(dirs) != 0 {
+		t.Errorf("directories after buildlet closed = %q; want none", dirs)
+	}
+}
+
+func TestLocalBuildletError(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		hostType string
+		hconf    *dashboard.HostConfig
+		env      string
+	}{
+		{
+			desc:     "unknown-host",
+			hostType: "host-type-y",
+			hconf:    &dashboard.HostConfig{HostArch: runtime.GOOS + "-" + runtime.GOARCH},
+		},
+		{
+			desc:     "other-arch",
+			hostType: "host-type-x",
+			hconf:    &dashboard.HostConfig{HostArch: "plan9-mips"},
+		},
+		{
+			desc:     "exits",
+			hostType: "host-type-x",
+			hconf:    &dashboard.HostConfig{HostArch: runtime.GOOS + "-" + runtime.GOARCH},
+			env:      "exit",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Setenv("GO_LOCAL_POOL_TEST_BUILDLET", tc.env)
+			lb := newTestLocalBuildlet(t, map[string]*dashboard.HostConfig{"host-type-x": tc.hconf})
+			_, err := lb.GetBuildlet(context.Background(), tc.hostType, noopEventTimeLogger{}, new(queue.SchedItem))
+			if err == nil {
+				t.Fatalf("LocalBuildlet.GetBuildlet(ctx, %q) = _, nil; want error", tc.hostType)
+			}
+			if got := lb.capacityString(); !strings.HasPrefix(got, "0/") {
+				t.Errorf("capacity after failure = %q; want none used", got)
+			}
+		})
+	}
+}
+
+// fakeContainerRuntime serves the parts of the Docker Engine API that
+// the local pool uses. Each started container runs a fake buildlet.
+type fakeContainerRuntime struct {
+	mu         sync.Mutex
+	created    []*containerConfig
+	containers map[string]*httptest.Server // nil until started
+	removed    []string
+}
+
+func (f *fakeContainerRuntime) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodGet && path == "/containers/json":
+		var list []map[string]string
+		for id := range f.containers {
+			list = append(list, map[string]string{"Id": id})
+		}
+		json.NewEncoder(w).Encode(list)
+	case r.Method == http.MethodPost && path == "/containers/create":
+		var config containerConfig
+		if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.created = append(f.created, &config)
+		id := r.URL.Query().Get("name") + "-id"
+		f.containers[id] = nil
+		fmt.Fprintf(w, `{"Id": %q}`, id)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/start"):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, "/containers/"), "/start")
+		f.containers[id] = httptest.NewServer(fakeBuildletHandler(func() {}))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/json"):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, "/containers/"), "/json")
+		srv := f.containers[id]
+		if srv == nil {
+			http.Error(w, `{"message": "no such container"}`, http.StatusNotFound)
+			return
+		}
+		_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+		fmt.Fprintf(w, `{"NetworkSettings": {"Ports": {%q: [{"HostIp": "127.0.0.1", "HostPort": %q}]}}}`, localContainerPort, port)
+	case r.Method == http.MethodDelete:
+		id := strings.TrimPrefix(path, "/containers/")
+		srv, ok := f.containers[id]
+		if !ok {
+			http.Error(w, `{"message": "no such container"}`, http.StatusNotFound)
+			return
+		}
+		if srv != nil {
+			srv.Close()
+		}
+		delete(f.containers, id)
+		f.removed = append(f.removed, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestLocalBuildletContainer(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeContainerRuntime{
+		containers: map[string]*httptest.Server{"old-id": nil},
+	}
+	srv := &httptest.Server{Listener: ln, Config: &http.Server{Handler: f}}
+	srv.Start()
+	defer srv.Close()
+
+	host := "host-type-x"
+	lb := newTestLocalBuildlet(t, map[string]*dashboard.HostConfig{
+		host:         {HostType: host, HostArch: runtime.GOOS + "-" + runtime.GOARCH, ContainerImage: "foo:latest", NestedVirt: true},
+		"host-other": {HostType: "host-other", HostArch: "plan9-mips", ContainerImage: "foo:latest"},
+	}, LocalContainerSocket(socket), LocalImagePrefix("gcr.io/proj/"))
+	if len(f.removed) != 1 || f.removed[0] != "old-id" {
+		t.Errorf("removed containers = %q; want the old container", f.removed)
+	}
+
+	ctx := context.Background()
+	// The container would run the local buildlet binary,
+	// which can't run on another architecture.
+	if _, err := lb.GetBuildlet(ctx, "host-other", noopEventTimeLogger{}, new(queue.SchedItem)); err == nil {
+		t.Errorf("LocalBuildlet.GetBuildlet(ctx, %q) = _, nil; want error for another architecture", "host-other")
+	}
+	bc, err := lb.GetBuildlet(ctx, host, noopEventTimeLogger{}, new(queue.SchedItem))
+	if err != nil {
+		t.Fatalf("LocalBuildlet.GetBuildlet(ctx, %q) = _, %s; want no error", host, err)
+	}
+	if _, err := bc.Status(ctx); err != nil {
+		t.Fatalf("Status() = %v", err)
+	}
+	f.mu.Lock()
+	config := f.created[0]
+	f.mu.Unlock()
+	if config.Image != "gcr.io/proj/foo:latest" {
+		t.Errorf("container image = %q; want gcr.io/proj/foo:latest", config.Image)
+	}
+	if want := lb.binary + ":" + localContainerBinary + ":ro"; len(config.HostConfig.Binds) != 1 || config.HostConfig.Binds[0] != want {
+		t.Errorf("container binds = %q; want %q", config.HostConfig.Binds, want)
+	}
+	if !config.HostConfig.Privileged {
+		t.Errorf("container for nested virtualization is not privileged")
+	}
+	if config.Labels[localContainerLabel] == "" {
+		t.Errorf("container is missing label %s", localContainerLabel)
+	}
+
+	bc.Close()
+	waitForCapacity(t, lb, fmt.Sprintf("0/%d buildlets", runtime.NumCPU()))
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.containers) != 0 {
+		t.Errorf("containers after buildlet closed = %v; want none", f.containers)
+	}
+}
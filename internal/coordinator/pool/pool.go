@@ -110,10 +110,11 @@ func isBuildlet(name string) bool {
 }
 
 // TestPoolHook is used to override the buildlet returned by ForConf. It should only be used for
-// testing purposes.
+// testing and simulation purposes.
 var TestPoolHook func(*dashboard.HostConfig) Buildlet
 
 // ForHost returns the appropriate buildlet depending on the host configuration that is passed it.
+// If a local buildlet pool has been created, it is used for every host that isn't reverse.
 // The returned buildlet can be overridden for testing purposes by registering a test hook.
 func ForHost(conf *dashboard.HostConfig) Buildlet {
 	if TestPoolHook != nil {
@@ -123,6 +124,8 @@ func ForHost(conf *dashboard.HostConfig) Buildlet {
 		panic("nil conf")
 	}
 	switch {
+	case localBuildlet != nil && !conf.IsReverse:
+		return localBuildlet
 	case conf.IsEC2:
 		return EC2BuildetPool()
 	case conf.IsVM(), conf.IsContainer():
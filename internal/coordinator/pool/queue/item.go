@@ -45,22 +45,38 @@ type SchedItem struct {
 	CommitTime  time.Time
 	RequestTime time.Time
 	User        string
+
+	// OnPreempt, if not nil, is called if the scheduling policy
+	// preempts the work after it got a buildlet. The work must then
+	// give up the buildlet, which returns its quota. Work without
+	// OnPreempt is never preempted.
+	OnPreempt func()
 }
 
-// Priority returns the BuildletPriority for a SchedItem.
-func (s *SchedItem) Priority() BuildletPriority {
+// Kind returns the kind of work s requests a buildlet for, such as KindTry.
+func (s *SchedItem) Kind() string {
 	switch {
 	case s.IsRelease:
-		return PriorityUrgent
+		return KindRelease
 	case s.IsGomote:
-		return PriorityInteractive
+		return KindGomote
 	case s.IsTry:
-		return PriorityAutomated
+		return KindTry
 	default:
-		return PriorityBatch
+		return KindPostSubmit
 	}
 }
 
+// Class returns the priority class of s under the current policy.
+func (s *SchedItem) Class() *PriorityClass {
+	return CurrentPolicy().classOf(s)
+}
+
+// Priority returns the BuildletPriority for a SchedItem.
+func (s *SchedItem) Priority() BuildletPriority {
+	return s.Class().Priority
+}
+
 func (s *SchedItem) SortTime() time.Time {
 	if s.IsGomote || s.IsTry || s.CommitTime.IsZero() {
 		return s.RequestTime
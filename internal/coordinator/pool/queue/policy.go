@@ -0,0 +1,162 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// The kinds of work that a SchedItem can request a buildlet for.
+const (
+	KindRelease    = "release"
+	KindGomote     = "gomote"
+	KindTry        = "try"
+	KindPostSubmit = "post-submit"
+)
+
+// A PriorityClass is a class of work that is ordered by the same priority.
+type PriorityClass struct {
+	// Name identifies the class on status pages.
+	Name string
+	// Priority orders the classes. Work in a class with a lower
+	// priority gets buildlets first.
+	Priority BuildletPriority
+	// Kinds, if not empty, limits the class to these kinds of work,
+	// such as KindTry.
+	Kinds []string
+	// Repos, if not empty, limits the class to work on these repos,
+	// such as "go" or "net".
+	Repos []string
+	// Preemptible reports whether work in this class may lose its
+	// buildlet to work in a class with a lower priority that has
+	// waited longer than the policy's PreemptAfter.
+	Preemptible bool
+}
+
+func (c *PriorityClass) matches(s *SchedItem) bool {
+	return (len(c.Kinds) == 0 || slices.Contains(c.Kinds, s.Kind())) &&
+		(len(c.Repos) == 0 || slices.Contains(c.Repos, s.Repo))
+}
+
+// A Policy configures how quotas order the work waiting for buildlets.
+type Policy struct {
+	// Classes are the priority classes. Work belongs to the first class
+	// that matches it, or to the default class of its kind if none does.
+	Classes []PriorityClass
+	// FairShare orders work within a class by how much of the quota
+	// its share already holds, divided by the share's weight, before
+	// ordering it by time. Work shares by the user for gomotes, and by
+	// the repo otherwise.
+	FairShare bool
+	// Weights are the fair-share weights of users and repos. The
+	// default weight is 1.
+	Weights map[string]int
+	// PreemptAfter, if not zero, is how long work may wait before it
+	// preempts work holding quota in a preemptible class with a
+	// higher priority.
+	PreemptAfter time.Duration
+}
+
+// DefaultPolicy returns the policy used unless SetPolicy is called. Its
+// classes order release work before gomotes, gomotes before trybots, and
+// trybots before post-submit builds, and it doesn't preempt work.
+func DefaultPolicy() *Policy {
+	return &Policy{Classes: slices.Clone(defaultClasses)}
+}
+
+var defaultClasses = []PriorityClass{
+	{Name: KindRelease, Priority: PriorityUrgent, Kinds: []string{KindRelease}},
+	{Name: KindGomote, Priority: PriorityInteractive, Kinds: []string{KindGomote}},
+	{Name: KindTry, Priority: PriorityAutomated, Kinds: []string{KindTry}},
+	{Name: KindPostSubmit, Priority: PriorityBatch, Kinds: []string{KindPostSubmit}},
+}
+
+// classOf returns the class that s belongs to.
+func (p *Policy) classOf(s *SchedItem) *PriorityClass {
+	for i := range p.Classes {
+		if p.Classes[i].matches(s) {
+			return &p.Classes[i]
+		}
+	}
+	for i := range defaultClasses {
+		if defaultClasses[i].matches(s) {
+			return &defaultClasses[i]
+		}
+	}
+	panic("unreachable")
+}
+
+// shareKey returns the user or repo whose fair share s counts against.
+func shareKey(s *SchedItem) string {
+	if s.IsGomote {
+		return s.User
+	}
+	return s.Repo
+}
+
+// weight returns the fair-share weight of key.
+func (p *Policy) weight(key string) int {
+	if w, ok := p.Weights[key]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
+// ParsePolicy parses a policy from its JSON encoding, in which
+// PreemptAfter is a string accepted by time.ParseDuration.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var p struct {
+		Policy
+		PreemptAfter string
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid scheduling policy: %w", err)
+	}
+	if p.PreemptAfter != "" {
+		d, err := time.ParseDuration(p.PreemptAfter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scheduling policy: %w", err)
+		}
+		p.Policy.PreemptAfter = d
+	}
+	names := make(map[string]bool)
+	for _, c := range p.Classes {
+		if c.Name == "" || names[c.Name] {
+			return nil, fmt.Errorf("invalid scheduling policy: missing or duplicate class name %q", c.Name)
+		}
+		names[c.Name] = true
+		for _, k := range c.Kinds {
+			switch k {
+			case KindRelease, KindGomote, KindTry, KindPostSubmit:
+			default:
+				return nil, fmt.Errorf("invalid scheduling policy: class %s has unknown kind %q", c.Name, k)
+			}
+		}
+	}
+	return &p.Policy, nil
+}
+
+var policy atomic.Pointer[Policy]
+
+func init() {
+	policy.Store(DefaultPolicy())
+}
+
+// SetPolicy sets the policy used by all quotas. It affects the order
+// of work that is already waiting the next time a quota changes.
+func SetPolicy(p *Policy) {
+	policy.Store(p)
+}
+
+// CurrentPolicy returns the policy used by all quotas.
+func CurrentPolicy() *Policy {
+	return policy.Load()
+}
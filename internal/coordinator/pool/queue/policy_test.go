@@ -0,0 +1,87 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// setTestPolicy sets the policy for the duration of the test.
+func setTestPolicy(t *testing.T, p *Policy) {
+	old := CurrentPolicy()
+	SetPolicy(p)
+	t.Cleanup(func() { SetPolicy(old) })
+}
+
+func TestParsePolicy(t *testing.T) {
+	got, err := ParsePolicy([]byte(`{
+		"Classes": [
+			{"Name": "try-go", "Priority": 1, "Kinds": ["try"], "Repos": ["go"]},
+			{"Name": "post-submit", "Priority": 3, "Kinds": ["post-submit"], "Preemptible": true}
+		],
+		"FairShare": true,
+		"Weights": {"go": 3},
+		"PreemptAfter": "10m"
+	}`))
+	if err != nil {
+		t.Fatalf("ParsePolicy() = %v", err)
+	}
+	want := &Policy{
+		Classes: []PriorityClass{
+			{Name: "try-go", Priority: PriorityInteractive, Kinds: []string{KindTry}, Repos: []string{"go"}},
+			{Name: "post-submit", Priority: PriorityBatch, Kinds: []string{KindPostSubmit}, Preemptible: true},
+		},
+		FairShare:    true,
+		Weights:      map[string]int{"go": 3},
+		PreemptAfter: 10 * time.Minute,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ParsePolicy() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParsePolicyError(t *testing.T) {
+	for _, s := range []string{
+		`{"Classes": [{"Name": "a"}, {"Name": "a"}]}`,
+		`{"Classes": [{"Priority": 1}]}`,
+		`{"Classes": [{"Name": "a", "Kinds": ["nightly"]}]}`,
+		`{"PreemptAfter": "soon"}`,
+		`{`,
+	} {
+		if _, err := ParsePolicy([]byte(s)); err == nil {
+			t.Errorf("ParsePolicy(%s) = _, nil; want error", s)
+		}
+	}
+}
+
+func TestSchedItemClass(t *testing.T) {
+	setTestPolicy(t, &Policy{
+		Classes: []PriorityClass{
+			{Name: "try-go", Priority: PriorityInteractive, Kinds: []string{KindTry}, Repos: []string{"go"}},
+		},
+	})
+	tests := []struct {
+		si           *SchedItem
+		wantClass    string
+		wantPriority BuildletPriority
+	}{
+		{&SchedItem{IsTry: true, Repo: "go"}, "try-go", PriorityInteractive},
+		// Work that matches no class is in the default class of its kind.
+		{&SchedItem{IsTry: true, Repo: "net"}, KindTry, PriorityAutomated},
+		{&SchedItem{IsRelease: true, Repo: "go"}, KindRelease, PriorityUrgent},
+		{&SchedItem{Repo: "go"}, KindPostSubmit, PriorityBatch},
+	}
+	for _, tt := range tests {
+		c := tt.si.Class()
+		if c.Name != tt.wantClass || tt.si.Priority() != tt.wantPriority {
+			t.Errorf("%+v is in class %s with priority %d; want %s with priority %d", tt.si, c.Name, tt.si.Priority(), tt.wantClass, tt.wantPriority)
+		}
+	}
+}
@@ -11,12 +11,19 @@ import (
 	"context"
 	"sort"
 	"sync"
+	"time"
+
+	"golang.org/x/build/internal/coordinator/clock"
 )
 
+// maxPreemptions is the number of recent preemptions a Quota reports.
+const maxPreemptions = 10
+
 // NewQuota returns an initialized *Quota ready for use.
 func NewQuota() *Quota {
 	return &Quota{
 		queue: new(buildletQueue),
+		held:  make(map[*Item]bool),
 	}
 }
 
@@ -29,6 +36,19 @@ type Quota struct {
 	// On GCE, other instances run in the same project as buildlet
 	// instances. Track those separately, and subtract from available.
 	untrackedUsed int
+	// held is the set of items that hold quota.
+	held map[*Item]bool
+	// preemptions are the most recent preemptions, oldest first.
+	preemptions []Preemption
+	// preempted is the number of items ever preempted.
+	preempted int
+}
+
+// A Preemption records work that lost its buildlet to other work.
+type Preemption struct {
+	Time  time.Time
+	Build *SchedItem // the preempted work
+	For   *SchedItem // the work that waited too long
 }
 
 func (q *Quota) push(item *Item) {
@@ -45,14 +65,15 @@ func (q *Quota) cancel(item *Item) {
 	if item.index != -1 {
 		heap.Remove(q.queue, item.index)
 	}
+	if item.timer != nil {
+		item.timer.Stop()
+	}
 }
 
 func (q *Quota) updated() {
-	for {
-		if q.tryPop() == nil {
-			return
-		}
+	for q.tryPop() != nil {
 	}
+	q.maybePreempt()
 }
 
 // tryPop returns a Item if quota is available and unblocks the
@@ -60,15 +81,111 @@ func (q *Quota) updated() {
 func (q *Quota) tryPop() *Item {
 	q.mu.Lock()
 	defer q.mu.Unlock()
+	q.updateSharesLocked()
 	if !(q.queue.Len() != 0 && q.queue.Peek().cost <= q.limit-q.used-q.untrackedUsed) {
 		return nil
 	}
 	b := q.queue.PopBuildlet()
 	q.used += b.cost
+	q.held[b] = true
+	b.poppedAt = clock.Now()
 	b.ready()
 	return b
 }
 
+// updateSharesLocked sets the fair share of each waiting item to the
+// quota held by its share, divided by the share's weight, and reorders
+// the queue for the current policy. The lock q.mu must be held.
+func (q *Quota) updateSharesLocked() {
+	p := CurrentPolicy()
+	held := make(map[string]int)
+	if p.FairShare {
+		for item := range q.held {
+			held[shareKey(item.build)] += item.cost
+		}
+	}
+	for _, item := range *q.queue {
+		key := shareKey(item.build)
+		item.share = float64(held[key]) / float64(p.weight(key))
+	}
+	heap.Init(q.queue)
+}
+
+// maybePreempt preempts work that holds quota, if the first waiting
+// item has waited longer than the policy's PreemptAfter and preempting
+// work of less important, preemptible classes would make room for it.
+// The least important and most recently started work is preempted first.
+func (q *Quota) maybePreempt() {
+	p := CurrentPolicy()
+	if p.PreemptAfter <= 0 {
+		return
+	}
+	var preempt []func()
+	defer func() {
+		for _, f := range preempt {
+			go f()
+		}
+	}()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.queue.Len() == 0 {
+		return
+	}
+	head := q.queue.Peek()
+	if clock.Since(head.enqueued) < p.PreemptAfter {
+		return
+	}
+	need := head.cost - (q.limit - q.used - q.untrackedUsed)
+	var victims []*Item
+	for item := range q.held {
+		if item.preempted {
+			// Its quota will be returned soon.
+			need -= item.cost
+			continue
+		}
+		c := item.build.Class()
+		if item.build.OnPreempt != nil && c.Preemptible && c.Priority > head.build.Priority() {
+			victims = append(victims, item)
+		}
+	}
+	sort.Slice(victims, func(i, j int) bool {
+		pi, pj := victims[i].build.Priority(), victims[j].build.Priority()
+		if pi != pj {
+			return pi > pj
+		}
+		return victims[i].poppedAt.After(victims[j].poppedAt)
+	})
+	avail := 0
+	for _, v := range victims {
+		avail += v.cost
+	}
+	if need <= 0 || avail < need {
+		return
+	}
+	now := clock.Now()
+	for _, v := range victims {
+		if need <= 0 {
+			break
+		}
+		need -= v.cost
+		v.preempted = true
+		q.preempted++
+		preempt = append(preempt, v.build.OnPreempt)
+		q.preemptions = append(q.preemptions, Preemption{Time: now, Build: v.SchedItem(), For: head.SchedItem()})
+	}
+	if n := len(q.preemptions); n > maxPreemptions {
+		q.preemptions = append([]Preemption(nil), q.preemptions[n-maxPreemptions:]...)
+	}
+}
+
+// Preempted returns the number of times work has been preempted to
+// make room in the quota.
+func (q *Quota) Preempted() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.preempted
+}
+
 // Empty returns true when there are no items in the queue.
 func (q *Quota) Empty() bool {
 	return q.Len() == 0
@@ -82,6 +199,10 @@ func (q *Quota) Len() int {
 }
 
 // UpdateQuotas updates the limit and used values on the queue.
+//
+// The used value replaces the quota held by Items, which are still
+// considered for fair sharing and preemption until they're returned
+// with Item.ReturnQuota.
 func (q *Quota) UpdateQuotas(used, limit int) {
 	defer q.updated()
 	q.mu.Lock()
@@ -106,6 +227,9 @@ func (q *Quota) UpdateUntracked(n int) {
 }
 
 // ReturnQuota decrements the used quota value by v.
+//
+// Items that hold quota must return it with Item.ReturnQuota instead,
+// so that they stop being considered for fair sharing and preemption.
 func (q *Quota) ReturnQuota(v int) {
 	defer q.updated()
 	q.mu.Lock()
@@ -113,6 +237,18 @@ func (q *Quota) ReturnQuota(v int) {
 	q.used -= v
 }
 
+// release returns the quota held by item, if it hasn't already.
+func (q *Quota) release(item *Item) {
+	defer q.updated()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.held[item] {
+		return
+	}
+	q.used -= item.cost
+	delete(q.held, item)
+}
+
 type Usage struct {
 	Used          int
 	Limit         int
@@ -134,12 +270,18 @@ func (q *Quota) Quotas() Usage {
 // waiting and releasing quota.
 func (q *Quota) Enqueue(cost int, si *SchedItem) *Item {
 	item := &Item{
-		cost:    cost,
-		release: func() { q.ReturnQuota(cost) },
-		popped:  make(chan struct{}),
-		build:   si,
+		cost:     cost,
+		popped:   make(chan struct{}),
+		build:    si,
+		enqueued: clock.Now(),
 	}
+	item.release = func() { q.release(item) }
 	item.cancel = func() { q.cancel(item) }
+	if p := CurrentPolicy(); p.PreemptAfter > 0 {
+		// Check whether the item may preempt other work once it has
+		// waited long enough, even if nothing else changes.
+		item.timer = clock.AfterFunc(p.PreemptAfter, q.updated)
+	}
 	q.push(item)
 	return item
 }
@@ -158,16 +300,19 @@ func (q *Quota) AwaitQueue(ctx context.Context, cost int, si *SchedItem) error {
 
 type QuotaStats struct {
 	Usage
-	Items []ItemStats
+	Items       []ItemStats
+	Preemptions []Preemption // most recent first
 }
 
 type ItemStats struct {
 	Build *SchedItem
 	Cost  int
+	Share float64 // the fair share used, if the policy uses fair sharing
 }
 
 func (q *Quota) ToExported() *QuotaStats {
 	q.mu.Lock()
+	defer q.mu.Unlock()
 	qs := &QuotaStats{
 		Usage: Usage{
 			Used:          q.used,
@@ -176,25 +321,39 @@ func (q *Quota) ToExported() *QuotaStats {
 		},
 		Items: make([]ItemStats, q.queue.Len()),
 	}
-	for i, item := range *q.queue {
+	items := append([]*Item(nil), *q.queue...)
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].less(items[j])
+	})
+	for i, item := range items {
 		qs.Items[i].Build = item.SchedItem()
 		qs.Items[i].Cost = item.cost
+		qs.Items[i].Share = item.share
+	}
+	for i := len(q.preemptions) - 1; i >= 0; i-- {
+		qs.Preemptions = append(qs.Preemptions, q.preemptions[i])
 	}
-	q.mu.Unlock()
-
-	sort.Slice(qs.Items, func(i, j int) bool {
-		return qs.Items[i].Build.Less(qs.Items[j].Build)
-	})
 	return qs
 }
 
 // An Item is something we manage in a priority buildletQueue.
 type Item struct {
-	build   *SchedItem
-	cancel  func()
-	cost    int
-	popped  chan struct{}
-	release func()
+	build    *SchedItem
+	cancel   func()
+	cost     int
+	popped   chan struct{}
+	release  func()
+	enqueued time.Time
+	timer    clock.Timer // checks for preemption, if the policy preempts work
+
+	// The following fields are guarded by the Quota's mutex.
+
+	// share is the item's fair share when the queue was last ordered.
+	share float64
+	// poppedAt is when the item got its quota.
+	poppedAt time.Time
+	// preempted is whether the item's work has been asked to return its quota.
+	preempted bool
 	// index is maintained by the heap.Interface methods.
 	index int
 }
@@ -226,7 +385,7 @@ func (i *Item) Await(ctx context.Context) error {
 }
 
 // ReturnQuota returns quota to the Queue. ReturnQuota is a no-op if
-// the item has never been popped.
+// the item has never been popped or has already returned its quota.
 func (i *Item) ReturnQuota() {
 	select {
 	case <-i.popped:
@@ -238,16 +397,32 @@ func (i *Item) ReturnQuota() {
 }
 
 func (i *Item) ready() {
+	if i.timer != nil {
+		i.timer.Stop()
+	}
 	close(i.popped)
 }
 
+// less reports whether i should get quota before j: if its class has
+// a lower priority, then if its fair share is smaller, and then by the
+// order of SchedItem.Less.
+func (i *Item) less(j *Item) bool {
+	if pi, pj := i.build.Priority(), j.build.Priority(); pi != pj {
+		return pi < pj
+	}
+	if i.share != j.share {
+		return i.share < j.share
+	}
+	return i.build.Less(j.build)
+}
+
 // A buildletQueue implements heap.Interface and holds Items.
 type buildletQueue []*Item
 
 func (q buildletQueue) Len() int { return len(q) }
 
 func (q buildletQueue) Less(i, j int) bool {
-	return q[i].build.Less(q[j].build)
+	return q[i].less(q[j])
 }
 
 func (q buildletQueue) Swap(i, j int) {
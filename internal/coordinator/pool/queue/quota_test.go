@@ -220,3 +220,168 @@ func TestQueueToExported(t *testing.T) {
 		t.Errorf("q.ToExported() mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestQueueFairShare(t *testing.T) {
+	setTestPolicy(t, &Policy{
+		Classes:   DefaultPolicy().Classes,
+		FairShare: true,
+		Weights:   map[string]int{"go": 2},
+	})
+	q := NewQuota()
+	q.UpdateLimit(3)
+	ctx := context.Background()
+	t1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if err := q.Enqueue(1, &SchedItem{IsTry: true, Repo: "net", RequestTime: t1}).Await(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Waiting work from a repo that holds less of the quota goes first,
+	// even if other work has waited longer.
+	net := q.Enqueue(1, &SchedItem{IsTry: true, Repo: "net", RequestTime: t1})
+	tools := q.Enqueue(1, &SchedItem{IsTry: true, Repo: "tools", RequestTime: t1.Add(time.Second)})
+	goItem := q.Enqueue(1, &SchedItem{IsTry: true, Repo: "go", RequestTime: t1.Add(2 * time.Second)})
+	stats := q.ToExported()
+	var got []string
+	for _, item := range stats.Items {
+		got = append(got, item.Build.Repo)
+	}
+	if want := []string{"tools", "go", "net"}; !cmp.Equal(got, want) {
+		t.Errorf("queue order = %q; want %q", got, want)
+	}
+	if stats.Items[2].Share != 3 {
+		t.Errorf("share of net = %v; want 3", stats.Items[2].Share)
+	}
+
+	q.ReturnQuota(1)
+	if err := tools.Await(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// go has twice the weight, so it still goes before net.
+	q.ReturnQuota(1)
+	if err := goItem.Await(ctx); err != nil {
+		t.Fatal(err)
+	}
+	q.ReturnQuota(1)
+	if err := net.Await(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueuePreempt(t *testing.T) {
+	classes := DefaultPolicy().Classes
+	classes[3].Preemptible = true // post-submit
+	setTestPolicy(t, &Policy{
+		Classes:      classes,
+		PreemptAfter: 50 * time.Millisecond,
+	})
+	q := NewQuota()
+	q.UpdateLimit(2)
+	ctx := context.Background()
+
+	preempted := make(chan string, 2)
+	hold := func(si *SchedItem, name string) *Item {
+		si.OnPreempt = func() { preempted <- name }
+		item := q.Enqueue(1, si)
+		if err := item.Await(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return item
+	}
+	hold(&SchedItem{Repo: "go"}, "old post-submit")
+	time.Sleep(time.Millisecond)
+	newer := hold(&SchedItem{Repo: "net"}, "new post-submit")
+
+	try := q.Enqueue(1, &SchedItem{IsTry: true, Repo: "go"})
+	select {
+	case name := <-preempted:
+		if name != "new post-submit" {
+			t.Errorf("preempted %s; want the newest post-submit build", name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiting trybot didn't preempt a post-submit build")
+	}
+	stats := q.ToExported()
+	if len(stats.Preemptions) != 1 || stats.Preemptions[0].Build.Repo != "net" || !stats.Preemptions[0].For.IsTry {
+		t.Errorf("preemptions = %+v; want net's build for the trybot", stats.Preemptions)
+	}
+	if got := q.Preempted(); got != 1 {
+		t.Errorf("q.Preempted() = %d; want 1", got)
+	}
+
+	newer.ReturnQuota()
+	if err := try.Await(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case name := <-preempted:
+		t.Errorf("preempted %s after the trybot got quota", name)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestQueueNoPreempt(t *testing.T) {
+	classes := DefaultPolicy().Classes
+	classes[3].Preemptible = true // post-submit
+	setTestPolicy(t, &Policy{
+		Classes:      classes,
+		PreemptAfter: 10 * time.Millisecond,
+	})
+	q := NewQuota()
+	q.UpdateLimit(1)
+	ctx := context.Background()
+
+	preempted := make(chan bool, 1)
+	gomote := q.Enqueue(1, &SchedItem{IsGomote: true, OnPreempt: func() { preempted <- true }})
+	if err := gomote.Await(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Gomotes aren't preemptible, so the trybot waits.
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if err := q.AwaitQueue(ctx, 1, &SchedItem{IsTry: true}); err == nil {
+		t.Errorf("trybot got quota held by a gomote")
+	}
+	select {
+	case <-preempted:
+		t.Errorf("preempted gomote in a class that isn't preemptible")
+	default:
+	}
+}
+
+func heldLen(q *Quota) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.held)
+}
+
+// TestQueueHeldReleased tests that items whose quota is returned
+// stop holding quota, so that finished work isn't chosen for
+// preemption.
+func TestQueueHeldReleased(t *testing.T) {
+	ctx := context.Background()
+	q := NewQuota()
+	q.UpdateLimit(8)
+	var prev *Item
+	for i := 0; i < 100; i++ {
+		item := q.Enqueue(4, &SchedItem{User: "gopher"})
+		if err := item.Await(ctx); err != nil {
+			t.Fatalf("Await: %v", err)
+		}
+		if prev != nil {
+			// The previous instance was destroyed.
+			prev.ReturnQuota()
+		}
+		prev = item
+	}
+	if n := heldLen(q); n != 1 {
+		t.Errorf("len(q.held) = %d, want 1", n)
+	}
+	prev.ReturnQuota()
+	if n := heldLen(q); n != 0 {
+		t.Errorf("len(q.held) = %d, want 0", n)
+	}
+	if usage := q.Quotas(); usage.Used != 0 {
+		t.Errorf("q.Quotas().Used = %d, want 0", usage.Used)
+	}
+}
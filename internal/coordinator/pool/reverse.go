@@ -36,6 +36,7 @@ import (
 	"crypto/md5"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"log"
 	"math/rand"
@@ -60,8 +61,17 @@ var (
 	}
 
 	builderMasterKey []byte
+
+	// ReverseTrace, if non-nil, returns the hooks that the revdial.Dialer
+	// of each reverse buildlet of the given host type calls. It's used to
+	// export connection metrics.
+	ReverseTrace func(hostType string) *revdial.Trace
 )
 
+// maxReverseDisconnects is how many recent disconnections of reverse
+// buildlets the status page shows.
+const maxReverseDisconnects = 20
+
 // SetBuilderMasterKey sets the builder master key used
 // to generate keys used by the builders.
 func SetBuilderMasterKey(masterKey []byte) {
@@ -75,7 +85,7 @@ func ReversePool() *ReverseBuildletPool {
 
 // ReverseBuildletPool manages the pool of reverse buildlet pools.
 type ReverseBuildletPool struct {
-	// mu guards all 5 fields below and also fields of
+	// mu guards all 6 fields below and also fields of
 	// *reverseBuildlet in buildlets
 	mu sync.Mutex
 
@@ -102,6 +112,33 @@ type ReverseBuildletPool struct {
 	// machines as both POWER8 and POWER9 host types, but with the
 	// same names).
 	hostLastGood map[string]time.Time
+
+	// disconnects are the most recent disconnections of reverse
+	// buildlets, oldest first.
+	disconnects []reverseDisconnect
+}
+
+// A reverseDisconnect records why a reverse buildlet went away.
+type reverseDisconnect struct {
+	hostname, hostType string
+	time               time.Time
+	reason             string
+}
+
+// recordDisconnect records that the reverse buildlet hostname of
+// hostType went away because of reason.
+func (p *ReverseBuildletPool) recordDisconnect(hostname, hostType, reason string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.disconnects = append(p.disconnects, reverseDisconnect{
+		hostname: hostname,
+		hostType: hostType,
+		time:     time.Now(),
+		reason:   reason,
+	})
+	if n := len(p.disconnects); n > maxReverseDisconnects {
+		p.disconnects = append(p.disconnects[:0], p.disconnects[n-maxReverseDisconnects:]...)
+	}
 }
 
 // BuildletLastSeen gives the last time a buildlet was connected to the pool. If
@@ -114,14 +151,14 @@ func (p *ReverseBuildletPool) BuildletLastSeen(host string) (time.Time, bool) {
 	return t, ok
 }
 
-// tryToGrab returns non-nil bc on success if a buildlet is free.
+// tryToGrab returns non-nil bc on success if a buildlet is free,
+// and records that item holds the quota for it.
 //
 // Otherwise it returns how many were busy, which might be 0 if none
 // were (yet?) registered. The busy valid is only valid if bc == nil.
-func (p *ReverseBuildletPool) tryToGrab(hostType string) (bc buildlet.Client, busy int) {
+func (p *ReverseBuildletPool) tryToGrab(hostType string, item *queue.Item) (bc buildlet.Client, busy int) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	defer p.updateQuotasLocked()
 	for _, b := range p.buildlets {
 		if b.hostType != hostType {
 			continue
@@ -133,6 +170,7 @@ func (p *ReverseBuildletPool) tryToGrab(hostType string) (bc buildlet.Client, bu
 		// Found an unused match.
 		b.inUse = true
 		b.inUseTime = time.Now()
+		b.quota = item
 		return b.client, 0
 	}
 	return nil, busy
@@ -142,6 +180,15 @@ func (p *ReverseBuildletPool) tryToGrab(hostType string) (bc buildlet.Client, bu
 // and closes its TCP connection in hopes that it will fix itself
 // later.
 func (p *ReverseBuildletPool) nukeBuildlet(victim buildlet.Client) {
+	if item := p.removeBuildlet(victim); item != nil {
+		// The build using it is done.
+		item.ReturnQuota()
+	}
+}
+
+// removeBuildlet removes victim from the pool, closes its connection,
+// and returns the quota held by the build using it, if any.
+func (p *ReverseBuildletPool) removeBuildlet(victim buildlet.Client) *queue.Item {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	defer p.updateQuotasLocked()
@@ -149,9 +196,12 @@ func (p *ReverseBuildletPool) nukeBuildlet(victim buildlet.Client) {
 		if rb.client == victim {
 			defer rb.conn.Close()
 			p.buildlets = append(p.buildlets[:i], p.buildlets[i+1:]...)
-			return
+			item := rb.quota
+			rb.quota = nil
+			return item
 		}
 	}
+	return nil
 }
 
 // healthCheckBuildletLoop periodically requests the status from b.
@@ -239,9 +289,10 @@ func (p *ReverseBuildletPool) hostTypeQueue(hostType string) *queue.Quota {
 // GetBuildlet builds a buildlet client for the passed in host.
 func (p *ReverseBuildletPool) GetBuildlet(ctx context.Context, hostType string, lg Logger, si *queue.SchedItem) (buildlet.Client, error) {
 	sp := lg.CreateSpan("wait_static_builder", hostType)
-	// No need to return quota when done. The quotas will be updated
-	// when the reverse buildlet reconnects and becomes healthy.
-	err := p.hostTypeQueue(hostType).AwaitQueue(ctx, 1, si)
+	// The quota is returned when the buildlet is closed and leaves
+	// the pool, to reconnect for the next build.
+	item := p.hostTypeQueue(hostType).Enqueue(1, si)
+	err := item.Await(ctx)
 	sp.Done(err)
 	if err != nil {
 		return nil, err
@@ -249,7 +300,7 @@ func (p *ReverseBuildletPool) GetBuildlet(ctx context.Context, hostType string,
 
 	seenErrInUse := false
 	for {
-		bc, busy := p.tryToGrab(hostType)
+		bc, busy := p.tryToGrab(hostType, item)
 		if bc != nil {
 			sp.Done(nil)
 			return p.cleanedBuildlet(bc, lg)
@@ -260,6 +311,7 @@ func (p *ReverseBuildletPool) GetBuildlet(ctx context.Context, hostType string,
 		}
 		select {
 		case <-ctx.Done():
+			item.ReturnQuota()
 			return nil, sp.Done(ctx.Err())
 		case <-time.After(10 * time.Second):
 		}
@@ -303,14 +355,21 @@ func (p *ReverseBuildletPool) WriteHTMLStatus(w io.Writer) {
 			machStatus = "working"
 			numInUse++
 		}
-		fmt.Fprintf(&buf, "<li>%s (%s) version %s, %s: connected %s, %s for %s</li>\n",
+		var conns string
+		if b.dialer != nil {
+			st := b.dialer.Stats()
+			conns = fmt.Sprintf("; rtt %v, conns %d active/%d pending, %d bytes in/%d out",
+				st.RTT.Round(time.Millisecond), st.Active, st.Pending, st.BytesRead, st.BytesWritten)
+		}
+		fmt.Fprintf(&buf, "<li>%s (%s) version %s, %s: connected %s, %s for %s%s</li>\n",
 			b.hostname,
 			b.conn.RemoteAddr(),
 			b.version,
 			b.hostType,
 			friendlyDuration(time.Since(b.regTime)),
 			machStatus,
-			friendlyDuration(time.Since(b.inUseTime)))
+			friendlyDuration(time.Since(b.inUseTime)),
+			conns)
 		total[b.hostType]++
 		if b.inUse && !b.inHealthCheck {
 
@@ -318,6 +377,13 @@ func (p *ReverseBuildletPool) WriteHTMLStatus(w io.Writer) {
 		}
 	}
 	numConnected := len(buildlets)
+	var disconnects bytes.Buffer
+	for i := len(p.disconnects) - 1; i >= 0; i-- {
+		d := p.disconnects[i]
+		fmt.Fprintf(&disconnects, "<li>%s (%s), %s ago: %s</li>\n",
+			html.EscapeString(d.hostname), d.hostType,
+			friendlyDuration(time.Since(d.time)), html.EscapeString(d.reason))
+	}
 	p.mu.Unlock()
 
 	var typs []string
@@ -346,6 +412,34 @@ func (p *ReverseBuildletPool) WriteHTMLStatus(w io.Writer) {
 	io.WriteString(w, "</ul>\n")
 
 	fmt.Fprintf(w, "<b>Reverse pool machine detail</b><ul>%s</ul>", buf.Bytes())
+	if disconnects.Len() > 0 {
+		fmt.Fprintf(w, "<b>Recent reverse buildlet disconnects</b><ul>%s</ul>", disconnects.Bytes())
+	}
+}
+
+// RevdialStats returns the statistics of the revdial connections of
+// the reverse buildlets, summed by host type. The RTT of each host type
+// is the largest of its buildlets.
+func (p *ReverseBuildletPool) RevdialStats() map[string]revdial.Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m := make(map[string]revdial.Stats)
+	for _, b := range p.buildlets {
+		if b.dialer == nil {
+			continue
+		}
+		st := b.dialer.Stats()
+		sum := m[b.hostType]
+		sum.RTT = max(sum.RTT, st.RTT)
+		sum.Active += st.Active
+		sum.Pending += st.Pending
+		sum.Conns += st.Conns
+		sum.DialErrors += st.DialErrors
+		sum.BytesRead += st.BytesRead
+		sum.BytesWritten += st.BytesWritten
+		m[b.hostType] = sum
+	}
+	return m
 }
 
 func (p *ReverseBuildletPool) QuotaStats() map[string]*queue.QuotaStats {
@@ -419,18 +513,16 @@ func (p *ReverseBuildletPool) updateQuotas() {
 	p.updateQuotasLocked()
 }
 
+// updateQuotasLocked sets the limit of each host type's queue to the
+// number of its buildlets. Builds return the quota they use when
+// their buildlets leave the pool.
 func (p *ReverseBuildletPool) updateQuotasLocked() {
 	limits := make(map[string]int)
-	used := make(map[string]int)
 	for _, b := range p.buildlets {
 		limits[b.hostType] += 1
-		if b.inUse {
-			used[b.hostType] += 1
-		}
 	}
 	for hostType, limit := range limits {
-		q := p.hostTypeQueue(hostType)
-		q.UpdateQuotas(used[hostType], limit)
+		p.hostTypeQueue(hostType).UpdateLimit(limit)
 	}
 }
 
@@ -469,7 +561,8 @@ type reverseBuildlet struct {
 
 	client  buildlet.Client
 	conn    net.Conn
-	regTime time.Time // when it was first connected
+	dialer  *revdial.Dialer // or nil, in tests
+	regTime time.Time       // when it was first connected
 
 	// hostType is the configuration of this machine.
 	// It is the key into the dashboard.Hosts map.
@@ -482,6 +575,10 @@ type reverseBuildlet struct {
 	inUse         bool
 	inUseTime     time.Time
 	inHealthCheck bool
+
+	// quota is the quota held by the build using the buildlet, if
+	// any. It's guarded by the mutex on ReverseBuildletPool.
+	quota *queue.Item
 }
 
 // HandleReverse handles reverse buildlet connections.
@@ -540,7 +637,18 @@ func HandleReverse(w http.ResponseWriter, r *http.Request) {
 	log.Printf("Registering reverse buildlet %q (%s) for host type %v; buildletVersion=%v",
 		hostname, r.RemoteAddr, hostType, buildletVersion)
 
-	revDialer := revdial.NewDialer(conn, "/revdial")
+	var opts []revdial.DialerOption
+	if revdial.StreamsOffered(r) {
+		// Multiplex connections to the buildlet over conn
+		// rather than have it dial back for each one.
+		opts = append(opts, revdial.Multiplexed())
+	}
+	if ReverseTrace != nil {
+		if t := ReverseTrace(hostType); t != nil {
+			opts = append(opts, revdial.WithTrace(t))
+		}
+	}
+	revDialer := revdial.NewDialer(conn, "/revdial", opts...)
 	revDialerDone := revDialer.Done()
 	dialer := revDialer.Dial
 
@@ -572,6 +680,7 @@ func HandleReverse(w http.ResponseWriter, r *http.Request) {
 	// client proactively show
 	go func() {
 		<-revDialerDone
+		reversePool.recordDisconnect(hostname, hostType, revDialer.Stats().CloseReason)
 		isDead.Lock()
 		defer isDead.Unlock()
 		if !isDead.v {
@@ -600,6 +709,7 @@ func HandleReverse(w http.ResponseWriter, r *http.Request) {
 		hostType:  hostType,
 		client:    client,
 		conn:      conn,
+		dialer:    revDialer,
 		inUseTime: now,
 		regTime:   now,
 	}
@@ -0,0 +1,54 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+
+package pool
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/build/buildlet"
+	"golang.org/x/build/internal/coordinator/pool/queue"
+)
+
+// TestReverseQuotaReturned tests that a build's quota is returned
+// when its buildlet leaves the pool.
+func TestReverseQuotaReturned(t *testing.T) {
+	p := &ReverseBuildletPool{
+		hostQueue:    make(map[string]*queue.Quota),
+		hostLastGood: make(map[string]time.Time),
+	}
+	const hostType = "host-x"
+	for i := 0; i < 2; i++ {
+		c1, c2 := net.Pipe()
+		defer c2.Close()
+		p.buildlets = append(p.buildlets, &reverseBuildlet{
+			hostType: hostType,
+			client:   &buildlet.FakeClient{},
+			conn:     c1,
+		})
+	}
+	p.updateQuotas()
+	q := p.hostTypeQueue(hostType)
+
+	ctx := context.Background()
+	bc, err := p.GetBuildlet(ctx, hostType, noopEventTimeLogger{}, new(queue.SchedItem))
+	if err != nil {
+		t.Fatalf("GetBuildlet: %v", err)
+	}
+	if got := q.Quotas(); got.Used != 1 || got.Limit != 2 {
+		t.Errorf("quota after GetBuildlet = %+v; want 1 of 2 used", got)
+	}
+	p.nukeBuildlet(bc)
+	if got := q.Quotas(); got.Used != 0 || got.Limit != 1 {
+		t.Errorf("quota after the buildlet left = %+v; want 0 of 1 used", got)
+	}
+	if stats := q.ToExported(); len(stats.Items) != 0 {
+		t.Errorf("items waiting = %v; want none", stats.Items)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/build/buildlet"
+	"golang.org/x/build/internal/cloud"
+	"golang.org/x/build/internal/coordinator/pool/queue"
+)
+
+// SimulatedHost describes a host type of a SimulatedBuildlet pool.
+type SimulatedHost struct {
+	// Quota is the name of the quota that the host type's buildlets use.
+	Quota string
+	// CPU is the number of vCPUs that each buildlet reserves.
+	CPU int
+}
+
+// SimulatedBuildlet is a pool of buildlets that don't exist, for
+// simulating how work is scheduled. Like the EC2 pool, it reserves the
+// vCPUs of each buildlet in a ledger, with one ledger per quota. Its
+// buildlets are returned as soon as their quota is reserved, and
+// closing them returns it.
+type SimulatedBuildlet struct {
+	hosts   map[string]SimulatedHost
+	ledgers map[string]*ledger // by quota name
+
+	mu  sync.Mutex
+	seq int // of instance names
+}
+
+// NewSimulatedBuildlet returns a simulated pool with quotas of the given
+// vCPU limits, keyed by name, for the host types in hosts.
+func NewSimulatedBuildlet(quotas map[string]int, hosts map[string]SimulatedHost) (*SimulatedBuildlet, error) {
+	p := &SimulatedBuildlet{
+		hosts:   hosts,
+		ledgers: make(map[string]*ledger),
+	}
+	for name, limit := range quotas {
+		l := newLedger()
+		l.SetCPULimit(int64(limit))
+		p.ledgers[name] = l
+	}
+	for hostType, h := range hosts {
+		l, ok := p.ledgers[h.Quota]
+		if !ok {
+			return nil, fmt.Errorf("host type %s uses unknown quota %q", hostType, h.Quota)
+		}
+		if h.CPU <= 0 || h.CPU > quotas[h.Quota] {
+			return nil, fmt.Errorf("host type %s needs %d vCPUs of quota %s, which has %d", hostType, h.CPU, h.Quota, quotas[h.Quota])
+		}
+		l.UpdateInstanceTypes([]*cloud.InstanceType{{Type: hostType, CPU: int64(h.CPU)}})
+	}
+	return p, nil
+}
+
+// GetBuildlet reserves the quota for a buildlet of hostType and returns
+// a buildlet client that returns the quota when it's closed.
+func (p *SimulatedBuildlet) GetBuildlet(ctx context.Context, hostType string, lg Logger, si *queue.SchedItem) (buildlet.Client, error) {
+	h, ok := p.hosts[hostType]
+	if !ok {
+		return nil, fmt.Errorf("simulated pool: unknown host type %q", hostType)
+	}
+	l := p.ledgers[h.Quota]
+	p.mu.Lock()
+	p.seq++
+	instName := fmt.Sprintf("sim-%s-%d", strings.TrimPrefix(hostType, "host-"), p.seq)
+	p.mu.Unlock()
+
+	if err := l.ReserveResources(ctx, instName, hostType, si); err != nil {
+		return nil, err
+	}
+	l.UpdateReservation(instName, instName)
+	fc := new(buildlet.FakeClient)
+	fc.SetInstanceName(instName)
+	fc.SetName(instName)
+	return &simulatedClient{FakeClient: fc, remove: func() { l.Remove(instName) }}, nil
+}
+
+// Waiting returns the number of callers of GetBuildlet that are waiting
+// for quota.
+func (p *SimulatedBuildlet) Waiting() int {
+	n := 0
+	for _, l := range p.ledgers {
+		n += l.cpuQueue.Len()
+	}
+	return n
+}
+
+// Preempted returns the number of buildlets that the quotas have
+// preempted.
+func (p *SimulatedBuildlet) Preempted() int {
+	n := 0
+	for _, l := range p.ledgers {
+		n += l.cpuQueue.Preempted()
+	}
+	return n
+}
+
+// Quotas returns the vCPU usage of each quota, keyed by name.
+func (p *SimulatedBuildlet) Quotas() map[string]queue.Usage {
+	m := make(map[string]queue.Usage)
+	for name, l := range p.ledgers {
+		m[name] = l.cpuQueue.Quotas()
+	}
+	return m
+}
+
+// QuotaStats returns the state of the pool's quotas.
+func (p *SimulatedBuildlet) QuotaStats() map[string]*queue.QuotaStats {
+	m := make(map[string]*queue.QuotaStats)
+	for name, l := range p.ledgers {
+		m["sim-"+name] = l.cpuQueue.ToExported()
+	}
+	return m
+}
+
+// String gives a report of the quotas of the simulated pool.
+func (p *SimulatedBuildlet) String() string {
+	var names []string
+	for name := range p.ledgers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Simulated pool:")
+	for _, name := range names {
+		r := p.ledgers[name].Resources()
+		fmt.Fprintf(&sb, " %s %d/%d vCPUs;", name, r.CPUUsed, r.CPULimit)
+	}
+	return strings.TrimSuffix(sb.String(), ";")
+}
+
+// simulatedClient is a buildlet client of a SimulatedBuildlet.
+type simulatedClient struct {
+	*buildlet.FakeClient
+	once   sync.Once
+	remove func()
+}
+
+// Close returns the buildlet's quota to the pool.
+func (c *simulatedClient) Close() error {
+	c.once.Do(c.remove)
+	return c.FakeClient.Close()
+}
@@ -32,6 +32,7 @@ type Session struct {
 	ID          string // unique identifier for instance "user-bradfitz-linux-amd64-0"
 	OwnerID     string // identity aware proxy user id: "accounts.google.com:userIDvalue"
 	buildlet    buildlet.Client
+	user        string // displayed user name, for Usage
 }
 
 // renew extends the expiration timestamp for a session.
@@ -54,6 +55,22 @@ type SessionPool struct {
 	pollWait   sync.WaitGroup
 	cancelPoll context.CancelFunc
 	m          map[string]*Session // keyed by buildletName
+	ended      map[usageKey]*Usage // usage of sessions no longer in m
+	onDestroy  func(name string)   // if non-nil, called after a session is removed
+}
+
+// Usage is the instance time used by one owner's sessions of one builder type.
+type Usage struct {
+	OwnerID     string
+	User        string // displayed user name
+	BuilderType string
+	Sessions    int           // sessions created
+	Active      int           // sessions still in the pool
+	Duration    time.Duration // total lifetime of the sessions so far
+}
+
+type usageKey struct {
+	ownerID, builderType string
 }
 
 // NewSessionPool creates a session pool which stores and provides access to active remote buildlet sessions.
@@ -63,6 +80,7 @@ func NewSessionPool(ctx context.Context) *SessionPool {
 	sp := &SessionPool{
 		cancelPoll: cancel,
 		m:          map[string]*Session{},
+		ended:      map[usageKey]*Usage{},
 	}
 	sp.pollWait.Add(1)
 	go func() {
@@ -91,12 +109,50 @@ func (sp *SessionPool) AddSession(ownerID, username, builderType, hostType strin
 				HostType:    hostType,
 				ID:          name,
 				OwnerID:     ownerID,
+				user:        username,
 			}
 			return name
 		}
 	}
 }
 
+// RestoreSession adds a session with the given name and metadata to the
+// session pool, for the buildlet of a session that connected again after
+// its connection was lost or the server restarted. If the pool still has
+// the session, it replaces its buildlet client instead, and returns the
+// previous client, which the caller must close.
+func (sp *SessionPool) RestoreSession(name, ownerID, username, builderType, hostType string, created time.Time, bc buildlet.Client) (old buildlet.Client) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	s, ok := sp.m[name]
+	if !ok {
+		sp.m[name] = &Session{
+			BuilderType: builderType,
+			buildlet:    bc,
+			Created:     created,
+			Expires:     time.Now().Add(remoteBuildletIdleTimeout),
+			HostType:    hostType,
+			ID:          name,
+			OwnerID:     ownerID,
+			user:        username,
+		}
+		return nil
+	}
+	old = s.buildlet
+	s.buildlet = bc
+	s.renew()
+	return old
+}
+
+// SetOnDestroy sets a function to call with the name of each session
+// after it is removed from the pool, because it was destroyed or it
+// expired, and before its buildlet client is closed.
+func (sp *SessionPool) SetOnDestroy(f func(name string)) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.onDestroy = f
+}
+
 // IsSession is true if the instance is found in the session pool. The instance name is the not the public
 // name of the instance. It is the name of the instance as it is tracked in the cloud service.
 func (sp *SessionPool) IsSession(instName string) bool {
@@ -119,12 +175,17 @@ func (sp *SessionPool) destroyExpiredSessions(ctx context.Context) {
 		if s.isExpired() {
 			ss = append(ss, s)
 			delete(sp.m, name)
+			sp.recordEnded(s)
 		}
 	}
+	onDestroy := sp.onDestroy
 	sp.mu.Unlock()
 	// the sessions are no longer in the map. They can be mutated.
 	for _, s := range ss {
 		log.Printf("remote: destroying expired buildlet %s", s.ID)
+		if onDestroy != nil {
+			onDestroy(s.ID)
+		}
 		if err := s.buildlet.Close(); err != nil {
 			log.Printf("remote: unable to close buildlet connection for %s: %s", s.ID, err)
 		}
@@ -137,17 +198,70 @@ func (sp *SessionPool) DestroySession(buildletName string) error {
 	s, ok := sp.m[buildletName]
 	if ok {
 		delete(sp.m, buildletName)
+		sp.recordEnded(s)
 	}
+	onDestroy := sp.onDestroy
 	sp.mu.Unlock()
 	if !ok {
 		return fmt.Errorf("remote buildlet does not exist=%s", buildletName)
 	}
+	if onDestroy != nil {
+		onDestroy(buildletName)
+	}
 	if err := s.buildlet.Close(); err != nil {
 		log.Printf("remote: unable to close buildlet connection %s: %s", buildletName, err)
 	}
 	return nil
 }
 
+// recordEnded adds the lifetime of s, which has been removed from the pool,
+// to the usage of ended sessions. The SessionPool lock should be held before calling.
+func (sp *SessionPool) recordEnded(s *Session) {
+	k := usageKey{s.OwnerID, s.BuilderType}
+	u, ok := sp.ended[k]
+	if !ok {
+		u = &Usage{OwnerID: s.OwnerID, User: s.user, BuilderType: s.BuilderType}
+		sp.ended[k] = u
+	}
+	u.Sessions++
+	u.Duration += time.Since(s.Created)
+}
+
+// Usage returns the instance time used by all sessions created in the pool,
+// by owner and builder type, sorted by user and builder type.
+func (sp *SessionPool) Usage() []Usage {
+	sp.mu.RLock()
+	defer sp.mu.RUnlock()
+
+	m := make(map[usageKey]*Usage)
+	for k, u := range sp.ended {
+		u := *u
+		m[k] = &u
+	}
+	for _, s := range sp.m {
+		k := usageKey{s.OwnerID, s.BuilderType}
+		u, ok := m[k]
+		if !ok {
+			u = &Usage{OwnerID: s.OwnerID, User: s.user, BuilderType: s.BuilderType}
+			m[k] = u
+		}
+		u.Sessions++
+		u.Active++
+		u.Duration += time.Since(s.Created)
+	}
+	var us []Usage
+	for _, u := range m {
+		us = append(us, *u)
+	}
+	sort.Slice(us, func(i, j int) bool {
+		if us[i].User != us[j].User {
+			return us[i].User < us[j].User
+		}
+		return us[i].BuilderType < us[j].BuilderType
+	})
+	return us
+}
+
 // Close cancels the polling performed by the session pool. It waits for polling to conclude
 // before returning.
 func (sp *SessionPool) Close() {
@@ -98,6 +98,70 @@ func TestSessionPoolDestroySession(t *testing.T) {
 	}
 }
 
+func TestSessionPoolUsage(t *testing.T) {
+	sp := NewSessionPool(context.Background())
+	defer sp.Close()
+
+	name := sp.AddSession("accounts.google.com:user-xyz-124", "xyz", "linux-amd64", "host", &buildlet.FakeClient{})
+	sp.AddSession("accounts.google.com:user-xyz-124", "xyz", "linux-amd64", "host", &buildlet.FakeClient{})
+	sp.AddSession("accounts.google.com:user-abc-123", "abc", "windows-amd64", "host", &buildlet.FakeClient{})
+	if err := sp.DestroySession(name); err != nil {
+		t.Fatalf("SessionPool.DestroySession(%q) = %s; want no error", name, err)
+	}
+	got := sp.Usage()
+	if len(got) != 2 {
+		t.Fatalf("SessionPool.Usage() = %+v; want 2 entries", got)
+	}
+	if u := got[0]; u.User != "abc" || u.BuilderType != "windows-amd64" || u.Sessions != 1 || u.Active != 1 {
+		t.Errorf("SessionPool.Usage()[0] = %+v; want 1 active windows-amd64 session for abc", u)
+	}
+	if u := got[1]; u.User != "xyz" || u.Sessions != 2 || u.Active != 1 || u.Duration <= 0 {
+		t.Errorf("SessionPool.Usage()[1] = %+v; want 2 sessions, 1 active, for xyz", u)
+	}
+}
+
+func TestSessionPoolRestoreSession(t *testing.T) {
+	sp := NewSessionPool(context.Background())
+	defer sp.Close()
+	var destroyed []string
+	sp.SetOnDestroy(func(name string) { destroyed = append(destroyed, name) })
+
+	created := time.Now().Add(-time.Hour)
+	first := &buildlet.FakeClient{}
+	if old := sp.RestoreSession("xyz-linux-amd64-0", "accounts.google.com:user-xyz-124", "xyz", "linux-amd64", "host", created, first); old != nil {
+		t.Errorf("SessionPool.RestoreSession() of a new session = %v; want nil", old)
+	}
+	s, err := sp.Session("xyz-linux-amd64-0")
+	if err != nil {
+		t.Fatalf("SessionPool.Session() of a restored session = %s; want no error", err)
+	}
+	if s.OwnerID != "accounts.google.com:user-xyz-124" || s.BuilderType != "linux-amd64" {
+		t.Errorf("SessionPool.Session() = %+v; want the restored metadata", s)
+	}
+	if u := sp.Usage(); len(u) != 1 || u[0].Duration < time.Hour {
+		t.Errorf("SessionPool.Usage() = %+v; want one session of at least an hour", u)
+	}
+
+	// Restoring an existing session replaces its buildlet client.
+	bc := &buildlet.FakeClient{}
+	if old := sp.RestoreSession("xyz-linux-amd64-0", "accounts.google.com:user-xyz-124", "xyz", "linux-amd64", "host", created, bc); old != first {
+		t.Errorf("SessionPool.RestoreSession() of an existing session = %v; want the previous client", old)
+	}
+	if got, err := sp.BuildletClient("xyz-linux-amd64-0"); err != nil || got != bc {
+		t.Errorf("SessionPool.BuildletClient() = %v, %v; want the new client", got, err)
+	}
+	if sp.Len() != 1 {
+		t.Errorf("SessionPool.Len() = %d; want 1", sp.Len())
+	}
+
+	if err := sp.DestroySession("xyz-linux-amd64-0"); err != nil {
+		t.Fatal(err)
+	}
+	if len(destroyed) != 1 || destroyed[0] != "xyz-linux-amd64-0" {
+		t.Errorf("OnDestroy called with %q; want [xyz-linux-amd64-0]", destroyed)
+	}
+}
+
 func TestRenewTimeout(t *testing.T) {
 	sp := NewSessionPool(context.Background())
 	defer sp.Close()
@@ -16,6 +16,7 @@ import (
 
 	"golang.org/x/build/buildlet"
 	"golang.org/x/build/dashboard"
+	"golang.org/x/build/internal/coordinator/clock"
 	"golang.org/x/build/internal/coordinator/pool"
 	"golang.org/x/build/internal/coordinator/pool/queue"
 	"golang.org/x/build/internal/spanlog"
@@ -38,6 +39,8 @@ type Scheduler struct {
 	hostsCreating map[string]int // hostType -> count
 
 	lastProgress map[string]time.Time // hostType -> time last delivered buildlet
+
+	preempted map[string]int // hostType -> count of preempted buildlets
 }
 
 // NewScheduler returns a new scheduler.
@@ -46,6 +49,7 @@ func NewScheduler() *Scheduler {
 		hostsCreating: make(map[string]int),
 		waiting:       make(map[string]map[*queue.SchedItem]bool),
 		lastProgress:  make(map[string]time.Time),
+		preempted:     make(map[string]int),
 	}
 	return s
 }
@@ -95,7 +99,7 @@ type SchedulerWaitingState struct {
 
 func (st *SchedulerWaitingState) add(si *queue.SchedItem) {
 	st.Count++
-	age := time.Since(si.RequestTime).Round(time.Second)
+	age := clock.Since(si.RequestTime).Round(time.Second)
 	if st.Newest == 0 || age < st.Newest {
 		st.Newest = age
 	}
@@ -104,6 +108,14 @@ func (st *SchedulerWaitingState) add(si *queue.SchedItem) {
 	}
 }
 
+// SchedulerClassState is the state of the callers waiting in a
+// priority class.
+type SchedulerClassState struct {
+	Name     string
+	Priority queue.BuildletPriority
+	SchedulerWaitingState
+}
+
 type SchedulerHostState struct {
 	HostType     string
 	LastProgress time.Duration
@@ -111,22 +123,41 @@ type SchedulerHostState struct {
 	Gomote       SchedulerWaitingState
 	Try          SchedulerWaitingState
 	Regular      SchedulerWaitingState
+	Classes      []SchedulerClassState // sorted by priority
+	Preempted    int                   // buildlets taken back from preemptible work
 }
 
 type SchedulerState struct {
 	HostTypes []SchedulerHostState
+
+	// The scheduling policy's fair sharing and preemption settings.
+	FairShare    bool
+	PreemptAfter time.Duration
 }
 
 func (s *Scheduler) State() (st SchedulerState) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	policy := queue.CurrentPolicy()
+	st.FairShare = policy.FairShare
+	st.PreemptAfter = policy.PreemptAfter
+
+	hostTypes := make(map[string]bool)
 	for hostType, m := range s.waiting {
-		if len(m) == 0 {
-			continue
+		if len(m) != 0 {
+			hostTypes[hostType] = true
 		}
+	}
+	for hostType := range s.preempted {
+		hostTypes[hostType] = true
+	}
+	for hostType := range hostTypes {
+		m := s.waiting[hostType]
 		var hst SchedulerHostState
 		hst.HostType = hostType
+		hst.Preempted = s.preempted[hostType]
+		classes := make(map[string]*SchedulerClassState)
 		for si := range m {
 			hst.Total.add(si)
 			if si.IsGomote {
@@ -136,9 +167,26 @@ func (s *Scheduler) State() (st SchedulerState) {
 			} else {
 				hst.Regular.add(si)
 			}
+			c := si.Class()
+			cst, ok := classes[c.Name]
+			if !ok {
+				cst = &SchedulerClassState{Name: c.Name, Priority: c.Priority}
+				classes[c.Name] = cst
+			}
+			cst.add(si)
+		}
+		for _, cst := range classes {
+			hst.Classes = append(hst.Classes, *cst)
 		}
+		sort.Slice(hst.Classes, func(i, j int) bool {
+			ci, cj := hst.Classes[i], hst.Classes[j]
+			if ci.Priority != cj.Priority {
+				return ci.Priority < cj.Priority
+			}
+			return ci.Name < cj.Name
+		})
 		if lp := s.lastProgress[hostType]; !lp.IsZero() {
-			lastProgressAgo := time.Since(lp)
+			lastProgressAgo := clock.Since(lp)
 			if lastProgressAgo < hst.Total.Oldest {
 				hst.LastProgress = lastProgressAgo.Round(time.Second)
 			}
@@ -174,7 +222,15 @@ func (s *Scheduler) GetBuildlet(ctx context.Context, si *queue.SchedItem) (build
 	if !ok && pool.TestPoolHook == nil {
 		return nil, fmt.Errorf("invalid SchedItem.HostType %q", si.HostType)
 	}
-	si.RequestTime = time.Now()
+	si.RequestTime = clock.Now()
+	if onPreempt := si.OnPreempt; onPreempt != nil {
+		si.OnPreempt = func() {
+			s.mu.Lock()
+			s.preempted[si.HostType]++
+			s.mu.Unlock()
+			onPreempt()
+		}
+	}
 
 	s.addWaiter(si)
 	defer s.removeWaiter(si)
@@ -205,3 +205,48 @@ func TestScheduler(t *testing.T) {
 		})
 	}
 }
+
+// preemptingPool is a buildlet pool that preempts each item as soon as
+// it gives it a buildlet.
+type preemptingPool struct{}
+
+func (preemptingPool) GetBuildlet(ctx context.Context, hostType string, lg cpool.Logger, item *queue.SchedItem) (buildlet.Client, error) {
+	item.OnPreempt()
+	return &buildlet.FakeClient{}, nil
+}
+
+func (preemptingPool) String() string { return "preempting pool" }
+
+func TestSchedulerState(t *testing.T) {
+	defer func() { cpool.TestPoolHook = nil }()
+	cpool.TestPoolHook = func(*dashboard.HostConfig) cpool.Buildlet { return preemptingPool{} }
+
+	s := NewScheduler()
+	now := time.Now()
+	s.addWaiter(&queue.SchedItem{HostType: "host-a", IsTry: true, RequestTime: now})
+	s.addWaiter(&queue.SchedItem{HostType: "host-a", IsTry: true, RequestTime: now})
+	s.addWaiter(&queue.SchedItem{HostType: "host-a", IsGomote: true, RequestTime: now})
+	preempted := false
+	if _, err := s.GetBuildlet(context.Background(), &queue.SchedItem{HostType: "host-b", OnPreempt: func() { preempted = true }}); err != nil {
+		t.Fatal(err)
+	}
+	if !preempted {
+		t.Errorf("the caller's OnPreempt wasn't called")
+	}
+
+	st := s.State()
+	if len(st.HostTypes) != 2 {
+		t.Fatalf("State() has %d host types; want 2", len(st.HostTypes))
+	}
+	a, b := st.HostTypes[0], st.HostTypes[1]
+	var classes []string
+	for _, c := range a.Classes {
+		classes = append(classes, fmt.Sprintf("%s:%d", c.Name, c.Count))
+	}
+	if got, want := fmt.Sprint(classes), "[gomote:1 try:2]"; got != want {
+		t.Errorf("host-a classes = %s; want %s", got, want)
+	}
+	if b.HostType != "host-b" || b.Preempted != 1 || b.Total.Count != 0 {
+		t.Errorf("host-b state = %+v; want 1 preempted and none waiting", b)
+	}
+}
@@ -63,6 +63,15 @@ func CreateSpan(el pool.EventTimeLogger, event string, optText ...string) *Span
 	}
 }
 
+// Annotate adds text to the span's optional details,
+// which are logged when the span is done.
+func (s *Span) Annotate(text string) {
+	if s.optText != "" {
+		s.optText += "; "
+	}
+	s.optText += text
+}
+
 // Done ends a span.
 // It is legal to call Done multiple times. Only the first call
 // logs.
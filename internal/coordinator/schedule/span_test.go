@@ -7,6 +7,7 @@
 package schedule
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -34,3 +35,16 @@ func TestSpan(t *testing.T) {
 		t.Errorf("EventTimeLogger.optText = %+v; want entries", l.optText)
 	}
 }
+
+func TestSpanAnnotate(t *testing.T) {
+	l := &fakeEventTimeLogger{}
+	s := CreateSpan(l, "make", "go1.22")
+	s.Annotate("wall=1s")
+	s.Done(nil)
+	if want := "go1.22; wall=1s"; s.OptText() != want {
+		t.Errorf("OptText() = %q, want %q", s.OptText(), want)
+	}
+	if len(l.optText) != 1 || !strings.HasSuffix(l.optText[0], "; go1.22; wall=1s") {
+		t.Errorf("EventTimeLogger.optText = %q, want text ending in the annotation", l.optText)
+	}
+}
@@ -0,0 +1,419 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+
+// Package simulate replays recorded buildlet requests against the
+// coordinator's scheduler, quotas and scheduling policy on a virtual
+// clock, to evaluate changes to them without deploying.
+package simulate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/build/buildlet"
+	"golang.org/x/build/dashboard"
+	"golang.org/x/build/internal/coordinator/clock"
+	"golang.org/x/build/internal/coordinator/pool"
+	"golang.org/x/build/internal/coordinator/pool/queue"
+	"golang.org/x/build/internal/coordinator/schedule"
+)
+
+// A Workload is a recorded trace of buildlet requests and the
+// quotas that served them.
+type Workload struct {
+	// Quotas are the vCPU limits of the quotas, keyed by name.
+	Quotas map[string]int
+	// Hosts are the host types that the requests use.
+	Hosts map[string]pool.SimulatedHost
+	// Requests are the buildlet requests, in any order.
+	Requests []Request
+}
+
+// A Request is a recorded request for a buildlet.
+type Request struct {
+	// Time is when the buildlet was requested.
+	Time     time.Time
+	HostType string
+	// Kind is the kind of work, such as queue.KindTry.
+	Kind   string
+	Repo   string
+	Branch string
+	// User is the owner of a gomote.
+	User string
+	// CommitTime is the commit time of post-submit work.
+	CommitTime time.Time
+	// StartLatency is how long the buildlet took to start once its quota
+	// was reserved.
+	StartLatency Duration
+	// Duration is how long the work used the buildlet once it started.
+	Duration Duration
+}
+
+// Duration is a time.Duration that is encoded in JSON as a string
+// accepted by time.ParseDuration.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// ParseWorkload parses a workload from its JSON encoding.
+func ParseWorkload(data []byte) (*Workload, error) {
+	w := new(Workload)
+	if err := json.Unmarshal(data, w); err != nil {
+		return nil, fmt.Errorf("invalid workload: %w", err)
+	}
+	return w, nil
+}
+
+func (r *Request) schedItem() (*queue.SchedItem, error) {
+	si := &queue.SchedItem{
+		HostType:   r.HostType,
+		Repo:       r.Repo,
+		Branch:     r.Branch,
+		User:       r.User,
+		CommitTime: r.CommitTime,
+	}
+	switch r.Kind {
+	case queue.KindRelease:
+		si.IsRelease = true
+	case queue.KindGomote:
+		si.IsGomote = true
+	case queue.KindTry:
+		si.IsTry = true
+	case queue.KindPostSubmit, "":
+	default:
+		return nil, fmt.Errorf("request at %v has unknown kind %q", r.Time, r.Kind)
+	}
+	return si, nil
+}
+
+// A Report summarizes a simulation.
+type Report struct {
+	Start, End time.Time
+	Hosts      []HostReport  // sorted by host type
+	Quotas     []QuotaReport // sorted by name
+}
+
+// A HostReport summarizes the requests for a host type.
+type HostReport struct {
+	HostType  string
+	Requests  int
+	Preempted int // requests that lost their buildlet
+	// Wait is how long requests waited for quota, and Ready is how long
+	// they waited for a started buildlet.
+	Wait, Ready Percentiles
+	// Peak is the largest number of buildlets held at once.
+	Peak int
+	// Buildlets is the average number of buildlets held.
+	Buildlets float64
+}
+
+// A QuotaReport summarizes the use of a quota.
+type QuotaReport struct {
+	Name  string
+	Limit int
+	Peak  int     // the most vCPUs used at once
+	Used  float64 // the average number of vCPUs used
+}
+
+// Utilization returns the fraction of the quota used on average.
+func (q QuotaReport) Utilization() float64 {
+	if q.Limit == 0 {
+		return 0
+	}
+	return q.Used / float64(q.Limit)
+}
+
+// Percentiles summarizes a distribution of durations.
+type Percentiles struct {
+	P50, P90, P99, Max time.Duration
+}
+
+func percentiles(ds []time.Duration) Percentiles {
+	if len(ds) == 0 {
+		return Percentiles{}
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+	// rank returns the nearest-rank percentile p.
+	rank := func(p int) time.Duration {
+		i := (p*len(ds)+99)/100 - 1
+		return ds[max(i, 0)]
+	}
+	return Percentiles{P50: rank(50), P90: rank(90), P99: rank(99), Max: ds[len(ds)-1]}
+}
+
+// Write writes the report as tables.
+func (r *Report) Write(w io.Writer) error {
+	elapsed := r.End.Sub(r.Start)
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Simulated %v from %v.\n\n", elapsed, r.Start.Format(time.RFC3339))
+	fmt.Fprintf(tw, "HOST TYPE\tREQUESTS\tPREEMPTED\tWAIT P50\tP90\tP99\tMAX\tREADY P50\tP90\tP99\tPEAK\tAVG BUILDLETS\n")
+	for _, h := range r.Hosts {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%d\t%.1f\n",
+			h.HostType, h.Requests, h.Preempted,
+			h.Wait.P50, h.Wait.P90, h.Wait.P99, h.Wait.Max,
+			h.Ready.P50, h.Ready.P90, h.Ready.P99,
+			h.Peak, h.Buildlets)
+	}
+	fmt.Fprintf(tw, "\nQUOTA\tLIMIT\tPEAK\tAVG USED\tUTILIZATION\n")
+	for _, q := range r.Quotas {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%.1f%%\n", q.Name, q.Limit, q.Peak, q.Used, 100*q.Utilization())
+	}
+	return tw.Flush()
+}
+
+// request is the state of a simulated request.
+type request struct {
+	*Request
+	granted   time.Time // when GetBuildlet returned
+	released  time.Time
+	bc        buildlet.Client
+	hold      clock.Timer // releases the buildlet when the work is done
+	preempted bool
+}
+
+// A simulation is the state of a call to Run.
+type simulation struct {
+	w     *Workload
+	clock *clock.Virtual
+	pool  *pool.SimulatedBuildlet
+	sched *schedule.Scheduler
+
+	mu        sync.Mutex
+	err       error
+	inFlight  int // requests that haven't got or failed to get a buildlet
+	preempted int // preemptions handled
+	done      []*request
+	held      map[string]int     // host type -> buildlets held
+	peak      map[string]int     // host type -> most buildlets held
+	cpuTime   map[string]float64 // quota -> vCPU seconds used
+	peakUsed  map[string]int     // quota -> most vCPUs used
+}
+
+// Run simulates the scheduling of the workload's requests under the
+// policy p, and reports how long they waited and how much quota they
+// used. The simulation replaces the clock, the policy and the buildlet
+// pools of the whole process while it runs, so Run must not be called
+// concurrently or in a process that schedules real work.
+func Run(w *Workload, p *queue.Policy) (*Report, error) {
+	reqs := make([]*request, len(w.Requests))
+	for i := range w.Requests {
+		r := &w.Requests[i]
+		if _, ok := w.Hosts[r.HostType]; !ok {
+			return nil, fmt.Errorf("request at %v has unknown host type %q", r.Time, r.HostType)
+		}
+		if _, err := r.schedItem(); err != nil {
+			return nil, err
+		}
+		reqs[i] = &request{Request: r}
+	}
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("workload has no requests")
+	}
+	sort.SliceStable(reqs, func(i, j int) bool { return reqs[i].Time.Before(reqs[j].Time) })
+	sp, err := pool.NewSimulatedBuildlet(w.Quotas, w.Hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &simulation{
+		w:        w,
+		clock:    clock.NewVirtual(reqs[0].Time),
+		pool:     sp,
+		sched:    schedule.NewScheduler(),
+		held:     make(map[string]int),
+		peak:     make(map[string]int),
+		cpuTime:  make(map[string]float64),
+		peakUsed: make(map[string]int),
+	}
+	defer clock.Set(s.clock)()
+	oldPolicy := queue.CurrentPolicy()
+	queue.SetPolicy(p)
+	defer queue.SetPolicy(oldPolicy)
+	oldHook := pool.TestPoolHook
+	pool.TestPoolHook = func(*dashboard.HostConfig) pool.Buildlet { return sp }
+	defer func() { pool.TestPoolHook = oldHook }()
+
+	// Each step starts the next request or fires the next timer, and
+	// then waits until every request is waiting for quota or the clock.
+	for len(reqs) > 0 || s.pending() {
+		if err := s.settle(); err != nil {
+			return nil, err
+		}
+		next, ok := s.clock.Next()
+		if len(reqs) > 0 && (!ok || reqs[0].Time.Before(next)) {
+			s.advanceTo(reqs[0].Time)
+			s.start(reqs[0])
+			reqs = reqs[1:]
+			continue
+		}
+		if !ok {
+			break
+		}
+		s.advanceTo(next)
+	}
+	if err := s.settle(); err != nil {
+		return nil, err
+	}
+	if s.inFlight > 0 {
+		return nil, fmt.Errorf("%d requests never got a buildlet", s.inFlight)
+	}
+	return s.report(), nil
+}
+
+// pending reports whether any timers are pending.
+func (s *simulation) pending() bool {
+	_, ok := s.clock.Next()
+	return ok
+}
+
+// settle waits until every request in flight is waiting for quota and
+// every preemption has been handled, so that nothing changes until the
+// clock advances. It returns the first error from GetBuildlet.
+func (s *simulation) settle() error {
+	for {
+		s.mu.Lock()
+		settled := s.inFlight == s.pool.Waiting() && s.preempted == s.pool.Preempted()
+		err := s.err
+		s.mu.Unlock()
+		if err != nil || settled {
+			return err
+		}
+		runtime.Gosched()
+	}
+}
+
+// advanceTo accounts for the quota used until t, and advances the clock.
+func (s *simulation) advanceTo(t time.Time) {
+	d := t.Sub(s.clock.Now())
+	s.mu.Lock()
+	for name, u := range s.pool.Quotas() {
+		s.cpuTime[name] += float64(u.Used) * d.Seconds()
+		s.peakUsed[name] = max(s.peakUsed[name], u.Used)
+	}
+	s.mu.Unlock()
+	s.clock.AdvanceTo(t)
+}
+
+// start requests a buildlet for r.
+func (s *simulation) start(r *request) {
+	si, _ := r.schedItem()
+	si.OnPreempt = func() { s.preempt(r) }
+	s.mu.Lock()
+	s.inFlight++
+	s.mu.Unlock()
+	go func() {
+		bc, err := s.sched.GetBuildlet(context.Background(), si)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.inFlight--
+		if err != nil {
+			s.err = fmt.Errorf("request at %v: %w", r.Time, err)
+			return
+		}
+		r.bc = bc
+		r.granted = clock.Now()
+		s.held[r.HostType]++
+		s.peak[r.HostType] = max(s.peak[r.HostType], s.held[r.HostType])
+		if r.preempted {
+			s.releaseLocked(r)
+			return
+		}
+		r.hold = clock.AfterFunc(time.Duration(r.StartLatency+r.Duration), func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.releaseLocked(r)
+		})
+	}()
+}
+
+// preempt takes the buildlet of r back, once it has one.
+func (s *simulation) preempt(r *request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r.preempted = true
+	if r.bc != nil && r.released.IsZero() {
+		r.hold.Stop()
+		s.releaseLocked(r)
+	}
+	s.preempted++
+}
+
+// releaseLocked closes the buildlet of r. The lock s.mu must be held.
+func (s *simulation) releaseLocked(r *request) {
+	r.released = clock.Now()
+	s.held[r.HostType]--
+	s.done = append(s.done, r)
+	r.bc.Close()
+}
+
+func (s *simulation) report() *Report {
+	rep := &Report{End: s.clock.Now()}
+	waits := make(map[string][]time.Duration)
+	readies := make(map[string][]time.Duration)
+	hosts := make(map[string]*HostReport)
+	busy := make(map[string]time.Duration)
+	for _, r := range s.done {
+		if rep.Start.IsZero() || r.Time.Before(rep.Start) {
+			rep.Start = r.Time
+		}
+		h, ok := hosts[r.HostType]
+		if !ok {
+			h = &HostReport{HostType: r.HostType, Peak: s.peak[r.HostType]}
+			hosts[r.HostType] = h
+		}
+		h.Requests++
+		if r.preempted {
+			h.Preempted++
+		}
+		wait := r.granted.Sub(r.Time)
+		waits[r.HostType] = append(waits[r.HostType], wait)
+		if ready := r.granted.Add(time.Duration(r.StartLatency)); !r.released.Before(ready) {
+			readies[r.HostType] = append(readies[r.HostType], ready.Sub(r.Time))
+		}
+		busy[r.HostType] += r.released.Sub(r.granted)
+	}
+	elapsed := rep.End.Sub(rep.Start)
+	for hostType, h := range hosts {
+		h.Wait = percentiles(waits[hostType])
+		h.Ready = percentiles(readies[hostType])
+		if elapsed > 0 {
+			h.Buildlets = float64(busy[hostType]) / float64(elapsed)
+		}
+		rep.Hosts = append(rep.Hosts, *h)
+	}
+	sort.Slice(rep.Hosts, func(i, j int) bool { return rep.Hosts[i].HostType < rep.Hosts[j].HostType })
+	for name, limit := range s.w.Quotas {
+		q := QuotaReport{Name: name, Limit: limit, Peak: s.peakUsed[name]}
+		if elapsed > 0 {
+			q.Used = s.cpuTime[name] / elapsed.Seconds()
+		}
+		rep.Quotas = append(rep.Quotas, q)
+	}
+	sort.Slice(rep.Quotas, func(i, j int) bool { return rep.Quotas[i].Name < rep.Quotas[j].Name })
+	return rep
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+
+package simulate
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/build/internal/coordinator/pool/queue"
+)
+
+var testWorkload = `{
+	"Quotas": {"q": 4},
+	"Hosts": {"host-a": {"Quota": "q", "CPU": 2}},
+	"Requests": [
+		{"Time": "2026-01-01T00:00:02Z", "HostType": "host-a", "Kind": "try", "Repo": "go", "StartLatency": "1m", "Duration": "9m"},
+		{"Time": "2026-01-01T00:00:00Z", "HostType": "host-a", "Repo": "go", "StartLatency": "1m", "Duration": "9m"},
+		{"Time": "2026-01-01T00:00:01Z", "HostType": "host-a", "Repo": "net", "StartLatency": "1m", "Duration": "9m"}
+	]
+}`
+
+func TestRun(t *testing.T) {
+	w, err := ParseWorkload([]byte(testWorkload))
+	if err != nil {
+		t.Fatalf("ParseWorkload() = %v", err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		desc   string
+		policy *queue.Policy
+		want   *Report
+	}{
+		{
+			desc:   "default",
+			policy: queue.DefaultPolicy(),
+			// The try work waits for the first post-submit build to finish.
+			want: &Report{
+				Start: start,
+				End:   start.Add(20 * time.Minute),
+				Hosts: []HostReport{{
+					HostType:  "host-a",
+					Requests:  3,
+					Wait:      Percentiles{P90: 9*time.Minute + 58*time.Second, P99: 9*time.Minute + 58*time.Second, Max: 9*time.Minute + 58*time.Second},
+					Ready:     Percentiles{P50: time.Minute, P90: 10*time.Minute + 58*time.Second, P99: 10*time.Minute + 58*time.Second, Max: 10*time.Minute + 58*time.Second},
+					Peak:      2,
+					Buildlets: 1.5,
+				}},
+				Quotas: []QuotaReport{{Name: "q", Limit: 4, Peak: 4, Used: 3}},
+			},
+		},
+		{
+			desc: "preempt",
+			policy: &queue.Policy{
+				Classes: []queue.PriorityClass{
+					{Name: "post-submit", Priority: queue.PriorityBatch, Kinds: []string{queue.KindPostSubmit}, Preemptible: true},
+				},
+				PreemptAfter: 5 * time.Minute,
+			},
+			// The try work preempts the newest post-submit build.
+			want: &Report{
+				Start: start,
+				End:   start.Add(15*time.Minute + 2*time.Second),
+				Hosts: []HostReport{{
+					HostType:  "host-a",
+					Requests:  3,
+					Preempted: 1,
+					Wait:      Percentiles{P90: 5 * time.Minute, P99: 5 * time.Minute, Max: 5 * time.Minute},
+					Ready:     Percentiles{P50: time.Minute, P90: 6 * time.Minute, P99: 6 * time.Minute, Max: 6 * time.Minute},
+					Peak:      2,
+					Buildlets: float64(25*time.Minute+time.Second) / float64(15*time.Minute+2*time.Second),
+				}},
+				Quotas: []QuotaReport{{Name: "q", Limit: 4, Peak: 4, Used: 2 * float64(25*time.Minute+time.Second) / float64(15*time.Minute+2*time.Second)}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := Run(w, tc.policy)
+			if err != nil {
+				t.Fatalf("Run() = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+			var buf bytes.Buffer
+			if err := got.Write(&buf); err != nil {
+				t.Fatalf("Write() = %v", err)
+			}
+			if !strings.Contains(buf.String(), "host-a") {
+				t.Errorf("Write() = %q; want a row for host-a", buf.String())
+			}
+		})
+	}
+}
+
+func TestRunError(t *testing.T) {
+	for _, s := range []string{
+		`{"Quotas": {"q": 4}, "Hosts": {"host-a": {"Quota": "q", "CPU": 2}}}`,
+		`{"Quotas": {"q": 4}, "Hosts": {"host-a": {"Quota": "q", "CPU": 8}}, "Requests": [{"HostType": "host-a"}]}`,
+		`{"Quotas": {"q": 4}, "Hosts": {"host-a": {"Quota": "r", "CPU": 2}}, "Requests": [{"HostType": "host-a"}]}`,
+		`{"Quotas": {"q": 4}, "Hosts": {"host-a": {"Quota": "q", "CPU": 2}}, "Requests": [{"HostType": "host-b"}]}`,
+		`{"Quotas": {"q": 4}, "Hosts": {"host-a": {"Quota": "q", "CPU": 2}}, "Requests": [{"HostType": "host-a", "Kind": "nightly"}]}`,
+	} {
+		w, err := ParseWorkload([]byte(s))
+		if err != nil {
+			t.Fatalf("ParseWorkload(%s) = %v", s, err)
+		}
+		if _, err := Run(w, queue.DefaultPolicy()); err == nil {
+			t.Errorf("Run(%s) = _, nil; want error", s)
+		}
+	}
+	if _, err := ParseWorkload([]byte(`{"Requests": [{"Duration": "forever"}]}`)); err == nil {
+		t.Errorf("ParseWorkload() with an invalid duration = _, nil; want error")
+	}
+}
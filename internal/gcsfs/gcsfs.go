@@ -58,6 +58,21 @@ type CreateFS interface {
 	Create(string) (WriterFile, error)
 }
 
+// Remove removes the named file from fsys, which must be a RemoveFS.
+func Remove(fsys fs.FS, name string) error {
+	rfs, ok := fsys.(RemoveFS)
+	if !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fmt.Errorf("not implemented on type %T", fsys)}
+	}
+	return rfs.Remove(name)
+}
+
+// RemoveFS is an fs.FS that supports removing files.
+type RemoveFS interface {
+	fs.FS
+	Remove(string) error
+}
+
 // WriterFile is an fs.File that can be written to.
 // The behavior of writing and reading the same file is undefined.
 type WriterFile interface {
@@ -88,6 +103,7 @@ type gcsFS struct {
 
 var _ = fs.FS((*gcsFS)(nil))
 var _ = CreateFS((*gcsFS)(nil))
+var _ = RemoveFS((*gcsFS)(nil))
 var _ = fs.SubFS((*gcsFS)(nil))
 
 // NewFS creates a new fs.FS that uses ctx for all of its operations.
@@ -131,6 +147,21 @@ func (fsys *gcsFS) Create(name string) (WriterFile, error) {
 	return f.(*GCSFile), nil
 }
 
+// Remove removes the named file.
+func (fsys *gcsFS) Remove(name string) error {
+	if !validPath(name) || name == "." {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+	err := fsys.object(name).Delete(fsys.ctx)
+	if err == storage.ErrObjectNotExist {
+		err = fs.ErrNotExist
+	}
+	if err != nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: err}
+	}
+	return nil
+}
+
 func (fsys *gcsFS) Sub(dir string) (fs.FS, error) {
 	copy := *fsys
 	copy.prefix = path.Join(fsys.prefix, dir)
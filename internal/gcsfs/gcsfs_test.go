@@ -6,6 +6,7 @@ package gcsfs
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"io/fs"
 	"os"
@@ -89,3 +90,20 @@ func TestDirFSWrite(t *testing.T) {
 		t.Fatalf("unexpected file contents %q, want %q", string(b), "hey\n")
 	}
 }
+
+func TestDirFSRemove(t *testing.T) {
+	temp := t.TempDir()
+	fsys := DirFS(temp)
+	if err := WriteFile(fsys, "dir/fsystest.txt", []byte("hey\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(fsys, "dir/fsystest.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(temp, "dir/fsystest.txt")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat after Remove = %v; want fs.ErrNotExist", err)
+	}
+	if err := Remove(fsys, "dir/fsystest.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Remove of a missing file = %v; want fs.ErrNotExist", err)
+	}
+}
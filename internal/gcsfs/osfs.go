@@ -82,6 +82,13 @@ func (dir dirFS) Create(name string) (WriterFile, error) {
 	return &atomicWriteFile{temp, finalize}, nil
 }
 
+func (dir dirFS) Remove(name string) error {
+	if !fs.ValidPath(name) || runtime.GOOS == "windows" && containsAny(name, `\:`) {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+	return os.Remove(path.Join(string(dir), name))
+}
+
 type atomicWriteFile struct {
 	*os.File
 	finalize func() error
@@ -16,6 +16,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"cloud.google.com/go/storage"
@@ -54,10 +55,12 @@ type Server struct {
 	// embed the unimplemented server.
 	protos.UnimplementedGomoteServiceServer
 
+	admission               admission
 	bucket                  bucketHandle
 	buildlets               *remote.SessionPool
 	gceBucketName           string
 	scheduler               scheduler
+	snapshots               snapshotStore
 	sshCertificateAuthority ssh.Signer
 }
 
@@ -67,15 +70,24 @@ func New(rsp *remote.SessionPool, sched *schedule.Scheduler, rawCAPriKey []byte,
 	if err != nil {
 		log.Fatalf("unable to parse raw certificate authority private key into signer=%s", err)
 	}
+	snapshots := &gcsSnapshotStore{bucket: storageClient.Bucket(gomoteGCSBucket)}
+	go sweepSnapshots(snapshots)
 	return &Server{
 		bucket:                  storageClient.Bucket(gomoteGCSBucket),
 		buildlets:               rsp,
 		gceBucketName:           gomoteGCSBucket,
 		scheduler:               sched,
+		snapshots:               snapshots,
 		sshCertificateAuthority: signer,
 	}
 }
 
+// SetQuotas sets the limits on the number of instances that may exist at once.
+// Requests to create instances beyond the limits wait in a queue.
+func (s *Server) SetQuotas(q Quotas) {
+	s.admission.setQuotas(q)
+}
+
 // AddBootstrap adds the bootstrap version of Go to an instance and returns the URL for the bootstrap version. If no
 // bootstrap version is defined then the returned version URL will be empty.
 func (s *Server) AddBootstrap(ctx context.Context, req *protos.AddBootstrapRequest) (*protos.AddBootstrapResponse, error) {
@@ -135,6 +147,11 @@ func (s *Server) CreateInstance(req *protos.CreateInstanceRequest, stream protos
 	if err != nil {
 		return status.Errorf(codes.Internal, "invalid user email format")
 	}
+	admitted, err := s.admission.wait(stream.Context(), s.buildlets, creds.ID, req.GetBuilderType(), queuedUpdate(stream))
+	if err != nil {
+		return admissionError(err)
+	}
+	defer admitted()
 	si := &queue.SchedItem{
 		HostType:  bconf.HostType,
 		IsGomote:  true,
@@ -200,6 +217,39 @@ func (s *Server) CreateInstance(req *protos.CreateInstanceRequest, stream protos
 	}
 }
 
+// CreateSnapshot saves a directory on a gomote instance as a snapshot owned by the requester, which can
+// later be restored onto another instance of the same builder type. The requester must be authenticated.
+func (s *Server) CreateSnapshot(ctx context.Context, req *protos.CreateSnapshotRequest) (*protos.CreateSnapshotResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		log.Printf("CreateSnapshot access.IAPFromContext(ctx) = nil, %s", err)
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	ses, bc, err := s.sessionAndClient(ctx, req.GetGomoteId(), creds.ID)
+	if err != nil {
+		// the helper function returns meaningful GRPC error.
+		return nil, err
+	}
+	snap, err := createSnapshot(ctx, s.snapshots, bc, creds.ID, ses.BuilderType, req)
+	if err != nil {
+		return nil, err
+	}
+	return &protos.CreateSnapshotResponse{Snapshot: snap}, nil
+}
+
+// DeleteSnapshot deletes a snapshot owned by the requester. The requester must be authenticated.
+func (s *Server) DeleteSnapshot(ctx context.Context, req *protos.DeleteSnapshotRequest) (*protos.DeleteSnapshotResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		log.Printf("DeleteSnapshot access.IAPFromContext(ctx) = nil, %s", err)
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	if err := deleteSnapshot(ctx, s.snapshots, creds.ID, req.GetSnapshotId()); err != nil {
+		return nil, err
+	}
+	return &protos.DeleteSnapshotResponse{}, nil
+}
+
 // InstanceAlive will ensure that the gomote instance is still alive and will extend the timeout. The requester must be authenticated.
 func (s *Server) InstanceAlive(ctx context.Context, req *protos.InstanceAliveRequest) (*protos.InstanceAliveResponse, error) {
 	creds, err := access.IAPFromContext(ctx)
@@ -301,6 +351,32 @@ func (s *Server) ListInstances(ctx context.Context, req *protos.ListInstancesReq
 	return res, nil
 }
 
+// ListSnapshots lists the unexpired snapshots owned by the requester, and deletes the expired ones.
+// The requester must be authenticated.
+func (s *Server) ListSnapshots(ctx context.Context, req *protos.ListSnapshotsRequest) (*protos.ListSnapshotsResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		log.Printf("ListSnapshots access.IAPFromContext(ctx) = nil, %s", err)
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	snaps, err := listSnapshots(ctx, s.snapshots, creds.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &protos.ListSnapshotsResponse{Snapshots: snaps}, nil
+}
+
+// ListUsage lists the instance time used by the requester, or by all users if the request asks and the
+// requester is privileged. The requester must be authenticated.
+func (s *Server) ListUsage(ctx context.Context, req *protos.ListUsageRequest) (*protos.ListUsageResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		log.Printf("ListUsage access.IAPFromContext(ctx) = nil, %s", err)
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	return listUsage(s.buildlets, s.admission.getQuotas(), creds, req)
+}
+
 // DestroyInstance will destroy a gomote instance. It will ensure that the caller is authenticated and is the owner of the instance
 // before it destroys the instance.
 func (s *Server) DestroyInstance(ctx context.Context, req *protos.DestroyInstanceRequest) (*protos.DestroyInstanceResponse, error) {
@@ -321,6 +397,7 @@ func (s *Server) DestroyInstance(ctx context.Context, req *protos.DestroyInstanc
 		log.Printf("DestroyInstance remote.DestroySession(%s) = %s", req.GetGomoteId(), err)
 		return nil, status.Errorf(codes.Internal, "unable to destroy gomote instance")
 	}
+	s.admission.notify()
 	return &protos.DestroyInstanceResponse{}, nil
 }
 
@@ -343,6 +420,7 @@ func (s *Server) ExecuteCommand(req *protos.ExecuteCommandRequest, stream protos
 	if !ok {
 		return status.Errorf(codes.Internal, "unable to retrieve configuration for instance")
 	}
+	var stats buildlet.ExecStats
 	remoteErr, execErr := bc.Exec(stream.Context(), req.GetCommand(), buildlet.ExecOpts{
 		Dir:         req.GetDirectory(),
 		SystemLevel: req.GetSystemLevel(),
@@ -359,11 +437,16 @@ func (s *Server) ExecuteCommand(req *protos.ExecuteCommandRequest, stream protos
 		ExtraEnv: envutil.Dedup(conf.GOOS(), append(conf.Env(), req.GetAppendEnvironment()...)),
 		Debug:    req.GetDebug(),
 		Path:     req.GetPath(),
+		Stats:    &stats,
+		Sandbox:  execSandbox(req),
 	})
 	if execErr != nil {
 		// there were system errors preventing the command from being started or seen to completion.
 		return status.Errorf(codes.Aborted, "unable to execute command: %s", execErr)
 	}
+	if err := sendExecStats(stream, stats); err != nil {
+		return err
+	}
 	if remoteErr != nil {
 		// the command succeeded remotely
 		return status.Errorf(codes.Unknown, "command execution failed: %s", remoteErr)
@@ -371,6 +454,210 @@ func (s *Server) ExecuteCommand(req *protos.ExecuteCommandRequest, stream protos
 	return nil
 }
 
+// execSandbox returns the sandbox requested for a command, if any.
+func execSandbox(req *protos.ExecuteCommandRequest) *buildlet.Sandbox {
+	sb := req.GetSandbox()
+	if sb == nil {
+		return nil
+	}
+	return &buildlet.Sandbox{
+		Network:         buildlet.SandboxNet(sb.GetNetwork()),
+		Allow:           sb.GetAllow(),
+		ReadOnlyWorkDir: sb.GetReadOnlyWorkDir(),
+	}
+}
+
+// sendExecStats sends the resource usage of a command executed by
+// ExecuteCommand, if the buildlet reported it.
+func sendExecStats(stream protos.GomoteService_ExecuteCommandServer, stats buildlet.ExecStats) error {
+	if stats == (buildlet.ExecStats{}) {
+		return nil
+	}
+	err := stream.Send(&protos.ExecuteCommandResponse{
+		Stats: &protos.ExecStats{
+			WallNanos:   int64(stats.Wall),
+			UserNanos:   int64(stats.User),
+			SystemNanos: int64(stats.System),
+			MaxRssBytes: stats.MaxRSS,
+			WriteBytes:  stats.WriteBytes,
+			OutputBytes: stats.OutputBytes,
+		},
+	})
+	if err != nil {
+		return status.Errorf(codes.Aborted, "unable to send command stats: %s", err)
+	}
+	return nil
+}
+
+// StreamCommand executes a command on a gomote instance, streaming its standard input, output, and error,
+// and forwarding signals to it. The command's exit status is sent as the last response.
+func (s *Server) StreamCommand(stream protos.GomoteService_StreamCommandServer) error {
+	creds, err := access.IAPFromContext(stream.Context())
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	start, err := recvStreamCommandStart(stream)
+	if err != nil {
+		return err
+	}
+	req := start.GetCommand()
+	ses, bc, err := s.sessionAndClient(stream.Context(), req.GetGomoteId(), creds.ID)
+	if err != nil {
+		// the helper function returns meaningful GRPC error.
+		return err
+	}
+	builderType := req.GetImitateHostType()
+	if builderType == "" {
+		builderType = ses.BuilderType
+	}
+	conf, ok := dashboard.Builders[builderType]
+	if !ok {
+		return status.Errorf(codes.Internal, "unable to retrieve configuration for instance")
+	}
+	return streamCommand(stream, bc, start, envutil.Dedup(conf.GOOS(), append(conf.Env(), req.GetAppendEnvironment()...)))
+}
+
+// recvStreamCommandStart receives the first request of a StreamCommand call,
+// which must start the command.
+func recvStreamCommandStart(stream protos.GomoteService_StreamCommandServer) (*protos.StreamCommandStart, error) {
+	first, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	start := first.GetStart()
+	if start == nil || start.GetCommand() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "first request must start a command")
+	}
+	return start, nil
+}
+
+// streamStdinQueueLen is the number of standard input messages that
+// StreamCommand buffers for a command that isn't reading them. Once it's
+// full, no more messages are received until the command reads some input.
+const streamStdinQueueLen = 64
+
+// streamCommand runs the command described by start on bc with the given environment,
+// relaying the rest of the requests in stream to it.
+func streamCommand(stream protos.GomoteService_StreamCommandServer, bc buildlet.RemoteClient, start *protos.StreamCommandStart, env []string) error {
+	req := start.GetCommand()
+	stdin, stdinW := io.Pipe()
+	// Once the command is done, its input is no longer read.
+	defer stdin.Close()
+	signals := make(chan buildlet.Signal)
+	resize := make(chan buildlet.TermSize)
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	// Standard input is written by its own goroutine, so that a command
+	// that doesn't read it doesn't hold up signals and resizes.
+	// A nil slice closes the input.
+	stdinc := make(chan []byte, streamStdinQueueLen)
+	go func() {
+		defer stdinW.Close()
+		var werr error
+		for data := range stdinc {
+			if werr != nil {
+				continue // keep draining, so that receiving doesn't block
+			}
+			if data == nil {
+				werr = stdinW.Close()
+			} else {
+				_, werr = stdinW.Write(data)
+			}
+		}
+	}()
+	go func() {
+		defer close(stdinc)
+		for {
+			r, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			switch r := r.GetRequest().(type) {
+			case *protos.StreamCommandRequest_Stdin:
+				if len(r.Stdin) == 0 {
+					continue
+				}
+				select {
+				case stdinc <- r.Stdin:
+				case <-ctx.Done():
+					return
+				}
+			case *protos.StreamCommandRequest_CloseStdin:
+				select {
+				case stdinc <- nil:
+				case <-ctx.Done():
+					return
+				}
+			case *protos.StreamCommandRequest_Signal:
+				select {
+				case signals <- buildlet.Signal(r.Signal):
+				case <-ctx.Done():
+					return
+				}
+			case *protos.StreamCommandRequest_Resize:
+				size := buildlet.TermSize{Rows: uint16(r.Resize.GetRows()), Cols: uint16(r.Resize.GetCols())}
+				select {
+				case resize <- size:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	// gRPC streams allow only one goroutine to send at a time.
+	var mu sync.Mutex
+	send := func(resp *protos.StreamCommandResponse) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := stream.Send(resp); err != nil {
+			return fmt.Errorf("unable to send data=%w", err)
+		}
+		return nil
+	}
+	opts := buildlet.StreamExecOpts{
+		ExecOpts: buildlet.ExecOpts{
+			Dir:         req.GetDirectory(),
+			SystemLevel: req.GetSystemLevel(),
+			Output: &streamWriter{writeFunc: func(p []byte) (int, error) {
+				if err := send(&protos.StreamCommandResponse{Response: &protos.StreamCommandResponse_Stdout{Stdout: p}}); err != nil {
+					return 0, err
+				}
+				return len(p), nil
+			}},
+			Args:     req.GetArgs(),
+			ExtraEnv: env,
+			Debug:    req.GetDebug(),
+			Path:     req.GetPath(),
+			Sandbox:  execSandbox(req),
+		},
+		Stdin: stdin,
+		Stderr: &streamWriter{writeFunc: func(p []byte) (int, error) {
+			if err := send(&protos.StreamCommandResponse{Response: &protos.StreamCommandResponse_Stderr{Stderr: p}}); err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		}},
+		Signals: signals,
+	}
+	if start.GetPty() {
+		opts.PTY = &buildlet.PTYOpts{
+			Term:   start.GetTerm(),
+			Size:   buildlet.TermSize{Rows: uint16(start.GetSize().GetRows()), Cols: uint16(start.GetSize().GetCols())},
+			Resize: resize,
+		}
+	}
+	st, err := bc.StreamExec(ctx, req.GetCommand(), opts)
+	if err != nil {
+		// there were system errors preventing the command from being started or seen to completion.
+		return status.Errorf(codes.Aborted, "unable to execute command: %s", err)
+	}
+	return send(&protos.StreamCommandResponse{Response: &protos.StreamCommandResponse_ExitStatus{ExitStatus: &protos.ExitStatus{
+		Code:   int32(st.Code),
+		Signal: st.Signal,
+		Error:  st.Error,
+	}}})
+}
+
 // streamWriter implements the io.Writer interface.
 type streamWriter struct {
 	writeFunc func(p []byte) (int, error)
@@ -399,19 +686,8 @@ func (s *Server) ReadTGZToURL(ctx context.Context, req *protos.ReadTGZToURLReque
 	}
 	defer tgz.Close()
 	objectName := uuid.NewString()
-	objectHandle := s.bucket.Object(objectName)
-	// A context for writes is used to ensure we can cancel the context if a
-	// problem is encountered while writing to the object store. The API documentation
-	// states that the context should be canceled to stop writing without saving the data.
-	writeCtx, cancel := context.WithCancel(ctx)
-	tgzWriter := objectHandle.NewWriter(writeCtx)
-	defer cancel()
-	if _, err = io.Copy(tgzWriter, tgz); err != nil {
-		return nil, status.Errorf(codes.Aborted, "unable to stream tar.gz: %s", err)
-	}
-	// when close is called, the object is stored in the bucket.
-	if err := tgzWriter.Close(); err != nil {
-		return nil, status.Errorf(codes.Aborted, "unable to store object: %s", err)
+	if _, err := copyToObject(ctx, s.bucket.Object(objectName), tgz, nil); err != nil {
+		return nil, status.Errorf(codes.Aborted, "%s", err)
 	}
 	url, err := s.signURLForDownload(objectName)
 	if err != nil {
@@ -445,6 +721,25 @@ func (s *Server) RemoveFiles(ctx context.Context, req *protos.RemoveFilesRequest
 	return &protos.RemoveFilesResponse{}, nil
 }
 
+// RestoreSnapshot expands a snapshot owned by the requester onto a gomote instance of the same builder
+// type as the one the snapshot was saved from. The requester must be authenticated.
+func (s *Server) RestoreSnapshot(ctx context.Context, req *protos.RestoreSnapshotRequest) (*protos.RestoreSnapshotResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		log.Printf("RestoreSnapshot access.IAPFromContext(ctx) = nil, %s", err)
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	ses, bc, err := s.sessionAndClient(ctx, req.GetGomoteId(), creds.ID)
+	if err != nil {
+		// the helper function returns meaningful GRPC error.
+		return nil, err
+	}
+	if err := restoreSnapshot(ctx, s.snapshots, bc, creds.ID, ses.BuilderType, req.GetSnapshotId(), s.signURLForDownload); err != nil {
+		return nil, err
+	}
+	return &protos.RestoreSnapshotResponse{}, nil
+}
+
 // SignSSHKey signs the public SSH key with a certificate. The signed public SSH key is intended for use with the gomote service SSH
 // server. It will be signed by the certificate authority of the server and will restrict access to the gomote instance that it was
 // signed for.
@@ -600,6 +895,176 @@ func (s *Server) WriteTGZFromURL(ctx context.Context, req *protos.WriteTGZFromUR
 	return &protos.WriteTGZFromURLResponse{}, nil
 }
 
+// SyncFiles makes a directory on the gomote instance match the manifest in the request, transferring
+// only the file contents the instance is missing from the tar.gz file at the request's blobs URL.
+func (s *Server) SyncFiles(ctx context.Context, req *protos.SyncFilesRequest) (*protos.SyncFilesResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		log.Printf("SyncFiles access.IAPFromContext(ctx) = nil, %s", err)
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	if req.GetGomoteId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid gomote ID")
+	}
+	_, bc, err := s.sessionAndClient(ctx, req.GetGomoteId(), creds.ID)
+	if err != nil {
+		// the helper function returns meaningful GRPC error.
+		return nil, err
+	}
+	var url string
+	if req.GetBlobsUrl() != "" {
+		object, err := objectFromURL(s.gceBucketName, req.GetBlobsUrl())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid blobs URL")
+		}
+		url, err = s.signURLForDownload(object)
+		if err != nil {
+			return nil, status.Errorf(codes.Aborted, "unable to sign url for download: %s", err)
+		}
+	}
+	return syncFiles(ctx, bc, req, url)
+}
+
+// TailFile streams the contents of a file on a gomote instance, optionally following bytes
+// appended to it. The requester must be authenticated.
+func (s *Server) TailFile(req *protos.TailFileRequest, stream grpc.ServerStreamingServer[protos.TailFileResponse]) error {
+	creds, err := access.IAPFromContext(stream.Context())
+	if err != nil {
+		log.Printf("TailFile access.IAPFromContext(ctx) = nil, %s", err)
+		return status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	if req.GetGomoteId() == "" || req.GetPath() == "" {
+		return status.Errorf(codes.InvalidArgument, "invalid arguments")
+	}
+	_, bc, err := s.sessionAndClient(stream.Context(), req.GetGomoteId(), creds.ID)
+	if err != nil {
+		// the helper function returns meaningful GRPC error.
+		return err
+	}
+	return tailFile(stream, bc, req)
+}
+
+// WatchDirectory streams changes to the files in a directory on a gomote instance.
+// The requester must be authenticated.
+func (s *Server) WatchDirectory(req *protos.WatchDirectoryRequest, stream grpc.ServerStreamingServer[protos.WatchDirectoryResponse]) error {
+	creds, err := access.IAPFromContext(stream.Context())
+	if err != nil {
+		log.Printf("WatchDirectory access.IAPFromContext(ctx) = nil, %s", err)
+		return status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	if req.GetGomoteId() == "" || req.GetDirectory() == "" {
+		return status.Errorf(codes.InvalidArgument, "invalid arguments")
+	}
+	_, bc, err := s.sessionAndClient(stream.Context(), req.GetGomoteId(), creds.ID)
+	if err != nil {
+		// the helper function returns meaningful GRPC error.
+		return err
+	}
+	return watchDirectory(stream, bc, req)
+}
+
+// maxSyncBlobsSize is the maximum size of the file contents in a SyncFiles request.
+const maxSyncBlobsSize = 512 << 20
+
+// syncBlobsSem bounds the number of SyncFiles requests that hold file
+// contents at once. Each keeps up to maxSyncBlobsSize bytes of them
+// in a temporary file.
+var syncBlobsSem = make(chan struct{}, 4)
+
+// syncFiles syncs the directory in req on bc, using the file contents in the tar.gz file
+// at blobsURL, if it's not empty.
+func syncFiles(ctx context.Context, bc buildlet.RemoteClient, req *protos.SyncFilesRequest, blobsURL string) (*protos.SyncFilesResponse, error) {
+	m := &buildlet.SyncManifest{Delete: req.GetDelete()}
+	for _, f := range req.GetFiles() {
+		m.Files = append(m.Files, buildlet.SyncFile{Path: f.GetPath(), Digest: f.GetDigest(), Mode: fs.FileMode(f.GetMode())})
+	}
+	var open func(buildlet.SyncFile) (io.ReadCloser, error)
+	if blobsURL != "" {
+		select {
+		case syncBlobsSem <- struct{}{}:
+			defer func() { <-syncBlobsSem }()
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		hreq, err := http.NewRequestWithContext(ctx, "GET", blobsURL, nil)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid blobs URL")
+		}
+		res, err := http.DefaultClient.Do(hreq)
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "unable to fetch blobs: %s", err)
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return nil, status.Errorf(codes.FailedPrecondition, "unable to fetch blobs: %s", res.Status)
+		}
+		_, blobs, err := buildlet.ReadSyncTGZ(res.Body, "", maxSyncBlobsSize)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid blobs: %s", err)
+		}
+		defer blobs.Close()
+		open = blobs.Open
+	}
+	res, err := bc.Sync(ctx, req.GetDirectory(), m, open)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	} else if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "unable to sync files: %s", err)
+	}
+	return &protos.SyncFilesResponse{
+		MissingDigests: res.Missing,
+		Written:        int32(res.Written),
+		Deleted:        int32(res.Deleted),
+	}, nil
+}
+
+// tailFile streams the file in req on bc.
+func tailFile(stream grpc.ServerStreamingServer[protos.TailFileResponse], bc buildlet.RemoteClient, req *protos.TailFileRequest) error {
+	ctx := stream.Context()
+	err := bc.Tail(ctx, req.GetPath(), buildlet.TailOpts{Offset: req.GetOffset(), Follow: req.GetFollow()},
+		&streamWriter{writeFunc: func(p []byte) (int, error) {
+			if err := stream.Send(&protos.TailFileResponse{Data: p}); err != nil {
+				return 0, fmt.Errorf("unable to send data=%w", err)
+			}
+			return len(p), nil
+		}})
+	if err != nil && ctx.Err() == nil {
+		return status.Errorf(codes.Aborted, "unable to tail file: %s", err)
+	}
+	return nil
+}
+
+// watchDirectory streams the changes to the directory in req on bc.
+func watchDirectory(stream grpc.ServerStreamingServer[protos.WatchDirectoryResponse], bc buildlet.RemoteClient, req *protos.WatchDirectoryRequest) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	var sendErr error
+	err := bc.Watch(ctx, req.GetDirectory(), func(e buildlet.WatchEvent) {
+		if sendErr != nil {
+			return
+		}
+		resp := &protos.WatchDirectoryResponse{
+			Op:   e.Op,
+			Path: e.Path,
+			Mode: uint32(e.Mode),
+			Size: e.Size,
+		}
+		if !e.ModTime.IsZero() {
+			resp.ModTime = e.ModTime.Unix()
+		}
+		if sendErr = stream.Send(resp); sendErr != nil {
+			cancel()
+		}
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil && stream.Context().Err() == nil {
+		return status.Errorf(codes.Aborted, "unable to watch directory: %s", err)
+	}
+	return nil
+}
+
 // session is a helper function that retrieves a session associated with the gomoteID and ownerID.
 func (s *Server) session(gomoteID, ownerID string) (*remote.Session, error) {
 	session, err := s.buildlets.Session(gomoteID)
@@ -13,11 +13,14 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/build/buildlet"
 	"golang.org/x/build/internal/access"
 	"golang.org/x/build/internal/coordinator/remote"
 	"golang.org/x/build/internal/coordinator/schedule"
@@ -27,6 +30,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
 )
 
@@ -42,18 +46,24 @@ func fakeGomoteServer(t *testing.T, ctx context.Context) protos.GomoteServiceSer
 		buildlets:               remote.NewSessionPool(ctx),
 		gceBucketName:           testBucketName,
 		scheduler:               schedule.NewFake(),
+		snapshots:               &fakeSnapshotStore{},
 		sshCertificateAuthority: signer,
 	}
 }
 
 func setupGomoteTest(t *testing.T, ctx context.Context) protos.GomoteServiceClient {
+	return setupGomoteTestServer(t, fakeGomoteServer(t, ctx))
+}
+
+// setupGomoteTestServer serves srv and returns a client for it.
+func setupGomoteTestServer(t *testing.T, srv protos.GomoteServiceServer) protos.GomoteServiceClient {
 	lis, err := nettest.NewLocalListener("tcp")
 	if err != nil {
 		t.Fatalf("unable to create net listener: %s", err)
 	}
 	sopts := access.FakeIAPAuthInterceptorOptions()
 	s := grpc.NewServer(sopts...)
-	protos.RegisterGomoteServiceServer(s, fakeGomoteServer(t, ctx))
+	protos.RegisterGomoteServiceServer(s, srv)
 	go s.Serve(lis)
 
 	// create GRPC client
@@ -436,6 +446,49 @@ func TestListInstance(t *testing.T) {
 	}
 }
 
+func TestListUsage(t *testing.T) {
+	client := setupGomoteTest(t, context.Background())
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	mustCreateInstance(t, client, fakeIAP())
+	mustCreateInstance(t, client, fakeIAP())
+	mustCreateInstance(t, client, fakeIAPWithUser("user-x", "uuid-user-x"))
+	response, err := client.ListUsage(ctx, &protos.ListUsageRequest{})
+	if err != nil {
+		t.Fatalf("client.ListUsage = nil, %s; want no error", err)
+	}
+	want := []*protos.Usage{{User: "example", BuilderType: "linux-amd64", Instances: 2, ActiveInstances: 2}}
+	if diff := cmp.Diff(want, response.GetUsage(), protocmp.Transform(), protocmp.IgnoreFields(&protos.Usage{}, "instance_seconds")); diff != "" {
+		t.Errorf("ListUsage() mismatch (-want, +got):\n%s", diff)
+	}
+	if _, err := client.ListUsage(ctx, &protos.ListUsageRequest{AllUsers: true}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ListUsage of all users by unprivileged user = %s; want %s", err, codes.PermissionDenied)
+	}
+}
+
+func TestCreateInstanceQueued(t *testing.T) {
+	server := fakeGomoteServer(t, context.Background()).(*Server)
+	server.SetQuotas(Quotas{PerUser: 1})
+	client := setupGomoteTestServer(t, server)
+	mustCreateInstance(t, client, fakeIAP())
+
+	ctx, cancel := context.WithTimeout(access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()), 2*time.Second)
+	defer cancel()
+	stream, err := client.CreateInstance(ctx, &protos.CreateInstanceRequest{BuilderType: "linux-amd64"})
+	if err != nil {
+		t.Fatalf("client.CreateInstance(ctx, req) = _, %s; want no error", err)
+	}
+	update, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("stream.Recv() = _, %s; want no error", err)
+	}
+	if update.GetStatus() != protos.CreateInstanceResponse_QUEUED {
+		t.Errorf("CreateInstance over quota sent %v; want a QUEUED update", update)
+	}
+	if got, want := update.GetQueueReason(), "you have 1 instances, and the limit is 1"; got != want {
+		t.Errorf("queue reason = %q; want %q", got, want)
+	}
+}
+
 func TestDestroyInstance(t *testing.T) {
 	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
 	client := setupGomoteTest(t, context.Background())
@@ -538,6 +591,387 @@ func TestExecuteCommand(t *testing.T) {
 	}
 }
 
+func TestStreamCommand(t *testing.T) {
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	client := setupGomoteTest(t, context.Background())
+	gomoteID := mustCreateInstance(t, client, fakeIAP())
+	stream, err := client.StreamCommand(ctx)
+	if err != nil {
+		t.Fatalf("client.StreamCommand(ctx) = _, %s; want no error", err)
+	}
+	start := &protos.StreamCommandStart{
+		Command: &protos.ExecuteCommandRequest{
+			GomoteId:  gomoteID,
+			Command:   "ls",
+			Directory: "/workdir",
+			Args:      []string{"-alh"},
+		},
+	}
+	if err := stream.Send(&protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_Start{Start: start}}); err != nil {
+		t.Fatalf("stream.Send(start) = %s; want no error", err)
+	}
+	if err := stream.Send(&protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_CloseStdin{CloseStdin: true}}); err != nil {
+		t.Fatalf("stream.Send(close_stdin) = %s; want no error", err)
+	}
+	var out []byte
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("stream.Recv() = _, %s; want no error", err)
+		}
+		if st := res.GetExitStatus(); st != nil {
+			if st.GetCode() != 0 {
+				t.Errorf("exit status = %v; want success", st)
+			}
+			break
+		}
+		out = append(out, res.GetStdout()...)
+	}
+	if len(out) == 0 {
+		t.Fatalf("output: %q, expected non-empty", out)
+	}
+}
+
+func TestStreamCommandError(t *testing.T) {
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	client := setupGomoteTest(t, context.Background())
+	stream, err := client.StreamCommand(ctx)
+	if err != nil {
+		t.Fatalf("client.StreamCommand(ctx) = _, %s; want no error", err)
+	}
+	// The first message must start a command.
+	if err := stream.Send(&protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_Stdin{Stdin: []byte("x")}}); err != nil {
+		t.Fatalf("stream.Send(stdin) = %s; want no error", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("stream.Recv() = _, %s; want %s", err, codes.InvalidArgument)
+	}
+}
+
+// commandStream is a StreamCommand server stream that receives the
+// requests sent on reqs, and sends responses to resps.
+type commandStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	reqs  chan *protos.StreamCommandRequest
+	resps chan *protos.StreamCommandResponse
+}
+
+func (s *commandStream) Context() context.Context { return s.ctx }
+
+func (s *commandStream) Recv() (*protos.StreamCommandRequest, error) {
+	select {
+	case r := <-s.reqs:
+		return r, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+func (s *commandStream) Send(r *protos.StreamCommandResponse) error {
+	s.resps <- r
+	return nil
+}
+
+// signalWaitClient is a buildlet client whose commands never read
+// their input, and wait for a signal.
+type signalWaitClient struct {
+	buildlet.RemoteClient
+}
+
+func (signalWaitClient) StreamExec(ctx context.Context, cmd string, opts buildlet.StreamExecOpts) (*buildlet.ExitStatus, error) {
+	select {
+	case sig := <-opts.Signals:
+		return &buildlet.ExitStatus{Code: -1, Signal: string(sig)}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestStreamCommandSignalUnreadStdin(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream := &commandStream{
+		ctx:   ctx,
+		reqs:  make(chan *protos.StreamCommandRequest),
+		resps: make(chan *protos.StreamCommandResponse, 1),
+	}
+	errc := make(chan error, 1)
+	go func() {
+		start := &protos.StreamCommandStart{Command: &protos.ExecuteCommandRequest{Command: "cat"}}
+		errc <- streamCommand(stream, signalWaitClient{}, start, nil)
+	}()
+	send := func(r *protos.StreamCommandRequest) {
+		select {
+		case stream.reqs <- r:
+		case <-ctx.Done():
+			t.Fatalf("request %v not received while the command doesn't read its input", r)
+		}
+	}
+	for range 10 {
+		send(&protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_Stdin{Stdin: []byte("input")}})
+	}
+	send(&protos.StreamCommandRequest{Request: &protos.StreamCommandRequest_Signal{Signal: string(buildlet.SignalInterrupt)}})
+	if err := <-errc; err != nil {
+		t.Fatalf("streamCommand = %s; want no error", err)
+	}
+	if st := (<-stream.resps).GetExitStatus(); st.GetSignal() != string(buildlet.SignalInterrupt) {
+		t.Errorf("exit status = %v; want signal %s", st, buildlet.SignalInterrupt)
+	}
+}
+
+func TestSyncFiles(t *testing.T) {
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	client := setupGomoteTest(t, context.Background())
+	gomoteID := mustCreateInstance(t, client, fakeIAP())
+	req := &protos.SyncFilesRequest{
+		GomoteId:  gomoteID,
+		Directory: "go",
+		Files: []*protos.SyncFile{
+			{Path: "VERSION", Digest: "da39a3ee5e6b4b0d3255bfef95601890afd80709", Mode: 0644},
+		},
+	}
+	got, err := client.SyncFiles(ctx, req)
+	if err != nil {
+		t.Fatalf("client.SyncFiles(ctx, req) = response, %s; want no error", err)
+	}
+	if want := []string{"da39a3ee5e6b4b0d3255bfef95601890afd80709"}; !cmp.Equal(got.GetMissingDigests(), want) {
+		t.Errorf("missing digests = %q; want %q", got.GetMissingDigests(), want)
+	}
+
+	req.BlobsUrl = "https://example.com/blobs.tar.gz"
+	if _, err := client.SyncFiles(ctx, req); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("client.SyncFiles(ctx, req) with a foreign blobs URL = _, %s; want %s", err, codes.InvalidArgument)
+	}
+}
+
+func TestTailFile(t *testing.T) {
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	client := setupGomoteTest(t, context.Background())
+	gomoteID := mustCreateInstance(t, client, fakeIAP())
+	stream, err := client.TailFile(ctx, &protos.TailFileRequest{
+		GomoteId: gomoteID,
+		Path:     "go/test.log",
+	})
+	if err != nil {
+		t.Fatalf("client.TailFile(ctx, req) = _, %s; want no error", err)
+	}
+	var out []byte
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("stream.Recv() = _, %s; want no error", err)
+		}
+		out = append(out, res.GetData()...)
+	}
+	if want := "fake contents of go/test.log\n"; string(out) != want {
+		t.Errorf("tailed %q; want %q", out, want)
+	}
+
+	stream, err = client.TailFile(ctx, &protos.TailFileRequest{GomoteId: gomoteID})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("TailFile without a path = %s; want %s", err, codes.InvalidArgument)
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	client := setupGomoteTest(t, context.Background())
+	gomoteID := mustCreateInstance(t, client, fakeIAP())
+	created, err := client.CreateSnapshot(ctx, &protos.CreateSnapshotRequest{
+		GomoteId:    gomoteID,
+		Directory:   "go",
+		GoRevision:  "abc123",
+		Description: "after make.bash",
+	})
+	if err != nil {
+		t.Fatalf("client.CreateSnapshot(ctx, req) = _, %s; want no error", err)
+	}
+	snap := created.GetSnapshot()
+	if snap.GetBuilderType() != "linux-amd64" || snap.GetGoRevision() != "abc123" || snap.GetSizeBytes() == 0 {
+		t.Errorf("created snapshot %v; want linux-amd64 snapshot of abc123 with contents", snap)
+	}
+	if ttl := time.Duration(snap.GetExpires()-snap.GetCreated()) * time.Second; ttl != defaultSnapshotTTL {
+		t.Errorf("snapshot expires after %v; want %v", ttl, defaultSnapshotTTL)
+	}
+
+	list, err := client.ListSnapshots(ctx, &protos.ListSnapshotsRequest{})
+	if err != nil {
+		t.Fatalf("client.ListSnapshots(ctx, req) = _, %s; want no error", err)
+	}
+	if diff := cmp.Diff([]*protos.Snapshot{snap}, list.GetSnapshots(), protocmp.Transform()); diff != "" {
+		t.Errorf("ListSnapshots mismatch (-want, +got):\n%s", diff)
+	}
+
+	otherID := mustCreateInstance(t, client, fakeIAP())
+	if _, err := client.RestoreSnapshot(ctx, &protos.RestoreSnapshotRequest{GomoteId: otherID, SnapshotId: snap.GetSnapshotId()}); err != nil {
+		t.Errorf("client.RestoreSnapshot(ctx, req) = _, %s; want no error", err)
+	}
+
+	if _, err := client.DeleteSnapshot(ctx, &protos.DeleteSnapshotRequest{SnapshotId: snap.GetSnapshotId()}); err != nil {
+		t.Fatalf("client.DeleteSnapshot(ctx, req) = _, %s; want no error", err)
+	}
+	if _, err := client.RestoreSnapshot(ctx, &protos.RestoreSnapshotRequest{GomoteId: otherID, SnapshotId: snap.GetSnapshotId()}); status.Code(err) != codes.NotFound {
+		t.Errorf("RestoreSnapshot of a deleted snapshot = %s; want %s", err, codes.NotFound)
+	}
+}
+
+func TestSnapshotsError(t *testing.T) {
+	client := setupGomoteTest(t, context.Background())
+	gomoteID := mustCreateInstance(t, client, fakeIAP())
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	created, err := client.CreateSnapshot(ctx, &protos.CreateSnapshotRequest{GomoteId: gomoteID})
+	if err != nil {
+		t.Fatalf("client.CreateSnapshot(ctx, req) = _, %s; want no error", err)
+	}
+	snapID := created.GetSnapshot().GetSnapshotId()
+	otherCtx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAPWithUser("foo", "bar"))
+
+	testCases := []struct {
+		desc     string
+		call     func() error
+		wantCode codes.Code
+	}{
+		{
+			desc: "unauthenticated create",
+			call: func() error {
+				_, err := client.CreateSnapshot(context.Background(), &protos.CreateSnapshotRequest{GomoteId: gomoteID})
+				return err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc: "create from another user's gomote",
+			call: func() error {
+				_, err := client.CreateSnapshot(otherCtx, &protos.CreateSnapshotRequest{GomoteId: gomoteID})
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			desc: "negative TTL",
+			call: func() error {
+				_, err := client.CreateSnapshot(ctx, &protos.CreateSnapshotRequest{GomoteId: gomoteID, TtlSeconds: -1})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc: "restore another user's snapshot",
+			call: func() error {
+				otherID := mustCreateInstance(t, client, fakeIAPWithUser("foo", "bar"))
+				_, err := client.RestoreSnapshot(otherCtx, &protos.RestoreSnapshotRequest{GomoteId: otherID, SnapshotId: snapID})
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			desc: "delete another user's snapshot",
+			call: func() error {
+				_, err := client.DeleteSnapshot(otherCtx, &protos.DeleteSnapshotRequest{SnapshotId: snapID})
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			desc: "delete without snapshot ID",
+			call: func() error {
+				_, err := client.DeleteSnapshot(ctx, &protos.DeleteSnapshotRequest{})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := tc.call(); status.Code(err) != tc.wantCode {
+				t.Errorf("got error %v; want %s", err, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestListSnapshotsExpires(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	store := &fakeSnapshotStore{}
+	for _, snap := range []*protos.Snapshot{
+		{SnapshotId: "new", Created: now, Expires: now + 60},
+		{SnapshotId: "expired", Created: now - 120, Expires: now - 60},
+		{SnapshotId: "old", Created: now - 60, Expires: now + 60},
+	} {
+		if err := store.Create(ctx, "owner", snap, strings.NewReader("tgz")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snaps, err := listSnapshots(ctx, store, "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, snap := range snaps {
+		ids = append(ids, snap.GetSnapshotId())
+	}
+	if want := []string{"old", "new"}; !cmp.Equal(ids, want) {
+		t.Errorf("listSnapshots = %q; want %q", ids, want)
+	}
+	if _, err := store.Get(ctx, "owner", "expired"); err != errSnapshotNotExist {
+		t.Errorf("expired snapshot not deleted: Get = %v", err)
+	}
+}
+
+func TestSweepSnapshots(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	store := &fakeSnapshotStore{}
+	for _, owner := range []string{"alice", "bob"} {
+		for _, snap := range []*protos.Snapshot{
+			{SnapshotId: "live", Created: now, Expires: now + 60},
+			{SnapshotId: "expired", Created: now - 120, Expires: now - 60},
+		} {
+			if err := store.Create(ctx, owner, snap, strings.NewReader("tgz")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	sweepSnapshotsOnce(ctx, store)
+	for _, owner := range []string{"alice", "bob"} {
+		if _, err := store.Get(ctx, owner, "expired"); err != errSnapshotNotExist {
+			t.Errorf("expired snapshot of %s not deleted: Get = %v", owner, err)
+		}
+		if _, err := store.Get(ctx, owner, "live"); err != nil {
+			t.Errorf("unexpired snapshot of %s: Get = %v", owner, err)
+		}
+	}
+}
+
+func TestWatchDirectory(t *testing.T) {
+	ctx, cancel := context.WithCancel(access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()))
+	defer cancel()
+	client := setupGomoteTest(t, context.Background())
+	gomoteID := mustCreateInstance(t, client, fakeIAP())
+	stream, err := client.WatchDirectory(ctx, &protos.WatchDirectoryRequest{
+		GomoteId:  gomoteID,
+		Directory: "go",
+	})
+	if err != nil {
+		t.Fatalf("client.WatchDirectory(ctx, req) = _, %s; want no error", err)
+	}
+	res, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("stream.Recv() = _, %s; want no error", err)
+	}
+	if res.GetOp() != "create" || res.GetPath() != "fake.txt" {
+		t.Errorf("event = %v; want creation of fake.txt", res)
+	}
+}
+
 func TestExecuteCommandError(t *testing.T) {
 	// This test will create a gomote instance and attempt to call TestExecuteCommand.
 	// If overrideID is set to true, the test will use a different gomoteID than
@@ -1193,6 +1627,75 @@ OfjWFhdu6e4JYiVfN7ZYAAAAE3Rlc3R1c2VyQGdvbGFuZy5vcmcBAg==
 	devCertCAPublic = `ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJV3YUncNv+hXneJEO3VEuxxOfjWFhdu6e4JYiVfN7ZY testuser@golang.org`
 )
 
+// fakeSnapshotStore is a snapshotStore that keeps snapshots in memory.
+type fakeSnapshotStore struct {
+	mu    sync.Mutex
+	snaps map[string]*protos.Snapshot // by snapshotObject
+}
+
+func (f *fakeSnapshotStore) Create(ctx context.Context, owner string, snap *protos.Snapshot, tgz io.Reader) error {
+	n, err := io.Copy(io.Discard, tgz)
+	if err != nil {
+		return err
+	}
+	snap.SizeBytes = n
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.snaps == nil {
+		f.snaps = make(map[string]*protos.Snapshot)
+	}
+	f.snaps[snapshotObject(owner, snap.GetSnapshotId())] = proto.Clone(snap).(*protos.Snapshot)
+	return nil
+}
+
+func (f *fakeSnapshotStore) Get(ctx context.Context, owner, id string) (*protos.Snapshot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	snap, ok := f.snaps[snapshotObject(owner, id)]
+	if !ok {
+		return nil, errSnapshotNotExist
+	}
+	return proto.Clone(snap).(*protos.Snapshot), nil
+}
+
+func (f *fakeSnapshotStore) List(ctx context.Context, owner string) ([]*protos.Snapshot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var snaps []*protos.Snapshot
+	for name, snap := range f.snaps {
+		if strings.HasPrefix(name, snapshotPrefix(owner)) {
+			snaps = append(snaps, proto.Clone(snap).(*protos.Snapshot))
+		}
+	}
+	return snaps, nil
+}
+
+func (f *fakeSnapshotStore) Delete(ctx context.Context, owner, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := snapshotObject(owner, id)
+	if _, ok := f.snaps[name]; !ok {
+		return errSnapshotNotExist
+	}
+	delete(f.snaps, name)
+	return nil
+}
+
+func (f *fakeSnapshotStore) Owners(ctx context.Context) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	seen := make(map[string]bool)
+	var owners []string
+	for name := range f.snaps {
+		owner, _, _ := strings.Cut(strings.TrimPrefix(name, snapshotsDir), "/")
+		if !seen[owner] {
+			seen[owner] = true
+			owners = append(owners, owner)
+		}
+	}
+	return owners, nil
+}
+
 type fakeBucketHandler struct{ bucketName string }
 
 func (fbc *fakeBucketHandler) GenerateSignedPostPolicyV4(object string, opts *storage.PostPolicyV4Options) (*storage.PostPolicyV4, error) {
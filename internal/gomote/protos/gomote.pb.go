@@ -30,6 +30,9 @@ const (
 	CreateInstanceResponse_UNKNOWN  CreateInstanceResponse_Status = 0
 	CreateInstanceResponse_WAITING  CreateInstanceResponse_Status = 1
 	CreateInstanceResponse_COMPLETE CreateInstanceResponse_Status = 2
+	// QUEUED means the request is waiting for the caller or the builder
+	// type to be under its instance quota.
+	CreateInstanceResponse_QUEUED CreateInstanceResponse_Status = 3
 )
 
 // Enum value maps for CreateInstanceResponse_Status.
@@ -38,11 +41,13 @@ var (
 		0: "UNKNOWN",
 		1: "WAITING",
 		2: "COMPLETE",
+		3: "QUEUED",
 	}
 	CreateInstanceResponse_Status_value = map[string]int32{
 		"UNKNOWN":  0,
 		"WAITING":  1,
 		"COMPLETE": 2,
+		"QUEUED":   3,
 	}
 )
 
@@ -321,6 +326,8 @@ type CreateInstanceResponse struct {
 	// Waiters ahead is the count of how many instances are being scheduled for
 	// creation before the current instance creation request.
 	WaitersAhead int64 `protobuf:"varint,3,opt,name=waiters_ahead,json=waitersAhead,proto3" json:"waiters_ahead,omitempty"`
+	// Why the request is queued, if it is.
+	QueueReason string `protobuf:"bytes,4,opt,name=queue_reason,json=queueReason,proto3" json:"queue_reason,omitempty"`
 }
 
 func (x *CreateInstanceResponse) Reset() {
@@ -376,6 +383,236 @@ func (x *CreateInstanceResponse) GetWaitersAhead() int64 {
 	return 0
 }
 
+func (x *CreateInstanceResponse) GetQueueReason() string {
+	if x != nil {
+		return x.QueueReason
+	}
+	return ""
+}
+
+// CreateSnapshotRequest specifies the data needed to save a directory on a gomote instance as a snapshot.
+type CreateSnapshotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique identifier for a gomote instance.
+	GomoteId string `protobuf:"bytes,1,opt,name=gomote_id,json=gomoteId,proto3" json:"gomote_id,omitempty"`
+	// The relative directory from the gomote's work directory to save.
+	// The default is the whole work directory.
+	Directory string `protobuf:"bytes,2,opt,name=directory,proto3" json:"directory,omitempty"`
+	// The Go revision in the directory, for the caller's reference.
+	GoRevision string `protobuf:"bytes,3,opt,name=go_revision,json=goRevision,proto3" json:"go_revision,omitempty"`
+	// A description of the snapshot, for the caller's reference.
+	Description string `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	// How long to keep the snapshot, in seconds. Zero means the server's default.
+	// The server may keep the snapshot for less time than requested.
+	TtlSeconds int64 `protobuf:"varint,5,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+}
+
+func (x *CreateSnapshotRequest) Reset() {
+	*x = CreateSnapshotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSnapshotRequest) ProtoMessage() {}
+
+func (x *CreateSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*CreateSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateSnapshotRequest) GetGomoteId() string {
+	if x != nil {
+		return x.GomoteId
+	}
+	return ""
+}
+
+func (x *CreateSnapshotRequest) GetDirectory() string {
+	if x != nil {
+		return x.Directory
+	}
+	return ""
+}
+
+func (x *CreateSnapshotRequest) GetGoRevision() string {
+	if x != nil {
+		return x.GoRevision
+	}
+	return ""
+}
+
+func (x *CreateSnapshotRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateSnapshotRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+// CreateSnapshotResponse contains the snapshot that was created.
+type CreateSnapshotResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Snapshot *Snapshot `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+}
+
+func (x *CreateSnapshotResponse) Reset() {
+	*x = CreateSnapshotResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateSnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSnapshotResponse) ProtoMessage() {}
+
+func (x *CreateSnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSnapshotResponse.ProtoReflect.Descriptor instead.
+func (*CreateSnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateSnapshotResponse) GetSnapshot() *Snapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+// DeleteSnapshotRequest specifies the snapshot to delete.
+type DeleteSnapshotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique identifier for a snapshot.
+	SnapshotId string `protobuf:"bytes,1,opt,name=snapshot_id,json=snapshotId,proto3" json:"snapshot_id,omitempty"`
+}
+
+func (x *DeleteSnapshotRequest) Reset() {
+	*x = DeleteSnapshotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSnapshotRequest) ProtoMessage() {}
+
+func (x *DeleteSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteSnapshotRequest) GetSnapshotId() string {
+	if x != nil {
+		return x.SnapshotId
+	}
+	return ""
+}
+
+// DeleteSnapshotResponse is the response from deleting a snapshot.
+type DeleteSnapshotResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteSnapshotResponse) Reset() {
+	*x = DeleteSnapshotResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteSnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSnapshotResponse) ProtoMessage() {}
+
+func (x *DeleteSnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSnapshotResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{9}
+}
+
 // DestroyInstanceRequest specifies the data needed to destroy a gomote instance.
 type DestroyInstanceRequest struct {
 	state         protoimpl.MessageState
@@ -389,7 +626,7 @@ type DestroyInstanceRequest struct {
 func (x *DestroyInstanceRequest) Reset() {
 	*x = DestroyInstanceRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[6]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -402,7 +639,7 @@ func (x *DestroyInstanceRequest) String() string {
 func (*DestroyInstanceRequest) ProtoMessage() {}
 
 func (x *DestroyInstanceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[6]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -415,7 +652,7 @@ func (x *DestroyInstanceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DestroyInstanceRequest.ProtoReflect.Descriptor instead.
 func (*DestroyInstanceRequest) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{6}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *DestroyInstanceRequest) GetGomoteId() string {
@@ -435,7 +672,7 @@ type DestroyInstanceResponse struct {
 func (x *DestroyInstanceResponse) Reset() {
 	*x = DestroyInstanceResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[7]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -448,7 +685,7 @@ func (x *DestroyInstanceResponse) String() string {
 func (*DestroyInstanceResponse) ProtoMessage() {}
 
 func (x *DestroyInstanceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[7]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -461,7 +698,7 @@ func (x *DestroyInstanceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DestroyInstanceResponse.ProtoReflect.Descriptor instead.
 func (*DestroyInstanceResponse) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{7}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{11}
 }
 
 // ExecuteCommandRequest specifies the data needed to execute a command on a gomote instance.
@@ -494,12 +731,15 @@ type ExecuteCommandRequest struct {
 	Args []string `protobuf:"bytes,8,rep,name=args,proto3" json:"args,omitempty"`
 	// Optional alternate builder to act like. It must be a compatible builder.
 	ImitateHostType string `protobuf:"bytes,9,opt,name=imitate_host_type,json=imitateHostType,proto3" json:"imitate_host_type,omitempty"`
+	// If set, the command runs in a sandbox on the buildlet.
+	// Only Linux buildlets support sandboxes.
+	Sandbox *Sandbox `protobuf:"bytes,10,opt,name=sandbox,proto3" json:"sandbox,omitempty"`
 }
 
 func (x *ExecuteCommandRequest) Reset() {
 	*x = ExecuteCommandRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[8]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -512,7 +752,7 @@ func (x *ExecuteCommandRequest) String() string {
 func (*ExecuteCommandRequest) ProtoMessage() {}
 
 func (x *ExecuteCommandRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[8]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -525,7 +765,7 @@ func (x *ExecuteCommandRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecuteCommandRequest.ProtoReflect.Descriptor instead.
 func (*ExecuteCommandRequest) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{8}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ExecuteCommandRequest) GetGomoteId() string {
@@ -591,6 +831,13 @@ func (x *ExecuteCommandRequest) GetImitateHostType() string {
 	return ""
 }
 
+func (x *ExecuteCommandRequest) GetSandbox() *Sandbox {
+	if x != nil {
+		return x.Sandbox
+	}
+	return nil
+}
+
 // ExecuteCommandResponse contains data about the executed command.
 type ExecuteCommandResponse struct {
 	state         protoimpl.MessageState
@@ -599,12 +846,15 @@ type ExecuteCommandResponse struct {
 
 	// The output from the executed command.
 	Output []byte `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
+	// The resource usage of the command. It is only set in the last
+	// response, once the command has finished, if the buildlet reports it.
+	Stats *ExecStats `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
 }
 
 func (x *ExecuteCommandResponse) Reset() {
 	*x = ExecuteCommandResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[9]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -617,7 +867,7 @@ func (x *ExecuteCommandResponse) String() string {
 func (*ExecuteCommandResponse) ProtoMessage() {}
 
 func (x *ExecuteCommandResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[9]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -630,7 +880,7 @@ func (x *ExecuteCommandResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecuteCommandResponse.ProtoReflect.Descriptor instead.
 func (*ExecuteCommandResponse) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{9}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ExecuteCommandResponse) GetOutput() []byte {
@@ -640,42 +890,50 @@ func (x *ExecuteCommandResponse) GetOutput() []byte {
 	return nil
 }
 
-// Instance contains descriptive information about a gomote instance.
-type Instance struct {
+func (x *ExecuteCommandResponse) GetStats() *ExecStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// ExecStats is the resource usage of an executed command.
+type ExecStats struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The unique identifier for a gomote instance.
-	GomoteId string `protobuf:"bytes,1,opt,name=gomote_id,json=gomoteId,proto3" json:"gomote_id,omitempty"`
-	// Builder type for the gomote instance.
-	BuilderType string `protobuf:"bytes,2,opt,name=builder_type,json=builderType,proto3" json:"builder_type,omitempty"`
-	// Host type for the gomote instance.
-	HostType string `protobuf:"bytes,3,opt,name=host_type,json=hostType,proto3" json:"host_type,omitempty"`
-	// The timestamp for when the builder instance will expire. It is
-	// represented in Unix epoch time format.
-	Expires int64 `protobuf:"varint,4,opt,name=expires,proto3" json:"expires,omitempty"`
-	// The working directory of the instance.
-	WorkingDir string `protobuf:"bytes,5,opt,name=working_dir,json=workingDir,proto3" json:"working_dir,omitempty"`
-}
-
-func (x *Instance) Reset() {
-	*x = Instance{}
+	// The elapsed real time, in nanoseconds.
+	WallNanos int64 `protobuf:"varint,1,opt,name=wall_nanos,json=wallNanos,proto3" json:"wall_nanos,omitempty"`
+	// The user CPU time, in nanoseconds.
+	UserNanos int64 `protobuf:"varint,2,opt,name=user_nanos,json=userNanos,proto3" json:"user_nanos,omitempty"`
+	// The system CPU time, in nanoseconds.
+	SystemNanos int64 `protobuf:"varint,3,opt,name=system_nanos,json=systemNanos,proto3" json:"system_nanos,omitempty"`
+	// The maximum resident set size, in bytes.
+	MaxRssBytes int64 `protobuf:"varint,4,opt,name=max_rss_bytes,json=maxRssBytes,proto3" json:"max_rss_bytes,omitempty"`
+	// The number of bytes written to storage.
+	WriteBytes int64 `protobuf:"varint,5,opt,name=write_bytes,json=writeBytes,proto3" json:"write_bytes,omitempty"`
+	// The number of bytes of output.
+	OutputBytes int64 `protobuf:"varint,6,opt,name=output_bytes,json=outputBytes,proto3" json:"output_bytes,omitempty"`
+}
+
+func (x *ExecStats) Reset() {
+	*x = ExecStats{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[10]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Instance) String() string {
+func (x *ExecStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Instance) ProtoMessage() {}
+func (*ExecStats) ProtoMessage() {}
 
-func (x *Instance) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[10]
+func (x *ExecStats) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -686,60 +944,220 @@ func (x *Instance) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Instance.ProtoReflect.Descriptor instead.
-func (*Instance) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ExecStats.ProtoReflect.Descriptor instead.
+func (*ExecStats) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *Instance) GetGomoteId() string {
+func (x *ExecStats) GetWallNanos() int64 {
 	if x != nil {
-		return x.GomoteId
+		return x.WallNanos
 	}
-	return ""
+	return 0
 }
 
-func (x *Instance) GetBuilderType() string {
+func (x *ExecStats) GetUserNanos() int64 {
 	if x != nil {
-		return x.BuilderType
+		return x.UserNanos
 	}
-	return ""
+	return 0
 }
 
-func (x *Instance) GetHostType() string {
+func (x *ExecStats) GetSystemNanos() int64 {
 	if x != nil {
-		return x.HostType
+		return x.SystemNanos
 	}
-	return ""
+	return 0
 }
 
-func (x *Instance) GetExpires() int64 {
+func (x *ExecStats) GetMaxRssBytes() int64 {
 	if x != nil {
-		return x.Expires
+		return x.MaxRssBytes
 	}
 	return 0
 }
 
-func (x *Instance) GetWorkingDir() string {
+func (x *ExecStats) GetWriteBytes() int64 {
 	if x != nil {
-		return x.WorkingDir
+		return x.WriteBytes
 	}
-	return ""
+	return 0
 }
 
-// InstanceAliveRequest specifies the data needed to check the liveness of a gomote instance.
-type InstanceAliveRequest struct {
+func (x *ExecStats) GetOutputBytes() int64 {
+	if x != nil {
+		return x.OutputBytes
+	}
+	return 0
+}
+
+// ExitStatus describes how a command finished.
+type ExitStatus struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The unique identifier for a gomote instance.
+	// The exit code of the command, or -1 if it was killed by a signal or didn't run.
+	Code int32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	// The signal that killed the command, if any, like "interrupt".
+	Signal string `protobuf:"bytes,2,opt,name=signal,proto3" json:"signal,omitempty"`
+	// Why the command couldn't be started or waited for, if it couldn't.
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ExitStatus) Reset() {
+	*x = ExitStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExitStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExitStatus) ProtoMessage() {}
+
+func (x *ExitStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExitStatus.ProtoReflect.Descriptor instead.
+func (*ExitStatus) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExitStatus) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ExitStatus) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *ExitStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Instance contains descriptive information about a gomote instance.
+type Instance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique identifier for a gomote instance.
+	GomoteId string `protobuf:"bytes,1,opt,name=gomote_id,json=gomoteId,proto3" json:"gomote_id,omitempty"`
+	// Builder type for the gomote instance.
+	BuilderType string `protobuf:"bytes,2,opt,name=builder_type,json=builderType,proto3" json:"builder_type,omitempty"`
+	// Host type for the gomote instance.
+	HostType string `protobuf:"bytes,3,opt,name=host_type,json=hostType,proto3" json:"host_type,omitempty"`
+	// The timestamp for when the builder instance will expire. It is
+	// represented in Unix epoch time format.
+	Expires int64 `protobuf:"varint,4,opt,name=expires,proto3" json:"expires,omitempty"`
+	// The working directory of the instance.
+	WorkingDir string `protobuf:"bytes,5,opt,name=working_dir,json=workingDir,proto3" json:"working_dir,omitempty"`
+}
+
+func (x *Instance) Reset() {
+	*x = Instance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Instance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Instance) ProtoMessage() {}
+
+func (x *Instance) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Instance.ProtoReflect.Descriptor instead.
+func (*Instance) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Instance) GetGomoteId() string {
+	if x != nil {
+		return x.GomoteId
+	}
+	return ""
+}
+
+func (x *Instance) GetBuilderType() string {
+	if x != nil {
+		return x.BuilderType
+	}
+	return ""
+}
+
+func (x *Instance) GetHostType() string {
+	if x != nil {
+		return x.HostType
+	}
+	return ""
+}
+
+func (x *Instance) GetExpires() int64 {
+	if x != nil {
+		return x.Expires
+	}
+	return 0
+}
+
+func (x *Instance) GetWorkingDir() string {
+	if x != nil {
+		return x.WorkingDir
+	}
+	return ""
+}
+
+// InstanceAliveRequest specifies the data needed to check the liveness of a gomote instance.
+type InstanceAliveRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique identifier for a gomote instance.
 	GomoteId string `protobuf:"bytes,1,opt,name=gomote_id,json=gomoteId,proto3" json:"gomote_id,omitempty"`
 }
 
 func (x *InstanceAliveRequest) Reset() {
 	*x = InstanceAliveRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[11]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -752,7 +1170,7 @@ func (x *InstanceAliveRequest) String() string {
 func (*InstanceAliveRequest) ProtoMessage() {}
 
 func (x *InstanceAliveRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[11]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -765,7 +1183,7 @@ func (x *InstanceAliveRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InstanceAliveRequest.ProtoReflect.Descriptor instead.
 func (*InstanceAliveRequest) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{11}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *InstanceAliveRequest) GetGomoteId() string {
@@ -785,7 +1203,7 @@ type InstanceAliveResponse struct {
 func (x *InstanceAliveResponse) Reset() {
 	*x = InstanceAliveResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[12]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -798,7 +1216,7 @@ func (x *InstanceAliveResponse) String() string {
 func (*InstanceAliveResponse) ProtoMessage() {}
 
 func (x *InstanceAliveResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[12]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -811,7 +1229,7 @@ func (x *InstanceAliveResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InstanceAliveResponse.ProtoReflect.Descriptor instead.
 func (*InstanceAliveResponse) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{12}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{18}
 }
 
 // ListDirectoryRequest specifies the data needed to list contents of a directory from a gomote instance.
@@ -836,7 +1254,7 @@ type ListDirectoryRequest struct {
 func (x *ListDirectoryRequest) Reset() {
 	*x = ListDirectoryRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[13]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -849,7 +1267,7 @@ func (x *ListDirectoryRequest) String() string {
 func (*ListDirectoryRequest) ProtoMessage() {}
 
 func (x *ListDirectoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[13]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -862,7 +1280,7 @@ func (x *ListDirectoryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDirectoryRequest.ProtoReflect.Descriptor instead.
 func (*ListDirectoryRequest) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{13}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ListDirectoryRequest) GetGomoteId() string {
@@ -913,7 +1331,7 @@ type ListDirectoryResponse struct {
 func (x *ListDirectoryResponse) Reset() {
 	*x = ListDirectoryResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[14]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -926,7 +1344,7 @@ func (x *ListDirectoryResponse) String() string {
 func (*ListDirectoryResponse) ProtoMessage() {}
 
 func (x *ListDirectoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[14]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -939,7 +1357,7 @@ func (x *ListDirectoryResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDirectoryResponse.ProtoReflect.Descriptor instead.
 func (*ListDirectoryResponse) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{14}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *ListDirectoryResponse) GetEntries() []string {
@@ -959,7 +1377,7 @@ type ListInstancesRequest struct {
 func (x *ListInstancesRequest) Reset() {
 	*x = ListInstancesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[15]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -972,7 +1390,7 @@ func (x *ListInstancesRequest) String() string {
 func (*ListInstancesRequest) ProtoMessage() {}
 
 func (x *ListInstancesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[15]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -985,7 +1403,7 @@ func (x *ListInstancesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListInstancesRequest.ProtoReflect.Descriptor instead.
 func (*ListInstancesRequest) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{15}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{21}
 }
 
 // ListInstancesResponse contains the list of live gomote instances owned by the caller.
@@ -1000,7 +1418,7 @@ type ListInstancesResponse struct {
 func (x *ListInstancesResponse) Reset() {
 	*x = ListInstancesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[16]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1013,7 +1431,7 @@ func (x *ListInstancesResponse) String() string {
 func (*ListInstancesResponse) ProtoMessage() {}
 
 func (x *ListInstancesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[16]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1026,7 +1444,7 @@ func (x *ListInstancesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListInstancesResponse.ProtoReflect.Descriptor instead.
 func (*ListInstancesResponse) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{16}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *ListInstancesResponse) GetInstances() []*Instance {
@@ -1036,30 +1454,30 @@ func (x *ListInstancesResponse) GetInstances() []*Instance {
 	return nil
 }
 
-// ListSwarmingBuildersRequest specifies the data needed to list all swarming builders.
-type ListSwarmingBuildersRequest struct {
+// ListSnapshotsRequest specifies the data needed to list the caller's snapshots.
+type ListSnapshotsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *ListSwarmingBuildersRequest) Reset() {
-	*x = ListSwarmingBuildersRequest{}
+func (x *ListSnapshotsRequest) Reset() {
+	*x = ListSnapshotsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[17]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListSwarmingBuildersRequest) String() string {
+func (x *ListSnapshotsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSwarmingBuildersRequest) ProtoMessage() {}
+func (*ListSnapshotsRequest) ProtoMessage() {}
 
-func (x *ListSwarmingBuildersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[17]
+func (x *ListSnapshotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1070,37 +1488,37 @@ func (x *ListSwarmingBuildersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSwarmingBuildersRequest.ProtoReflect.Descriptor instead.
-func (*ListSwarmingBuildersRequest) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use ListSnapshotsRequest.ProtoReflect.Descriptor instead.
+func (*ListSnapshotsRequest) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{23}
 }
 
-// ListSwarmingBuildersResponse contains a list of swarming builders.
-type ListSwarmingBuildersResponse struct {
+// ListSnapshotsResponse contains the caller's unexpired snapshots, oldest first.
+type ListSnapshotsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Builders []string `protobuf:"bytes,1,rep,name=builders,proto3" json:"builders,omitempty"`
+	Snapshots []*Snapshot `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
 }
 
-func (x *ListSwarmingBuildersResponse) Reset() {
-	*x = ListSwarmingBuildersResponse{}
+func (x *ListSnapshotsResponse) Reset() {
+	*x = ListSnapshotsResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[18]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListSwarmingBuildersResponse) String() string {
+func (x *ListSnapshotsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSwarmingBuildersResponse) ProtoMessage() {}
+func (*ListSnapshotsResponse) ProtoMessage() {}
 
-func (x *ListSwarmingBuildersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[18]
+func (x *ListSnapshotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1111,47 +1529,42 @@ func (x *ListSwarmingBuildersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSwarmingBuildersResponse.ProtoReflect.Descriptor instead.
-func (*ListSwarmingBuildersResponse) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use ListSnapshotsResponse.ProtoReflect.Descriptor instead.
+func (*ListSnapshotsResponse) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *ListSwarmingBuildersResponse) GetBuilders() []string {
+func (x *ListSnapshotsResponse) GetSnapshots() []*Snapshot {
 	if x != nil {
-		return x.Builders
+		return x.Snapshots
 	}
 	return nil
 }
 
-// ReadTGZToURLRequest specifies the data needed to retrieve a tar and zipped directory from a gomote instance.
-type ReadTGZToURLRequest struct {
+// ListSwarmingBuildersRequest specifies the data needed to list all swarming builders.
+type ListSwarmingBuildersRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// The unique identifier for a gomote instance.
-	GomoteId string `protobuf:"bytes,1,opt,name=gomote_id,json=gomoteId,proto3" json:"gomote_id,omitempty"`
-	// The relative directory from the gomote's work directory to tar up.
-	Directory string `protobuf:"bytes,2,opt,name=directory,proto3" json:"directory,omitempty"`
 }
 
-func (x *ReadTGZToURLRequest) Reset() {
-	*x = ReadTGZToURLRequest{}
+func (x *ListSwarmingBuildersRequest) Reset() {
+	*x = ListSwarmingBuildersRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[19]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ReadTGZToURLRequest) String() string {
+func (x *ListSwarmingBuildersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadTGZToURLRequest) ProtoMessage() {}
+func (*ListSwarmingBuildersRequest) ProtoMessage() {}
 
-func (x *ReadTGZToURLRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[19]
+func (x *ListSwarmingBuildersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1162,39 +1575,238 @@ func (x *ReadTGZToURLRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadTGZToURLRequest.ProtoReflect.Descriptor instead.
-func (*ReadTGZToURLRequest) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{19}
-}
-
-func (x *ReadTGZToURLRequest) GetGomoteId() string {
-	if x != nil {
-		return x.GomoteId
-	}
-	return ""
-}
-
-func (x *ReadTGZToURLRequest) GetDirectory() string {
-	if x != nil {
-		return x.Directory
-	}
-	return ""
+// Deprecated: Use ListSwarmingBuildersRequest.ProtoReflect.Descriptor instead.
+func (*ListSwarmingBuildersRequest) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{25}
 }
 
-// ReadTGZToURLResponse contains a URL where the tar and zipped directory from a gomote instance can be downloaded from.
-type ReadTGZToURLResponse struct {
+// ListSwarmingBuildersResponse contains a list of swarming builders.
+type ListSwarmingBuildersResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// URL to retrieve the tarball from.
-	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Builders []string `protobuf:"bytes,1,rep,name=builders,proto3" json:"builders,omitempty"`
 }
 
-func (x *ReadTGZToURLResponse) Reset() {
-	*x = ReadTGZToURLResponse{}
+func (x *ListSwarmingBuildersResponse) Reset() {
+	*x = ListSwarmingBuildersResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[20]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSwarmingBuildersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSwarmingBuildersResponse) ProtoMessage() {}
+
+func (x *ListSwarmingBuildersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSwarmingBuildersResponse.ProtoReflect.Descriptor instead.
+func (*ListSwarmingBuildersResponse) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListSwarmingBuildersResponse) GetBuilders() []string {
+	if x != nil {
+		return x.Builders
+	}
+	return nil
+}
+
+// ListUsageRequest specifies the data needed to list instance usage.
+type ListUsageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// List the usage of all users, rather than only the caller's.
+	// Only privileged users may do this.
+	AllUsers bool `protobuf:"varint,1,opt,name=all_users,json=allUsers,proto3" json:"all_users,omitempty"`
+}
+
+func (x *ListUsageRequest) Reset() {
+	*x = ListUsageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsageRequest) ProtoMessage() {}
+
+func (x *ListUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsageRequest.ProtoReflect.Descriptor instead.
+func (*ListUsageRequest) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListUsageRequest) GetAllUsers() bool {
+	if x != nil {
+		return x.AllUsers
+	}
+	return false
+}
+
+// ListUsageResponse contains the instance usage, by user and builder type.
+type ListUsageResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Usage []*Usage `protobuf:"bytes,1,rep,name=usage,proto3" json:"usage,omitempty"`
+	// The number of instances each user may have at once, or zero if there's no limit.
+	MaxInstancesPerUser int64 `protobuf:"varint,2,opt,name=max_instances_per_user,json=maxInstancesPerUser,proto3" json:"max_instances_per_user,omitempty"`
+}
+
+func (x *ListUsageResponse) Reset() {
+	*x = ListUsageResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsageResponse) ProtoMessage() {}
+
+func (x *ListUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsageResponse.ProtoReflect.Descriptor instead.
+func (*ListUsageResponse) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListUsageResponse) GetUsage() []*Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *ListUsageResponse) GetMaxInstancesPerUser() int64 {
+	if x != nil {
+		return x.MaxInstancesPerUser
+	}
+	return 0
+}
+
+// ReadTGZToURLRequest specifies the data needed to retrieve a tar and zipped directory from a gomote instance.
+type ReadTGZToURLRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique identifier for a gomote instance.
+	GomoteId string `protobuf:"bytes,1,opt,name=gomote_id,json=gomoteId,proto3" json:"gomote_id,omitempty"`
+	// The relative directory from the gomote's work directory to tar up.
+	Directory string `protobuf:"bytes,2,opt,name=directory,proto3" json:"directory,omitempty"`
+}
+
+func (x *ReadTGZToURLRequest) Reset() {
+	*x = ReadTGZToURLRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadTGZToURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadTGZToURLRequest) ProtoMessage() {}
+
+func (x *ReadTGZToURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadTGZToURLRequest.ProtoReflect.Descriptor instead.
+func (*ReadTGZToURLRequest) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ReadTGZToURLRequest) GetGomoteId() string {
+	if x != nil {
+		return x.GomoteId
+	}
+	return ""
+}
+
+func (x *ReadTGZToURLRequest) GetDirectory() string {
+	if x != nil {
+		return x.Directory
+	}
+	return ""
+}
+
+// ReadTGZToURLResponse contains a URL where the tar and zipped directory from a gomote instance can be downloaded from.
+type ReadTGZToURLResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// URL to retrieve the tarball from.
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+}
+
+func (x *ReadTGZToURLResponse) Reset() {
+	*x = ReadTGZToURLResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1207,7 +1819,7 @@ func (x *ReadTGZToURLResponse) String() string {
 func (*ReadTGZToURLResponse) ProtoMessage() {}
 
 func (x *ReadTGZToURLResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[20]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1220,7 +1832,7 @@ func (x *ReadTGZToURLResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadTGZToURLResponse.ProtoReflect.Descriptor instead.
 func (*ReadTGZToURLResponse) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{20}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *ReadTGZToURLResponse) GetUrl() string {
@@ -1247,7 +1859,7 @@ type RemoveFilesRequest struct {
 func (x *RemoveFilesRequest) Reset() {
 	*x = RemoveFilesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[21]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1260,7 +1872,7 @@ func (x *RemoveFilesRequest) String() string {
 func (*RemoveFilesRequest) ProtoMessage() {}
 
 func (x *RemoveFilesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[21]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1273,7 +1885,7 @@ func (x *RemoveFilesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveFilesRequest.ProtoReflect.Descriptor instead.
 func (*RemoveFilesRequest) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{21}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *RemoveFilesRequest) GetGomoteId() string {
@@ -1300,7 +1912,7 @@ type RemoveFilesResponse struct {
 func (x *RemoveFilesResponse) Reset() {
 	*x = RemoveFilesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[22]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1313,7 +1925,7 @@ func (x *RemoveFilesResponse) String() string {
 func (*RemoveFilesResponse) ProtoMessage() {}
 
 func (x *RemoveFilesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[22]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1326,38 +1938,38 @@ func (x *RemoveFilesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveFilesResponse.ProtoReflect.Descriptor instead.
 func (*RemoveFilesResponse) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{22}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{32}
 }
 
-// SignSSHKeyRequest specifies the data needed to sign a public SSH key which attaches a certificate to the key.
-type SignSSHKeyRequest struct {
+// RestoreSnapshotRequest specifies the snapshot to restore and the gomote instance to restore it onto.
+type RestoreSnapshotRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	// The unique identifier for a gomote instance.
 	GomoteId string `protobuf:"bytes,1,opt,name=gomote_id,json=gomoteId,proto3" json:"gomote_id,omitempty"`
-	// A user provided public SSH key which the user intends to initiate an SSH session with.
-	PublicSshKey []byte `protobuf:"bytes,2,opt,name=public_ssh_key,json=publicSshKey,proto3" json:"public_ssh_key,omitempty"`
+	// The unique identifier for a snapshot.
+	SnapshotId string `protobuf:"bytes,2,opt,name=snapshot_id,json=snapshotId,proto3" json:"snapshot_id,omitempty"`
 }
 
-func (x *SignSSHKeyRequest) Reset() {
-	*x = SignSSHKeyRequest{}
+func (x *RestoreSnapshotRequest) Reset() {
+	*x = RestoreSnapshotRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[23]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SignSSHKeyRequest) String() string {
+func (x *RestoreSnapshotRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignSSHKeyRequest) ProtoMessage() {}
+func (*RestoreSnapshotRequest) ProtoMessage() {}
 
-func (x *SignSSHKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[23]
+func (x *RestoreSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1368,53 +1980,1205 @@ func (x *SignSSHKeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignSSHKeyRequest.ProtoReflect.Descriptor instead.
-func (*SignSSHKeyRequest) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use RestoreSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*RestoreSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RestoreSnapshotRequest) GetGomoteId() string {
+	if x != nil {
+		return x.GomoteId
+	}
+	return ""
+}
+
+func (x *RestoreSnapshotRequest) GetSnapshotId() string {
+	if x != nil {
+		return x.SnapshotId
+	}
+	return ""
+}
+
+// RestoreSnapshotResponse is the response from restoring a snapshot.
+type RestoreSnapshotResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RestoreSnapshotResponse) Reset() {
+	*x = RestoreSnapshotResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreSnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreSnapshotResponse) ProtoMessage() {}
+
+func (x *RestoreSnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreSnapshotResponse.ProtoReflect.Descriptor instead.
+func (*RestoreSnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{34}
+}
+
+// Sandbox describes the isolation of a command run in a sandbox.
+type Sandbox struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The network the command can reach: "" for the host's network,
+	// "none", "loopback", or "allowlist".
+	Network string `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	// The hosts the command may connect to through an HTTP proxy with the
+	// "allowlist" network, as "host" or "host:port". A host of the form
+	// "*.example.com" matches any subdomain of example.com.
+	Allow []string `protobuf:"bytes,2,rep,name=allow,proto3" json:"allow,omitempty"`
+	// Whether the work directory is read-only, except for GOCACHE and GOPLSCACHE.
+	ReadOnlyWorkDir bool `protobuf:"varint,3,opt,name=read_only_work_dir,json=readOnlyWorkDir,proto3" json:"read_only_work_dir,omitempty"`
+}
+
+func (x *Sandbox) Reset() {
+	*x = Sandbox{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Sandbox) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sandbox) ProtoMessage() {}
+
+func (x *Sandbox) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sandbox.ProtoReflect.Descriptor instead.
+func (*Sandbox) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *Sandbox) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *Sandbox) GetAllow() []string {
+	if x != nil {
+		return x.Allow
+	}
+	return nil
+}
+
+func (x *Sandbox) GetReadOnlyWorkDir() bool {
+	if x != nil {
+		return x.ReadOnlyWorkDir
+	}
+	return false
+}
+
+// Snapshot describes a saved copy of a directory on a gomote instance.
+type Snapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique identifier for the snapshot.
+	SnapshotId string `protobuf:"bytes,1,opt,name=snapshot_id,json=snapshotId,proto3" json:"snapshot_id,omitempty"`
+	// Builder type of the gomote instance the snapshot was saved from.
+	BuilderType string `protobuf:"bytes,2,opt,name=builder_type,json=builderType,proto3" json:"builder_type,omitempty"`
+	// The Go revision in the directory, as given when the snapshot was created.
+	GoRevision string `protobuf:"bytes,3,opt,name=go_revision,json=goRevision,proto3" json:"go_revision,omitempty"`
+	// The relative directory from the gomote's work directory that was saved.
+	Directory string `protobuf:"bytes,4,opt,name=directory,proto3" json:"directory,omitempty"`
+	// A description of the snapshot.
+	Description string `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	// The timestamp for when the snapshot was created. It is
+	// represented in Unix epoch time format.
+	Created int64 `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
+	// The timestamp for when the snapshot will expire. It is
+	// represented in Unix epoch time format.
+	Expires int64 `protobuf:"varint,7,opt,name=expires,proto3" json:"expires,omitempty"`
+	// The size of the snapshot's tar.gz file.
+	SizeBytes int64 `protobuf:"varint,8,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+}
+
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Snapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snapshot) ProtoMessage() {}
+
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *Snapshot) GetSnapshotId() string {
+	if x != nil {
+		return x.SnapshotId
+	}
+	return ""
+}
+
+func (x *Snapshot) GetBuilderType() string {
+	if x != nil {
+		return x.BuilderType
+	}
+	return ""
+}
+
+func (x *Snapshot) GetGoRevision() string {
+	if x != nil {
+		return x.GoRevision
+	}
+	return ""
+}
+
+func (x *Snapshot) GetDirectory() string {
+	if x != nil {
+		return x.Directory
+	}
+	return ""
+}
+
+func (x *Snapshot) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Snapshot) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *Snapshot) GetExpires() int64 {
+	if x != nil {
+		return x.Expires
+	}
+	return 0
+}
+
+func (x *Snapshot) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+// SignSSHKeyRequest specifies the data needed to sign a public SSH key which attaches a certificate to the key.
+type SignSSHKeyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique identifier for a gomote instance.
+	GomoteId string `protobuf:"bytes,1,opt,name=gomote_id,json=gomoteId,proto3" json:"gomote_id,omitempty"`
+	// A user provided public SSH key which the user intends to initiate an SSH session with.
+	PublicSshKey []byte `protobuf:"bytes,2,opt,name=public_ssh_key,json=publicSshKey,proto3" json:"public_ssh_key,omitempty"`
+}
+
+func (x *SignSSHKeyRequest) Reset() {
+	*x = SignSSHKeyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignSSHKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignSSHKeyRequest) ProtoMessage() {}
+
+func (x *SignSSHKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignSSHKeyRequest.ProtoReflect.Descriptor instead.
+func (*SignSSHKeyRequest) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SignSSHKeyRequest) GetGomoteId() string {
+	if x != nil {
+		return x.GomoteId
+	}
+	return ""
+}
+
+func (x *SignSSHKeyRequest) GetPublicSshKey() []byte {
+	if x != nil {
+		return x.PublicSshKey
+	}
+	return nil
+}
+
+// SignSSHKeyResponse contains the results from a request to sign a public SSH key.
+type SignSSHKeyResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A signed SSH key can be used in conjunction with the associated private key to initiate an SSH session to a gomote instance.
+	// The certificate attached to the key will contain principles which restrict the instance that can be logged into.
+	SignedPublicSshKey []byte `protobuf:"bytes,1,opt,name=signed_public_ssh_key,json=signedPublicSshKey,proto3" json:"signed_public_ssh_key,omitempty"`
+}
+
+func (x *SignSSHKeyResponse) Reset() {
+	*x = SignSSHKeyResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignSSHKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignSSHKeyResponse) ProtoMessage() {}
+
+func (x *SignSSHKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignSSHKeyResponse.ProtoReflect.Descriptor instead.
+func (*SignSSHKeyResponse) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *SignSSHKeyResponse) GetSignedPublicSshKey() []byte {
+	if x != nil {
+		return x.SignedPublicSshKey
+	}
+	return nil
+}
+
+// StreamCommandRequest is a message sent by the client of a StreamCommand call.
+// The first message must contain start, and later ones must not.
+type StreamCommandRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Request:
+	//	*StreamCommandRequest_Start
+	//	*StreamCommandRequest_Stdin
+	//	*StreamCommandRequest_CloseStdin
+	//	*StreamCommandRequest_Signal
+	//	*StreamCommandRequest_Resize
+	Request isStreamCommandRequest_Request `protobuf_oneof:"request"`
+}
+
+func (x *StreamCommandRequest) Reset() {
+	*x = StreamCommandRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamCommandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamCommandRequest) ProtoMessage() {}
+
+func (x *StreamCommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamCommandRequest.ProtoReflect.Descriptor instead.
+func (*StreamCommandRequest) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{39}
+}
+
+func (m *StreamCommandRequest) GetRequest() isStreamCommandRequest_Request {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (x *StreamCommandRequest) GetStart() *StreamCommandStart {
+	if x, ok := x.GetRequest().(*StreamCommandRequest_Start); ok {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *StreamCommandRequest) GetStdin() []byte {
+	if x, ok := x.GetRequest().(*StreamCommandRequest_Stdin); ok {
+		return x.Stdin
+	}
+	return nil
+}
+
+func (x *StreamCommandRequest) GetCloseStdin() bool {
+	if x, ok := x.GetRequest().(*StreamCommandRequest_CloseStdin); ok {
+		return x.CloseStdin
+	}
+	return false
+}
+
+func (x *StreamCommandRequest) GetSignal() string {
+	if x, ok := x.GetRequest().(*StreamCommandRequest_Signal); ok {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *StreamCommandRequest) GetResize() *TerminalSize {
+	if x, ok := x.GetRequest().(*StreamCommandRequest_Resize); ok {
+		return x.Resize
+	}
+	return nil
+}
+
+type isStreamCommandRequest_Request interface {
+	isStreamCommandRequest_Request()
+}
+
+type StreamCommandRequest_Start struct {
+	// The command to start.
+	Start *StreamCommandStart `protobuf:"bytes,1,opt,name=start,proto3,oneof"`
+}
+
+type StreamCommandRequest_Stdin struct {
+	// Data for the command's standard input.
+	Stdin []byte `protobuf:"bytes,2,opt,name=stdin,proto3,oneof"`
+}
+
+type StreamCommandRequest_CloseStdin struct {
+	// Closes the command's standard input.
+	CloseStdin bool `protobuf:"varint,3,opt,name=close_stdin,json=closeStdin,proto3,oneof"`
+}
+
+type StreamCommandRequest_Signal struct {
+	// A signal to forward to the command: "INT", "TERM", or "QUIT".
+	Signal string `protobuf:"bytes,4,opt,name=signal,proto3,oneof"`
+}
+
+type StreamCommandRequest_Resize struct {
+	// The new size of the command's terminal. Only valid if a terminal was requested.
+	Resize *TerminalSize `protobuf:"bytes,5,opt,name=resize,proto3,oneof"`
+}
+
+func (*StreamCommandRequest_Start) isStreamCommandRequest_Request() {}
+
+func (*StreamCommandRequest_Stdin) isStreamCommandRequest_Request() {}
+
+func (*StreamCommandRequest_CloseStdin) isStreamCommandRequest_Request() {}
+
+func (*StreamCommandRequest_Signal) isStreamCommandRequest_Request() {}
+
+func (*StreamCommandRequest_Resize) isStreamCommandRequest_Request() {}
+
+// StreamCommandStart specifies a command to start with StreamCommand.
+type StreamCommandStart struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The command to execute, as for ExecuteCommand.
+	Command *ExecuteCommandRequest `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	// Whether to run the command in a pseudo-terminal. If set, the command's
+	// standard output and error are both sent as stdout.
+	Pty bool `protobuf:"varint,2,opt,name=pty,proto3" json:"pty,omitempty"`
+	// The TERM environment variable for the terminal, like "xterm-256color".
+	Term string `protobuf:"bytes,3,opt,name=term,proto3" json:"term,omitempty"`
+	// The initial size of the terminal.
+	Size *TerminalSize `protobuf:"bytes,4,opt,name=size,proto3" json:"size,omitempty"`
+}
+
+func (x *StreamCommandStart) Reset() {
+	*x = StreamCommandStart{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamCommandStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamCommandStart) ProtoMessage() {}
+
+func (x *StreamCommandStart) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamCommandStart.ProtoReflect.Descriptor instead.
+func (*StreamCommandStart) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *StreamCommandStart) GetCommand() *ExecuteCommandRequest {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *StreamCommandStart) GetPty() bool {
+	if x != nil {
+		return x.Pty
+	}
+	return false
+}
+
+func (x *StreamCommandStart) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *StreamCommandStart) GetSize() *TerminalSize {
+	if x != nil {
+		return x.Size
+	}
+	return nil
+}
+
+// StreamCommandResponse is a message sent by the server of a StreamCommand call.
+type StreamCommandResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Response:
+	//	*StreamCommandResponse_Stdout
+	//	*StreamCommandResponse_Stderr
+	//	*StreamCommandResponse_ExitStatus
+	Response isStreamCommandResponse_Response `protobuf_oneof:"response"`
+}
+
+func (x *StreamCommandResponse) Reset() {
+	*x = StreamCommandResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamCommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamCommandResponse) ProtoMessage() {}
+
+func (x *StreamCommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamCommandResponse.ProtoReflect.Descriptor instead.
+func (*StreamCommandResponse) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{41}
+}
+
+func (m *StreamCommandResponse) GetResponse() isStreamCommandResponse_Response {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+func (x *StreamCommandResponse) GetStdout() []byte {
+	if x, ok := x.GetResponse().(*StreamCommandResponse_Stdout); ok {
+		return x.Stdout
+	}
+	return nil
+}
+
+func (x *StreamCommandResponse) GetStderr() []byte {
+	if x, ok := x.GetResponse().(*StreamCommandResponse_Stderr); ok {
+		return x.Stderr
+	}
+	return nil
+}
+
+func (x *StreamCommandResponse) GetExitStatus() *ExitStatus {
+	if x, ok := x.GetResponse().(*StreamCommandResponse_ExitStatus); ok {
+		return x.ExitStatus
+	}
+	return nil
+}
+
+type isStreamCommandResponse_Response interface {
+	isStreamCommandResponse_Response()
+}
+
+type StreamCommandResponse_Stdout struct {
+	// Output written to the command's standard output.
+	Stdout []byte `protobuf:"bytes,1,opt,name=stdout,proto3,oneof"`
+}
+
+type StreamCommandResponse_Stderr struct {
+	// Output written to the command's standard error.
+	Stderr []byte `protobuf:"bytes,2,opt,name=stderr,proto3,oneof"`
+}
+
+type StreamCommandResponse_ExitStatus struct {
+	// The command's exit status. It is the last message sent.
+	ExitStatus *ExitStatus `protobuf:"bytes,3,opt,name=exit_status,json=exitStatus,proto3,oneof"`
+}
+
+func (*StreamCommandResponse_Stdout) isStreamCommandResponse_Response() {}
+
+func (*StreamCommandResponse_Stderr) isStreamCommandResponse_Response() {}
+
+func (*StreamCommandResponse_ExitStatus) isStreamCommandResponse_Response() {}
+
+// SyncFile is a regular file in the manifest of a SyncFiles request.
+type SyncFile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The slash-separated path of the file, relative to the directory being synced.
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// The lowercase hex SHA-1 digest of the file's contents.
+	Digest string `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	// The file's permission bits.
+	Mode uint32 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`
+}
+
+func (x *SyncFile) Reset() {
+	*x = SyncFile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncFile) ProtoMessage() {}
+
+func (x *SyncFile) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncFile.ProtoReflect.Descriptor instead.
+func (*SyncFile) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *SyncFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *SyncFile) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+func (x *SyncFile) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+// SyncFilesRequest specifies the desired contents of a directory on a gomote instance.
+type SyncFilesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique identifier for a gomote instance.
+	GomoteId string `protobuf:"bytes,1,opt,name=gomote_id,json=gomoteId,proto3" json:"gomote_id,omitempty"`
+	// The directory to sync, relative to the work directory.
+	Directory string `protobuf:"bytes,2,opt,name=directory,proto3" json:"directory,omitempty"`
+	// The files to create or update.
+	Files []*SyncFile `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	// Slash-separated paths, relative to the directory, to remove before any files are written.
+	Delete []string `protobuf:"bytes,4,rep,name=delete,proto3" json:"delete,omitempty"`
+	// The URL of a tar.gz file with the missing file contents, as written by buildlet.WriteSyncTGZ
+	// and uploaded with UploadFile. If empty, the manifest is only applied if the instance already
+	// has all the contents it needs.
+	BlobsUrl string `protobuf:"bytes,5,opt,name=blobs_url,json=blobsUrl,proto3" json:"blobs_url,omitempty"`
+}
+
+func (x *SyncFilesRequest) Reset() {
+	*x = SyncFilesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncFilesRequest) ProtoMessage() {}
+
+func (x *SyncFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncFilesRequest.ProtoReflect.Descriptor instead.
+func (*SyncFilesRequest) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *SyncFilesRequest) GetGomoteId() string {
+	if x != nil {
+		return x.GomoteId
+	}
+	return ""
+}
+
+func (x *SyncFilesRequest) GetDirectory() string {
+	if x != nil {
+		return x.Directory
+	}
+	return ""
+}
+
+func (x *SyncFilesRequest) GetFiles() []*SyncFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *SyncFilesRequest) GetDelete() []string {
+	if x != nil {
+		return x.Delete
+	}
+	return nil
+}
+
+func (x *SyncFilesRequest) GetBlobsUrl() string {
+	if x != nil {
+		return x.BlobsUrl
+	}
+	return ""
+}
+
+// SyncFilesResponse contains the results of a SyncFiles request.
+type SyncFilesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The digests of the file contents the instance is missing. If any are listed,
+	// nothing was changed, and the request should be repeated with blobs_url.
+	MissingDigests []string `protobuf:"bytes,1,rep,name=missing_digests,json=missingDigests,proto3" json:"missing_digests,omitempty"`
+	// The number of files written.
+	Written int32 `protobuf:"varint,2,opt,name=written,proto3" json:"written,omitempty"`
+	// The number of paths deleted.
+	Deleted int32 `protobuf:"varint,3,opt,name=deleted,proto3" json:"deleted,omitempty"`
+}
+
+func (x *SyncFilesResponse) Reset() {
+	*x = SyncFilesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncFilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncFilesResponse) ProtoMessage() {}
+
+func (x *SyncFilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncFilesResponse.ProtoReflect.Descriptor instead.
+func (*SyncFilesResponse) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *SyncFilesResponse) GetMissingDigests() []string {
+	if x != nil {
+		return x.MissingDigests
+	}
+	return nil
+}
+
+func (x *SyncFilesResponse) GetWritten() int32 {
+	if x != nil {
+		return x.Written
+	}
+	return 0
+}
+
+func (x *SyncFilesResponse) GetDeleted() int32 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+// TailFileRequest specifies the file to tail on a gomote instance.
+type TailFileRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique identifier for a gomote instance.
+	GomoteId string `protobuf:"bytes,1,opt,name=gomote_id,json=gomoteId,proto3" json:"gomote_id,omitempty"`
+	// The path of the file, absolute or relative to the work directory.
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// The offset at which to start. If negative, it is relative to the end of the file.
+	Offset int64 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Whether to keep sending bytes appended to the file until the request is canceled.
+	Follow bool `protobuf:"varint,4,opt,name=follow,proto3" json:"follow,omitempty"`
+}
+
+func (x *TailFileRequest) Reset() {
+	*x = TailFileRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TailFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TailFileRequest) ProtoMessage() {}
+
+func (x *TailFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TailFileRequest.ProtoReflect.Descriptor instead.
+func (*TailFileRequest) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *TailFileRequest) GetGomoteId() string {
+	if x != nil {
+		return x.GomoteId
+	}
+	return ""
+}
+
+func (x *TailFileRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *TailFileRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *TailFileRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+// TailFileResponse contains some of the contents of a file.
+type TailFileResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The next bytes of the file.
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *TailFileResponse) Reset() {
+	*x = TailFileResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TailFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TailFileResponse) ProtoMessage() {}
+
+func (x *TailFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TailFileResponse.ProtoReflect.Descriptor instead.
+func (*TailFileResponse) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *TailFileResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// TerminalSize is the size of a terminal, in characters.
+type TerminalSize struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rows uint32 `protobuf:"varint,1,opt,name=rows,proto3" json:"rows,omitempty"`
+	Cols uint32 `protobuf:"varint,2,opt,name=cols,proto3" json:"cols,omitempty"`
+}
+
+func (x *TerminalSize) Reset() {
+	*x = TerminalSize{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TerminalSize) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerminalSize) ProtoMessage() {}
+
+func (x *TerminalSize) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminalSize.ProtoReflect.Descriptor instead.
+func (*TerminalSize) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *TerminalSize) GetRows() uint32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *TerminalSize) GetCols() uint32 {
+	if x != nil {
+		return x.Cols
+	}
+	return 0
+}
+
+// UploadFileRequest specifies the data needed to create a request to upload an object to GCS.
+type UploadFileRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UploadFileRequest) Reset() {
+	*x = UploadFileRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadFileRequest) ProtoMessage() {}
+
+func (x *UploadFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadFileRequest.ProtoReflect.Descriptor instead.
+func (*UploadFileRequest) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{48}
+}
+
+// UploadFileResponse contains the results from a request to upload an object to GCS.
+type UploadFileResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// URL to post file to.
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// Form fields used when http posting files to GCS.
+	Fields map[string]string `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Name used to reference the object.
+	ObjectName string `protobuf:"bytes,3,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+}
+
+func (x *UploadFileResponse) Reset() {
+	*x = UploadFileResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadFileResponse) ProtoMessage() {}
+
+func (x *UploadFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadFileResponse.ProtoReflect.Descriptor instead.
+func (*UploadFileResponse) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *UploadFileResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
 }
 
-func (x *SignSSHKeyRequest) GetGomoteId() string {
+func (x *UploadFileResponse) GetFields() map[string]string {
 	if x != nil {
-		return x.GomoteId
+		return x.Fields
 	}
-	return ""
+	return nil
 }
 
-func (x *SignSSHKeyRequest) GetPublicSshKey() []byte {
+func (x *UploadFileResponse) GetObjectName() string {
 	if x != nil {
-		return x.PublicSshKey
+		return x.ObjectName
 	}
-	return nil
+	return ""
 }
 
-// SignSSHKeyResponse contains the results from a request to sign a public SSH key.
-type SignSSHKeyResponse struct {
+// Usage describes the instances of one builder type created by one user
+// since the server started.
+type Usage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// A signed SSH key can be used in conjunction with the associated private key to initiate an SSH session to a gomote instance.
-	// The certificate attached to the key will contain principles which restrict the instance that can be logged into.
-	SignedPublicSshKey []byte `protobuf:"bytes,1,opt,name=signed_public_ssh_key,json=signedPublicSshKey,proto3" json:"signed_public_ssh_key,omitempty"`
+	// The user who created the instances.
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// Builder type of the instances.
+	BuilderType string `protobuf:"bytes,2,opt,name=builder_type,json=builderType,proto3" json:"builder_type,omitempty"`
+	// The number of instances created.
+	Instances int64 `protobuf:"varint,3,opt,name=instances,proto3" json:"instances,omitempty"`
+	// The number of instances that still exist.
+	ActiveInstances int64 `protobuf:"varint,4,opt,name=active_instances,json=activeInstances,proto3" json:"active_instances,omitempty"`
+	// The total lifetime of the instances, in seconds.
+	InstanceSeconds int64 `protobuf:"varint,5,opt,name=instance_seconds,json=instanceSeconds,proto3" json:"instance_seconds,omitempty"`
 }
 
-func (x *SignSSHKeyResponse) Reset() {
-	*x = SignSSHKeyResponse{}
+func (x *Usage) Reset() {
+	*x = Usage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[24]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[50]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SignSSHKeyResponse) String() string {
+func (x *Usage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignSSHKeyResponse) ProtoMessage() {}
+func (*Usage) ProtoMessage() {}
 
-func (x *SignSSHKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[24]
+func (x *Usage) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[50]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1425,42 +3189,75 @@ func (x *SignSSHKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignSSHKeyResponse.ProtoReflect.Descriptor instead.
-func (*SignSSHKeyResponse) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use Usage.ProtoReflect.Descriptor instead.
+func (*Usage) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{50}
 }
 
-func (x *SignSSHKeyResponse) GetSignedPublicSshKey() []byte {
+func (x *Usage) GetUser() string {
 	if x != nil {
-		return x.SignedPublicSshKey
+		return x.User
 	}
-	return nil
+	return ""
 }
 
-// UploadFileRequest specifies the data needed to create a request to upload an object to GCS.
-type UploadFileRequest struct {
+func (x *Usage) GetBuilderType() string {
+	if x != nil {
+		return x.BuilderType
+	}
+	return ""
+}
+
+func (x *Usage) GetInstances() int64 {
+	if x != nil {
+		return x.Instances
+	}
+	return 0
+}
+
+func (x *Usage) GetActiveInstances() int64 {
+	if x != nil {
+		return x.ActiveInstances
+	}
+	return 0
+}
+
+func (x *Usage) GetInstanceSeconds() int64 {
+	if x != nil {
+		return x.InstanceSeconds
+	}
+	return 0
+}
+
+// WatchDirectoryRequest specifies the directory to watch on a gomote instance.
+type WatchDirectoryRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The unique identifier for a gomote instance.
+	GomoteId string `protobuf:"bytes,1,opt,name=gomote_id,json=gomoteId,proto3" json:"gomote_id,omitempty"`
+	// The directory, absolute or relative to the work directory.
+	Directory string `protobuf:"bytes,2,opt,name=directory,proto3" json:"directory,omitempty"`
 }
 
-func (x *UploadFileRequest) Reset() {
-	*x = UploadFileRequest{}
+func (x *WatchDirectoryRequest) Reset() {
+	*x = WatchDirectoryRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[25]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[51]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *UploadFileRequest) String() string {
+func (x *WatchDirectoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadFileRequest) ProtoMessage() {}
+func (*WatchDirectoryRequest) ProtoMessage() {}
 
-func (x *UploadFileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[25]
+func (x *WatchDirectoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[51]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1471,42 +3268,60 @@ func (x *UploadFileRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadFileRequest.ProtoReflect.Descriptor instead.
-func (*UploadFileRequest) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use WatchDirectoryRequest.ProtoReflect.Descriptor instead.
+func (*WatchDirectoryRequest) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{51}
 }
 
-// UploadFileResponse contains the results from a request to upload an object to GCS.
-type UploadFileResponse struct {
+func (x *WatchDirectoryRequest) GetGomoteId() string {
+	if x != nil {
+		return x.GomoteId
+	}
+	return ""
+}
+
+func (x *WatchDirectoryRequest) GetDirectory() string {
+	if x != nil {
+		return x.Directory
+	}
+	return ""
+}
+
+// WatchDirectoryResponse describes a change to a file in the watched directory.
+type WatchDirectoryResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// URL to post file to.
-	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	// Form fields used when http posting files to GCS.
-	Fields map[string]string `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	// Name used to reference the object.
-	ObjectName string `protobuf:"bytes,3,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// What happened to the file: "create", "write", "chmod", or "remove".
+	Op string `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"`
+	// The slash-separated path of the file, relative to the watched directory.
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// The file's mode after the change, as an os.FileMode.
+	Mode uint32 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	// The file's size after the change.
+	Size int64 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	// The file's modification time after the change, in Unix epoch time format.
+	ModTime int64 `protobuf:"varint,5,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
 }
 
-func (x *UploadFileResponse) Reset() {
-	*x = UploadFileResponse{}
+func (x *WatchDirectoryResponse) Reset() {
+	*x = WatchDirectoryResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[26]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[52]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *UploadFileResponse) String() string {
+func (x *WatchDirectoryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadFileResponse) ProtoMessage() {}
+func (*WatchDirectoryResponse) ProtoMessage() {}
 
-func (x *UploadFileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[26]
+func (x *WatchDirectoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[52]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1517,30 +3332,44 @@ func (x *UploadFileResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadFileResponse.ProtoReflect.Descriptor instead.
-func (*UploadFileResponse) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use WatchDirectoryResponse.ProtoReflect.Descriptor instead.
+func (*WatchDirectoryResponse) Descriptor() ([]byte, []int) {
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *UploadFileResponse) GetUrl() string {
+func (x *WatchDirectoryResponse) GetOp() string {
 	if x != nil {
-		return x.Url
+		return x.Op
 	}
 	return ""
 }
 
-func (x *UploadFileResponse) GetFields() map[string]string {
+func (x *WatchDirectoryResponse) GetPath() string {
 	if x != nil {
-		return x.Fields
+		return x.Path
 	}
-	return nil
+	return ""
 }
 
-func (x *UploadFileResponse) GetObjectName() string {
+func (x *WatchDirectoryResponse) GetMode() uint32 {
 	if x != nil {
-		return x.ObjectName
+		return x.Mode
 	}
-	return ""
+	return 0
+}
+
+func (x *WatchDirectoryResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *WatchDirectoryResponse) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
 }
 
 // WriteFileFromURLRequest specifies the data needed to request that a gomote download the contents of a URL and place
@@ -1563,7 +3392,7 @@ type WriteFileFromURLRequest struct {
 func (x *WriteFileFromURLRequest) Reset() {
 	*x = WriteFileFromURLRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[27]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[53]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1576,7 +3405,7 @@ func (x *WriteFileFromURLRequest) String() string {
 func (*WriteFileFromURLRequest) ProtoMessage() {}
 
 func (x *WriteFileFromURLRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[27]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[53]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1589,7 +3418,7 @@ func (x *WriteFileFromURLRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WriteFileFromURLRequest.ProtoReflect.Descriptor instead.
 func (*WriteFileFromURLRequest) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{27}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{53}
 }
 
 func (x *WriteFileFromURLRequest) GetGomoteId() string {
@@ -1630,7 +3459,7 @@ type WriteFileFromURLResponse struct {
 func (x *WriteFileFromURLResponse) Reset() {
 	*x = WriteFileFromURLResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[28]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[54]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1643,7 +3472,7 @@ func (x *WriteFileFromURLResponse) String() string {
 func (*WriteFileFromURLResponse) ProtoMessage() {}
 
 func (x *WriteFileFromURLResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[28]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[54]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1656,7 +3485,7 @@ func (x *WriteFileFromURLResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WriteFileFromURLResponse.ProtoReflect.Descriptor instead.
 func (*WriteFileFromURLResponse) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{28}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{54}
 }
 
 // WriteTGZFromURLRequest specifies the data needed to retrieve a file and expand it onto the file system of a gomote instance.
@@ -1677,7 +3506,7 @@ type WriteTGZFromURLRequest struct {
 func (x *WriteTGZFromURLRequest) Reset() {
 	*x = WriteTGZFromURLRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[29]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[55]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1690,7 +3519,7 @@ func (x *WriteTGZFromURLRequest) String() string {
 func (*WriteTGZFromURLRequest) ProtoMessage() {}
 
 func (x *WriteTGZFromURLRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[29]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[55]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1703,7 +3532,7 @@ func (x *WriteTGZFromURLRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WriteTGZFromURLRequest.ProtoReflect.Descriptor instead.
 func (*WriteTGZFromURLRequest) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{29}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{55}
 }
 
 func (x *WriteTGZFromURLRequest) GetGomoteId() string {
@@ -1737,7 +3566,7 @@ type WriteTGZFromURLResponse struct {
 func (x *WriteTGZFromURLResponse) Reset() {
 	*x = WriteTGZFromURLResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[30]
+		mi := &file_internal_gomote_protos_gomote_proto_msgTypes[56]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1750,7 +3579,7 @@ func (x *WriteTGZFromURLResponse) String() string {
 func (*WriteTGZFromURLResponse) ProtoMessage() {}
 
 func (x *WriteTGZFromURLResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[30]
+	mi := &file_internal_gomote_protos_gomote_proto_msgTypes[56]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1763,7 +3592,7 @@ func (x *WriteTGZFromURLResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WriteTGZFromURLResponse.ProtoReflect.Descriptor instead.
 func (*WriteTGZFromURLResponse) Descriptor() ([]byte, []int) {
-	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{30}
+	return file_internal_gomote_protos_gomote_proto_rawDescGZIP(), []int{56}
 }
 
 var File_internal_gomote_protos_gomote_proto protoreflect.FileDescriptor
@@ -1788,7 +3617,7 @@ var file_internal_gomote_protos_gomote_proto_rawDesc = []byte{
 	0x09, 0x52, 0x0b, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2b,
 	0x0a, 0x11, 0x65, 0x78, 0x70, 0x65, 0x72, 0x69, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6f, 0x70, 0x74,
 	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x65, 0x78, 0x70, 0x65, 0x72,
-	0x69, 0x6d, 0x65, 0x6e, 0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xdc, 0x01, 0x0a, 0x16,
+	0x69, 0x6d, 0x65, 0x6e, 0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x8b, 0x02, 0x0a, 0x16,
 	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65,
 	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e,
 	0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
@@ -1799,78 +3628,142 @@ var file_internal_gomote_protos_gomote_proto_rawDesc = []byte{
 	0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61,
 	0x74, 0x75, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x77, 0x61, 0x69, 0x74, 0x65, 0x72, 0x73, 0x5f, 0x61,
 	0x68, 0x65, 0x61, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x77, 0x61, 0x69, 0x74,
-	0x65, 0x72, 0x73, 0x41, 0x68, 0x65, 0x61, 0x64, 0x22, 0x30, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12,
-	0x0b, 0x0a, 0x07, 0x57, 0x41, 0x49, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08,
-	0x43, 0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x54, 0x45, 0x10, 0x02, 0x22, 0x35, 0x0a, 0x16, 0x44, 0x65,
-	0x73, 0x74, 0x72, 0x6f, 0x79, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x49,
-	0x64, 0x22, 0x19, 0x0a, 0x17, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x49, 0x6e, 0x73, 0x74,
-	0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xa8, 0x02, 0x0a,
-	0x15, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74,
-	0x65, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x21, 0x0a,
-	0x0c, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x0b, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x4c, 0x65, 0x76, 0x65, 0x6c,
-	0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x12, 0x2d, 0x0a, 0x12, 0x61, 0x70, 0x70, 0x65, 0x6e, 0x64,
-	0x5f, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x11, 0x61, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f,
-	0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x06, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72,
-	0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69,
-	0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18,
-	0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x69,
-	0x6d, 0x69, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65,
-	0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x69, 0x6d, 0x69, 0x74, 0x61, 0x74, 0x65, 0x48,
-	0x6f, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0x30, 0x0a, 0x16, 0x45, 0x78, 0x65, 0x63, 0x75,
-	0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0xa2, 0x01, 0x0a, 0x08, 0x49, 0x6e,
-	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74,
-	0x65, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x5f, 0x74,
-	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x75, 0x69, 0x6c, 0x64,
-	0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x74,
-	0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x54,
-	0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x12, 0x1f, 0x0a,
-	0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x44, 0x69, 0x72, 0x22, 0x33,
-	0x0a, 0x14, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74,
-	0x65, 0x49, 0x64, 0x22, 0x17, 0x0a, 0x15, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x41,
-	0x6c, 0x69, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xa6, 0x01, 0x0a,
-	0x14, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
+	0x65, 0x72, 0x73, 0x41, 0x68, 0x65, 0x61, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x71, 0x75, 0x65, 0x75,
+	0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x71, 0x75, 0x65, 0x75, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x3c, 0x0a, 0x06, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e,
+	0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x57, 0x41, 0x49, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12,
+	0x0c, 0x0a, 0x08, 0x43, 0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x54, 0x45, 0x10, 0x02, 0x12, 0x0a, 0x0a,
+	0x06, 0x51, 0x55, 0x45, 0x55, 0x45, 0x44, 0x10, 0x03, 0x22, 0xb6, 0x01, 0x0a, 0x15, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x49, 0x64,
+	0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1f,
+	0x0a, 0x0b, 0x67, 0x6f, 0x5f, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x67, 0x6f, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x74, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x74, 0x74, 0x6c, 0x53, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x22, 0x46, 0x0a, 0x16, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x08,
+	0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x22, 0x38, 0x0a, 0x15, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x49, 0x64, 0x22, 0x18, 0x0a, 0x16, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x35,
+	0x0a, 0x16, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f, 0x6d, 0x6f,
+	0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x6f, 0x6d,
+	0x6f, 0x74, 0x65, 0x49, 0x64, 0x22, 0x19, 0x0a, 0x17, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79,
+	0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0xd3, 0x02, 0x0a, 0x15, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f,
+	0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67,
+	0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x6c, 0x65, 0x76, 0x65,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x12, 0x2d, 0x0a, 0x12, 0x61, 0x70,
+	0x70, 0x65, 0x6e, 0x64, 0x5f, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x11, 0x61, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x45, 0x6e,
+	0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x1c, 0x0a,
+	0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x61,
+	0x72, 0x67, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12,
+	0x2a, 0x0a, 0x11, 0x69, 0x6d, 0x69, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x69, 0x6d, 0x69, 0x74,
+	0x61, 0x74, 0x65, 0x48, 0x6f, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x29, 0x0a, 0x07, 0x73,
+	0x61, 0x6e, 0x64, 0x62, 0x6f, 0x78, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x53, 0x61, 0x6e, 0x64, 0x62, 0x6f, 0x78, 0x52, 0x07, 0x73,
+	0x61, 0x6e, 0x64, 0x62, 0x6f, 0x78, 0x22, 0x59, 0x0a, 0x16, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74,
+	0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x27, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
+	0x2e, 0x45, 0x78, 0x65, 0x63, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x73, 0x22, 0xd4, 0x01, 0x0a, 0x09, 0x45, 0x78, 0x65, 0x63, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12,
+	0x1d, 0x0a, 0x0a, 0x77, 0x61, 0x6c, 0x6c, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x77, 0x61, 0x6c, 0x6c, 0x4e, 0x61, 0x6e, 0x6f, 0x73, 0x12, 0x1d,
+	0x0a, 0x0a, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6e, 0x6f, 0x73, 0x12, 0x21, 0x0a,
+	0x0c, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0b, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x4e, 0x61, 0x6e, 0x6f, 0x73,
+	0x12, 0x22, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x73, 0x73, 0x5f, 0x62, 0x79, 0x74, 0x65,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x52, 0x73, 0x73, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x77, 0x72, 0x69, 0x74, 0x65,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x4e, 0x0a, 0x0a, 0x45, 0x78, 0x69, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x69,
+	0x67, 0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xa2, 0x01, 0x0a, 0x08, 0x49, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65,
+	0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x65,
+	0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x44, 0x69, 0x72, 0x22, 0x33, 0x0a,
+	0x14, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x52, 0x65,
 	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x5f,
 	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65,
-	0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
-	0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x75, 0x72, 0x73, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x09, 0x72, 0x65, 0x63, 0x75, 0x72, 0x73, 0x69, 0x76, 0x65, 0x12, 0x1d,
-	0x0a, 0x0a, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x09, 0x73, 0x6b, 0x69, 0x70, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x16, 0x0a,
-	0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64,
-	0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x31, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72,
-	0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
-	0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x16, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74,
-	0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x22, 0x47, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
-	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x09, 0x69, 0x6e, 0x73,
-	0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x09,
-	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x22, 0x1d, 0x0a, 0x1b, 0x4c, 0x69, 0x73,
+	0x49, 0x64, 0x22, 0x17, 0x0a, 0x15, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x41, 0x6c,
+	0x69, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xa6, 0x01, 0x0a, 0x14,
+	0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x49,
+	0x64, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12,
+	0x1c, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x75, 0x72, 0x73, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x09, 0x72, 0x65, 0x63, 0x75, 0x72, 0x73, 0x69, 0x76, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x09, 0x73, 0x6b, 0x69, 0x70, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x22, 0x31, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x16, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x47, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x09, 0x69, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x09, 0x69,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x22, 0x16, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x47, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x09, 0x73, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x09,
+	0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x22, 0x1d, 0x0a, 0x1b, 0x4c, 0x69, 0x73,
 	0x74, 0x53, 0x77, 0x61, 0x72, 0x6d, 0x69, 0x6e, 0x67, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72,
 	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x3a, 0x0a, 0x1c, 0x4c, 0x69, 0x73, 0x74,
 	0x53, 0x77, 0x61, 0x72, 0x6d, 0x69, 0x6e, 0x67, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x73,
 	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c,
 	0x64, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x62, 0x75, 0x69, 0x6c,
-	0x64, 0x65, 0x72, 0x73, 0x22, 0x50, 0x0a, 0x13, 0x52, 0x65, 0x61, 0x64, 0x54, 0x47, 0x5a, 0x54,
+	0x64, 0x65, 0x72, 0x73, 0x22, 0x2f, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x6c, 0x6c, 0x5f,
+	0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x6c, 0x6c,
+	0x55, 0x73, 0x65, 0x72, 0x73, 0x22, 0x6d, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x61,
+	0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x05, 0x75, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x73, 0x2e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x33, 0x0a, 0x16, 0x6d, 0x61, 0x78, 0x5f, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73,
+	0x5f, 0x70, 0x65, 0x72, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x13, 0x6d, 0x61, 0x78, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x50, 0x65, 0x72,
+	0x55, 0x73, 0x65, 0x72, 0x22, 0x50, 0x0a, 0x13, 0x52, 0x65, 0x61, 0x64, 0x54, 0x47, 0x5a, 0x54,
 	0x6f, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67,
 	0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
 	0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65,
@@ -1884,6 +3777,36 @@ var file_internal_gomote_protos_gomote_proto_rawDesc = []byte{
 	0x65, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x02, 0x20, 0x03,
 	0x28, 0x09, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x22, 0x15, 0x0a, 0x13, 0x52, 0x65, 0x6d,
 	0x6f, 0x76, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x56, 0x0a, 0x16, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f,
+	0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67,
+	0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x49, 0x64, 0x22, 0x19, 0x0a, 0x17, 0x52, 0x65, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x66, 0x0a, 0x07, 0x53, 0x61, 0x6e, 0x64, 0x62, 0x6f, 0x78, 0x12, 0x18,
+	0x0a, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x6c, 0x6f,
+	0x77, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x12, 0x2b,
+	0x0a, 0x12, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x5f, 0x77, 0x6f, 0x72, 0x6b,
+	0x5f, 0x64, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x72, 0x65, 0x61, 0x64,
+	0x4f, 0x6e, 0x6c, 0x79, 0x57, 0x6f, 0x72, 0x6b, 0x44, 0x69, 0x72, 0x22, 0x82, 0x02, 0x0a, 0x08,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x65, 0x72, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b,
+	0x67, 0x6f, 0x5f, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x67, 0x6f, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a,
+	0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x64,
+	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a,
+	0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72,
+	0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65,
+	0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73,
 	0x22, 0x56, 0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x53, 0x53, 0x48, 0x4b, 0x65, 0x79, 0x52, 0x65,
 	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x5f,
 	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65,
@@ -1894,124 +3817,261 @@ var file_internal_gomote_protos_gomote_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f,
 	0x73, 0x73, 0x68, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x12, 0x73,
 	0x69, 0x67, 0x6e, 0x65, 0x64, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x53, 0x73, 0x68, 0x4b, 0x65,
-	0x79, 0x22, 0x13, 0x0a, 0x11, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xc2, 0x01, 0x0a, 0x12, 0x55, 0x70, 0x6c, 0x6f, 0x61,
-	0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a,
-	0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12,
-	0x3e, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46,
-	0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x46, 0x69, 0x65, 0x6c,
-	0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x12,
-	0x1f, 0x0a, 0x0b, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65,
-	0x1a, 0x39, 0x0a, 0x0b, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
-	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
-	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x78, 0x0a, 0x17, 0x57,
-	0x72, 0x69, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c, 0x52,
+	0x79, 0x22, 0xda, 0x01, 0x0a, 0x14, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x73, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x48, 0x00, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x16,
+	0x0a, 0x05, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52,
+	0x05, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x12, 0x21, 0x0a, 0x0b, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x5f,
+	0x73, 0x74, 0x64, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x0a, 0x63,
+	0x6c, 0x6f, 0x73, 0x65, 0x53, 0x74, 0x64, 0x69, 0x6e, 0x12, 0x18, 0x0a, 0x06, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x6c, 0x12, 0x2e, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x54, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65, 0x73,
+	0x69, 0x7a, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x9d,
+	0x01, 0x0a, 0x12, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x37, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e,
+	0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x10,
+	0x0a, 0x03, 0x70, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x70, 0x74, 0x79,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x72, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x74, 0x65, 0x72, 0x6d, 0x12, 0x28, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x54, 0x65, 0x72, 0x6d,
+	0x69, 0x6e, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x8e,
+	0x01, 0x0a, 0x15, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x6f,
+	0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x64, 0x6f,
+	0x75, 0x74, 0x12, 0x18, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x12, 0x35, 0x0a, 0x0b,
+	0x65, 0x78, 0x69, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x45, 0x78, 0x69, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x0a, 0x65, 0x78, 0x69, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x42, 0x0a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x4a, 0x0a, 0x08, 0x53, 0x79, 0x6e, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12,
+	0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x22, 0xaa, 0x01, 0x0a, 0x10,
+	0x53, 0x79, 0x6e, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x49, 0x64, 0x12, 0x1c, 0x0a,
+	0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x26, 0x0a, 0x05, 0x66,
+	0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x73, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x66, 0x69,
+	0x6c, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x62,
+	0x6c, 0x6f, 0x62, 0x73, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x62, 0x6c, 0x6f, 0x62, 0x73, 0x55, 0x72, 0x6c, 0x22, 0x70, 0x0a, 0x11, 0x53, 0x79, 0x6e, 0x63,
+	0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a,
+	0x0f, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x44,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x72, 0x69, 0x74, 0x74, 0x65,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x77, 0x72, 0x69, 0x74, 0x74, 0x65, 0x6e,
+	0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x22, 0x72, 0x0a, 0x0f, 0x54, 0x61,
+	0x69, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a,
+	0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x16,
+	0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x22, 0x26,
+	0x0a, 0x10, 0x54, 0x61, 0x69, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x36, 0x0a, 0x0c, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e,
+	0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f,
+	0x6c, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x63, 0x6f, 0x6c, 0x73, 0x22, 0x13,
+	0x0a, 0x11, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0xc2, 0x01, 0x0a, 0x12, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69,
+	0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72,
+	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x3e, 0x0a, 0x06,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x1a, 0x39, 0x0a,
+	0x0b, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xb2, 0x01, 0x0a, 0x05, 0x55, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x65,
+	0x72, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x5f, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x73,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x52, 0x0a,
+	0x15, 0x57, 0x61, 0x74, 0x63, 0x68, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52,
 	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65,
 	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74,
-	0x65, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d,
-	0x65, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x07, 0x52,
-	0x04, 0x6d, 0x6f, 0x64, 0x65, 0x22, 0x1a, 0x0a, 0x18, 0x57, 0x72, 0x69, 0x74, 0x65, 0x46, 0x69,
-	0x6c, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x65, 0x0a, 0x16, 0x57, 0x72, 0x69, 0x74, 0x65, 0x54, 0x47, 0x5a, 0x46, 0x72, 0x6f,
-	0x6d, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67,
-	0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
-	0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69,
-	0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64,
-	0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x19, 0x0a, 0x17, 0x57, 0x72, 0x69, 0x74,
-	0x65, 0x54, 0x47, 0x5a, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x32, 0xad, 0x0a, 0x0a, 0x0d, 0x47, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a, 0x0c, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74,
-	0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x41,
-	0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68,
-	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0c, 0x41, 0x64, 0x64, 0x42, 0x6f, 0x6f, 0x74, 0x73, 0x74, 0x72,
-	0x61, 0x70, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x41, 0x64, 0x64, 0x42,
-	0x6f, 0x6f, 0x74, 0x73, 0x74, 0x72, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x41, 0x64, 0x64, 0x42, 0x6f, 0x6f, 0x74,
-	0x73, 0x74, 0x72, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
-	0x53, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
-	0x65, 0x12, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x30, 0x01, 0x12, 0x54, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x49,
-	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
-	0x2e, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
-	0x2e, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x53, 0x0a, 0x0e, 0x45, 0x78,
-	0x65, 0x63, 0x75, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1d, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x43, 0x6f, 0x6d,
-	0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d,
-	0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12,
-	0x4e, 0x0a, 0x0d, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x41, 0x6c, 0x69, 0x76, 0x65,
-	0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e,
-	0x63, 0x65, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
-	0x41, 0x6c, 0x69, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
-	0x4e, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
-	0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69,
-	0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x65,
-	0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
-	0x59, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
-	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
-	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4e, 0x0a, 0x0d, 0x4c, 0x69,
-	0x73, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
-	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x63, 0x0a, 0x14, 0x4c, 0x69,
+	0x65, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x79, 0x22, 0x7f, 0x0a, 0x16, 0x57, 0x61, 0x74, 0x63, 0x68, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x6f,
+	0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x6f, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12,
+	0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d,
+	0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x6f, 0x64, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6d, 0x6f, 0x64, 0x54, 0x69,
+	0x6d, 0x65, 0x22, 0x78, 0x0a, 0x17, 0x57, 0x72, 0x69, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x46,
+	0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a,
+	0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x1a, 0x0a, 0x08,
+	0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x07, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x22, 0x1a, 0x0a, 0x18,
+	0x57, 0x72, 0x69, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x65, 0x0a, 0x16, 0x57, 0x72, 0x69, 0x74,
+	0x65, 0x54, 0x47, 0x5a, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x49, 0x64, 0x12,
+	0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72,
+	0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x22,
+	0x19, 0x0a, 0x17, 0x57, 0x72, 0x69, 0x74, 0x65, 0x54, 0x47, 0x5a, 0x46, 0x72, 0x6f, 0x6d, 0x55,
+	0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xed, 0x0f, 0x0a, 0x0d, 0x47,
+	0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a, 0x0c,
+	0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0c, 0x41, 0x64, 0x64,
+	0x42, 0x6f, 0x6f, 0x74, 0x73, 0x74, 0x72, 0x61, 0x70, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x73, 0x2e, 0x41, 0x64, 0x64, 0x42, 0x6f, 0x6f, 0x74, 0x73, 0x74, 0x72, 0x61, 0x70, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e,
+	0x41, 0x64, 0x64, 0x42, 0x6f, 0x6f, 0x74, 0x73, 0x74, 0x72, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x53, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
+	0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x51, 0x0a, 0x0e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1d, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x51,
+	0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x12, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x54, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x49, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x12, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x44, 0x65,
+	0x73, 0x74, 0x72, 0x6f, 0x79, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x44, 0x65,
+	0x73, 0x74, 0x72, 0x6f, 0x79, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x53, 0x0a, 0x0e, 0x45, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x73, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x73, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4e, 0x0a, 0x0d,
+	0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x12, 0x1c, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x41,
+	0x6c, 0x69, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x41, 0x6c, 0x69,
+	0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4e, 0x0a, 0x0d,
+	0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1c, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x59, 0x0a, 0x16,
+	0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4e, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4e, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x53,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x63, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x53,
+	0x77, 0x61, 0x72, 0x6d, 0x69, 0x6e, 0x67, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x12,
+	0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x77, 0x61,
+	0x72, 0x6d, 0x69, 0x6e, 0x67, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x4c, 0x69,
 	0x73, 0x74, 0x53, 0x77, 0x61, 0x72, 0x6d, 0x69, 0x6e, 0x67, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x65,
-	0x72, 0x73, 0x12, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74,
-	0x53, 0x77, 0x61, 0x72, 0x6d, 0x69, 0x6e, 0x67, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
-	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x77, 0x61, 0x72, 0x6d, 0x69, 0x6e, 0x67, 0x42, 0x75, 0x69,
-	0x6c, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
-	0x4b, 0x0a, 0x0c, 0x52, 0x65, 0x61, 0x64, 0x54, 0x47, 0x5a, 0x54, 0x6f, 0x55, 0x52, 0x4c, 0x12,
-	0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x54, 0x47, 0x5a,
-	0x54, 0x6f, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x54, 0x47, 0x5a, 0x54, 0x6f, 0x55,
-	0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x0b,
-	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1a, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
-	0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x45, 0x0a, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x53, 0x53,
-	0x48, 0x4b, 0x65, 0x79, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x53, 0x69,
-	0x67, 0x6e, 0x53, 0x53, 0x48, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x53, 0x53, 0x48,
-	0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x45, 0x0a,
-	0x0a, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x19, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52,
+	0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x09,
+	0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x4b, 0x0a, 0x0c, 0x52, 0x65, 0x61, 0x64, 0x54, 0x47, 0x5a, 0x54, 0x6f, 0x55, 0x52, 0x4c,
+	0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x54, 0x47,
+	0x5a, 0x54, 0x6f, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x54, 0x47, 0x5a, 0x54, 0x6f,
+	0x55, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x48, 0x0a,
+	0x0b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1a, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x46, 0x69, 0x6c, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x54, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1e, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x45, 0x0a,
+	0x0a, 0x53, 0x69, 0x67, 0x6e, 0x53, 0x53, 0x48, 0x4b, 0x65, 0x79, 0x12, 0x19, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x53, 0x53, 0x48, 0x4b, 0x65, 0x79, 0x52,
 	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e,
-	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x10, 0x57, 0x72, 0x69, 0x74, 0x65, 0x46, 0x69, 0x6c,
-	0x65, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c, 0x12, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x73, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x55,
-	0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x73, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x46, 0x72, 0x6f, 0x6d,
-	0x55, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x54, 0x0a,
-	0x0f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x54, 0x47, 0x5a, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c,
-	0x12, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x54,
-	0x47, 0x5a, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x54,
-	0x47, 0x5a, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x6f, 0x6c, 0x61, 0x6e, 0x67, 0x2e, 0x6f, 0x72,
-	0x67, 0x2f, 0x78, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
-	0x61, 0x6c, 0x2f, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x53, 0x69, 0x67, 0x6e, 0x53, 0x53, 0x48, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x09, 0x53, 0x79, 0x6e, 0x63,
+	0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x53,
+	0x79, 0x6e, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x46, 0x69, 0x6c,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x41, 0x0a, 0x08,
+	0x54, 0x61, 0x69, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x73, 0x2e, 0x54, 0x61, 0x69, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x54, 0x61, 0x69, 0x6c, 0x46,
+	0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12,
+	0x45, 0x0a, 0x0a, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x19, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x53, 0x0a, 0x0e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x44,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x73, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
+	0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x57, 0x0a, 0x10, 0x57,
+	0x72, 0x69, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c, 0x12,
+	0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x46, 0x69,
+	0x6c, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x46,
+	0x69, 0x6c, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x54, 0x0a, 0x0f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x54, 0x47, 0x5a,
+	0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c, 0x12, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
+	0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x54, 0x47, 0x5a, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
+	0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x54, 0x47, 0x5a, 0x46, 0x72, 0x6f, 0x6d, 0x55, 0x52, 0x4c,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x6f,
+	0x6c, 0x61, 0x6e, 0x67, 0x2e, 0x6f, 0x72, 0x67, 0x2f, 0x78, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x67, 0x6f, 0x6d, 0x6f, 0x74, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2027,7 +4087,7 @@ func file_internal_gomote_protos_gomote_proto_rawDescGZIP() []byte {
 }
 
 var file_internal_gomote_protos_gomote_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_internal_gomote_protos_gomote_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_internal_gomote_protos_gomote_proto_msgTypes = make([]protoimpl.MessageInfo, 58)
 var file_internal_gomote_protos_gomote_proto_goTypes = []interface{}{
 	(CreateInstanceResponse_Status)(0),   // 0: protos.CreateInstanceResponse.Status
 	(*AuthenticateRequest)(nil),          // 1: protos.AuthenticateRequest
@@ -2036,75 +4096,130 @@ var file_internal_gomote_protos_gomote_proto_goTypes = []interface{}{
 	(*AddBootstrapResponse)(nil),         // 4: protos.AddBootstrapResponse
 	(*CreateInstanceRequest)(nil),        // 5: protos.CreateInstanceRequest
 	(*CreateInstanceResponse)(nil),       // 6: protos.CreateInstanceResponse
-	(*DestroyInstanceRequest)(nil),       // 7: protos.DestroyInstanceRequest
-	(*DestroyInstanceResponse)(nil),      // 8: protos.DestroyInstanceResponse
-	(*ExecuteCommandRequest)(nil),        // 9: protos.ExecuteCommandRequest
-	(*ExecuteCommandResponse)(nil),       // 10: protos.ExecuteCommandResponse
-	(*Instance)(nil),                     // 11: protos.Instance
-	(*InstanceAliveRequest)(nil),         // 12: protos.InstanceAliveRequest
-	(*InstanceAliveResponse)(nil),        // 13: protos.InstanceAliveResponse
-	(*ListDirectoryRequest)(nil),         // 14: protos.ListDirectoryRequest
-	(*ListDirectoryResponse)(nil),        // 15: protos.ListDirectoryResponse
-	(*ListInstancesRequest)(nil),         // 16: protos.ListInstancesRequest
-	(*ListInstancesResponse)(nil),        // 17: protos.ListInstancesResponse
-	(*ListSwarmingBuildersRequest)(nil),  // 18: protos.ListSwarmingBuildersRequest
-	(*ListSwarmingBuildersResponse)(nil), // 19: protos.ListSwarmingBuildersResponse
-	(*ReadTGZToURLRequest)(nil),          // 20: protos.ReadTGZToURLRequest
-	(*ReadTGZToURLResponse)(nil),         // 21: protos.ReadTGZToURLResponse
-	(*RemoveFilesRequest)(nil),           // 22: protos.RemoveFilesRequest
-	(*RemoveFilesResponse)(nil),          // 23: protos.RemoveFilesResponse
-	(*SignSSHKeyRequest)(nil),            // 24: protos.SignSSHKeyRequest
-	(*SignSSHKeyResponse)(nil),           // 25: protos.SignSSHKeyResponse
-	(*UploadFileRequest)(nil),            // 26: protos.UploadFileRequest
-	(*UploadFileResponse)(nil),           // 27: protos.UploadFileResponse
-	(*WriteFileFromURLRequest)(nil),      // 28: protos.WriteFileFromURLRequest
-	(*WriteFileFromURLResponse)(nil),     // 29: protos.WriteFileFromURLResponse
-	(*WriteTGZFromURLRequest)(nil),       // 30: protos.WriteTGZFromURLRequest
-	(*WriteTGZFromURLResponse)(nil),      // 31: protos.WriteTGZFromURLResponse
-	nil,                                  // 32: protos.UploadFileResponse.FieldsEntry
+	(*CreateSnapshotRequest)(nil),        // 7: protos.CreateSnapshotRequest
+	(*CreateSnapshotResponse)(nil),       // 8: protos.CreateSnapshotResponse
+	(*DeleteSnapshotRequest)(nil),        // 9: protos.DeleteSnapshotRequest
+	(*DeleteSnapshotResponse)(nil),       // 10: protos.DeleteSnapshotResponse
+	(*DestroyInstanceRequest)(nil),       // 11: protos.DestroyInstanceRequest
+	(*DestroyInstanceResponse)(nil),      // 12: protos.DestroyInstanceResponse
+	(*ExecuteCommandRequest)(nil),        // 13: protos.ExecuteCommandRequest
+	(*ExecuteCommandResponse)(nil),       // 14: protos.ExecuteCommandResponse
+	(*ExecStats)(nil),                    // 15: protos.ExecStats
+	(*ExitStatus)(nil),                   // 16: protos.ExitStatus
+	(*Instance)(nil),                     // 17: protos.Instance
+	(*InstanceAliveRequest)(nil),         // 18: protos.InstanceAliveRequest
+	(*InstanceAliveResponse)(nil),        // 19: protos.InstanceAliveResponse
+	(*ListDirectoryRequest)(nil),         // 20: protos.ListDirectoryRequest
+	(*ListDirectoryResponse)(nil),        // 21: protos.ListDirectoryResponse
+	(*ListInstancesRequest)(nil),         // 22: protos.ListInstancesRequest
+	(*ListInstancesResponse)(nil),        // 23: protos.ListInstancesResponse
+	(*ListSnapshotsRequest)(nil),         // 24: protos.ListSnapshotsRequest
+	(*ListSnapshotsResponse)(nil),        // 25: protos.ListSnapshotsResponse
+	(*ListSwarmingBuildersRequest)(nil),  // 26: protos.ListSwarmingBuildersRequest
+	(*ListSwarmingBuildersResponse)(nil), // 27: protos.ListSwarmingBuildersResponse
+	(*ListUsageRequest)(nil),             // 28: protos.ListUsageRequest
+	(*ListUsageResponse)(nil),            // 29: protos.ListUsageResponse
+	(*ReadTGZToURLRequest)(nil),          // 30: protos.ReadTGZToURLRequest
+	(*ReadTGZToURLResponse)(nil),         // 31: protos.ReadTGZToURLResponse
+	(*RemoveFilesRequest)(nil),           // 32: protos.RemoveFilesRequest
+	(*RemoveFilesResponse)(nil),          // 33: protos.RemoveFilesResponse
+	(*RestoreSnapshotRequest)(nil),       // 34: protos.RestoreSnapshotRequest
+	(*RestoreSnapshotResponse)(nil),      // 35: protos.RestoreSnapshotResponse
+	(*Sandbox)(nil),                      // 36: protos.Sandbox
+	(*Snapshot)(nil),                     // 37: protos.Snapshot
+	(*SignSSHKeyRequest)(nil),            // 38: protos.SignSSHKeyRequest
+	(*SignSSHKeyResponse)(nil),           // 39: protos.SignSSHKeyResponse
+	(*StreamCommandRequest)(nil),         // 40: protos.StreamCommandRequest
+	(*StreamCommandStart)(nil),           // 41: protos.StreamCommandStart
+	(*StreamCommandResponse)(nil),        // 42: protos.StreamCommandResponse
+	(*SyncFile)(nil),                     // 43: protos.SyncFile
+	(*SyncFilesRequest)(nil),             // 44: protos.SyncFilesRequest
+	(*SyncFilesResponse)(nil),            // 45: protos.SyncFilesResponse
+	(*TailFileRequest)(nil),              // 46: protos.TailFileRequest
+	(*TailFileResponse)(nil),             // 47: protos.TailFileResponse
+	(*TerminalSize)(nil),                 // 48: protos.TerminalSize
+	(*UploadFileRequest)(nil),            // 49: protos.UploadFileRequest
+	(*UploadFileResponse)(nil),           // 50: protos.UploadFileResponse
+	(*Usage)(nil),                        // 51: protos.Usage
+	(*WatchDirectoryRequest)(nil),        // 52: protos.WatchDirectoryRequest
+	(*WatchDirectoryResponse)(nil),       // 53: protos.WatchDirectoryResponse
+	(*WriteFileFromURLRequest)(nil),      // 54: protos.WriteFileFromURLRequest
+	(*WriteFileFromURLResponse)(nil),     // 55: protos.WriteFileFromURLResponse
+	(*WriteTGZFromURLRequest)(nil),       // 56: protos.WriteTGZFromURLRequest
+	(*WriteTGZFromURLResponse)(nil),      // 57: protos.WriteTGZFromURLResponse
+	nil,                                  // 58: protos.UploadFileResponse.FieldsEntry
 }
 var file_internal_gomote_protos_gomote_proto_depIdxs = []int32{
-	11, // 0: protos.CreateInstanceResponse.instance:type_name -> protos.Instance
+	17, // 0: protos.CreateInstanceResponse.instance:type_name -> protos.Instance
 	0,  // 1: protos.CreateInstanceResponse.status:type_name -> protos.CreateInstanceResponse.Status
-	11, // 2: protos.ListInstancesResponse.instances:type_name -> protos.Instance
-	32, // 3: protos.UploadFileResponse.fields:type_name -> protos.UploadFileResponse.FieldsEntry
-	1,  // 4: protos.GomoteService.Authenticate:input_type -> protos.AuthenticateRequest
-	3,  // 5: protos.GomoteService.AddBootstrap:input_type -> protos.AddBootstrapRequest
-	5,  // 6: protos.GomoteService.CreateInstance:input_type -> protos.CreateInstanceRequest
-	7,  // 7: protos.GomoteService.DestroyInstance:input_type -> protos.DestroyInstanceRequest
-	9,  // 8: protos.GomoteService.ExecuteCommand:input_type -> protos.ExecuteCommandRequest
-	12, // 9: protos.GomoteService.InstanceAlive:input_type -> protos.InstanceAliveRequest
-	14, // 10: protos.GomoteService.ListDirectory:input_type -> protos.ListDirectoryRequest
-	14, // 11: protos.GomoteService.ListDirectoryStreaming:input_type -> protos.ListDirectoryRequest
-	16, // 12: protos.GomoteService.ListInstances:input_type -> protos.ListInstancesRequest
-	18, // 13: protos.GomoteService.ListSwarmingBuilders:input_type -> protos.ListSwarmingBuildersRequest
-	20, // 14: protos.GomoteService.ReadTGZToURL:input_type -> protos.ReadTGZToURLRequest
-	22, // 15: protos.GomoteService.RemoveFiles:input_type -> protos.RemoveFilesRequest
-	24, // 16: protos.GomoteService.SignSSHKey:input_type -> protos.SignSSHKeyRequest
-	26, // 17: protos.GomoteService.UploadFile:input_type -> protos.UploadFileRequest
-	28, // 18: protos.GomoteService.WriteFileFromURL:input_type -> protos.WriteFileFromURLRequest
-	30, // 19: protos.GomoteService.WriteTGZFromURL:input_type -> protos.WriteTGZFromURLRequest
-	2,  // 20: protos.GomoteService.Authenticate:output_type -> protos.AuthenticateResponse
-	4,  // 21: protos.GomoteService.AddBootstrap:output_type -> protos.AddBootstrapResponse
-	6,  // 22: protos.GomoteService.CreateInstance:output_type -> protos.CreateInstanceResponse
-	8,  // 23: protos.GomoteService.DestroyInstance:output_type -> protos.DestroyInstanceResponse
-	10, // 24: protos.GomoteService.ExecuteCommand:output_type -> protos.ExecuteCommandResponse
-	13, // 25: protos.GomoteService.InstanceAlive:output_type -> protos.InstanceAliveResponse
-	15, // 26: protos.GomoteService.ListDirectory:output_type -> protos.ListDirectoryResponse
-	15, // 27: protos.GomoteService.ListDirectoryStreaming:output_type -> protos.ListDirectoryResponse
-	17, // 28: protos.GomoteService.ListInstances:output_type -> protos.ListInstancesResponse
-	19, // 29: protos.GomoteService.ListSwarmingBuilders:output_type -> protos.ListSwarmingBuildersResponse
-	21, // 30: protos.GomoteService.ReadTGZToURL:output_type -> protos.ReadTGZToURLResponse
-	23, // 31: protos.GomoteService.RemoveFiles:output_type -> protos.RemoveFilesResponse
-	25, // 32: protos.GomoteService.SignSSHKey:output_type -> protos.SignSSHKeyResponse
-	27, // 33: protos.GomoteService.UploadFile:output_type -> protos.UploadFileResponse
-	29, // 34: protos.GomoteService.WriteFileFromURL:output_type -> protos.WriteFileFromURLResponse
-	31, // 35: protos.GomoteService.WriteTGZFromURL:output_type -> protos.WriteTGZFromURLResponse
-	20, // [20:36] is the sub-list for method output_type
-	4,  // [4:20] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	37, // 2: protos.CreateSnapshotResponse.snapshot:type_name -> protos.Snapshot
+	36, // 3: protos.ExecuteCommandRequest.sandbox:type_name -> protos.Sandbox
+	15, // 4: protos.ExecuteCommandResponse.stats:type_name -> protos.ExecStats
+	17, // 5: protos.ListInstancesResponse.instances:type_name -> protos.Instance
+	37, // 6: protos.ListSnapshotsResponse.snapshots:type_name -> protos.Snapshot
+	51, // 7: protos.ListUsageResponse.usage:type_name -> protos.Usage
+	41, // 8: protos.StreamCommandRequest.start:type_name -> protos.StreamCommandStart
+	48, // 9: protos.StreamCommandRequest.resize:type_name -> protos.TerminalSize
+	13, // 10: protos.StreamCommandStart.command:type_name -> protos.ExecuteCommandRequest
+	48, // 11: protos.StreamCommandStart.size:type_name -> protos.TerminalSize
+	16, // 12: protos.StreamCommandResponse.exit_status:type_name -> protos.ExitStatus
+	43, // 13: protos.SyncFilesRequest.files:type_name -> protos.SyncFile
+	58, // 14: protos.UploadFileResponse.fields:type_name -> protos.UploadFileResponse.FieldsEntry
+	1,  // 15: protos.GomoteService.Authenticate:input_type -> protos.AuthenticateRequest
+	3,  // 16: protos.GomoteService.AddBootstrap:input_type -> protos.AddBootstrapRequest
+	5,  // 17: protos.GomoteService.CreateInstance:input_type -> protos.CreateInstanceRequest
+	7,  // 18: protos.GomoteService.CreateSnapshot:input_type -> protos.CreateSnapshotRequest
+	9,  // 19: protos.GomoteService.DeleteSnapshot:input_type -> protos.DeleteSnapshotRequest
+	11, // 20: protos.GomoteService.DestroyInstance:input_type -> protos.DestroyInstanceRequest
+	13, // 21: protos.GomoteService.ExecuteCommand:input_type -> protos.ExecuteCommandRequest
+	18, // 22: protos.GomoteService.InstanceAlive:input_type -> protos.InstanceAliveRequest
+	20, // 23: protos.GomoteService.ListDirectory:input_type -> protos.ListDirectoryRequest
+	20, // 24: protos.GomoteService.ListDirectoryStreaming:input_type -> protos.ListDirectoryRequest
+	22, // 25: protos.GomoteService.ListInstances:input_type -> protos.ListInstancesRequest
+	24, // 26: protos.GomoteService.ListSnapshots:input_type -> protos.ListSnapshotsRequest
+	26, // 27: protos.GomoteService.ListSwarmingBuilders:input_type -> protos.ListSwarmingBuildersRequest
+	28, // 28: protos.GomoteService.ListUsage:input_type -> protos.ListUsageRequest
+	30, // 29: protos.GomoteService.ReadTGZToURL:input_type -> protos.ReadTGZToURLRequest
+	32, // 30: protos.GomoteService.RemoveFiles:input_type -> protos.RemoveFilesRequest
+	34, // 31: protos.GomoteService.RestoreSnapshot:input_type -> protos.RestoreSnapshotRequest
+	38, // 32: protos.GomoteService.SignSSHKey:input_type -> protos.SignSSHKeyRequest
+	40, // 33: protos.GomoteService.StreamCommand:input_type -> protos.StreamCommandRequest
+	44, // 34: protos.GomoteService.SyncFiles:input_type -> protos.SyncFilesRequest
+	46, // 35: protos.GomoteService.TailFile:input_type -> protos.TailFileRequest
+	49, // 36: protos.GomoteService.UploadFile:input_type -> protos.UploadFileRequest
+	52, // 37: protos.GomoteService.WatchDirectory:input_type -> protos.WatchDirectoryRequest
+	54, // 38: protos.GomoteService.WriteFileFromURL:input_type -> protos.WriteFileFromURLRequest
+	56, // 39: protos.GomoteService.WriteTGZFromURL:input_type -> protos.WriteTGZFromURLRequest
+	2,  // 40: protos.GomoteService.Authenticate:output_type -> protos.AuthenticateResponse
+	4,  // 41: protos.GomoteService.AddBootstrap:output_type -> protos.AddBootstrapResponse
+	6,  // 42: protos.GomoteService.CreateInstance:output_type -> protos.CreateInstanceResponse
+	8,  // 43: protos.GomoteService.CreateSnapshot:output_type -> protos.CreateSnapshotResponse
+	10, // 44: protos.GomoteService.DeleteSnapshot:output_type -> protos.DeleteSnapshotResponse
+	12, // 45: protos.GomoteService.DestroyInstance:output_type -> protos.DestroyInstanceResponse
+	14, // 46: protos.GomoteService.ExecuteCommand:output_type -> protos.ExecuteCommandResponse
+	19, // 47: protos.GomoteService.InstanceAlive:output_type -> protos.InstanceAliveResponse
+	21, // 48: protos.GomoteService.ListDirectory:output_type -> protos.ListDirectoryResponse
+	21, // 49: protos.GomoteService.ListDirectoryStreaming:output_type -> protos.ListDirectoryResponse
+	23, // 50: protos.GomoteService.ListInstances:output_type -> protos.ListInstancesResponse
+	25, // 51: protos.GomoteService.ListSnapshots:output_type -> protos.ListSnapshotsResponse
+	27, // 52: protos.GomoteService.ListSwarmingBuilders:output_type -> protos.ListSwarmingBuildersResponse
+	29, // 53: protos.GomoteService.ListUsage:output_type -> protos.ListUsageResponse
+	31, // 54: protos.GomoteService.ReadTGZToURL:output_type -> protos.ReadTGZToURLResponse
+	33, // 55: protos.GomoteService.RemoveFiles:output_type -> protos.RemoveFilesResponse
+	35, // 56: protos.GomoteService.RestoreSnapshot:output_type -> protos.RestoreSnapshotResponse
+	39, // 57: protos.GomoteService.SignSSHKey:output_type -> protos.SignSSHKeyResponse
+	42, // 58: protos.GomoteService.StreamCommand:output_type -> protos.StreamCommandResponse
+	45, // 59: protos.GomoteService.SyncFiles:output_type -> protos.SyncFilesResponse
+	47, // 60: protos.GomoteService.TailFile:output_type -> protos.TailFileResponse
+	50, // 61: protos.GomoteService.UploadFile:output_type -> protos.UploadFileResponse
+	53, // 62: protos.GomoteService.WatchDirectory:output_type -> protos.WatchDirectoryResponse
+	55, // 63: protos.GomoteService.WriteFileFromURL:output_type -> protos.WriteFileFromURLResponse
+	57, // 64: protos.GomoteService.WriteTGZFromURL:output_type -> protos.WriteTGZFromURLResponse
+	40, // [40:65] is the sub-list for method output_type
+	15, // [15:40] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
 }
 
 func init() { file_internal_gomote_protos_gomote_proto_init() }
@@ -2125,8 +4240,116 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthenticateResponse); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthenticateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddBootstrapRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddBootstrapResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateInstanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateInstanceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateSnapshotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateSnapshotResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteSnapshotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteSnapshotResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DestroyInstanceRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2137,8 +4360,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddBootstrapRequest); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DestroyInstanceResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2149,8 +4372,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddBootstrapResponse); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteCommandRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2161,8 +4384,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateInstanceRequest); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteCommandResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2173,8 +4396,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateInstanceResponse); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecStats); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2185,8 +4408,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DestroyInstanceRequest); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExitStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2197,8 +4420,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DestroyInstanceResponse); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Instance); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2209,8 +4432,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExecuteCommandRequest); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InstanceAliveRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2221,8 +4444,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExecuteCommandResponse); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InstanceAliveResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2233,8 +4456,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Instance); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDirectoryRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2245,8 +4468,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InstanceAliveRequest); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDirectoryResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2257,8 +4480,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InstanceAliveResponse); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListInstancesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2269,8 +4492,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListDirectoryRequest); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListInstancesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2281,8 +4504,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListDirectoryResponse); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListSnapshotsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2293,8 +4516,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListInstancesRequest); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListSnapshotsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2305,8 +4528,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListInstancesResponse); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListSwarmingBuildersRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2317,8 +4540,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListSwarmingBuildersRequest); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListSwarmingBuildersResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2329,8 +4552,8 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListSwarmingBuildersResponse); i {
+		file_internal_gomote_protos_gomote_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListUsageRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2341,7 +4564,19 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+		file_internal_gomote_protos_gomote_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListUsageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReadTGZToURLRequest); i {
 			case 0:
 				return &v.state
@@ -2353,7 +4588,7 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+		file_internal_gomote_protos_gomote_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReadTGZToURLResponse); i {
 			case 0:
 				return &v.state
@@ -2365,7 +4600,7 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+		file_internal_gomote_protos_gomote_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RemoveFilesRequest); i {
 			case 0:
 				return &v.state
@@ -2377,7 +4612,7 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+		file_internal_gomote_protos_gomote_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RemoveFilesResponse); i {
 			case 0:
 				return &v.state
@@ -2389,7 +4624,55 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+		file_internal_gomote_protos_gomote_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreSnapshotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreSnapshotResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Sandbox); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Snapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SignSSHKeyRequest); i {
 			case 0:
 				return &v.state
@@ -2401,7 +4684,7 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+		file_internal_gomote_protos_gomote_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SignSSHKeyResponse); i {
 			case 0:
 				return &v.state
@@ -2413,7 +4696,115 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+		file_internal_gomote_protos_gomote_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamCommandRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamCommandStart); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamCommandResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncFile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncFilesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncFilesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TailFileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TailFileResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TerminalSize); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UploadFileRequest); i {
 			case 0:
 				return &v.state
@@ -2425,7 +4816,7 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+		file_internal_gomote_protos_gomote_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UploadFileResponse); i {
 			case 0:
 				return &v.state
@@ -2437,7 +4828,43 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+		file_internal_gomote_protos_gomote_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Usage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchDirectoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchDirectoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_gomote_protos_gomote_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*WriteFileFromURLRequest); i {
 			case 0:
 				return &v.state
@@ -2449,7 +4876,7 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+		file_internal_gomote_protos_gomote_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*WriteFileFromURLResponse); i {
 			case 0:
 				return &v.state
@@ -2461,7 +4888,7 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+		file_internal_gomote_protos_gomote_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*WriteTGZFromURLRequest); i {
 			case 0:
 				return &v.state
@@ -2473,7 +4900,7 @@ func file_internal_gomote_protos_gomote_proto_init() {
 				return nil
 			}
 		}
-		file_internal_gomote_protos_gomote_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+		file_internal_gomote_protos_gomote_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*WriteTGZFromURLResponse); i {
 			case 0:
 				return &v.state
@@ -2486,13 +4913,25 @@ func file_internal_gomote_protos_gomote_proto_init() {
 			}
 		}
 	}
+	file_internal_gomote_protos_gomote_proto_msgTypes[39].OneofWrappers = []interface{}{
+		(*StreamCommandRequest_Start)(nil),
+		(*StreamCommandRequest_Stdin)(nil),
+		(*StreamCommandRequest_CloseStdin)(nil),
+		(*StreamCommandRequest_Signal)(nil),
+		(*StreamCommandRequest_Resize)(nil),
+	}
+	file_internal_gomote_protos_gomote_proto_msgTypes[41].OneofWrappers = []interface{}{
+		(*StreamCommandResponse_Stdout)(nil),
+		(*StreamCommandResponse_Stderr)(nil),
+		(*StreamCommandResponse_ExitStatus)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_internal_gomote_protos_gomote_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   32,
+			NumMessages:   58,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -26,17 +26,26 @@ const (
 	GomoteService_Authenticate_FullMethodName           = "/protos.GomoteService/Authenticate"
 	GomoteService_AddBootstrap_FullMethodName           = "/protos.GomoteService/AddBootstrap"
 	GomoteService_CreateInstance_FullMethodName         = "/protos.GomoteService/CreateInstance"
+	GomoteService_CreateSnapshot_FullMethodName         = "/protos.GomoteService/CreateSnapshot"
+	GomoteService_DeleteSnapshot_FullMethodName         = "/protos.GomoteService/DeleteSnapshot"
 	GomoteService_DestroyInstance_FullMethodName        = "/protos.GomoteService/DestroyInstance"
 	GomoteService_ExecuteCommand_FullMethodName         = "/protos.GomoteService/ExecuteCommand"
 	GomoteService_InstanceAlive_FullMethodName          = "/protos.GomoteService/InstanceAlive"
 	GomoteService_ListDirectory_FullMethodName          = "/protos.GomoteService/ListDirectory"
 	GomoteService_ListDirectoryStreaming_FullMethodName = "/protos.GomoteService/ListDirectoryStreaming"
 	GomoteService_ListInstances_FullMethodName          = "/protos.GomoteService/ListInstances"
+	GomoteService_ListSnapshots_FullMethodName          = "/protos.GomoteService/ListSnapshots"
 	GomoteService_ListSwarmingBuilders_FullMethodName   = "/protos.GomoteService/ListSwarmingBuilders"
+	GomoteService_ListUsage_FullMethodName              = "/protos.GomoteService/ListUsage"
 	GomoteService_ReadTGZToURL_FullMethodName           = "/protos.GomoteService/ReadTGZToURL"
 	GomoteService_RemoveFiles_FullMethodName            = "/protos.GomoteService/RemoveFiles"
+	GomoteService_RestoreSnapshot_FullMethodName        = "/protos.GomoteService/RestoreSnapshot"
 	GomoteService_SignSSHKey_FullMethodName             = "/protos.GomoteService/SignSSHKey"
+	GomoteService_StreamCommand_FullMethodName          = "/protos.GomoteService/StreamCommand"
+	GomoteService_SyncFiles_FullMethodName              = "/protos.GomoteService/SyncFiles"
+	GomoteService_TailFile_FullMethodName               = "/protos.GomoteService/TailFile"
 	GomoteService_UploadFile_FullMethodName             = "/protos.GomoteService/UploadFile"
+	GomoteService_WatchDirectory_FullMethodName         = "/protos.GomoteService/WatchDirectory"
 	GomoteService_WriteFileFromURL_FullMethodName       = "/protos.GomoteService/WriteFileFromURL"
 	GomoteService_WriteTGZFromURL_FullMethodName        = "/protos.GomoteService/WriteTGZFromURL"
 )
@@ -53,6 +62,11 @@ type GomoteServiceClient interface {
 	AddBootstrap(ctx context.Context, in *AddBootstrapRequest, opts ...grpc.CallOption) (*AddBootstrapResponse, error)
 	// CreateInstance creates a gomote instance.
 	CreateInstance(ctx context.Context, in *CreateInstanceRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CreateInstanceResponse], error)
+	// CreateSnapshot saves a directory on a gomote instance as a snapshot owned by the caller,
+	// which can later be restored onto another instance of the same builder type.
+	CreateSnapshot(ctx context.Context, in *CreateSnapshotRequest, opts ...grpc.CallOption) (*CreateSnapshotResponse, error)
+	// DeleteSnapshot deletes a snapshot owned by the caller.
+	DeleteSnapshot(ctx context.Context, in *DeleteSnapshotRequest, opts ...grpc.CallOption) (*DeleteSnapshotResponse, error)
 	// DestroyInstance destroys a gomote instance.
 	DestroyInstance(ctx context.Context, in *DestroyInstanceRequest, opts ...grpc.CallOption) (*DestroyInstanceResponse, error)
 	// ExecuteCommand executes a command on the gomote instance.
@@ -65,18 +79,35 @@ type GomoteServiceClient interface {
 	ListDirectoryStreaming(ctx context.Context, in *ListDirectoryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ListDirectoryResponse], error)
 	// ListInstances lists all of the live gomote instances owned by the caller.
 	ListInstances(ctx context.Context, in *ListInstancesRequest, opts ...grpc.CallOption) (*ListInstancesResponse, error)
+	// ListSnapshots lists all of the unexpired snapshots owned by the caller.
+	ListSnapshots(ctx context.Context, in *ListSnapshotsRequest, opts ...grpc.CallOption) (*ListSnapshotsResponse, error)
 	// ListSwarmingBuilders lists all of the swarming builders for the project.
 	ListSwarmingBuilders(ctx context.Context, in *ListSwarmingBuildersRequest, opts ...grpc.CallOption) (*ListSwarmingBuildersResponse, error)
+	// ListUsage lists the instance time used by the caller, or by all users.
+	ListUsage(ctx context.Context, in *ListUsageRequest, opts ...grpc.CallOption) (*ListUsageResponse, error)
 	// ReadTGZToURL tars and zips a directory which exists on the gomote instance and returns a URL where it can be
 	// downloaded from.
 	ReadTGZToURL(ctx context.Context, in *ReadTGZToURLRequest, opts ...grpc.CallOption) (*ReadTGZToURLResponse, error)
 	// RemoveFiles removes files or directories from the gomote instance.
 	RemoveFiles(ctx context.Context, in *RemoveFilesRequest, opts ...grpc.CallOption) (*RemoveFilesResponse, error)
+	// RestoreSnapshot expands a snapshot owned by the caller onto a gomote instance.
+	RestoreSnapshot(ctx context.Context, in *RestoreSnapshotRequest, opts ...grpc.CallOption) (*RestoreSnapshotResponse, error)
 	// SignSSHKey signs an SSH public key which can be used to SSH into instances owned by the caller.
 	SignSSHKey(ctx context.Context, in *SignSSHKeyRequest, opts ...grpc.CallOption) (*SignSSHKeyResponse, error)
+	// StreamCommand executes a command on the gomote instance, streaming its standard input,
+	// output, and error separately, forwarding signals, and reporting its exit status.
+	StreamCommand(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamCommandRequest, StreamCommandResponse], error)
+	// SyncFiles makes a directory on the gomote instance match a manifest of files and their digests,
+	// transferring only the file contents the instance doesn't already have.
+	SyncFiles(ctx context.Context, in *SyncFilesRequest, opts ...grpc.CallOption) (*SyncFilesResponse, error)
+	// TailFile streams the contents of a file on the gomote instance, optionally following
+	// bytes appended to it, like tail -f.
+	TailFile(ctx context.Context, in *TailFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TailFileResponse], error)
 	// UploadFile generates a signed URL and associated fields to be used when uploading the object to GCS. Once uploaded
 	// the corresponding Write endpoint can be used to send the file to the gomote instance.
 	UploadFile(ctx context.Context, in *UploadFileRequest, opts ...grpc.CallOption) (*UploadFileResponse, error)
+	// WatchDirectory streams changes to the files in a directory on the gomote instance.
+	WatchDirectory(ctx context.Context, in *WatchDirectoryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchDirectoryResponse], error)
 	// WriteFileFromURL
 	WriteFileFromURL(ctx context.Context, in *WriteFileFromURLRequest, opts ...grpc.CallOption) (*WriteFileFromURLResponse, error)
 	// WriteTGZFromURL retrieves a tar and zipped file from a URL and expands it onto the file system of a gomote instance.
@@ -130,6 +161,26 @@ func (c *gomoteServiceClient) CreateInstance(ctx context.Context, in *CreateInst
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type GomoteService_CreateInstanceClient = grpc.ServerStreamingClient[CreateInstanceResponse]
 
+func (c *gomoteServiceClient) CreateSnapshot(ctx context.Context, in *CreateSnapshotRequest, opts ...grpc.CallOption) (*CreateSnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateSnapshotResponse)
+	err := c.cc.Invoke(ctx, GomoteService_CreateSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gomoteServiceClient) DeleteSnapshot(ctx context.Context, in *DeleteSnapshotRequest, opts ...grpc.CallOption) (*DeleteSnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSnapshotResponse)
+	err := c.cc.Invoke(ctx, GomoteService_DeleteSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gomoteServiceClient) DestroyInstance(ctx context.Context, in *DestroyInstanceRequest, opts ...grpc.CallOption) (*DestroyInstanceResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DestroyInstanceResponse)
@@ -208,6 +259,16 @@ func (c *gomoteServiceClient) ListInstances(ctx context.Context, in *ListInstanc
 	return out, nil
 }
 
+func (c *gomoteServiceClient) ListSnapshots(ctx context.Context, in *ListSnapshotsRequest, opts ...grpc.CallOption) (*ListSnapshotsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSnapshotsResponse)
+	err := c.cc.Invoke(ctx, GomoteService_ListSnapshots_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gomoteServiceClient) ListSwarmingBuilders(ctx context.Context, in *ListSwarmingBuildersRequest, opts ...grpc.CallOption) (*ListSwarmingBuildersResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListSwarmingBuildersResponse)
@@ -218,6 +279,16 @@ func (c *gomoteServiceClient) ListSwarmingBuilders(ctx context.Context, in *List
 	return out, nil
 }
 
+func (c *gomoteServiceClient) ListUsage(ctx context.Context, in *ListUsageRequest, opts ...grpc.CallOption) (*ListUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUsageResponse)
+	err := c.cc.Invoke(ctx, GomoteService_ListUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gomoteServiceClient) ReadTGZToURL(ctx context.Context, in *ReadTGZToURLRequest, opts ...grpc.CallOption) (*ReadTGZToURLResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ReadTGZToURLResponse)
@@ -238,6 +309,16 @@ func (c *gomoteServiceClient) RemoveFiles(ctx context.Context, in *RemoveFilesRe
 	return out, nil
 }
 
+func (c *gomoteServiceClient) RestoreSnapshot(ctx context.Context, in *RestoreSnapshotRequest, opts ...grpc.CallOption) (*RestoreSnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreSnapshotResponse)
+	err := c.cc.Invoke(ctx, GomoteService_RestoreSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gomoteServiceClient) SignSSHKey(ctx context.Context, in *SignSSHKeyRequest, opts ...grpc.CallOption) (*SignSSHKeyResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SignSSHKeyResponse)
@@ -248,6 +329,48 @@ func (c *gomoteServiceClient) SignSSHKey(ctx context.Context, in *SignSSHKeyRequ
 	return out, nil
 }
 
+func (c *gomoteServiceClient) StreamCommand(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamCommandRequest, StreamCommandResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GomoteService_ServiceDesc.Streams[3], GomoteService_StreamCommand_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamCommandRequest, StreamCommandResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GomoteService_StreamCommandClient = grpc.BidiStreamingClient[StreamCommandRequest, StreamCommandResponse]
+
+func (c *gomoteServiceClient) SyncFiles(ctx context.Context, in *SyncFilesRequest, opts ...grpc.CallOption) (*SyncFilesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncFilesResponse)
+	err := c.cc.Invoke(ctx, GomoteService_SyncFiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gomoteServiceClient) TailFile(ctx context.Context, in *TailFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TailFileResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GomoteService_ServiceDesc.Streams[4], GomoteService_TailFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TailFileRequest, TailFileResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GomoteService_TailFileClient = grpc.ServerStreamingClient[TailFileResponse]
+
 func (c *gomoteServiceClient) UploadFile(ctx context.Context, in *UploadFileRequest, opts ...grpc.CallOption) (*UploadFileResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UploadFileResponse)
@@ -258,6 +381,25 @@ func (c *gomoteServiceClient) UploadFile(ctx context.Context, in *UploadFileRequ
 	return out, nil
 }
 
+func (c *gomoteServiceClient) WatchDirectory(ctx context.Context, in *WatchDirectoryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchDirectoryResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GomoteService_ServiceDesc.Streams[5], GomoteService_WatchDirectory_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchDirectoryRequest, WatchDirectoryResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GomoteService_WatchDirectoryClient = grpc.ServerStreamingClient[WatchDirectoryResponse]
+
 func (c *gomoteServiceClient) WriteFileFromURL(ctx context.Context, in *WriteFileFromURLRequest, opts ...grpc.CallOption) (*WriteFileFromURLResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(WriteFileFromURLResponse)
@@ -290,6 +432,11 @@ type GomoteServiceServer interface {
 	AddBootstrap(context.Context, *AddBootstrapRequest) (*AddBootstrapResponse, error)
 	// CreateInstance creates a gomote instance.
 	CreateInstance(*CreateInstanceRequest, grpc.ServerStreamingServer[CreateInstanceResponse]) error
+	// CreateSnapshot saves a directory on a gomote instance as a snapshot owned by the caller,
+	// which can later be restored onto another instance of the same builder type.
+	CreateSnapshot(context.Context, *CreateSnapshotRequest) (*CreateSnapshotResponse, error)
+	// DeleteSnapshot deletes a snapshot owned by the caller.
+	DeleteSnapshot(context.Context, *DeleteSnapshotRequest) (*DeleteSnapshotResponse, error)
 	// DestroyInstance destroys a gomote instance.
 	DestroyInstance(context.Context, *DestroyInstanceRequest) (*DestroyInstanceResponse, error)
 	// ExecuteCommand executes a command on the gomote instance.
@@ -302,18 +449,35 @@ type GomoteServiceServer interface {
 	ListDirectoryStreaming(*ListDirectoryRequest, grpc.ServerStreamingServer[ListDirectoryResponse]) error
 	// ListInstances lists all of the live gomote instances owned by the caller.
 	ListInstances(context.Context, *ListInstancesRequest) (*ListInstancesResponse, error)
+	// ListSnapshots lists all of the unexpired snapshots owned by the caller.
+	ListSnapshots(context.Context, *ListSnapshotsRequest) (*ListSnapshotsResponse, error)
 	// ListSwarmingBuilders lists all of the swarming builders for the project.
 	ListSwarmingBuilders(context.Context, *ListSwarmingBuildersRequest) (*ListSwarmingBuildersResponse, error)
+	// ListUsage lists the instance time used by the caller, or by all users.
+	ListUsage(context.Context, *ListUsageRequest) (*ListUsageResponse, error)
 	// ReadTGZToURL tars and zips a directory which exists on the gomote instance and returns a URL where it can be
 	// downloaded from.
 	ReadTGZToURL(context.Context, *ReadTGZToURLRequest) (*ReadTGZToURLResponse, error)
 	// RemoveFiles removes files or directories from the gomote instance.
 	RemoveFiles(context.Context, *RemoveFilesRequest) (*RemoveFilesResponse, error)
+	// RestoreSnapshot expands a snapshot owned by the caller onto a gomote instance.
+	RestoreSnapshot(context.Context, *RestoreSnapshotRequest) (*RestoreSnapshotResponse, error)
 	// SignSSHKey signs an SSH public key which can be used to SSH into instances owned by the caller.
 	SignSSHKey(context.Context, *SignSSHKeyRequest) (*SignSSHKeyResponse, error)
+	// StreamCommand executes a command on the gomote instance, streaming its standard input,
+	// output, and error separately, forwarding signals, and reporting its exit status.
+	StreamCommand(grpc.BidiStreamingServer[StreamCommandRequest, StreamCommandResponse]) error
+	// SyncFiles makes a directory on the gomote instance match a manifest of files and their digests,
+	// transferring only the file contents the instance doesn't already have.
+	SyncFiles(context.Context, *SyncFilesRequest) (*SyncFilesResponse, error)
+	// TailFile streams the contents of a file on the gomote instance, optionally following
+	// bytes appended to it, like tail -f.
+	TailFile(*TailFileRequest, grpc.ServerStreamingServer[TailFileResponse]) error
 	// UploadFile generates a signed URL and associated fields to be used when uploading the object to GCS. Once uploaded
 	// the corresponding Write endpoint can be used to send the file to the gomote instance.
 	UploadFile(context.Context, *UploadFileRequest) (*UploadFileResponse, error)
+	// WatchDirectory streams changes to the files in a directory on the gomote instance.
+	WatchDirectory(*WatchDirectoryRequest, grpc.ServerStreamingServer[WatchDirectoryResponse]) error
 	// WriteFileFromURL
 	WriteFileFromURL(context.Context, *WriteFileFromURLRequest) (*WriteFileFromURLResponse, error)
 	// WriteTGZFromURL retrieves a tar and zipped file from a URL and expands it onto the file system of a gomote instance.
@@ -337,6 +501,12 @@ func (UnimplementedGomoteServiceServer) AddBootstrap(context.Context, *AddBootst
 func (UnimplementedGomoteServiceServer) CreateInstance(*CreateInstanceRequest, grpc.ServerStreamingServer[CreateInstanceResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method CreateInstance not implemented")
 }
+func (UnimplementedGomoteServiceServer) CreateSnapshot(context.Context, *CreateSnapshotRequest) (*CreateSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSnapshot not implemented")
+}
+func (UnimplementedGomoteServiceServer) DeleteSnapshot(context.Context, *DeleteSnapshotRequest) (*DeleteSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSnapshot not implemented")
+}
 func (UnimplementedGomoteServiceServer) DestroyInstance(context.Context, *DestroyInstanceRequest) (*DestroyInstanceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DestroyInstance not implemented")
 }
@@ -355,21 +525,42 @@ func (UnimplementedGomoteServiceServer) ListDirectoryStreaming(*ListDirectoryReq
 func (UnimplementedGomoteServiceServer) ListInstances(context.Context, *ListInstancesRequest) (*ListInstancesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListInstances not implemented")
 }
+func (UnimplementedGomoteServiceServer) ListSnapshots(context.Context, *ListSnapshotsRequest) (*ListSnapshotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSnapshots not implemented")
+}
 func (UnimplementedGomoteServiceServer) ListSwarmingBuilders(context.Context, *ListSwarmingBuildersRequest) (*ListSwarmingBuildersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListSwarmingBuilders not implemented")
 }
+func (UnimplementedGomoteServiceServer) ListUsage(context.Context, *ListUsageRequest) (*ListUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsage not implemented")
+}
 func (UnimplementedGomoteServiceServer) ReadTGZToURL(context.Context, *ReadTGZToURLRequest) (*ReadTGZToURLResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReadTGZToURL not implemented")
 }
 func (UnimplementedGomoteServiceServer) RemoveFiles(context.Context, *RemoveFilesRequest) (*RemoveFilesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveFiles not implemented")
 }
+func (UnimplementedGomoteServiceServer) RestoreSnapshot(context.Context, *RestoreSnapshotRequest) (*RestoreSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreSnapshot not implemented")
+}
 func (UnimplementedGomoteServiceServer) SignSSHKey(context.Context, *SignSSHKeyRequest) (*SignSSHKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SignSSHKey not implemented")
 }
+func (UnimplementedGomoteServiceServer) StreamCommand(grpc.BidiStreamingServer[StreamCommandRequest, StreamCommandResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamCommand not implemented")
+}
+func (UnimplementedGomoteServiceServer) SyncFiles(context.Context, *SyncFilesRequest) (*SyncFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncFiles not implemented")
+}
+func (UnimplementedGomoteServiceServer) TailFile(*TailFileRequest, grpc.ServerStreamingServer[TailFileResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method TailFile not implemented")
+}
 func (UnimplementedGomoteServiceServer) UploadFile(context.Context, *UploadFileRequest) (*UploadFileResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
 }
+func (UnimplementedGomoteServiceServer) WatchDirectory(*WatchDirectoryRequest, grpc.ServerStreamingServer[WatchDirectoryResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDirectory not implemented")
+}
 func (UnimplementedGomoteServiceServer) WriteFileFromURL(context.Context, *WriteFileFromURLRequest) (*WriteFileFromURLResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method WriteFileFromURL not implemented")
 }
@@ -444,6 +635,42 @@ func _GomoteService_CreateInstance_Handler(srv interface{}, stream grpc.ServerSt
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type GomoteService_CreateInstanceServer = grpc.ServerStreamingServer[CreateInstanceResponse]
 
+func _GomoteService_CreateSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GomoteServiceServer).CreateSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GomoteService_CreateSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GomoteServiceServer).CreateSnapshot(ctx, req.(*CreateSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GomoteService_DeleteSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GomoteServiceServer).DeleteSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GomoteService_DeleteSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GomoteServiceServer).DeleteSnapshot(ctx, req.(*DeleteSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GomoteService_DestroyInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DestroyInstanceRequest)
 	if err := dec(in); err != nil {
@@ -538,6 +765,24 @@ func _GomoteService_ListInstances_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GomoteService_ListSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSnapshotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GomoteServiceServer).ListSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GomoteService_ListSnapshots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GomoteServiceServer).ListSnapshots(ctx, req.(*ListSnapshotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GomoteService_ListSwarmingBuilders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListSwarmingBuildersRequest)
 	if err := dec(in); err != nil {
@@ -556,6 +801,24 @@ func _GomoteService_ListSwarmingBuilders_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GomoteService_ListUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GomoteServiceServer).ListUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GomoteService_ListUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GomoteServiceServer).ListUsage(ctx, req.(*ListUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GomoteService_ReadTGZToURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReadTGZToURLRequest)
 	if err := dec(in); err != nil {
@@ -592,6 +855,24 @@ func _GomoteService_RemoveFiles_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GomoteService_RestoreSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GomoteServiceServer).RestoreSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GomoteService_RestoreSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GomoteServiceServer).RestoreSnapshot(ctx, req.(*RestoreSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GomoteService_SignSSHKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SignSSHKeyRequest)
 	if err := dec(in); err != nil {
@@ -610,6 +891,42 @@ func _GomoteService_SignSSHKey_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GomoteService_StreamCommand_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GomoteServiceServer).StreamCommand(&grpc.GenericServerStream[StreamCommandRequest, StreamCommandResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GomoteService_StreamCommandServer = grpc.BidiStreamingServer[StreamCommandRequest, StreamCommandResponse]
+
+func _GomoteService_SyncFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GomoteServiceServer).SyncFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GomoteService_SyncFiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GomoteServiceServer).SyncFiles(ctx, req.(*SyncFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GomoteService_TailFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GomoteServiceServer).TailFile(m, &grpc.GenericServerStream[TailFileRequest, TailFileResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GomoteService_TailFileServer = grpc.ServerStreamingServer[TailFileResponse]
+
 func _GomoteService_UploadFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UploadFileRequest)
 	if err := dec(in); err != nil {
@@ -628,6 +945,17 @@ func _GomoteService_UploadFile_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GomoteService_WatchDirectory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDirectoryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GomoteServiceServer).WatchDirectory(m, &grpc.GenericServerStream[WatchDirectoryRequest, WatchDirectoryResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GomoteService_WatchDirectoryServer = grpc.ServerStreamingServer[WatchDirectoryResponse]
+
 func _GomoteService_WriteFileFromURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(WriteFileFromURLRequest)
 	if err := dec(in); err != nil {
@@ -679,6 +1007,14 @@ var GomoteService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddBootstrap",
 			Handler:    _GomoteService_AddBootstrap_Handler,
 		},
+		{
+			MethodName: "CreateSnapshot",
+			Handler:    _GomoteService_CreateSnapshot_Handler,
+		},
+		{
+			MethodName: "DeleteSnapshot",
+			Handler:    _GomoteService_DeleteSnapshot_Handler,
+		},
 		{
 			MethodName: "DestroyInstance",
 			Handler:    _GomoteService_DestroyInstance_Handler,
@@ -695,10 +1031,18 @@ var GomoteService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListInstances",
 			Handler:    _GomoteService_ListInstances_Handler,
 		},
+		{
+			MethodName: "ListSnapshots",
+			Handler:    _GomoteService_ListSnapshots_Handler,
+		},
 		{
 			MethodName: "ListSwarmingBuilders",
 			Handler:    _GomoteService_ListSwarmingBuilders_Handler,
 		},
+		{
+			MethodName: "ListUsage",
+			Handler:    _GomoteService_ListUsage_Handler,
+		},
 		{
 			MethodName: "ReadTGZToURL",
 			Handler:    _GomoteService_ReadTGZToURL_Handler,
@@ -707,10 +1051,18 @@ var GomoteService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveFiles",
 			Handler:    _GomoteService_RemoveFiles_Handler,
 		},
+		{
+			MethodName: "RestoreSnapshot",
+			Handler:    _GomoteService_RestoreSnapshot_Handler,
+		},
 		{
 			MethodName: "SignSSHKey",
 			Handler:    _GomoteService_SignSSHKey_Handler,
 		},
+		{
+			MethodName: "SyncFiles",
+			Handler:    _GomoteService_SyncFiles_Handler,
+		},
 		{
 			MethodName: "UploadFile",
 			Handler:    _GomoteService_UploadFile_Handler,
@@ -740,6 +1092,22 @@ var GomoteService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _GomoteService_ListDirectoryStreaming_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamCommand",
+			Handler:       _GomoteService_StreamCommand_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "TailFile",
+			Handler:       _GomoteService_TailFile_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchDirectory",
+			Handler:       _GomoteService_WatchDirectory_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "internal/gomote/protos/gomote.proto",
 }
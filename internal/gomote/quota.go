@@ -0,0 +1,273 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+
+package gomote
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/build/internal/access"
+	"golang.org/x/build/internal/coordinator/remote"
+	"golang.org/x/build/internal/gomote/protos"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Quotas limits the number of gomote instances that may exist at once.
+// A limit of zero means no limit.
+type Quotas struct {
+	// PerUser is the number of instances each user may have.
+	PerUser int
+	// PerBuilderType is the number of instances of each builder type
+	// there may be, across all users.
+	PerBuilderType int
+	// BuilderTypes overrides PerBuilderType for some builder types.
+	BuilderTypes map[string]int
+}
+
+// RegisterQuotaFlags registers the flags that set the quotas of a gomote
+// server in fs. The returned Quotas are set when the flags are parsed.
+func RegisterQuotaFlags(fs *flag.FlagSet) *Quotas {
+	q := new(Quotas)
+	fs.IntVar(&q.PerUser, "max_instances_per_user", 0, "The number of gomote instances each user may have at once. Further requests wait in a queue. Zero means no limit.")
+	fs.IntVar(&q.PerBuilderType, "max_instances_per_builder", 0, "The number of gomote instances of each builder type there may be at once. Further requests wait in a queue. Zero means no limit.")
+	fs.Func("max_instances_of_builder", "The number of gomote instances of one builder type there may be at once, as builder-type=limit, overriding -max_instances_per_builder. May be repeated.", func(s string) error {
+		builderType, limit, ok := strings.Cut(s, "=")
+		n, err := strconv.Atoi(limit)
+		if !ok || builderType == "" || err != nil || n < 0 {
+			return fmt.Errorf("want builder-type=limit, got %q", s)
+		}
+		if q.BuilderTypes == nil {
+			q.BuilderTypes = make(map[string]int)
+		}
+		q.BuilderTypes[builderType] = n
+		return nil
+	})
+	return q
+}
+
+func (q Quotas) builderTypeLimit(builderType string) int {
+	if n, ok := q.BuilderTypes[builderType]; ok {
+		return n
+	}
+	return q.PerBuilderType
+}
+
+// queueUpdatePeriod is how often a request waiting for quota is told
+// where it is in the queue, if nothing changes sooner.
+const queueUpdatePeriod = 5 * time.Second
+
+// admission admits requests to create instances within the quotas, and
+// queues the rest in fair-share order: the request from the user with
+// the fewest instances goes first, and among those, the one that has
+// waited longest. The zero value admits every request.
+type admission struct {
+	mu       sync.Mutex
+	quotas   Quotas
+	seq      int
+	waiting  []*admissionRequest
+	creating []*admissionRequest // admitted, but the instance isn't in the session pool yet
+	changed  chan struct{}       // closed and replaced when the queue might move
+}
+
+type admissionRequest struct {
+	ownerID     string
+	builderType string
+	seq         int
+}
+
+// setQuotas sets the quotas for requests that haven't been admitted yet.
+func (a *admission) setQuotas(q Quotas) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.quotas = q
+	a.notifyLocked()
+}
+
+// getQuotas returns the quotas.
+func (a *admission) getQuotas() Quotas {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.quotas
+}
+
+// notify wakes the queued requests, such as when an instance is destroyed.
+func (a *admission) notify() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.notifyLocked()
+}
+
+func (a *admission) notifyLocked() {
+	if a.changed != nil {
+		close(a.changed)
+		a.changed = nil
+	}
+}
+
+// wait waits until the quotas allow ownerID to create an instance of
+// builderType, given the instances in sp, and calls queued each time
+// the request's place in the queue might have changed. The caller must
+// call done once the instance is in sp, or creating it has failed.
+func (a *admission) wait(ctx context.Context, sp *remote.SessionPool, ownerID, builderType string, queued func(ahead int, reason string) error) (done func(), err error) {
+	a.mu.Lock()
+	a.seq++
+	r := &admissionRequest{ownerID: ownerID, builderType: builderType, seq: a.seq}
+	a.waiting = append(a.waiting, r)
+	a.mu.Unlock()
+
+	var ticker *time.Ticker
+	for {
+		a.mu.Lock()
+		ok, ahead, reason := a.tryAdmitLocked(r, sp.List())
+		if a.changed == nil {
+			a.changed = make(chan struct{})
+		}
+		changed := a.changed
+		a.mu.Unlock()
+		if ok {
+			return func() { a.remove(&a.creating, r) }, nil
+		}
+		if err := queued(ahead, reason); err != nil {
+			a.remove(&a.waiting, r)
+			return nil, err
+		}
+		if ticker == nil {
+			ticker = time.NewTicker(queueUpdatePeriod)
+			defer ticker.Stop()
+		}
+		select {
+		case <-ctx.Done():
+			a.remove(&a.waiting, r)
+			return nil, ctx.Err()
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// remove removes r from the list, and wakes the queued requests.
+func (a *admission) remove(list *[]*admissionRequest, r *admissionRequest) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	*list = slices.DeleteFunc(*list, func(x *admissionRequest) bool { return x == r })
+	a.notifyLocked()
+}
+
+// tryAdmitLocked admits r if the quotas allow it and no request ahead of
+// it in the queue could be admitted instead. If it doesn't admit r, it
+// returns the number of requests ahead of r and why r is waiting.
+// The admission lock must be held.
+func (a *admission) tryAdmitLocked(r *admissionRequest, sessions []*remote.Session) (ok bool, ahead int, reason string) {
+	users := make(map[string]int)
+	builderTypes := make(map[string]int)
+	for _, s := range sessions {
+		users[s.OwnerID]++
+		builderTypes[s.BuilderType]++
+	}
+	for _, c := range a.creating {
+		users[c.ownerID]++
+		builderTypes[c.builderType]++
+	}
+	// why returns why x can't be admitted now, or "" if it can.
+	why := func(x *admissionRequest) string {
+		if n, limit := users[x.ownerID], a.quotas.PerUser; limit > 0 && n >= limit {
+			return fmt.Sprintf("you have %d instances, and the limit is %d", n, limit)
+		}
+		if n, limit := builderTypes[x.builderType], a.quotas.builderTypeLimit(x.builderType); limit > 0 && n >= limit {
+			return fmt.Sprintf("there are %d %s instances, and the limit is %d", n, x.builderType, limit)
+		}
+		return ""
+	}
+	queue := slices.Clone(a.waiting)
+	sort.SliceStable(queue, func(i, j int) bool {
+		qi, qj := queue[i], queue[j]
+		if ni, nj := users[qi.ownerID], users[qj.ownerID]; ni != nj {
+			return ni < nj
+		}
+		return qi.seq < qj.seq
+	})
+	reason = why(r)
+	for _, x := range queue {
+		if x == r {
+			break
+		}
+		ahead++
+		if why(x) == "" {
+			if reason == "" {
+				reason = "other requests are ahead of yours in the queue"
+			}
+			// Wake x, which the quotas might not have allowed when it last looked.
+			a.notifyLocked()
+		}
+	}
+	if reason != "" {
+		return false, ahead, reason
+	}
+	a.waiting = slices.DeleteFunc(a.waiting, func(x *admissionRequest) bool { return x == r })
+	a.creating = append(a.creating, r)
+	return true, 0, ""
+}
+
+// queuedUpdate returns a function that tells the requester of a new
+// instance where its request is in the admission queue.
+func queuedUpdate(stream protos.GomoteService_CreateInstanceServer) func(ahead int, reason string) error {
+	return func(ahead int, reason string) error {
+		err := stream.Send(&protos.CreateInstanceResponse{
+			Status:       protos.CreateInstanceResponse_QUEUED,
+			WaitersAhead: int64(ahead),
+			QueueReason:  reason,
+		})
+		if err != nil {
+			return status.Errorf(codes.Internal, "unable to stream result: %s", err)
+		}
+		return nil
+	}
+}
+
+// admissionError converts an error from admission.wait to a gRPC error.
+func admissionError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Errorf(codes.Canceled, "canceled while waiting for quota to create gomote instance")
+	}
+	return status.Errorf(codes.DeadlineExceeded, "timed out waiting for quota to create gomote instance")
+}
+
+// listUsage returns the instance time used by the caller with creds,
+// or by all users if req asks and the caller is privileged.
+func listUsage(sp *remote.SessionPool, quotas Quotas, creds *access.IAPFields, req *protos.ListUsageRequest) (*protos.ListUsageResponse, error) {
+	if req.GetAllUsers() && !isPrivilegedUser(creds.Email) {
+		return nil, status.Errorf(codes.PermissionDenied, "not allowed to list the usage of all users")
+	}
+	resp := &protos.ListUsageResponse{
+		MaxInstancesPerUser: int64(quotas.PerUser),
+	}
+	for _, u := range sp.Usage() {
+		if !req.GetAllUsers() && u.OwnerID != creds.ID {
+			continue
+		}
+		resp.Usage = append(resp.Usage, &protos.Usage{
+			User:            u.User,
+			BuilderType:     u.BuilderType,
+			Instances:       int64(u.Sessions),
+			ActiveInstances: int64(u.Active),
+			InstanceSeconds: int64(u.Duration / time.Second),
+		})
+	}
+	return resp, nil
+}
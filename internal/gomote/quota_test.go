@@ -0,0 +1,184 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+
+package gomote
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/build/buildlet"
+	"golang.org/x/build/internal/coordinator/remote"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdmissionUnlimited(t *testing.T) {
+	sp := remote.NewSessionPool(context.Background())
+	defer sp.Close()
+	sp.AddSession("owner", "user", "linux-amd64", "host", &buildlet.FakeClient{})
+
+	var a admission
+	done, err := a.wait(context.Background(), sp, "owner", "linux-amd64", func(ahead int, reason string) error {
+		t.Errorf("request without quotas queued: %s", reason)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("admission.wait() = %v; want no error", err)
+	}
+	done()
+}
+
+func TestAdmissionPerUser(t *testing.T) {
+	sp := remote.NewSessionPool(context.Background())
+	defer sp.Close()
+	name := sp.AddSession("owner", "user", "linux-amd64", "host", &buildlet.FakeClient{})
+
+	var a admission
+	a.setQuotas(Quotas{PerUser: 1})
+	queued := make(chan string, 10)
+	admitted := make(chan error, 1)
+	go func() {
+		done, err := a.wait(context.Background(), sp, "owner", "linux-amd64", func(ahead int, reason string) error {
+			queued <- reason
+			return nil
+		})
+		if err == nil {
+			done()
+		}
+		admitted <- err
+	}()
+	if reason := <-queued; reason != "you have 1 instances, and the limit is 1" {
+		t.Errorf("queued because %q; want because of the per-user limit", reason)
+	}
+	// Other users aren't limited.
+	done, err := a.wait(context.Background(), sp, "other", "linux-amd64", func(ahead int, reason string) error {
+		return errors.New("other user queued: " + reason)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	done()
+
+	if err := sp.DestroySession(name); err != nil {
+		t.Fatal(err)
+	}
+	a.notify()
+	select {
+	case err := <-admitted:
+		if err != nil {
+			t.Errorf("admission.wait() = %v; want no error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request not admitted after the user's instance was destroyed")
+	}
+}
+
+func TestAdmissionFairShare(t *testing.T) {
+	sp := remote.NewSessionPool(context.Background())
+	defer sp.Close()
+	// heavy has two instances, and light has none. The only allowed
+	// linux-arm64 instance belongs to someone else.
+	sp.AddSession("heavy", "heavy", "linux-amd64", "host", &buildlet.FakeClient{})
+	sp.AddSession("heavy", "heavy", "linux-amd64", "host", &buildlet.FakeClient{})
+	name := sp.AddSession("other", "other", "linux-arm64", "host", &buildlet.FakeClient{})
+
+	var a admission
+	a.setQuotas(Quotas{BuilderTypes: map[string]int{"linux-arm64": 1}})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	type result struct {
+		owner string
+		done  func()
+	}
+	results := make(chan result, 2)
+	request := func(owner string) <-chan int {
+		queued := make(chan int, 10)
+		go func() {
+			done, err := a.wait(ctx, sp, owner, "linux-arm64", func(ahead int, reason string) error {
+				queued <- ahead
+				return nil
+			})
+			if err == nil {
+				results <- result{owner, done}
+			}
+		}()
+		return queued
+	}
+	if ahead := <-request("heavy"); ahead != 0 {
+		t.Errorf("first request has %d ahead; want 0", ahead)
+	}
+	// light's request goes ahead of heavy's, even though it's later.
+	if ahead := <-request("light"); ahead != 0 {
+		t.Errorf("request from user with fewer instances has %d ahead; want 0", ahead)
+	}
+
+	if err := sp.DestroySession(name); err != nil {
+		t.Fatal(err)
+	}
+	a.notify()
+	first := <-results
+	if first.owner != "light" {
+		t.Errorf("admitted %s first; want light", first.owner)
+	}
+	select {
+	case r := <-results:
+		t.Fatalf("admitted %s while %s's instance is being created", r.owner, first.owner)
+	case <-time.After(100 * time.Millisecond):
+	}
+	first.done()
+	// light's instance wasn't added to the pool, so heavy's request fits now.
+	select {
+	case r := <-results:
+		r.done()
+	case <-time.After(5 * time.Second):
+		t.Fatal("second request not admitted")
+	}
+}
+
+func TestRegisterQuotaFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	q := RegisterQuotaFlags(fs)
+	err := fs.Parse([]string{
+		"-max_instances_per_user=2",
+		"-max_instances_per_builder=5",
+		"-max_instances_of_builder=linux-arm64=1",
+		"-max_instances_of_builder=darwin-amd64=0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Quotas{PerUser: 2, PerBuilderType: 5, BuilderTypes: map[string]int{"linux-arm64": 1, "darwin-amd64": 0}}
+	if diff := cmp.Diff(want, *q); diff != "" {
+		t.Errorf("parsed quotas mismatch (-want +got):\n%s", diff)
+	}
+	for _, bad := range []string{"linux-arm64", "=1", "linux-arm64=x", "linux-arm64=-1"} {
+		if err := fs.Parse([]string{"-max_instances_of_builder=" + bad}); err == nil {
+			t.Errorf("-max_instances_of_builder=%s parsed without error", bad)
+		}
+	}
+}
+
+func TestAdmissionError(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want codes.Code
+	}{
+		{context.Canceled, codes.Canceled},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{status.Error(codes.Internal, "unable to stream result"), codes.Internal},
+	} {
+		if got := status.Code(admissionError(tc.err)); got != tc.want {
+			t.Errorf("admissionError(%v) has code %v; want %v", tc.err, got, tc.want)
+		}
+	}
+}
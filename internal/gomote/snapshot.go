@@ -0,0 +1,328 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+
+package gomote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/google/uuid"
+	"golang.org/x/build/buildlet"
+	"golang.org/x/build/internal/gomote/protos"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultSnapshotTTL is how long a snapshot is kept if the
+	// request doesn't say.
+	defaultSnapshotTTL = 7 * 24 * time.Hour
+	// maxSnapshotTTL is the longest a snapshot is kept.
+	maxSnapshotTTL = 30 * 24 * time.Hour
+	// maxSnapshotsPerUser is the number of unexpired snapshots
+	// each user may have.
+	maxSnapshotsPerUser = 10
+	// snapshotSweepInterval is how often the server deletes the
+	// expired snapshots of all users.
+	snapshotSweepInterval = 6 * time.Hour
+)
+
+// errSnapshotNotExist is returned by a snapshotStore for a snapshot
+// that doesn't exist.
+var errSnapshotNotExist = errors.New("snapshot does not exist")
+
+// snapshotStore stores the snapshots of each user's gomote instances.
+type snapshotStore interface {
+	// Create stores the tar.gz file read from tgz as snap, owned by owner,
+	// and sets snap.SizeBytes.
+	Create(ctx context.Context, owner string, snap *protos.Snapshot, tgz io.Reader) error
+	// Get returns owner's snapshot with the given ID.
+	Get(ctx context.Context, owner, id string) (*protos.Snapshot, error)
+	// List returns all of owner's snapshots, including expired ones.
+	List(ctx context.Context, owner string) ([]*protos.Snapshot, error)
+	// Delete deletes owner's snapshot with the given ID.
+	Delete(ctx context.Context, owner, id string) error
+	// Owners returns the owners of all the stored snapshots.
+	Owners(ctx context.Context) ([]string, error)
+}
+
+// snapshotObject returns the name of the object in the gomote
+// transfer bucket that holds owner's snapshot with the given ID.
+func snapshotObject(owner, id string) string {
+	return snapshotPrefix(owner) + id + ".tar.gz"
+}
+
+func snapshotPrefix(owner string) string {
+	return snapshotsDir + owner + "/"
+}
+
+// snapshotsDir is the prefix of all the snapshot objects.
+const snapshotsDir = "snapshots/"
+
+// gcsSnapshotStore stores snapshots as objects in a GCS bucket, with
+// the rest of each snapshot's description in the object's metadata.
+type gcsSnapshotStore struct {
+	bucket *storage.BucketHandle
+}
+
+func (g *gcsSnapshotStore) Create(ctx context.Context, owner string, snap *protos.Snapshot, tgz io.Reader) error {
+	attrs, err := copyToObject(ctx, g.bucket.Object(snapshotObject(owner, snap.GetSnapshotId())), tgz, snapshotMetadata(snap))
+	if err != nil {
+		return err
+	}
+	snap.SizeBytes = attrs.Size
+	return nil
+}
+
+func (g *gcsSnapshotStore) Get(ctx context.Context, owner, id string) (*protos.Snapshot, error) {
+	attrs, err := g.bucket.Object(snapshotObject(owner, id)).Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, errSnapshotNotExist
+	} else if err != nil {
+		return nil, err
+	}
+	return snapshotFromAttrs(attrs), nil
+}
+
+func (g *gcsSnapshotStore) List(ctx context.Context, owner string) ([]*protos.Snapshot, error) {
+	var snaps []*protos.Snapshot
+	it := g.bucket.Objects(ctx, &storage.Query{Prefix: snapshotPrefix(owner)})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return snaps, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, snapshotFromAttrs(attrs))
+	}
+}
+
+func (g *gcsSnapshotStore) Delete(ctx context.Context, owner, id string) error {
+	err := g.bucket.Object(snapshotObject(owner, id)).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return errSnapshotNotExist
+	}
+	return err
+}
+
+func (g *gcsSnapshotStore) Owners(ctx context.Context) ([]string, error) {
+	var owners []string
+	it := g.bucket.Objects(ctx, &storage.Query{Prefix: snapshotsDir, Delimiter: "/"})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return owners, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		// With a delimiter, each owner's objects are listed as
+		// a single prefix.
+		if owner := strings.TrimSuffix(strings.TrimPrefix(attrs.Prefix, snapshotsDir), "/"); owner != "" {
+			owners = append(owners, owner)
+		}
+	}
+}
+
+// snapshotMetadata returns the object metadata that describes snap.
+func snapshotMetadata(snap *protos.Snapshot) map[string]string {
+	return map[string]string{
+		"builder-type": snap.GetBuilderType(),
+		"go-revision":  snap.GetGoRevision(),
+		"directory":    snap.GetDirectory(),
+		"description":  snap.GetDescription(),
+		"created":      strconv.FormatInt(snap.GetCreated(), 10),
+		"expires":      strconv.FormatInt(snap.GetExpires(), 10),
+	}
+}
+
+// snapshotFromAttrs returns the snapshot stored in the object with attrs.
+func snapshotFromAttrs(attrs *storage.ObjectAttrs) *protos.Snapshot {
+	md := attrs.Metadata
+	created, _ := strconv.ParseInt(md["created"], 10, 64)
+	expires, _ := strconv.ParseInt(md["expires"], 10, 64)
+	name := attrs.Name[strings.LastIndex(attrs.Name, "/")+1:]
+	return &protos.Snapshot{
+		SnapshotId:  strings.TrimSuffix(name, ".tar.gz"),
+		BuilderType: md["builder-type"],
+		GoRevision:  md["go-revision"],
+		Directory:   md["directory"],
+		Description: md["description"],
+		Created:     created,
+		Expires:     expires,
+		SizeBytes:   attrs.Size,
+	}
+}
+
+// copyToObject writes the contents of r to obj, with the given metadata.
+func copyToObject(ctx context.Context, obj *storage.ObjectHandle, r io.Reader, metadata map[string]string) (*storage.ObjectAttrs, error) {
+	// A context for writes is used to ensure we can cancel the context if a
+	// problem is encountered while writing to the object store. The API documentation
+	// states that the context should be canceled to stop writing without saving the data.
+	writeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w := obj.NewWriter(writeCtx)
+	w.Metadata = metadata
+	if _, err := io.Copy(w, r); err != nil {
+		return nil, fmt.Errorf("unable to stream tar.gz: %w", err)
+	}
+	// when close is called, the object is stored in the bucket.
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("unable to store object: %w", err)
+	}
+	return w.Attrs(), nil
+}
+
+// createSnapshot saves the directory in req from the instance bc of
+// builderType as a new snapshot owned by owner.
+func createSnapshot(ctx context.Context, store snapshotStore, bc buildlet.RemoteClient, owner, builderType string, req *protos.CreateSnapshotRequest) (*protos.Snapshot, error) {
+	if req.GetTtlSeconds() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid TTL")
+	}
+	snaps, err := listSnapshots(ctx, store, owner)
+	if err != nil {
+		return nil, err
+	}
+	if len(snaps) >= maxSnapshotsPerUser {
+		return nil, status.Errorf(codes.ResourceExhausted, "too many snapshots (%d); delete some first", len(snaps))
+	}
+	ttl := time.Duration(req.GetTtlSeconds()) * time.Second
+	if ttl == 0 {
+		ttl = defaultSnapshotTTL
+	}
+	ttl = min(ttl, maxSnapshotTTL)
+	now := time.Now()
+	snap := &protos.Snapshot{
+		SnapshotId:  uuid.NewString(),
+		BuilderType: builderType,
+		GoRevision:  req.GetGoRevision(),
+		Directory:   req.GetDirectory(),
+		Description: req.GetDescription(),
+		Created:     now.Unix(),
+		Expires:     now.Add(ttl).Unix(),
+	}
+	tgz, err := bc.GetTar(ctx, req.GetDirectory())
+	if err != nil {
+		return nil, status.Errorf(codes.Aborted, "unable to retrieve tar from gomote instance: %s", err)
+	}
+	defer tgz.Close()
+	if err := store.Create(ctx, owner, snap, tgz); err != nil {
+		return nil, status.Errorf(codes.Aborted, "unable to store snapshot: %s", err)
+	}
+	return snap, nil
+}
+
+// listSnapshots returns owner's unexpired snapshots, oldest first,
+// and deletes the expired ones.
+func listSnapshots(ctx context.Context, store snapshotStore, owner string) ([]*protos.Snapshot, error) {
+	all, err := store.List(ctx, owner)
+	if err != nil {
+		log.Printf("unable to list snapshots for %s: %s", owner, err)
+		return nil, status.Errorf(codes.Internal, "unable to list snapshots")
+	}
+	now := time.Now().Unix()
+	var snaps []*protos.Snapshot
+	for _, snap := range all {
+		if snap.GetExpires() > now {
+			snaps = append(snaps, snap)
+			continue
+		}
+		if err := store.Delete(ctx, owner, snap.GetSnapshotId()); err != nil && !errors.Is(err, errSnapshotNotExist) {
+			log.Printf("unable to delete expired snapshot %s of %s: %s", snap.GetSnapshotId(), owner, err)
+		}
+	}
+	sort.Slice(snaps, func(i, j int) bool {
+		return snaps[i].GetCreated() < snaps[j].GetCreated()
+	})
+	return snaps, nil
+}
+
+// sweepSnapshots deletes the expired snapshots of all users every
+// snapshotSweepInterval, so that the snapshots of users who don't list
+// theirs again don't stay in the store forever. It never returns.
+func sweepSnapshots(store snapshotStore) {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), snapshotSweepInterval)
+		sweepSnapshotsOnce(ctx, store)
+		cancel()
+		time.Sleep(snapshotSweepInterval)
+	}
+}
+
+// sweepSnapshotsOnce deletes the expired snapshots of all users.
+func sweepSnapshotsOnce(ctx context.Context, store snapshotStore) {
+	owners, err := store.Owners(ctx)
+	if err != nil {
+		log.Printf("unable to list snapshot owners: %s", err)
+		return
+	}
+	for _, owner := range owners {
+		// listSnapshots logs its errors.
+		listSnapshots(ctx, store, owner)
+	}
+}
+
+// getSnapshot returns owner's unexpired snapshot with the given ID.
+func getSnapshot(ctx context.Context, store snapshotStore, owner, id string) (*protos.Snapshot, error) {
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid snapshot ID")
+	}
+	snap, err := store.Get(ctx, owner, id)
+	if errors.Is(err, errSnapshotNotExist) || err == nil && snap.GetExpires() <= time.Now().Unix() {
+		return nil, status.Errorf(codes.NotFound, "specified snapshot does not exist")
+	} else if err != nil {
+		log.Printf("unable to get snapshot %s of %s: %s", id, owner, err)
+		return nil, status.Errorf(codes.Internal, "unable to get snapshot")
+	}
+	return snap, nil
+}
+
+// restoreSnapshot expands owner's snapshot with the given ID onto the
+// instance bc of builderType, using signURL to sign a URL the instance
+// can download the snapshot from.
+func restoreSnapshot(ctx context.Context, store snapshotStore, bc buildlet.RemoteClient, owner, builderType, id string, signURL func(object string) (string, error)) error {
+	snap, err := getSnapshot(ctx, store, owner, id)
+	if err != nil {
+		return err
+	}
+	if snap.GetBuilderType() != builderType {
+		return status.Errorf(codes.FailedPrecondition, "snapshot is of builder type %s, not %s", snap.GetBuilderType(), builderType)
+	}
+	url, err := signURL(snapshotObject(owner, id))
+	if err != nil {
+		return status.Errorf(codes.Aborted, "unable to sign url for download: %s", err)
+	}
+	if err := bc.PutTarFromURL(ctx, url, snap.GetDirectory()); err != nil {
+		return status.Errorf(codes.FailedPrecondition, "unable to write tar.gz: %s", err)
+	}
+	return nil
+}
+
+// deleteSnapshot deletes owner's snapshot with the given ID.
+func deleteSnapshot(ctx context.Context, store snapshotStore, owner, id string) error {
+	if _, err := getSnapshot(ctx, store, owner, id); err != nil {
+		return err
+	}
+	if err := store.Delete(ctx, owner, id); errors.Is(err, errSnapshotNotExist) {
+		return status.Errorf(codes.NotFound, "specified snapshot does not exist")
+	} else if err != nil {
+		log.Printf("unable to delete snapshot %s of %s: %s", id, owner, err)
+		return status.Errorf(codes.Internal, "unable to delete snapshot")
+	}
+	return nil
+}
@@ -42,8 +42,10 @@ const expDisableGolangbuild = "disable-golang-build"
 
 type rendezvousClient interface {
 	DeregisterInstance(ctx context.Context, id string)
+	DeregisterSession(ctx context.Context, name string)
 	HandleReverse(w http.ResponseWriter, r *http.Request)
 	RegisterInstance(ctx context.Context, id string, wait time.Duration)
+	SetSession(ctx context.Context, id string, s *rendezvous.SessionInfo) error
 	WaitForInstance(ctx context.Context, id string) (buildlet.Client, error)
 }
 
@@ -58,11 +60,13 @@ type SwarmingServer struct {
 	// embed the unimplemented server.
 	protos.UnimplementedGomoteServiceServer
 
+	admission               admission
 	bucket                  bucketHandle
 	buildersClient          BuildersClient
 	buildlets               *remote.SessionPool
 	gceBucketName           string
 	rendezvous              rendezvousClient
+	snapshots               snapshotStore
 	sshCertificateAuthority ssh.Signer
 	swarmingClient          swarming.Client
 }
@@ -73,17 +77,26 @@ func NewSwarming(rsp *remote.SessionPool, rawCAPriKey []byte, gomoteGCSBucket st
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse raw certificate authority private key into signer=%w", err)
 	}
+	snapshots := &gcsSnapshotStore{bucket: storageClient.Bucket(gomoteGCSBucket)}
+	go sweepSnapshots(snapshots)
 	return &SwarmingServer{
 		bucket:                  storageClient.Bucket(gomoteGCSBucket),
 		buildersClient:          buildersClient,
 		buildlets:               rsp,
 		gceBucketName:           gomoteGCSBucket,
 		rendezvous:              rdv,
+		snapshots:               snapshots,
 		sshCertificateAuthority: signer,
 		swarmingClient:          swarmClient,
 	}, nil
 }
 
+// SetQuotas sets the limits on the number of instances that may exist at once.
+// Requests to create instances beyond the limits wait in a queue.
+func (ss *SwarmingServer) SetQuotas(q Quotas) {
+	ss.admission.setQuotas(q)
+}
+
 // Authenticate will allow the caller to verify that they are properly authenticated and authorized to interact with the
 // Service.
 func (ss *SwarmingServer) Authenticate(ctx context.Context, req *protos.AuthenticateRequest) (*protos.AuthenticateResponse, error) {
@@ -169,6 +182,11 @@ func (ss *SwarmingServer) CreateInstance(req *protos.CreateInstanceRequest, stre
 	if err != nil {
 		return status.Errorf(codes.Internal, "invalid user email format")
 	}
+	admitted, err := ss.admission.wait(stream.Context(), ss.buildlets, creds.ID, req.GetBuilderType(), queuedUpdate(stream))
+	if err != nil {
+		return admissionError(err)
+	}
+	defer admitted()
 	type result struct {
 		buildletClient buildlet.Client
 		err            error
@@ -219,6 +237,17 @@ func (ss *SwarmingServer) CreateInstance(req *protos.CreateInstanceRequest, stre
 			}
 			gomoteID := ss.buildlets.AddSession(creds.ID, userName, req.GetBuilderType(), req.GetBuilderType(), r.buildletClient)
 			log.Printf("created buildlet %s for %s (%s)", gomoteID, userName, r.buildletClient.String())
+			if err := ss.rendezvous.SetSession(stream.Context(), name, &rendezvous.SessionInfo{
+				Name:        gomoteID,
+				OwnerID:     creds.ID,
+				User:        userName,
+				BuilderType: req.GetBuilderType(),
+				HostType:    req.GetBuilderType(),
+				Created:     time.Now(),
+			}); err != nil {
+				// The instance works, but its buildlet won't be able to reconnect.
+				log.Printf("unable to record session %s of instance %s: %s", gomoteID, name, err)
+			}
 			session, err := ss.buildlets.Session(gomoteID)
 			if err != nil {
 				return status.Errorf(codes.Internal, "unable to query for gomote timeout") // this should never happen
@@ -246,6 +275,52 @@ func (ss *SwarmingServer) CreateInstance(req *protos.CreateInstanceRequest, stre
 	}
 }
 
+// ReconnectInstance restores the session of a swarming instance whose
+// buildlet reconnected, after its connection was lost or the server
+// restarted. It is the rendezvous.ReconnectFunc of the gomote server.
+func (ss *SwarmingServer) ReconnectInstance(ctx context.Context, reg *rendezvous.Registration, bc buildlet.Client) (buildlet.Client, error) {
+	s := reg.Session
+	if s == nil {
+		return nil, fmt.Errorf("instance %s has no session", reg.ID)
+	}
+	old := ss.buildlets.RestoreSession(s.Name, s.OwnerID, s.User, s.BuilderType, s.HostType, s.Created, bc)
+	log.Printf("restored buildlet %s for %s (%s)", s.Name, s.User, bc.String())
+	return old, nil
+}
+
+// CreateSnapshot saves a directory on a gomote instance as a snapshot owned by the requester, which can
+// later be restored onto another instance of the same builder type. The requester must be authenticated.
+func (ss *SwarmingServer) CreateSnapshot(ctx context.Context, req *protos.CreateSnapshotRequest) (*protos.CreateSnapshotResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		log.Printf("CreateSnapshot access.IAPFromContext(ctx) = nil, %s", err)
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	ses, bc, err := ss.sessionAndClient(ctx, req.GetGomoteId(), creds.ID)
+	if err != nil {
+		// the helper function returns meaningful GRPC error.
+		return nil, err
+	}
+	snap, err := createSnapshot(ctx, ss.snapshots, bc, creds.ID, ses.BuilderType, req)
+	if err != nil {
+		return nil, err
+	}
+	return &protos.CreateSnapshotResponse{Snapshot: snap}, nil
+}
+
+// DeleteSnapshot deletes a snapshot owned by the requester. The requester must be authenticated.
+func (ss *SwarmingServer) DeleteSnapshot(ctx context.Context, req *protos.DeleteSnapshotRequest) (*protos.DeleteSnapshotResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		log.Printf("DeleteSnapshot access.IAPFromContext(ctx) = nil, %s", err)
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	if err := deleteSnapshot(ctx, ss.snapshots, creds.ID, req.GetSnapshotId()); err != nil {
+		return nil, err
+	}
+	return &protos.DeleteSnapshotResponse{}, nil
+}
+
 // DestroyInstance will destroy a gomote instance. It will ensure that the caller is authenticated and is the owner of the instance
 // before it destroys the instance.
 func (ss *SwarmingServer) DestroyInstance(ctx context.Context, req *protos.DestroyInstanceRequest) (*protos.DestroyInstanceResponse, error) {
@@ -266,6 +341,7 @@ func (ss *SwarmingServer) DestroyInstance(ctx context.Context, req *protos.Destr
 		log.Printf("DestroyInstance remote.DestroySession(%s) = %s", req.GetGomoteId(), err)
 		return nil, status.Errorf(codes.Internal, "unable to destroy gomote instance")
 	}
+	ss.admission.notify()
 	// TODO(go.dev/issue/63819) consider destroying the bot after the task has ended.
 	return &protos.DestroyInstanceResponse{}, nil
 }
@@ -285,6 +361,7 @@ func (ss *SwarmingServer) ExecuteCommand(req *protos.ExecuteCommandRequest, stre
 	if builderType == "" {
 		builderType = ses.BuilderType
 	}
+	var stats buildlet.ExecStats
 	remoteErr, execErr := bc.Exec(stream.Context(), req.GetCommand(), buildlet.ExecOpts{
 		Dir:         req.GetDirectory(),
 		SystemLevel: req.GetSystemLevel(),
@@ -301,11 +378,16 @@ func (ss *SwarmingServer) ExecuteCommand(req *protos.ExecuteCommandRequest, stre
 		ExtraEnv: req.GetAppendEnvironment(),
 		Debug:    req.GetDebug(),
 		Path:     req.GetPath(),
+		Stats:    &stats,
+		Sandbox:  execSandbox(req),
 	})
 	if execErr != nil {
 		// there were system errors preventing the command from being started or seen to completion.
 		return status.Errorf(codes.Aborted, "unable to execute command: %s", execErr)
 	}
+	if err := sendExecStats(stream, stats); err != nil {
+		return err
+	}
 	if remoteErr != nil {
 		// the command failed remotely
 		return status.Errorf(codes.Unknown, "command execution failed: %s", remoteErr)
@@ -313,6 +395,26 @@ func (ss *SwarmingServer) ExecuteCommand(req *protos.ExecuteCommandRequest, stre
 	return nil
 }
 
+// StreamCommand executes a command on a gomote instance, streaming its standard input, output, and error,
+// and forwarding signals to it. The command's exit status is sent as the last response.
+func (ss *SwarmingServer) StreamCommand(stream protos.GomoteService_StreamCommandServer) error {
+	creds, err := access.IAPFromContext(stream.Context())
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	start, err := recvStreamCommandStart(stream)
+	if err != nil {
+		return err
+	}
+	req := start.GetCommand()
+	_, bc, err := ss.sessionAndClient(stream.Context(), req.GetGomoteId(), creds.ID)
+	if err != nil {
+		// the helper function returns meaningful GRPC error.
+		return err
+	}
+	return streamCommand(stream, bc, start, req.GetAppendEnvironment())
+}
+
 // InstanceAlive will ensure that the gomote instance is still alive and will extend the timeout. The requester must be authenticated.
 func (ss *SwarmingServer) InstanceAlive(ctx context.Context, req *protos.InstanceAliveRequest) (*protos.InstanceAliveResponse, error) {
 	creds, err := access.IAPFromContext(ctx)
@@ -512,6 +614,32 @@ func (ss *SwarmingServer) ListInstances(ctx context.Context, req *protos.ListIns
 	return res, nil
 }
 
+// ListSnapshots lists the unexpired snapshots owned by the requester, and deletes the expired ones.
+// The requester must be authenticated.
+func (ss *SwarmingServer) ListSnapshots(ctx context.Context, req *protos.ListSnapshotsRequest) (*protos.ListSnapshotsResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		log.Printf("ListSnapshots access.IAPFromContext(ctx) = nil, %s", err)
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	snaps, err := listSnapshots(ctx, ss.snapshots, creds.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &protos.ListSnapshotsResponse{Snapshots: snaps}, nil
+}
+
+// ListUsage lists the instance time used by the requester, or by all users if the request asks and the
+// requester is privileged. The requester must be authenticated.
+func (ss *SwarmingServer) ListUsage(ctx context.Context, req *protos.ListUsageRequest) (*protos.ListUsageResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		log.Printf("ListUsage access.IAPFromContext(ctx) = nil, %s", err)
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	return listUsage(ss.buildlets, ss.admission.getQuotas(), creds, req)
+}
+
 // ReadTGZToURL retrieves a directory from the gomote instance and writes the file to GCS. It returns a signed URL which the caller uses
 // to read the file from GCS.
 func (ss *SwarmingServer) ReadTGZToURL(ctx context.Context, req *protos.ReadTGZToURLRequest) (*protos.ReadTGZToURLResponse, error) {
@@ -530,19 +658,8 @@ func (ss *SwarmingServer) ReadTGZToURL(ctx context.Context, req *protos.ReadTGZT
 	}
 	defer tgz.Close()
 	objectName := uuid.NewString()
-	objectHandle := ss.bucket.Object(objectName)
-	// A context for writes is used to ensure we can cancel the context if a
-	// problem is encountered while writing to the object store. The API documentation
-	// states that the context should be canceled to stop writing without saving the data.
-	writeCtx, cancel := context.WithCancel(ctx)
-	tgzWriter := objectHandle.NewWriter(writeCtx)
-	defer cancel()
-	if _, err = io.Copy(tgzWriter, tgz); err != nil {
-		return nil, status.Errorf(codes.Aborted, "unable to stream tar.gz: %s", err)
-	}
-	// when close is called, the object is stored in the bucket.
-	if err := tgzWriter.Close(); err != nil {
-		return nil, status.Errorf(codes.Aborted, "unable to store object: %s", err)
+	if _, err := copyToObject(ctx, ss.bucket.Object(objectName), tgz, nil); err != nil {
+		return nil, status.Errorf(codes.Aborted, "%s", err)
 	}
 	url, err := ss.signURLForDownload(objectName)
 	if err != nil {
@@ -576,6 +693,25 @@ func (ss *SwarmingServer) RemoveFiles(ctx context.Context, req *protos.RemoveFil
 	return &protos.RemoveFilesResponse{}, nil
 }
 
+// RestoreSnapshot expands a snapshot owned by the requester onto a gomote instance of the same builder
+// type as the one the snapshot was saved from. The requester must be authenticated.
+func (ss *SwarmingServer) RestoreSnapshot(ctx context.Context, req *protos.RestoreSnapshotRequest) (*protos.RestoreSnapshotResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		log.Printf("RestoreSnapshot access.IAPFromContext(ctx) = nil, %s", err)
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	ses, bc, err := ss.sessionAndClient(ctx, req.GetGomoteId(), creds.ID)
+	if err != nil {
+		// the helper function returns meaningful GRPC error.
+		return nil, err
+	}
+	if err := restoreSnapshot(ctx, ss.snapshots, bc, creds.ID, ses.BuilderType, req.GetSnapshotId(), ss.signURLForDownload); err != nil {
+		return nil, err
+	}
+	return &protos.RestoreSnapshotResponse{}, nil
+}
+
 // SignSSHKey signs the public SSH key with a certificate. The signed public SSH key is intended for use with the gomote service SSH
 // server. It will be signed by the certificate authority of the server and will restrict access to the gomote instance that it was
 // signed for.
@@ -722,6 +858,74 @@ func (ss *SwarmingServer) WriteTGZFromURL(ctx context.Context, req *protos.Write
 	return &protos.WriteTGZFromURLResponse{}, nil
 }
 
+// SyncFiles makes a directory on the gomote instance match the manifest in the request, transferring
+// only the file contents the instance is missing from the tar.gz file at the request's blobs URL.
+func (ss *SwarmingServer) SyncFiles(ctx context.Context, req *protos.SyncFilesRequest) (*protos.SyncFilesResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		log.Printf("SyncFiles access.IAPFromContext(ctx) = nil, %s", err)
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	if req.GetGomoteId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid gomote ID")
+	}
+	_, bc, err := ss.sessionAndClient(ctx, req.GetGomoteId(), creds.ID)
+	if err != nil {
+		// the helper function returns meaningful GRPC error.
+		return nil, err
+	}
+	var url string
+	if req.GetBlobsUrl() != "" {
+		object, err := objectFromURL(ss.gceBucketName, req.GetBlobsUrl())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid blobs URL")
+		}
+		url, err = ss.signURLForDownload(object)
+		if err != nil {
+			return nil, status.Errorf(codes.Aborted, "unable to sign url for download: %s", err)
+		}
+	}
+	return syncFiles(ctx, bc, req, url)
+}
+
+// TailFile streams the contents of a file on a gomote instance, optionally following bytes
+// appended to it. The requester must be authenticated.
+func (ss *SwarmingServer) TailFile(req *protos.TailFileRequest, stream grpc.ServerStreamingServer[protos.TailFileResponse]) error {
+	creds, err := access.IAPFromContext(stream.Context())
+	if err != nil {
+		log.Printf("TailFile access.IAPFromContext(ctx) = nil, %s", err)
+		return status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	if req.GetGomoteId() == "" || req.GetPath() == "" {
+		return status.Errorf(codes.InvalidArgument, "invalid arguments")
+	}
+	_, bc, err := ss.sessionAndClient(stream.Context(), req.GetGomoteId(), creds.ID)
+	if err != nil {
+		// the helper function returns meaningful GRPC error.
+		return err
+	}
+	return tailFile(stream, bc, req)
+}
+
+// WatchDirectory streams changes to the files in a directory on a gomote instance.
+// The requester must be authenticated.
+func (ss *SwarmingServer) WatchDirectory(req *protos.WatchDirectoryRequest, stream grpc.ServerStreamingServer[protos.WatchDirectoryResponse]) error {
+	creds, err := access.IAPFromContext(stream.Context())
+	if err != nil {
+		log.Printf("WatchDirectory access.IAPFromContext(ctx) = nil, %s", err)
+		return status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	if req.GetGomoteId() == "" || req.GetDirectory() == "" {
+		return status.Errorf(codes.InvalidArgument, "invalid arguments")
+	}
+	_, bc, err := ss.sessionAndClient(stream.Context(), req.GetGomoteId(), creds.ID)
+	if err != nil {
+		// the helper function returns meaningful GRPC error.
+		return err
+	}
+	return watchDirectory(stream, bc, req)
+}
+
 // session is a helper function that retrieves a session associated with the gomoteID and ownerID.
 func (ss *SwarmingServer) session(gomoteID, ownerID string) (*remote.Session, error) {
 	session, err := ss.buildlets.Session(gomoteID)
@@ -25,6 +25,7 @@ import (
 	"go.chromium.org/luci/swarming/client/swarming"
 	"go.chromium.org/luci/swarming/client/swarming/swarmingtest"
 	swarmpb "go.chromium.org/luci/swarming/proto/api_v2"
+	"golang.org/x/build/buildlet"
 	"golang.org/x/build/internal/access"
 	"golang.org/x/build/internal/coordinator/remote"
 	"golang.org/x/build/internal/gomote/protos"
@@ -48,6 +49,7 @@ func fakeGomoteSwarmingServer(t *testing.T, ctx context.Context, swarmClient swa
 		bucket:                  &fakeBucketHandler{bucketName: testSwarmingBucketName},
 		buildlets:               remote.NewSessionPool(ctx),
 		gceBucketName:           testSwarmingBucketName,
+		snapshots:               &fakeSnapshotStore{},
 		sshCertificateAuthority: signer,
 		rendezvous:              rdv,
 		swarmingClient:          swarmClient,
@@ -1204,6 +1206,41 @@ func TestStartNewSwarmingTask(t *testing.T) {
 	}
 }
 
+func TestSwarmingReconnectInstance(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sp := remote.NewSessionPool(ctx)
+	ss := &SwarmingServer{buildlets: sp}
+
+	reg := &rendezvous.Registration{ID: "task-123", Connected: true}
+	if _, err := ss.ReconnectInstance(ctx, reg, &buildlet.FakeClient{}); err == nil {
+		t.Errorf("ReconnectInstance of a registration without a session succeeded")
+	}
+
+	reg.Session = &rendezvous.SessionInfo{
+		Name:        "user-gotest-linux-amd64-0",
+		OwnerID:     "owner",
+		User:        "gotest",
+		BuilderType: "linux-amd64",
+		HostType:    "host-linux-amd64",
+		Created:     time.Now(),
+	}
+	bc := &buildlet.FakeClient{}
+	if old, err := ss.ReconnectInstance(ctx, reg, bc); old != nil || err != nil {
+		t.Fatalf("ReconnectInstance = %v, %v; want no previous client and no error", old, err)
+	}
+	if old, err := ss.ReconnectInstance(ctx, reg, &buildlet.FakeClient{}); old != bc || err != nil {
+		t.Errorf("ReconnectInstance again = %v, %v; want the previous client", old, err)
+	}
+	ses, err := sp.Session(reg.Session.Name)
+	if err != nil {
+		t.Fatalf("Session(%q) = %s; want the restored session", reg.Session.Name, err)
+	}
+	if ses.OwnerID != "owner" || ses.BuilderType != "linux-amd64" {
+		t.Errorf("restored session = %+v; want owner %q and builder type %q", ses, "owner", "linux-amd64")
+	}
+}
+
 func mockSwarmClient() *swarmingtest.Client {
 	return &swarmingtest.Client{
 		NewTaskMock: func(context.Context, *swarmpb.NewTaskRequest) (*swarmpb.TaskRequestMetadataResponse, error) {
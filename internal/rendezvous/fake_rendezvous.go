@@ -15,8 +15,10 @@ import (
 
 type rendezvousServer interface {
 	DeregisterInstance(ctx context.Context, id string)
+	DeregisterSession(ctx context.Context, name string)
 	HandleReverse(w http.ResponseWriter, r *http.Request)
 	RegisterInstance(ctx context.Context, id string, wait time.Duration)
+	SetSession(ctx context.Context, id string, s *SessionInfo) error
 	WaitForInstance(ctx context.Context, id string) (buildlet.Client, error)
 }
 
@@ -46,6 +48,16 @@ func (rdv *FakeRendezvous) DeregisterInstance(ctx context.Context, id string) {
 	// do nothing
 }
 
+// SetSession is a fake implementation.
+func (rdv *FakeRendezvous) SetSession(ctx context.Context, id string, s *SessionInfo) error {
+	return nil
+}
+
+// DeregisterSession is a fake implementation.
+func (rdv *FakeRendezvous) DeregisterSession(ctx context.Context, name string) {
+	// do nothing
+}
+
 // WaitForInstance is a fake implementation.
 func (rdv *FakeRendezvous) WaitForInstance(ctx context.Context, id string) (buildlet.Client, error) {
 	return &buildlet.FakeClient{}, nil
@@ -6,6 +6,7 @@ package rendezvous
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -34,13 +35,29 @@ type entry struct {
 // TokenValidator verifies if a token is valid.
 type TokenValidator func(ctx context.Context, jwt string) bool
 
+// ReconnectFunc re-associates a buildlet that connected again, after its
+// connection was lost or the server restarted, with the session of its
+// registration, which may be nil. It returns the client that bc replaces,
+// if any, which the Rendezvous closes.
+type ReconnectFunc func(ctx context.Context, reg *Registration, bc buildlet.Client) (old buildlet.Client, err error)
+
+// reconnectWait is how long buildlets that were connected before a
+// restart have to reconnect.
+const reconnectWait = 10 * time.Minute
+
 // Rendezvous waits for buildlets to connect, verifies they are valid instances
 // and passes the connection to the waiting caller.
+//
+// It records the registrations in a Store, so that buildlets that were
+// connected may reconnect, even after a restart, if a ReconnectFunc is
+// set.
 type Rendezvous struct {
 	mu sync.Mutex
 
 	m         map[string]*entry
+	store     Store // nil if registrations aren't recorded
 	validator TokenValidator
+	reconnect ReconnectFunc
 }
 
 // Option is an optional configuration setting.
@@ -53,33 +70,109 @@ func OptionValidator(v TokenValidator) Option {
 	}
 }
 
+// OptionStore changes the Store of registrations used by Rendezvous,
+// which keeps them in memory by default.
+func OptionStore(s Store) Option {
+	return func(rdv *Rendezvous) {
+		rdv.store = s
+	}
+}
+
+// OptionReconnect sets the function that lets buildlets reconnect.
+// Without one, buildlets may only connect once.
+func OptionReconnect(f ReconnectFunc) Option {
+	return func(rdv *Rendezvous) {
+		rdv.reconnect = f
+	}
+}
+
 // New creates a Rendezvous element. The context that is passed in should be non-canceled
 // during the lifetime of the running service.
 func New(ctx context.Context, opts ...Option) *Rendezvous {
 	rdv := &Rendezvous{
 		m:         make(map[string]*entry),
+		store:     NewMemoryStore(),
 		validator: validateLUCIIDToken,
 	}
 	for _, opt := range opts {
 		opt(rdv)
 	}
+	rdv.restore()
 	go internal.PeriodicallyDo(ctx, 10*time.Second, func(ctx context.Context, t time.Time) {
 		rdv.purgeExpiredRegistrations()
 	})
 	return rdv
 }
 
+// restore prepares the registrations recorded before a restart. The
+// buildlets that were connected get time to reconnect, and the pending
+// registrations, whose callers are gone, are dropped.
+func (rdv *Rendezvous) restore() {
+	if rdv.store == nil {
+		return
+	}
+	regs, err := rdv.store.List()
+	if err != nil {
+		log.Printf("rendezvous: unable to list registrations: %s", err)
+		return
+	}
+	for _, reg := range regs {
+		if !reg.Connected {
+			log.Printf("rendezvous: dropping pending registration for instance=%q from before restart", reg.ID)
+			rdv.storeDelete(reg.ID)
+			continue
+		}
+		reg.Deadline = time.Now().Add(reconnectWait)
+		rdv.storePut(reg)
+	}
+}
+
+// storePut records reg in the store, if any, logging any error.
+func (rdv *Rendezvous) storePut(reg *Registration) {
+	if rdv.store == nil {
+		return
+	}
+	if err := rdv.store.Put(reg); err != nil {
+		log.Printf("rendezvous: unable to record registration for instance=%q: %s", reg.ID, err)
+	}
+}
+
+// storeDelete deletes the registration from the store, if any, logging any error.
+func (rdv *Rendezvous) storeDelete(id string) {
+	if rdv.store == nil {
+		return
+	}
+	if err := rdv.store.Delete(id); err != nil {
+		log.Printf("rendezvous: unable to delete registration for instance=%q: %s", id, err)
+	}
+}
+
 // purgeExpiredRegistrations will purge expired registrations.
 func (rdv *Rendezvous) purgeExpiredRegistrations() {
 	rdv.mu.Lock()
+	defer rdv.mu.Unlock()
 	for id, ent := range rdv.m {
 		if time.Now().After(ent.deadline) {
 			log.Printf("rendezvous: stopped waiting for instance=%q due to timeout", id)
 			ent.ch <- &result{err: fmt.Errorf("timed out waiting for rendezvous client=%q", id)}
 			delete(rdv.m, id)
+			rdv.storeDelete(id)
+		}
+	}
+	if rdv.store == nil {
+		return
+	}
+	regs, err := rdv.store.List()
+	if err != nil {
+		log.Printf("rendezvous: unable to list registrations: %s", err)
+		return
+	}
+	for _, reg := range regs {
+		if _, ok := rdv.m[reg.ID]; !ok && !reg.Deadline.IsZero() && time.Now().After(reg.Deadline) {
+			log.Printf("rendezvous: stopped waiting for instance=%q to reconnect due to timeout", reg.ID)
+			rdv.storeDelete(reg.ID)
 		}
 	}
-	rdv.mu.Unlock()
 }
 
 // RegisterInstance notes an instance and waits for that instance to connect to the handler. An
@@ -87,10 +180,12 @@ func (rdv *Rendezvous) purgeExpiredRegistrations() {
 // not connect before the end of the wait period, the instance will not be able to connect.
 func (rdv *Rendezvous) RegisterInstance(ctx context.Context, id string, wait time.Duration) {
 	rdv.mu.Lock()
+	deadline := time.Now().Add(wait)
 	rdv.m[id] = &entry{
-		deadline: time.Now().Add(wait),
+		deadline: deadline,
 		ch:       make(chan *result, 1),
 	}
+	rdv.storePut(&Registration{ID: id, Deadline: deadline})
 	rdv.mu.Unlock()
 }
 
@@ -99,9 +194,48 @@ func (rdv *Rendezvous) RegisterInstance(ctx context.Context, id string, wait tim
 func (rdv *Rendezvous) DeregisterInstance(ctx context.Context, id string) {
 	rdv.mu.Lock()
 	delete(rdv.m, id)
+	rdv.storeDelete(id)
 	rdv.mu.Unlock()
 }
 
+// SetSession records the session that uses the connected buildlet of a
+// registered instance, for the ReconnectFunc to restore it if the
+// buildlet reconnects.
+func (rdv *Rendezvous) SetSession(ctx context.Context, id string, s *SessionInfo) error {
+	rdv.mu.Lock()
+	defer rdv.mu.Unlock()
+	if rdv.store == nil {
+		return nil
+	}
+	reg, err := rdv.store.Get(id)
+	if err != nil {
+		return fmt.Errorf("instance %q: %w", id, err)
+	}
+	reg.Session = s
+	reg.Deadline = time.Time{}
+	return rdv.store.Put(reg)
+}
+
+// DeregisterSession removes the registrations of the instances used by
+// the named session, so that their buildlets may no longer reconnect.
+func (rdv *Rendezvous) DeregisterSession(ctx context.Context, name string) {
+	rdv.mu.Lock()
+	defer rdv.mu.Unlock()
+	if rdv.store == nil {
+		return
+	}
+	regs, err := rdv.store.List()
+	if err != nil {
+		log.Printf("rendezvous: unable to list registrations: %s", err)
+		return
+	}
+	for _, reg := range regs {
+		if reg.Session != nil && reg.Session.Name == name {
+			rdv.storeDelete(reg.ID)
+		}
+	}
+}
+
 // WaitForInstance waits for the registered instance to successfully connect. It waits for the
 // lifetime of the context. If the instance is not registered or has exceeded the timeout period,
 // it will immediately return an error.
@@ -164,9 +298,12 @@ func (rdv *Rendezvous) HandleReverse(w http.ResponseWriter, r *http.Request) {
 	res, ok := rdv.m[id]
 	rdv.mu.Unlock()
 
+	var reg *Registration // if the buildlet reconnects
 	if !ok {
-		http.Error(w, "not expecting buildlet client", http.StatusPreconditionFailed)
-		return
+		if reg = rdv.reconnectable(id, hostname); reg == nil {
+			http.Error(w, "not expecting buildlet client", http.StatusPreconditionFailed)
+			return
+		}
 	}
 	if !rdv.validator(r.Context(), authToken) {
 		log.Printf("rendezvous: Unable to validate authentication token id=%s", id)
@@ -181,26 +318,108 @@ func (rdv *Rendezvous) HandleReverse(w http.ResponseWriter, r *http.Request) {
 	conn, _, err := hj.Hijack()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
-		res.ch <- &result{err: err}
+		if res != nil {
+			res.ch <- &result{err: err}
+		}
 		return
 	}
-	bc, err := connToClient(conn, hostname, "swarming_task")
+	bc, err := connToClient(conn, hostname, "swarming_task", revdial.StreamsOffered(r))
 	if err != nil {
 		log.Printf("rendezvous: unable to create buildlet client: %s", err)
 		conn.Close()
-		res.ch <- &result{err: err}
+		if res != nil {
+			res.ch <- &result{err: err}
+		}
 		return
 	}
+	if reg != nil {
+		rdv.reconnected(r.Context(), id, hostname, bc)
+		return
+	}
+	// Keep the registration for the buildlet to reconnect, until
+	// the deadline for the caller to call SetSession.
+	rdv.storePut(&Registration{ID: id, Deadline: time.Now().Add(reconnectWait), Connected: true, Hostname: hostname})
 	res.ch <- &result{bc: bc}
 }
 
-func connToClient(conn net.Conn, hostname, hostType string) (buildlet.Client, error) {
+// reconnectable returns the registration of the instance if its
+// buildlet was connected and may reconnect from hostname, or nil.
+// Only the host of the buildlet that connected first may reconnect, so
+// that other buildlets can't take over its session.
+func (rdv *Rendezvous) reconnectable(id, hostname string) *Registration {
+	if rdv.store == nil || rdv.reconnect == nil {
+		return nil
+	}
+	reg, err := rdv.store.Get(id)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			log.Printf("rendezvous: unable to look up registration for instance=%q: %s", id, err)
+		}
+		return nil
+	}
+	if !reg.Connected {
+		return nil
+	}
+	if reg.Hostname != hostname {
+		log.Printf("rendezvous: refusing to reconnect instance=%q from %s; it connected from %s", id, hostname, reg.Hostname)
+		return nil
+	}
+	return reg
+}
+
+// reconnected passes the client of the buildlet of the instance, which
+// reconnected, to the ReconnectFunc.
+func (rdv *Rendezvous) reconnected(ctx context.Context, id, hostname string, bc buildlet.Client) {
+	// Closing a client may block, so clients are only closed after
+	// rdv.mu is unlocked.
+	old, err := rdv.reconnectLocked(ctx, id, hostname, bc)
+	if err != nil {
+		log.Printf("rendezvous: unable to reconnect instance=%q: %s", id, err)
+		bc.Close()
+		return
+	}
+	log.Printf("rendezvous: instance=%q reconnected from %s", id, hostname)
+	if old != nil {
+		if err := old.Close(); err != nil {
+			log.Printf("rendezvous: unable to close previous buildlet connection of instance=%q: %s", id, err)
+		}
+	}
+}
+
+// reconnectLocked passes bc to the ReconnectFunc, with rdv.mu locked, and
+// returns the client that bc replaces, if any.
+func (rdv *Rendezvous) reconnectLocked(ctx context.Context, id, hostname string, bc buildlet.Client) (old buildlet.Client, err error) {
+	rdv.mu.Lock()
+	defer rdv.mu.Unlock()
+	// Look the registration up again, in case it was deregistered
+	// while the buildlet was connecting.
+	reg := rdv.reconnectable(id, hostname)
+	if reg == nil {
+		return nil, errors.New("deregistered while reconnecting")
+	}
+	old, err = rdv.reconnect(ctx, reg, bc)
+	if err != nil {
+		return nil, err
+	}
+	reg.Deadline = time.Time{}
+	rdv.storePut(reg)
+	return old, nil
+}
+
+// connToClient returns a client for the buildlet that registered with
+// conn. If streams is true, the buildlet offered to multiplex its
+// connections over conn.
+func connToClient(conn net.Conn, hostname, hostType string, streams bool) (buildlet.Client, error) {
 	if err := (&http.Response{StatusCode: http.StatusSwitchingProtocols, Proto: "HTTP/1.1"}).Write(conn); err != nil {
 		log.Printf("gomote: error writing upgrade response to reverse buildlet %s (%s) at %s: %v", hostname, hostType, conn.RemoteAddr(), err)
 		conn.Close()
 		return nil, err
 	}
-	revDialer := revdial.NewDialer(conn, "/revdial")
+	var opts []revdial.DialerOption
+	if streams {
+		opts = append(opts, revdial.Multiplexed())
+	}
+	revDialer := revdial.NewDialer(conn, "/revdial", opts...)
 	revDialerDone := revDialer.Done()
 	dialer := revDialer.Dial
 
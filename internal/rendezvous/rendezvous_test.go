@@ -5,13 +5,20 @@
 package rendezvous
 
 import (
+	"bufio"
 	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
+	"golang.org/x/build/buildlet"
 	"golang.org/x/build/revdial/v2"
 )
 
@@ -149,3 +156,230 @@ func TestDeregisterInstance(t *testing.T) {
 		t.Errorf("/deregusterInstance() did not remove the entry: want 0 got %d", len(rdv.m))
 	}
 }
+
+func TestWaitForInstanceStreams(t *testing.T) {
+	rdv := &Rendezvous{
+		m: make(map[string]*entry),
+		validator: func(ctx context.Context, jwt string) bool {
+			return true
+		},
+	}
+	instanceID := "test-id-4"
+	ctx := context.Background()
+	rdv.RegisterInstance(ctx, instanceID, 15*time.Second)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/reverse", rdv.HandleReverse)
+	ts := httptest.NewTLSServer(mux)
+	defer ts.Close()
+
+	// Register like a buildlet that offers streams, which then
+	// doesn't need the /revdial endpoint.
+	conn, err := tls.Dial("tcp", ts.Listener.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest("GET", "/reverse", nil)
+	req.Header.Set(HeaderID, instanceID)
+	req.Header.Set(HeaderToken, "test-token")
+	req.Header.Set(HeaderHostname, "test-hostname")
+	revdial.OfferStreams(req)
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(conn)
+	if loc, err := revdial.ReadProtoSwitchOrRedirect(br, req); err != nil || loc != "" {
+		t.Fatalf("ReadProtoSwitchOrRedirect() = %q, %v; want a protocol switch", loc, err)
+	}
+	ln := revdial.NewListener(revdial.BufferedConn(conn, br), func(context.Context) (net.Conn, error) {
+		return nil, errors.New("dialing back with streams")
+	})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"Version": 35}`)
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	bc, err := rdv.WaitForInstance(ctx, instanceID)
+	if err != nil {
+		t.Fatalf("WaitForInstance() = _, %v; want no error", err)
+	}
+	bc.Close()
+}
+
+// connectBuildlet registers like a buildlet on hostname with the instance
+// ID at the TLS server ts, and serves its status until the end of the
+// test.
+func connectBuildlet(t *testing.T, ts *httptest.Server, id, hostname string) error {
+	conn, err := tls.Dial("tcp", ts.Listener.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest("GET", "/reverse", nil)
+	req.Header.Set(HeaderID, id)
+	req.Header.Set(HeaderToken, "test-token")
+	req.Header.Set(HeaderHostname, hostname)
+	revdial.OfferStreams(req)
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(conn)
+	if _, err := revdial.ReadProtoSwitchOrRedirect(br, req); err != nil {
+		conn.Close()
+		return err
+	}
+	ln := revdial.NewListener(revdial.BufferedConn(conn, br), func(context.Context) (net.Conn, error) {
+		return nil, errors.New("dialing back with streams")
+	})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"Version": 35}`)
+	})}
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Close() })
+	return nil
+}
+
+func TestReconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	file := filepath.Join(t.TempDir(), "registrations.json")
+	validator := OptionValidator(func(ctx context.Context, jwt string) bool { return true })
+	reconnected := make(chan *Registration, 1)
+	reconnect := OptionReconnect(func(ctx context.Context, reg *Registration, bc buildlet.Client) (buildlet.Client, error) {
+		bc.Close()
+		reconnected <- reg
+		return nil, nil
+	})
+	newRendezvous := func() (*Rendezvous, *httptest.Server) {
+		store, err := NewFileStore(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rdv := New(ctx, validator, reconnect, OptionStore(store))
+		mux := http.NewServeMux()
+		mux.HandleFunc("/reverse", rdv.HandleReverse)
+		ts := httptest.NewTLSServer(mux)
+		t.Cleanup(ts.Close)
+		return rdv, ts
+	}
+
+	rdv, ts := newRendezvous()
+	const id = "test-id-5"
+	rdv.RegisterInstance(ctx, id, time.Minute)
+	rdv.RegisterInstance(ctx, "pending", time.Minute)
+	if err := connectBuildlet(t, ts, id, "test-hostname"); err != nil {
+		t.Fatal(err)
+	}
+	bc, err := rdv.WaitForInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("WaitForInstance() = _, %v; want no error", err)
+	}
+	bc.Close()
+	session := &SessionInfo{Name: "user-linux-amd64-0", OwnerID: "owner", User: "user", BuilderType: "linux-amd64"}
+	if err := rdv.SetSession(ctx, id, session); err != nil {
+		t.Fatal(err)
+	}
+
+	// After a restart, the buildlet reconnects to its session,
+	// and the pending registration is gone.
+	rdv, ts = newRendezvous()
+	if reg, err := rdv.store.Get(id); err != nil || reg.Deadline.IsZero() {
+		t.Errorf("restored registration = %+v, %v; want a deadline to reconnect", reg, err)
+	}
+	if _, err := rdv.store.Get("pending"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("pending registration after restart: %v; want ErrNotFound", err)
+	}
+	// Another host may not take over the session.
+	var se *revdial.StatusError
+	if err := connectBuildlet(t, ts, id, "other-hostname"); !errors.As(err, &se) || se.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("reconnecting from another host = %v; want status 412", err)
+	}
+	if err := connectBuildlet(t, ts, id, "test-hostname"); err != nil {
+		t.Fatalf("reconnecting: %v", err)
+	}
+	if reg := <-reconnected; reg.ID != id || reg.Session == nil || reg.Session.Name != session.Name {
+		t.Errorf("ReconnectFunc called with %+v; want the registration with session %+v", reg, session)
+	}
+	if reg, err := rdv.store.Get(id); err != nil || !reg.Deadline.IsZero() {
+		t.Errorf("registration after reconnecting = %+v, %v; want no deadline", reg, err)
+	}
+
+	// Once its session is gone, the buildlet may not reconnect.
+	rdv.DeregisterSession(ctx, session.Name)
+	if err := connectBuildlet(t, ts, id, "test-hostname"); !errors.As(err, &se) || se.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("reconnecting after DeregisterSession = %v; want status 412", err)
+	}
+}
+
+// closeFuncClient is a buildlet client that calls a function when closed.
+type closeFuncClient struct {
+	buildlet.Client
+	close func()
+}
+
+func (c *closeFuncClient) Close() error {
+	c.close()
+	return nil
+}
+
+func TestReconnectedClosesOldUnlocked(t *testing.T) {
+	var rdv *Rendezvous
+	closed := false
+	old := &closeFuncClient{close: func() {
+		closed = true
+		if !rdv.mu.TryLock() {
+			t.Errorf("previous client closed with the Rendezvous locked")
+			return
+		}
+		rdv.mu.Unlock()
+	}}
+	rdv = &Rendezvous{
+		m:     make(map[string]*entry),
+		store: NewMemoryStore(),
+		reconnect: func(ctx context.Context, reg *Registration, bc buildlet.Client) (buildlet.Client, error) {
+			return old, nil
+		},
+	}
+	rdv.store.Put(&Registration{ID: "id", Connected: true, Deadline: time.Now().Add(time.Minute), Hostname: "host"})
+	rdv.reconnected(context.Background(), "id", "host", &buildlet.FakeClient{})
+	if !closed {
+		t.Errorf("previous client not closed")
+	}
+	if reg, err := rdv.store.Get("id"); err != nil || !reg.Deadline.IsZero() || reg.Hostname != "host" {
+		t.Errorf("registration after reconnecting = %+v, %v; want no deadline and hostname %q", reg, err, "host")
+	}
+}
+
+func TestReconnectedOtherHost(t *testing.T) {
+	rdv := &Rendezvous{
+		m:     make(map[string]*entry),
+		store: NewMemoryStore(),
+		reconnect: func(ctx context.Context, reg *Registration, bc buildlet.Client) (buildlet.Client, error) {
+			t.Errorf("ReconnectFunc called for a buildlet on another host")
+			return nil, nil
+		},
+	}
+	rdv.store.Put(&Registration{ID: "id", Connected: true, Hostname: "host"})
+	closed := false
+	rdv.reconnected(context.Background(), "id", "other-host", &closeFuncClient{close: func() { closed = true }})
+	if !closed {
+		t.Errorf("client of the buildlet on another host not closed")
+	}
+	if reg, err := rdv.store.Get("id"); err != nil || reg.Hostname != "host" {
+		t.Errorf("registration after reconnecting from another host = %+v, %v; want hostname %q", reg, err, "host")
+	}
+}
+
+func TestPurgeExpiredReconnects(t *testing.T) {
+	rdv := &Rendezvous{
+		m:     make(map[string]*entry),
+		store: NewMemoryStore(),
+	}
+	rdv.store.Put(&Registration{ID: "expired", Connected: true, Deadline: time.Unix(0, 0)})
+	rdv.store.Put(&Registration{ID: "waiting", Connected: true, Deadline: time.Now().Add(time.Minute)})
+	rdv.store.Put(&Registration{ID: "session", Connected: true})
+	rdv.purgeExpiredRegistrations()
+	regs, _ := rdv.store.List()
+	if len(regs) != 2 || regs[0].ID != "session" || regs[1].ID != "waiting" {
+		t.Errorf("registrations after purgeExpiredRegistrations() = %+v; want session and waiting", regs)
+	}
+}
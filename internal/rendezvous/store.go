@@ -0,0 +1,196 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rendezvous
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Registration is the persistent state of an instance registration.
+type Registration struct {
+	ID string // the instance ID, which the buildlet sends in HeaderID
+
+	// Deadline, if non-zero, is when to drop the registration: the end
+	// of the wait for the buildlet to connect, for the caller to set
+	// its session, or for it to reconnect after a restart.
+	Deadline time.Time
+
+	// Connected reports whether the buildlet has connected, so that it
+	// may reconnect.
+	Connected bool
+	Hostname  string // of the connected buildlet
+
+	// Session is the session that uses the connected buildlet,
+	// if any.
+	Session *SessionInfo `json:",omitempty"`
+}
+
+// SessionInfo describes the session that uses a connected buildlet,
+// so that it can be restored when the buildlet reconnects.
+type SessionInfo struct {
+	Name        string
+	OwnerID     string
+	User        string
+	BuilderType string
+	HostType    string
+	Created     time.Time
+}
+
+// ErrNotFound is returned by a Store for an unknown registration.
+var ErrNotFound = errors.New("registration not found")
+
+// A Store persists registrations.
+// Its methods may be called concurrently.
+type Store interface {
+	// Get returns the registration with the ID, or ErrNotFound.
+	Get(id string) (*Registration, error)
+	// Put adds or replaces the registration with the ID of reg.
+	Put(reg *Registration) error
+	// Delete deletes the registration with the ID, if any.
+	Delete(id string) error
+	// List returns all registrations, sorted by ID.
+	List() ([]*Registration, error)
+}
+
+// memoryStore is a Store that keeps registrations in memory.
+type memoryStore struct {
+	mu sync.Mutex
+	m  map[string]*Registration
+}
+
+// NewMemoryStore returns a Store that keeps registrations in memory,
+// which doesn't persist them across restarts.
+func NewMemoryStore() Store {
+	return &memoryStore{m: make(map[string]*Registration)}
+}
+
+func (s *memoryStore) Get(id string) (*Registration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reg, ok := s.m[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return reg.clone(), nil
+}
+
+func (s *memoryStore) Put(reg *Registration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[reg.ID] = reg.clone()
+	return nil
+}
+
+func (s *memoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, id)
+	return nil
+}
+
+func (s *memoryStore) List() ([]*Registration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	regs := make([]*Registration, 0, len(s.m))
+	for _, reg := range s.m {
+		regs = append(regs, reg.clone())
+	}
+	sort.Slice(regs, func(i, j int) bool { return regs[i].ID < regs[j].ID })
+	return regs, nil
+}
+
+// clone returns a deep copy of reg.
+func (reg *Registration) clone() *Registration {
+	c := *reg
+	if reg.Session != nil {
+		s := *reg.Session
+		c.Session = &s
+	}
+	return &c
+}
+
+// fileStore is a Store that keeps registrations in memory and in a
+// JSON file.
+type fileStore struct {
+	memoryStore
+	file string
+}
+
+// NewFileStore returns a Store that persists registrations in the
+// named JSON file, starting with those that the file already has, if
+// it exists. Each change rewrites the file.
+func NewFileStore(file string) (Store, error) {
+	s := &fileStore{
+		memoryStore: memoryStore{m: make(map[string]*Registration)},
+		file:        file,
+	}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var regs []*Registration
+	if err := json.Unmarshal(data, &regs); err != nil {
+		return nil, fmt.Errorf("reading registrations from %s: %w", file, err)
+	}
+	for _, reg := range regs {
+		s.m[reg.ID] = reg
+	}
+	return s, nil
+}
+
+func (s *fileStore) Put(reg *Registration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[reg.ID] = reg.clone()
+	return s.write()
+}
+
+func (s *fileStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.m[id]; !ok {
+		return nil
+	}
+	delete(s.m, id)
+	return s.write()
+}
+
+// write writes the registrations to the file, atomically replacing it.
+// The store lock must be held.
+func (s *fileStore) write() error {
+	regs := make([]*Registration, 0, len(s.m))
+	for _, reg := range s.m {
+		regs = append(regs, reg)
+	}
+	sort.Slice(regs, func(i, j int) bool { return regs[i].ID < regs[j].ID })
+	data, err := json.MarshalIndent(regs, "", "\t")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.file), filepath.Base(s.file)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), s.file)
+}
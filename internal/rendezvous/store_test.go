@@ -0,0 +1,88 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rendezvous
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testStore(t *testing.T, s Store) {
+	t.Helper()
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	regs := []*Registration{
+		{ID: "b", Deadline: created.Add(time.Minute)},
+		{ID: "a", Connected: true, Hostname: "host-a", Session: &SessionInfo{Name: "user-linux-amd64-0", OwnerID: "owner", Created: created}},
+	}
+	for _, reg := range regs {
+		if err := s.Put(reg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The store keeps copies.
+	regs[1].Session.Name = "changed"
+
+	got, err := s.Get("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Session == nil || got.Session.Name != "user-linux-amd64-0" || !got.Session.Created.Equal(created) || !got.Connected {
+		t.Errorf("Get(a) = %+v; want the registration that was put", got)
+	}
+	if _, err := s.Get("c"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(c) = %v; want ErrNotFound", err)
+	}
+	list, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != "a" || list[1].ID != "b" {
+		t.Errorf("List() = %+v; want a and b", list)
+	}
+	if err := s.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("missing"); err != nil {
+		t.Errorf("Delete(missing) = %v; want no error", err)
+	}
+	if list, _ := s.List(); len(list) != 1 {
+		t.Errorf("List() after Delete = %+v; want 1 registration", list)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "registrations.json")
+	s, err := NewFileStore(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+
+	// A new store of the same file has the same registrations.
+	want, _ := s.List()
+	s2, err := NewFileStore(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s2.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, reg := range append(got, want...) {
+		if reg.Session != nil {
+			reg.Session.Created = reg.Session.Created.UTC()
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reopened store has %+v; want %+v", got, want)
+	}
+}
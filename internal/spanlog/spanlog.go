@@ -26,3 +26,18 @@ type Span interface {
 	// The err is returned unmodified for convenience at callsites.
 	Done(err error) error
 }
+
+// An Annotator is a Span that can record details about its event
+// learned while the event was in progress.
+type Annotator interface {
+	// Annotate adds text to the span's details.
+	// It must be called before Done.
+	Annotate(text string)
+}
+
+// Annotate adds text to the details of sp, if sp is an Annotator.
+func Annotate(sp Span, text string) {
+	if a, ok := sp.(Annotator); ok {
+		a.Annotate(text)
+	}
+}
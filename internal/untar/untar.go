@@ -14,132 +14,516 @@ import (
 	"io/fs"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
-	"runtime"
 	"strings"
 	"time"
 )
 
-// TODO(bradfitz): this was copied from x/build/cmd/buildlet/buildlet.go
-// but there were some buildlet-specific bits in there, so the code is
-// forked for now.  Unfork and add some opts arguments here, so the
-// buildlet can use this code somehow.
+// Options configures how an archive is extracted.
+// The zero value extracts any number of files of any size,
+// rejects links, preserves permissions, and replaces existing files.
+type Options struct {
+	// MaxBytes, if positive, is the maximum total size of the
+	// regular files in the archive.
+	MaxBytes int64
 
-// Untar reads the gzip-compressed tar file from r and writes it into dir.
+	// MaxFiles, if positive, is the maximum number of entries
+	// in the archive.
+	MaxFiles int
+
+	// MaxDepth, if positive, is the maximum number of elements of
+	// the names of the entries in the archive.
+	MaxDepth int
+
+	// Symlinks and HardLinks are what to do with symbolic and hard
+	// links in the archive.
+	Symlinks  LinkPolicy
+	HardLinks LinkPolicy
+
+	// DropPerms makes regular files 0755 if any of their execute bits
+	// is set in the archive and 0644 otherwise, instead of the
+	// permissions in the archive.
+	DropPerms bool
+
+	// Overwrite is what to do with entries that already exist on disk.
+	Overwrite OverwritePolicy
+}
+
+// A LinkPolicy is what to do with links in an archive.
+type LinkPolicy int
+
+const (
+	// LinkReject fails the extraction.
+	LinkReject LinkPolicy = iota
+
+	// LinkSkip doesn't create the link.
+	LinkSkip
+
+	// LinkContain creates the link if it refers to something within
+	// the destination directory, and fails the extraction otherwise.
+	// A contained symbolic link has a relative target with no ".."
+	// elements but leading ones, and doesn't leave the directory
+	// when it is resolved as it is created.
+	LinkContain
+
+	// LinkAllow creates the link. The target of a hard link is an
+	// entry of the archive, so for hard links it is the same as
+	// LinkContain.
+	LinkAllow
+)
+
+// An OverwritePolicy is what to do with an entry of an archive that
+// already exists on disk. Existing directories are always reused for
+// directories of the archive, and never replaced by anything else.
+type OverwritePolicy int
+
+const (
+	// OverwriteReplace replaces the existing file.
+	OverwriteReplace OverwritePolicy = iota
+
+	// OverwriteSkip keeps the existing file, and skips the entry.
+	OverwriteSkip
+
+	// OverwriteReject fails the extraction.
+	OverwriteReject
+)
+
+// Errors that an extraction may fail with, wrapped in an *Error.
+var (
+	ErrMalformed   = errors.New("malformed archive")
+	ErrInvalidName = errors.New("invalid name")
+	ErrUnsupported = errors.New("unsupported file type")
+	ErrLink        = errors.New("link not allowed")
+	ErrExists      = errors.New("file exists")
+	ErrLimit       = errors.New("limit exceeded")
+)
+
+// An Error is an error extracting an archive.
+type Error struct {
+	Name string // of the entry, or empty if the error isn't about one
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Name == "" {
+		return "untar: " + e.Err.Error()
+	}
+	return fmt.Sprintf("untar: %s: %v", e.Name, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Rejected reports whether the extraction failed because of the
+// archive, rather than because of a failure to write it to disk:
+// the archive is malformed, or not allowed by the Options.
+func (e *Error) Rejected() bool {
+	for _, err := range []error{ErrMalformed, ErrInvalidName, ErrUnsupported, ErrLink, ErrExists, ErrLimit} {
+		if errors.Is(e.Err, err) {
+			return true
+		}
+	}
+	return false
+}
+
+// Untar reads the gzip-compressed tar file from r and writes it into dir,
+// with the default Options.
 func Untar(r io.Reader, dir string) error {
-	return untar(r, dir)
+	return UntarWithOptions(r, dir, nil)
 }
 
-func untar(r io.Reader, dir string) (err error) {
-	t0 := time.Now()
-	nFiles := 0
-	madeDir := map[string]bool{}
+// UntarWithOptions reads the gzip-compressed tar file from r and writes
+// it into dir, as configured by opts, which may be nil.
+func UntarWithOptions(r io.Reader, dir string, opts *Options) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return &Error{Err: fmt.Errorf("%w: requires gzip-compressed body: %v", ErrMalformed, err)}
+	}
+	return Extract(zr, dir, opts)
+}
+
+// Extract reads the uncompressed tar file from r and writes it into dir,
+// as configured by opts, which may be nil. Any error is an *Error.
+func Extract(r io.Reader, dir string, opts *Options) (err error) {
+	if opts == nil {
+		opts = new(Options)
+	}
+	x := &extractor{
+		opts:       opts,
+		dir:        dir,
+		t0:         time.Now(),
+		madeDir:    map[string]bool{},
+		checkedDir: map[string]bool{},
+	}
 	defer func() {
-		td := time.Since(t0)
+		td := time.Since(x.t0)
 		if err == nil {
-			log.Printf("extracted tarball into %s: %d files, %d dirs (%v)", dir, nFiles, len(madeDir), td)
+			log.Printf("extracted tarball into %s: %d files, %d dirs (%v)", dir, x.nFiles, len(x.madeDir), td)
 		} else {
-			log.Printf("error extracting tarball into %s after %d files, %d dirs, %v: %v", dir, nFiles, len(madeDir), td, err)
+			log.Printf("error extracting tarball into %s after %d files, %d dirs, %v: %v", dir, x.nFiles, len(x.madeDir), td, err)
 		}
 	}()
-	zr, err := gzip.NewReader(r)
-	if err != nil {
-		return fmt.Errorf("requires gzip-compressed body: %v", err)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return &Error{Err: err}
+	}
+	if x.realDir, err = filepath.EvalSymlinks(dir); err != nil {
+		return &Error{Err: err}
 	}
-	tr := tar.NewReader(zr)
-	loggedChtimesError := false
+	tr := tar.NewReader(r)
 	for {
 		f, err := tr.Next()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			log.Printf("tar reading error: %v", err)
-			return fmt.Errorf("tar error: %v", err)
-		}
-		if !validRelPath(f.Name) {
-			return fmt.Errorf("tar contained invalid name error %q", f.Name)
-		}
-		rel := filepath.FromSlash(f.Name)
-		abs := filepath.Join(dir, rel)
-
-		mode := f.FileInfo().Mode()
-		switch f.Typeflag {
-		case tar.TypeReg:
-			// Make the directory. This is redundant because it should
-			// already be made by a directory entry in the tar
-			// beforehand. Thus, don't check for errors; the next
-			// write will fail with the same error.
-			dir := filepath.Dir(abs)
-			if !madeDir[dir] {
-				if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
-					return err
-				}
-				madeDir[dir] = true
-			}
-			if runtime.GOOS == "darwin" && mode&0111 != 0 {
-				// The darwin kernel caches binary signatures
-				// and SIGKILLs binaries with mismatched
-				// signatures. Overwriting a binary with
-				// O_TRUNC does not clear the cache, rendering
-				// the new copy unusable. Removing the original
-				// file first does clear the cache. See #54132.
-				err := os.Remove(abs)
-				if err != nil && !errors.Is(err, fs.ErrNotExist) {
-					return err
-				}
-			}
-			wf, err := os.OpenFile(abs, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode.Perm())
-			if err != nil {
-				return err
-			}
-			n, err := io.Copy(wf, tr)
-			if closeErr := wf.Close(); closeErr != nil && err == nil {
-				err = closeErr
-			}
-			if err != nil {
-				return fmt.Errorf("error writing to %s: %v", abs, err)
-			}
-			if n != f.Size {
-				return fmt.Errorf("only wrote %d bytes to %s; expected %d", n, abs, f.Size)
-			}
-			modTime := f.ModTime
-			if modTime.After(t0) {
-				// Clamp modtimes at system time. See
-				// golang.org/issue/19062 when clock on
-				// buildlet was behind the gitmirror server
-				// doing the git-archive.
-				modTime = t0
-			}
-			if !modTime.IsZero() {
-				if err := os.Chtimes(abs, modTime, modTime); err != nil && !loggedChtimesError {
-					// benign error. Gerrit doesn't even set the
-					// modtime in these, and we don't end up relying
-					// on it anywhere (the gomote push command relies
-					// on digests only), so this is a little pointless
-					// for now.
-					log.Printf("error changing modtime: %v (further Chtimes errors suppressed)", err)
-					loggedChtimesError = true // once is enough
-				}
-			}
-			nFiles++
-		case tar.TypeDir:
-			if err := os.MkdirAll(abs, 0755); err != nil {
-				return err
-			}
-			madeDir[abs] = true
-		case tar.TypeXGlobalHeader:
-			// git archive generates these. Ignore them.
-		default:
-			return fmt.Errorf("tar file entry %s contained unsupported file type %v", f.Name, mode)
+			return &Error{Err: fmt.Errorf("%w: %v", ErrMalformed, err)}
+		}
+		if err := x.extract(tr, f); err != nil {
+			return &Error{Name: f.Name, Err: err}
 		}
 	}
 	return nil
 }
 
-func validRelPath(p string) bool {
-	if p == "" || strings.Contains(p, `\`) || strings.HasPrefix(p, "/") || strings.Contains(p, "../") {
+// An extractor extracts the entries of one archive.
+type extractor struct {
+	opts    *Options
+	dir     string
+	realDir string // dir, with symbolic links resolved
+	t0      time.Time
+
+	nEntries int
+	nFiles   int
+	nBytes   int64
+	madeDir  map[string]bool
+	// checkedDir are the directories that are known to be within
+	// realDir when resolved.
+	checkedDir         map[string]bool
+	loggedChtimesError bool
+}
+
+// extract extracts the entry f, whose content tr reads.
+func (x *extractor) extract(tr *tar.Reader, f *tar.Header) error {
+	if f.Typeflag == tar.TypeXGlobalHeader {
+		// golang.org/issue/22748: git archive exports
+		// a global header ('g') which after Go 1.9
+		// (for a bit?) contained an empty filename.
+		// Ignore it.
+		return nil
+	}
+	rel, err := nativeRelPath(f.Name)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidName, err)
+	}
+	if rel == "." {
+		// The destination directory itself, from something like
+		// "tar -C dir -c .".
+		return nil
+	}
+	abs := filepath.Join(x.dir, rel)
+
+	if x.nEntries++; x.opts.MaxFiles > 0 && x.nEntries > x.opts.MaxFiles {
+		return fmt.Errorf("%w: more than %d entries", ErrLimit, x.opts.MaxFiles)
+	}
+	if depth := strings.Count(rel, string(filepath.Separator)) + 1; x.opts.MaxDepth > 0 && depth > x.opts.MaxDepth {
+		return fmt.Errorf("%w: depth %d exceeds %d", ErrLimit, depth, x.opts.MaxDepth)
+	}
+
+	switch f.Typeflag {
+	case tar.TypeReg:
+		if x.nBytes += f.Size; x.opts.MaxBytes > 0 && x.nBytes > x.opts.MaxBytes {
+			return fmt.Errorf("%w: more than %d bytes", ErrLimit, x.opts.MaxBytes)
+		}
+		return x.writeFile(tr, f, abs)
+	case tar.TypeDir:
+		if err := x.checkParent(abs); err != nil {
+			return err
+		}
+		if fi, err := os.Lstat(abs); err == nil && !fi.IsDir() {
+			return fmt.Errorf("%w: not a directory", ErrExists)
+		}
+		if err := os.MkdirAll(abs, 0755); err != nil {
+			return err
+		}
+		x.madeDir[abs] = true
+		return nil
+	case tar.TypeSymlink:
+		return x.symlink(f, abs)
+	case tar.TypeLink:
+		return x.link(f, abs)
+	}
+	return fmt.Errorf("%w %v", ErrUnsupported, f.FileInfo().Mode().Type())
+}
+
+// writeFile writes the regular file f, whose content tr reads, to abs.
+func (x *extractor) writeFile(tr *tar.Reader, f *tar.Header, abs string) error {
+	// Make the directory. This is redundant because it should
+	// already be made by a directory entry in the tar
+	// beforehand.
+	dir := filepath.Dir(abs)
+	if !x.madeDir[dir] {
+		if err := x.checkParent(abs); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		x.madeDir[dir] = true
+	}
+	if ok, err := x.prepare(abs); !ok {
+		return err
+	}
+
+	mode := f.FileInfo().Mode()
+	perm := mode.Perm()
+	if x.opts.DropPerms {
+		perm = 0644
+		if mode&0111 != 0 {
+			perm = 0755
+		}
+	}
+	wf, err := os.OpenFile(abs, os.O_RDWR|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(wf, tr)
+	if closeErr := wf.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error writing to %s: %v", abs, err)
+	}
+	if n != f.Size {
+		return fmt.Errorf("only wrote %d bytes to %s; expected %d", n, abs, f.Size)
+	}
+	modTime := f.ModTime
+	if modTime.After(x.t0) {
+		// Clamp modtimes at system time. See
+		// golang.org/issue/19062 when clock on
+		// buildlet was behind the gitmirror server
+		// doing the git-archive.
+		modTime = x.t0
+	}
+	if !modTime.IsZero() {
+		if err := os.Chtimes(abs, modTime, modTime); err != nil && !x.loggedChtimesError {
+			// benign error. Gerrit doesn't even set the
+			// modtime in these, and we don't end up relying
+			// on it anywhere (the gomote push command relies
+			// on digests only), so this is a little pointless
+			// for now.
+			log.Printf("error changing modtime: %v (further Chtimes errors suppressed)", err)
+			x.loggedChtimesError = true // once is enough
+		}
+	}
+	x.nFiles++
+	return nil
+}
+
+// symlink creates the symbolic link f at abs.
+func (x *extractor) symlink(f *tar.Header, abs string) error {
+	switch x.opts.Symlinks {
+	case LinkSkip:
+		return nil
+	case LinkContain, LinkAllow:
+	default:
+		return fmt.Errorf("%w: symbolic link to %s", ErrLink, f.Linkname)
+	}
+	if err := x.mkdirParent(abs); err != nil {
+		return err
+	}
+	target := filepath.FromSlash(f.Linkname)
+	if x.opts.Symlinks == LinkContain && !x.contained(filepath.Dir(abs), target) {
+		return fmt.Errorf("%w: symbolic link to %s leaves the directory", ErrLink, f.Linkname)
+	}
+	if ok, err := x.prepare(abs); !ok {
+		return err
+	}
+	return os.Symlink(target, abs)
+}
+
+// link creates the hard link f at abs.
+func (x *extractor) link(f *tar.Header, abs string) error {
+	switch x.opts.HardLinks {
+	case LinkSkip:
+		return nil
+	case LinkContain, LinkAllow:
+	default:
+		return fmt.Errorf("%w: hard link to %s", ErrLink, f.Linkname)
+	}
+	rel, err := nativeRelPath(f.Linkname)
+	if err != nil {
+		return fmt.Errorf("%w: hard link to %s: %v", ErrLink, f.Linkname, err)
+	}
+	old := filepath.Join(x.dir, rel)
+	if err := x.checkParent(old); err != nil {
+		return err
+	}
+	if fi, err := os.Lstat(old); err != nil || !fi.Mode().IsRegular() {
+		return fmt.Errorf("%w: hard link to %s, which is not a regular file in the directory", ErrLink, f.Linkname)
+	}
+	if err := x.mkdirParent(abs); err != nil {
+		return err
+	}
+	if ok, err := x.prepare(abs); !ok {
+		return err
+	}
+	if err := os.Link(old, abs); err != nil {
+		return err
+	}
+	x.nFiles++
+	return nil
+}
+
+// mkdirParent makes the parent directory of abs.
+func (x *extractor) mkdirParent(abs string) error {
+	if err := x.checkParent(abs); err != nil {
+		return err
+	}
+	dir := filepath.Dir(abs)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	x.madeDir[dir] = true
+	return nil
+}
+
+// prepare prepares for a new file at abs according to the
+// OverwritePolicy, removing any existing one. It reports whether
+// to go ahead, and the error if not, if any.
+func (x *extractor) prepare(abs string) (bool, error) {
+	fi, err := os.Lstat(abs)
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if fi.IsDir() {
+		return false, fmt.Errorf("%w: is a directory", ErrExists)
+	}
+	switch x.opts.Overwrite {
+	case OverwriteSkip:
+		return false, nil
+	case OverwriteReject:
+		return false, ErrExists
+	}
+	// Remove the file rather than writing through it, in case it's
+	// a link. This also matters for binaries on darwin, whose kernel
+	// caches binary signatures and SIGKILLs binaries with mismatched
+	// signatures. Overwriting a binary with O_TRUNC does not clear
+	// the cache, rendering the new copy unusable. Removing the
+	// original file first does clear the cache. See #54132.
+	if err := os.Remove(abs); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checkParent checks that the parent directory of abs, as far as it
+// exists, is within the destination directory when links are resolved,
+// so that no entry is written through a link out of it.
+func (x *extractor) checkParent(abs string) error {
+	dir := filepath.Dir(abs)
+	if x.checkedDir[dir] {
+		return nil
+	}
+	if !x.contained(x.dir, mustRel(x.dir, dir)) {
+		return fmt.Errorf("%w: parent directory leaves the directory", ErrLink)
+	}
+	x.checkedDir[dir] = true
+	return nil
+}
+
+// contained reports whether the relative path rel, from the directory
+// from, stays within the destination directory when it's resolved
+// element by element, as far as it exists. Only leading ".." elements
+// are allowed, so that the result doesn't depend on the type of
+// what's created later.
+func (x *extractor) contained(from, rel string) bool {
+	if filepath.IsAbs(rel) || filepath.VolumeName(rel) != "" || strings.HasPrefix(rel, string(filepath.Separator)) {
 		return false
 	}
+	cur, err := filepath.EvalSymlinks(from)
+	if err != nil {
+		return false
+	}
+	leading := true
+	for _, elem := range strings.Split(rel, string(filepath.Separator)) {
+		switch elem {
+		case "", ".":
+			continue
+		case "..":
+			if !leading {
+				return false
+			}
+			cur = filepath.Dir(cur)
+		default:
+			leading = false
+			cur = filepath.Join(cur, elem)
+			if fi, err := os.Lstat(cur); err == nil && fi.Mode()&fs.ModeSymlink != 0 {
+				if cur, err = filepath.EvalSymlinks(cur); err != nil {
+					return false
+				}
+			}
+		}
+		if !within(x.realDir, cur) {
+			return false
+		}
+	}
 	return true
 }
+
+// within reports whether the clean path p is dir or within it.
+func within(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// mustRel returns the path of targ relative to the directory base,
+// which contains it.
+func mustRel(base, targ string) string {
+	rel, err := filepath.Rel(base, targ)
+	if err != nil {
+		panic(err)
+	}
+	return rel
+}
+
+// nativeRelPath verifies that p is a non-empty relative path
+// using either slashes or the buildlet's native path separator,
+// and returns it canonicalized to the native path separator.
+func nativeRelPath(p string) (string, error) {
+	if p == "" {
+		return "", errors.New("path not provided")
+	}
+
+	if filepath.Separator != '/' && strings.Contains(p, string(filepath.Separator)) {
+		clean := filepath.Clean(p)
+		if filepath.IsAbs(clean) {
+			return "", fmt.Errorf("path %q is not relative", p)
+		}
+		if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("path %q refers to a parent directory", p)
+		}
+		if strings.HasPrefix(p, string(filepath.Separator)) || filepath.VolumeName(clean) != "" {
+			// On Windows, this catches semi-relative paths like "C:" (meaning “the
+			// current working directory on volume C:”) and "\windows" (meaning “the
+			// windows subdirectory of the current drive letter”).
+			return "", fmt.Errorf("path %q is relative to volume", p)
+		}
+		return clean, nil
+	}
+
+	clean := path.Clean(p)
+	if path.IsAbs(clean) {
+		return "", fmt.Errorf("path %q is not relative", p)
+	}
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("path %q refers to a parent directory", p)
+	}
+	canon := filepath.FromSlash(clean)
+	if filepath.VolumeName(canon) != "" {
+		return "", fmt.Errorf("path %q begins with a native volume name", p)
+	}
+	return canon, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package untar
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// An entry is an entry of a test archive.
+type entry struct {
+	name     string
+	typ      byte
+	mode     int64
+	content  string
+	linkname string
+}
+
+func file(name, content string) entry {
+	return entry{name: name, typ: tar.TypeReg, mode: 0644, content: content}
+}
+func dir(name string) entry { return entry{name: name, typ: tar.TypeDir, mode: 0755} }
+func symlink(name, target string) entry {
+	return entry{name: name, typ: tar.TypeSymlink, mode: 0777, linkname: target}
+}
+func hardlink(name, target string) entry {
+	return entry{name: name, typ: tar.TypeLink, linkname: target}
+}
+
+// tarball returns an uncompressed tar file of the entries.
+func tarball(t *testing.T, entries ...entry) *bytes.Buffer {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		h := &tar.Header{Name: e.name, Typeflag: e.typ, Mode: e.mode, Size: int64(len(e.content)), Linkname: e.linkname}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestUntar(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(tarball(t, dir("a"), file("a/b.txt", "hello"), file("c/d.txt", "world")).Bytes())
+	zw.Close()
+
+	d := t.TempDir()
+	if err := Untar(&buf, d); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"a/b.txt": "hello", "c/d.txt": "world"} {
+		if got, err := os.ReadFile(filepath.Join(d, name)); err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", name, got, err, want)
+		}
+	}
+
+	err := Untar(bytes.NewReader([]byte("not gzip")), d)
+	if ue := (*Error)(nil); !errors.As(err, &ue) || !ue.Rejected() || !errors.Is(err, ErrMalformed) {
+		t.Errorf("Untar of a non-gzip file = %v; want a rejected ErrMalformed *Error", err)
+	}
+}
+
+func TestExtractErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []entry
+		opts    Options
+		want    error
+	}{
+		{"absolute", []entry{file("/etc/passwd", "x")}, Options{}, ErrInvalidName},
+		{"parent", []entry{file("a/../../x", "x")}, Options{}, ErrInvalidName},
+		{"fifo", []entry{{name: "p", typ: tar.TypeFifo}}, Options{}, ErrUnsupported},
+		{"max bytes", []entry{file("a", "1234"), file("b", "5678")}, Options{MaxBytes: 6}, ErrLimit},
+		{"max files", []entry{dir("a"), file("a/b", ""), file("c", "")}, Options{MaxFiles: 2}, ErrLimit},
+		{"max depth", []entry{file("a/b/c", "")}, Options{MaxDepth: 2}, ErrLimit},
+		{"symlink rejected", []entry{symlink("l", "x")}, Options{}, ErrLink},
+		{"hard link rejected", []entry{file("f", ""), hardlink("l", "f")}, Options{}, ErrLink},
+		{"symlink out", []entry{symlink("l", "../x")}, Options{Symlinks: LinkContain}, ErrLink},
+		{"absolute symlink", []entry{symlink("l", "/etc")}, Options{Symlinks: LinkContain}, ErrLink},
+		{"symlink inner parent", []entry{symlink("l", "a/../b")}, Options{Symlinks: LinkContain}, ErrLink},
+		{"symlink through symlink", []entry{dir("d"), symlink("d/s", ".."), symlink("d/s/l", "../x")}, Options{Symlinks: LinkContain}, ErrLink},
+		{"write through symlink", []entry{symlink("l", "/tmp"), file("l/x", "x")}, Options{Symlinks: LinkAllow}, ErrLink},
+		{"hard link out", []entry{hardlink("l", "../x")}, Options{HardLinks: LinkAllow}, ErrLink},
+		{"hard link to dir", []entry{dir("d"), hardlink("l", "d")}, Options{HardLinks: LinkContain}, ErrLink},
+		{"file over dir", []entry{dir("d"), file("d", "x")}, Options{}, ErrExists},
+		{"dir over file", []entry{file("f", "x"), dir("f")}, Options{}, ErrExists},
+		{"overwrite rejected", []entry{file("f", "x"), file("f", "y")}, Options{Overwrite: OverwriteReject}, ErrExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Extract(tarball(t, tt.entries...), t.TempDir(), &tt.opts)
+			var ue *Error
+			if !errors.Is(err, tt.want) || !errors.As(err, &ue) || !ue.Rejected() {
+				t.Errorf("Extract = %v; want a rejected *Error wrapping %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractOptions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("links and permissions differ on Windows")
+	}
+	d := t.TempDir()
+	if err := os.WriteFile(filepath.Join(d, "keep"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	exe := file("bin/tool", "#!/bin/sh")
+	exe.mode = 04775
+	private := file("private", "secret")
+	private.mode = 0600
+	opts := &Options{
+		MaxBytes:  100,
+		MaxFiles:  10,
+		MaxDepth:  3,
+		Symlinks:  LinkContain,
+		HardLinks: LinkContain,
+		DropPerms: true,
+		Overwrite: OverwriteSkip,
+	}
+	err := Extract(tarball(t,
+		dir("bin"),
+		exe,
+		private,
+		file("keep", "new"),
+		symlink("bin/up", "../private"),
+		symlink("bin/self", "."),
+		hardlink("bin/hard", "bin/tool"),
+	), d, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]os.FileMode{"bin/tool": 0755, "private": 0644} {
+		if fi, err := os.Stat(filepath.Join(d, name)); err != nil || fi.Mode() != want {
+			t.Errorf("%s has mode %v, %v; want %v", name, fi.Mode(), err, want)
+		}
+	}
+	if got, _ := os.ReadFile(filepath.Join(d, "keep")); string(got) != "old" {
+		t.Errorf("existing file keep = %q with OverwriteSkip; want %q", got, "old")
+	}
+	if got, _ := os.ReadFile(filepath.Join(d, "bin", "up")); string(got) != "secret" {
+		t.Errorf("bin/up = %q; want the content of private, %q", got, "secret")
+	}
+	if target, err := os.Readlink(filepath.Join(d, "bin", "self")); err != nil || target != "." {
+		t.Errorf("Readlink(bin/self) = %q, %v; want %q", target, err, ".")
+	}
+	fi1, _ := os.Stat(filepath.Join(d, "bin", "tool"))
+	fi2, err := os.Stat(filepath.Join(d, "bin", "hard"))
+	if err != nil || !os.SameFile(fi1, fi2) {
+		t.Errorf("bin/hard isn't a hard link of bin/tool: %v", err)
+	}
+
+	// Replacing a symbolic link replaces the link, not its target.
+	err = Extract(tarball(t, file("bin/up", "replaced")), d, &Options{Symlinks: LinkContain})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(d, "private")); string(got) != "secret" {
+		t.Errorf("private = %q after replacing the link bin/up; want %q", got, "secret")
+	}
+
+	// Skipped links aren't created.
+	err = Extract(tarball(t, symlink("skipped", "bin"), hardlink("hard", "private")), d, &Options{Symlinks: LinkSkip, HardLinks: LinkSkip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"skipped", "hard"} {
+		if _, err := os.Lstat(filepath.Join(d, name)); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Lstat(%s) = %v; want a skipped link", name, err)
+		}
+	}
+}
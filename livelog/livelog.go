@@ -3,7 +3,8 @@
 // license that can be found in the LICENSE file.
 
 // Package livelog provides a buffer that can be simultaneously written to by
-// one writer and read from by many readers.
+// one writer and read from by many readers, and a Log that does the same
+// without a maximum size, spilling its data to a file system.
 package livelog
 
 import (
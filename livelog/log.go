@@ -0,0 +1,551 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package livelog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/build/internal/gcsfs"
+)
+
+const (
+	// DefaultChunkSize is the size of the chunks in which a Log spills
+	// its data to its file system.
+	DefaultChunkSize = 1 << 20 // 1 MB
+
+	// lineIndexInterval is how many lines apart the offsets that a Log
+	// indexes are. Finding any other line scans at most this many lines.
+	lineIndexInterval = 1024
+
+	// defaultMaxBacklog is how many bytes a Log keeps in memory while
+	// they wait to be written to its file system, before it truncates
+	// its data like a Buffer.
+	defaultMaxBacklog = 8 * DefaultChunkSize
+
+	// maxChunkWrites is how many times a Log tries to write a chunk
+	// before it gives up on its file system.
+	maxChunkWrites = 5
+)
+
+// Log is an io.WriteCloser like Buffer, without its maximum size.
+// Instead of keeping all the data in memory, a Log spills it to a file
+// system in chunks of DefaultChunkSize bytes, keeping only the last,
+// partial chunk in memory. Chunks are written in the background, so
+// that a slow file system doesn't slow down the writer. If chunks
+// can't be written as fast as the data arrives, or can't be written at
+// all, a Log keeps a bounded amount of data in memory and then
+// truncates its data like a Buffer.
+//
+// A Log indexes the offsets of its lines, and its Readers may start at
+// any byte offset, line, or number of lines from the end. It's an
+// io.ReaderAt of the data written so far, and serves HTTP Range
+// requests of it.
+type Log struct {
+	fsys       fs.FS  // or nil, to keep all the data in memory
+	name       string // directory of the chunks in fsys
+	chunkSize  int
+	maxBacklog int           // bytes kept in memory while chunks wait to be written
+	retryDelay time.Duration // before writing a chunk again, doubled for each attempt
+
+	mu        sync.Mutex // guards the fields below
+	wake      *sync.Cond // created on demand by reader
+	chunks    map[int][]byte
+	tail      []byte  // the partial last chunk
+	size      int64   // bytes written
+	lines     int64   // complete lines written
+	lastNL    int64   // offset just past the last newline
+	index     []int64 // index[i] is the offset of line i*lineIndexInterval
+	eof       bool
+	truncated bool          // the truncation message was written
+	pending   []int         // full chunks waiting to be written, in order
+	uploading chan struct{} // closed when the running upload exits; nil if none
+	removed   bool
+	stop      chan struct{} // closed by Remove
+	err       error         // error that stopped writing chunks
+
+	// cache is the chunk most recently read from fsys.
+	cacheN int
+	cache  []byte
+}
+
+// NewLog returns a new Log that spills its chunks to the directory name
+// of fsys, which must support gcsfs.Create (for example, a file system
+// from gcsfs.FromURL). The chunks of different Logs must not share a
+// directory. If fsys is nil, the Log keeps all its data in memory.
+func NewLog(fsys fs.FS, name string) *Log {
+	return &Log{
+		fsys:       fsys,
+		name:       name,
+		chunkSize:  DefaultChunkSize,
+		maxBacklog: defaultMaxBacklog,
+		retryDelay: time.Second,
+		chunks:     make(map[int][]byte),
+		index:      []int64{0},
+		stop:       make(chan struct{}),
+		cacheN:     -1,
+	}
+}
+
+func (l *Log) chunkName(n int) string {
+	return path.Join(l.name, fmt.Sprintf("%08d", n))
+}
+
+// Write appends data to the Log.
+// It will wake any blocked Readers.
+//
+// Write doesn't wait for chunks to be written to the file system, and
+// doesn't fail if they can't be; they stay in memory instead, up to a
+// bound after which the Log is truncated, and Err reports the error.
+func (l *Log) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := len(p)
+	if l.truncated {
+		return n, nil
+	}
+	trunc := false
+	if l.fsys != nil {
+		// Don't let chunks that can't be written, or not fast
+		// enough, pile up in memory.
+		room := l.maxBacklog - len(l.chunks)*l.chunkSize - len(l.tail) - len(truncationMessage)
+		if len(p) > room {
+			p = p[:max(room, 0)]
+			trunc = true
+		}
+	}
+	l.appendLocked(p)
+	if trunc {
+		l.appendLocked([]byte(truncationMessage))
+		l.truncated = true
+	}
+	if len(l.pending) > 0 && l.uploading == nil && l.err == nil && !l.removed {
+		l.uploading = make(chan struct{})
+		go l.upload(l.uploading)
+	}
+	l.wakeReaders()
+	return n, nil
+}
+
+// appendLocked appends p to the data, and queues the chunks it fills
+// to be written to the file system. l.mu must be held when calling.
+func (l *Log) appendLocked(p []byte) {
+	for i, c := range p {
+		if c == '\n' {
+			l.lines++
+			l.lastNL = l.size + int64(i) + 1
+			if l.lines%lineIndexInterval == 0 {
+				l.index = append(l.index, l.lastNL)
+			}
+		}
+	}
+	l.size += int64(len(p))
+	for rest := p; len(rest) > 0; {
+		if l.tail == nil {
+			l.tail = make([]byte, 0, l.chunkSize)
+		}
+		n := min(len(rest), l.chunkSize-len(l.tail))
+		l.tail = append(l.tail, rest[:n]...)
+		rest = rest[n:]
+		if len(l.tail) == l.chunkSize {
+			n := int((l.size-int64(len(rest)))/int64(l.chunkSize)) - 1
+			l.chunks[n] = l.tail
+			l.tail = nil
+			if l.fsys != nil && l.err == nil {
+				l.pending = append(l.pending, n)
+			}
+		}
+	}
+}
+
+// upload writes the pending chunks to the file system, in order, until
+// there are none left, and then closes done. If a chunk can't be
+// written, it records the error and leaves the rest of the chunks in
+// memory.
+func (l *Log) upload(done chan struct{}) {
+	defer close(done)
+	for {
+		l.mu.Lock()
+		if len(l.pending) == 0 || l.removed {
+			l.uploading = nil
+			l.mu.Unlock()
+			return
+		}
+		n := l.pending[0]
+		data := l.chunks[n]
+		l.mu.Unlock()
+
+		err := l.writeChunk(n, data)
+
+		l.mu.Lock()
+		l.pending = l.pending[1:]
+		if err != nil {
+			l.err = fmt.Errorf("livelog: writing chunk %d of %s: %w", n, l.name, err)
+			l.pending = nil
+		} else {
+			delete(l.chunks, n)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// writeChunk writes chunk n to the file system, trying again with
+// exponential backoff if it fails.
+func (l *Log) writeChunk(n int, data []byte) error {
+	delay := l.retryDelay
+	for attempt := 1; ; attempt++ {
+		err := gcsfs.WriteFile(l.fsys, l.chunkName(n), data)
+		if err == nil || attempt == maxChunkWrites {
+			return err
+		}
+		select {
+		case <-time.After(delay):
+		case <-l.stop:
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// Close signals EOF to all Readers.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.eof = true
+	l.wakeReaders()
+	return nil
+}
+
+// Err returns the error that stopped the Log from writing chunks to its
+// file system, if any.
+func (l *Log) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// Remove removes the chunks that the Log wrote to its file system, if
+// the file system supports gcsfs.Remove. The Log must not be used
+// afterwards.
+func (l *Log) Remove() error {
+	l.mu.Lock()
+	if !l.removed {
+		l.removed = true
+		close(l.stop)
+	}
+	done := l.uploading
+	l.mu.Unlock()
+	if done != nil {
+		// Wait for the chunk being written, if any.
+		<-done
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.fsys == nil {
+		return nil
+	}
+	if _, ok := l.fsys.(gcsfs.RemoveFS); !ok {
+		return nil
+	}
+	var errs []error
+	for n := int64(0); n < l.size/int64(l.chunkSize); n++ {
+		if _, ok := l.chunks[int(n)]; ok {
+			continue // never written
+		}
+		if err := gcsfs.Remove(l.fsys, l.chunkName(int(n))); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// wakeReaders wakes any sleeping readers.
+// l.mu must be held when calling.
+func (l *Log) wakeReaders() {
+	if l.wake != nil {
+		l.wake.Broadcast()
+	}
+}
+
+// Size returns the number of bytes written to the Log.
+func (l *Log) Size() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.size
+}
+
+// Lines returns the number of lines written to the Log, counting an
+// unterminated last line.
+func (l *Log) Lines() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.size > l.lastNL {
+		return l.lines + 1
+	}
+	return l.lines
+}
+
+// chunk returns chunk n, reading it from the file system if needed.
+func (l *Log) chunk(n int) ([]byte, error) {
+	l.mu.Lock()
+	if n == int(l.size/int64(l.chunkSize)) {
+		defer l.mu.Unlock()
+		return l.tail, nil
+	}
+	if data, ok := l.chunks[n]; ok {
+		l.mu.Unlock()
+		return data, nil
+	}
+	if n == l.cacheN {
+		defer l.mu.Unlock()
+		return l.cache, nil
+	}
+	l.mu.Unlock()
+
+	data, err := fs.ReadFile(l.fsys, l.chunkName(n))
+	if err != nil {
+		return nil, fmt.Errorf("livelog: reading chunk %d of %s: %w", n, l.name, err)
+	}
+	if len(data) != l.chunkSize {
+		return nil, fmt.Errorf("livelog: chunk %d of %s has %d bytes, want %d", n, l.name, len(data), l.chunkSize)
+	}
+	l.mu.Lock()
+	l.cacheN, l.cache = n, data
+	l.mu.Unlock()
+	return data, nil
+}
+
+// ReadAt implements io.ReaderAt for the data written so far. It doesn't
+// wait for more data.
+func (l *Log) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("livelog: negative offset")
+	}
+	size := l.Size()
+	var n int
+	for n < len(p) && off < size {
+		data, err := l.chunk(int(off / int64(l.chunkSize)))
+		if err != nil {
+			return n, err
+		}
+		i := int(off % int64(l.chunkSize))
+		if i >= len(data) {
+			break // the tail was cut into a new chunk meanwhile; size is stale
+		}
+		m := copy(p[n:], data[i:])
+		n += m
+		off += int64(m)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Bytes returns a copy of the data written so far.
+func (l *Log) Bytes() ([]byte, error) {
+	buf := make([]byte, l.Size())
+	n, err := l.ReadAt(buf, 0)
+	if err == io.EOF && n == len(buf) {
+		err = nil
+	}
+	return buf[:n], err
+}
+
+// String returns a copy of the data written so far as a string. If part
+// of the data can't be read from the file system, the string ends with
+// a note about it.
+func (l *Log) String() string {
+	b, err := l.Bytes()
+	if err != nil {
+		return fmt.Sprintf("%s\n\n... %v ...", b, err)
+	}
+	return string(b)
+}
+
+// LineOffset returns the offset of the start of line n, counting from
+// zero. If the Log has fewer lines, it returns the offset just past the
+// last newline.
+func (l *Log) LineOffset(n int64) (int64, error) {
+	l.mu.Lock()
+	if n <= 0 {
+		l.mu.Unlock()
+		return 0, nil
+	}
+	if n >= l.lines {
+		defer l.mu.Unlock()
+		return l.lastNL, nil
+	}
+	i := n / lineIndexInterval
+	off, line := l.index[i], i*lineIndexInterval
+	l.mu.Unlock()
+
+	// Scan forward from the indexed line.
+	buf := make([]byte, 32<<10)
+	for line < n {
+		m, err := l.ReadAt(buf, off)
+		if m == 0 && err != nil {
+			return 0, err
+		}
+		for _, c := range buf[:m] {
+			off++
+			if c == '\n' {
+				line++
+				if line == n {
+					break
+				}
+			}
+		}
+	}
+	return off, nil
+}
+
+// TailOffset returns the offset of the start of the last n lines,
+// counting an unterminated last line.
+func (l *Log) TailOffset(n int64) (int64, error) {
+	return l.LineOffset(l.Lines() - n)
+}
+
+// Reader returns a ReadCloser that emits the data of the Log from offset
+// off, waiting for more data until the Log is closed. It is safe to call
+// Read and Close concurrently.
+func (l *Log) Reader(off int64) io.ReadCloser {
+	return &logReader{log: l, off: off}
+}
+
+type logReader struct {
+	log    *Log
+	off    int64 // accessed by only the Read method
+	closed bool  // guarded by log.mu
+}
+
+func (r *logReader) Read(b []byte) (int, error) {
+	l := r.log
+	l.mu.Lock()
+	// Wait for data or writer EOF or reader closed.
+	for l.size <= r.off && !l.eof && !r.closed {
+		if l.wake == nil {
+			l.wake = sync.NewCond(&l.mu)
+		}
+		l.wake.Wait()
+	}
+	// Return EOF if writer reported EOF or this reader is closed.
+	if (l.size <= r.off && l.eof) || r.closed {
+		l.mu.Unlock()
+		return 0, io.EOF
+	}
+	l.mu.Unlock()
+
+	n, err := l.ReadAt(b, r.off)
+	r.off += int64(n)
+	if err == io.EOF {
+		err = nil // there may be more data later
+	}
+	return n, err
+}
+
+func (r *logReader) Close() error {
+	r.log.mu.Lock()
+	defer r.log.mu.Unlock()
+
+	r.closed = true
+
+	// Wake any sleeping readers to unblock a pending read on this reader.
+	// (For other open readers this will be a no-op.)
+	r.log.wakeReaders()
+
+	return nil
+}
+
+// StartOffset returns the offset at which to start serving the Log for
+// request r, as selected by one of the form values "offset" (a byte
+// offset), "line" (a line, counting from zero), or "tail" (a number of
+// lines from the end). Without any, it returns zero.
+func (l *Log) StartOffset(r *http.Request) (int64, error) {
+	for _, key := range []string{"offset", "line", "tail"} {
+		v := r.FormValue(key)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid %s %q", key, v)
+		}
+		switch key {
+		case "offset":
+			return min(n, l.Size()), nil
+		case "line":
+			return l.LineOffset(n)
+		default:
+			return l.TailOffset(n)
+		}
+	}
+	return 0, nil
+}
+
+// ServeHTTP serves the Log as plain text. Range requests are served the
+// data written so far. Other requests start at the offset selected by
+// StartOffset, and stream the data as it's written until the Log is
+// closed or the request is canceled.
+func (l *Log) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if r.Header.Get("Range") != "" {
+		l.ServeRange(w, r)
+		return
+	}
+	off, err := l.StartOffset(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	l.Stream(w, r, off)
+}
+
+// ServeRange serves the data written so far with http.ServeContent,
+// which handles Range requests.
+func (l *Log) ServeRange(w http.ResponseWriter, r *http.Request) {
+	http.ServeContent(w, r, "", time.Time{}, io.NewSectionReader(l, 0, l.Size()))
+}
+
+// Stream copies the Log from offset off to w, flushing as data is
+// written, until the Log is closed or the request is canceled.
+func (l *Log) Stream(w http.ResponseWriter, r *http.Request, off int64) {
+	output := l.Reader(off)
+	go func() {
+		<-r.Context().Done()
+		output.Close()
+	}()
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 65536)
+	for {
+		n, err := output.Read(buf)
+		if _, err2 := w.Write(buf[:n]); err2 != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if err != nil {
+			if err != io.EOF {
+				fmt.Fprintf(w, "\n\n... %v ...\n", err)
+			}
+			return
+		}
+	}
+}
+
+var _ io.ReaderAt = (*Log)(nil)
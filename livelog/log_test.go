@@ -0,0 +1,318 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package livelog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/build/internal/gcsfs"
+)
+
+// newTestLog returns a Log with small chunks, spilling to a temporary
+// directory, which it also returns.
+func newTestLog(t *testing.T) (*Log, string) {
+	dir := t.TempDir()
+	l := NewLog(gcsfs.DirFS(dir), "B123")
+	l.chunkSize = 16
+	return l, dir
+}
+
+// waitUploads waits for l to finish writing chunks to its file system.
+func waitUploads(l *Log) {
+	l.mu.Lock()
+	done := l.uploading
+	l.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// flakyFS is a file system whose Create fails a number of times, and
+// otherwise creates files in FS. Create blocks until unblock is closed,
+// if it's non-nil.
+type flakyFS struct {
+	fs.FS
+	unblock chan struct{}
+
+	mu       sync.Mutex
+	failures int // calls of Create left to fail
+}
+
+func (f *flakyFS) Create(name string) (gcsfs.WriterFile, error) {
+	if f.unblock != nil {
+		<-f.unblock
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failures > 0 {
+		f.failures--
+		return nil, errors.New("flaky")
+	}
+	return gcsfs.Create(f.FS, name)
+}
+
+// testLines returns n numbered lines.
+func testLines(n int) string {
+	var sb strings.Builder
+	for i := range n {
+		fmt.Fprintf(&sb, "line %d\n", i)
+	}
+	return sb.String()
+}
+
+func TestLogSpill(t *testing.T) {
+	l, dir := newTestLog(t)
+	want := testLines(100) + "partial"
+	// Write in pieces that don't line up with the chunks.
+	for rest := want; rest != ""; {
+		n := min(len(rest), 7)
+		l.Write([]byte(rest[:n]))
+		rest = rest[n:]
+	}
+	waitUploads(l)
+	if err := l.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := filepath.Glob(filepath.Join(dir, "B123", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(chunks), len(want)/16; got != want {
+		t.Errorf("wrote %d chunks; want %d", got, want)
+	}
+	if len(l.chunks) != 0 || len(l.tail) != len(want)%16 {
+		t.Errorf("Log keeps %d chunks and %d bytes in memory; want 0 and %d", len(l.chunks), len(l.tail), len(want)%16)
+	}
+	if got := l.String(); got != want {
+		t.Errorf("String() = %q; want %q", got, want)
+	}
+	for _, off := range []int64{0, 5, 16, 300, int64(len(want)) - 3} {
+		buf := make([]byte, 20)
+		n, err := l.ReadAt(buf, off)
+		wantN := min(20, len(want)-int(off))
+		if n != wantN || string(buf[:n]) != want[off:off+int64(n)] {
+			t.Errorf("ReadAt(%d) = %q, %v; want %q", off, buf[:n], err, want[off:off+int64(wantN)])
+		}
+	}
+
+	if got := l.Lines(); got != 101 {
+		t.Errorf("Lines() = %d; want 101", got)
+	}
+	for _, line := range []int64{0, 1, 42, 99, 100} {
+		off, err := l.LineOffset(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantOff := int64(strings.Index(want, fmt.Sprintf("line %d\n", line)))
+		if line == 100 {
+			wantOff = int64(strings.Index(want, "partial"))
+		}
+		if off != wantOff {
+			t.Errorf("LineOffset(%d) = %d; want %d", line, off, wantOff)
+		}
+	}
+	off, err := l.TailOffset(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := want[off:], "line 99\npartial"; got != want {
+		t.Errorf("data from TailOffset(2) = %q; want %q", got, want)
+	}
+
+	if err := l.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if chunks, _ := filepath.Glob(filepath.Join(dir, "B123", "*")); len(chunks) != 0 {
+		t.Errorf("chunks left after Remove: %q", chunks)
+	}
+}
+
+func TestLogLineIndex(t *testing.T) {
+	l, _ := newTestLog(t)
+	want := testLines(3*lineIndexInterval + 10)
+	l.Write([]byte(want))
+	if len(l.index) != 4 {
+		t.Errorf("indexed %d lines; want 4", len(l.index))
+	}
+	for _, line := range []int64{lineIndexInterval - 1, lineIndexInterval, 2*lineIndexInterval + 500, 3*lineIndexInterval + 9} {
+		off, err := l.LineOffset(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wantOff := int64(strings.Index(want, fmt.Sprintf("\nline %d\n", line)) + 1); off != wantOff {
+			t.Errorf("LineOffset(%d) = %d; want %d", line, off, wantOff)
+		}
+	}
+}
+
+func TestLogReader(t *testing.T) {
+	l, _ := newTestLog(t)
+	l.Write([]byte("first chunk of the log\n"))
+
+	r := l.Reader(6)
+	done := make(chan string)
+	go func() {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Errorf("ReadAll() = %v", err)
+		}
+		done <- string(b)
+	}()
+	l.Write([]byte("second chunk of the log\n"))
+	l.Close()
+	if got, want := <-done, "chunk of the log\nsecond chunk of the log\n"; got != want {
+		t.Errorf("Reader(6) read %q; want %q", got, want)
+	}
+
+	// Closing a Reader unblocks its Read.
+	l2, _ := newTestLog(t)
+	r = l2.Reader(0)
+	go r.Close()
+	if _, err := r.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Read() of a closed Reader = %v; want io.EOF", err)
+	}
+}
+
+func TestLogWriteError(t *testing.T) {
+	// A file where the directory of chunks should be.
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "B123"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	l := NewLog(gcsfs.DirFS(dir), "B123")
+	l.chunkSize = 16
+	l.retryDelay = time.Millisecond
+	want := testLines(10)
+	if n, err := l.Write([]byte(want)); n != len(want) || err != nil {
+		t.Fatalf("Write() = %d, %v; want %d, nil", n, err, len(want))
+	}
+	waitUploads(l)
+	if err := l.Err(); err == nil {
+		t.Errorf("Err() = nil after failing to write a chunk")
+	}
+	if got := l.String(); got != want {
+		t.Errorf("String() = %q; want the data kept in memory, %q", got, want)
+	}
+}
+
+func TestLogWriteRetry(t *testing.T) {
+	dir := t.TempDir()
+	fsys := &flakyFS{FS: gcsfs.DirFS(dir), failures: maxChunkWrites - 1}
+	l := NewLog(fsys, "B123")
+	l.chunkSize = 16
+	l.retryDelay = time.Millisecond
+	want := testLines(10)
+	l.Write([]byte(want))
+	waitUploads(l)
+	if err := l.Err(); err != nil {
+		t.Fatalf("Err() = %v after chunk writes failed fewer than %d times", err, maxChunkWrites)
+	}
+	if chunks, _ := filepath.Glob(filepath.Join(dir, "B123", "*")); len(chunks) != len(want)/16 {
+		t.Errorf("wrote %d chunks; want %d", len(chunks), len(want)/16)
+	}
+	if got := l.String(); got != want {
+		t.Errorf("String() = %q; want %q", got, want)
+	}
+}
+
+func TestLogBacklog(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		fsys *flakyFS
+	}{
+		{"failing", &flakyFS{FS: gcsfs.DirFS(t.TempDir()), failures: 1 << 30}},
+		{"slow", &flakyFS{FS: gcsfs.DirFS(t.TempDir()), unblock: make(chan struct{})}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			l := NewLog(tc.fsys, "B123")
+			l.chunkSize = 16
+			l.maxBacklog = 64
+			l.retryDelay = time.Millisecond
+			data := testLines(20)
+			// Write doesn't wait for chunks to be written, and
+			// drops the data that doesn't fit in the backlog.
+			l.Write([]byte(data[:50]))
+			l.Write([]byte(data[50:]))
+			l.Write([]byte("more"))
+			want := data[:64-len(truncationMessage)] + truncationMessage
+			if got := l.String(); got != want {
+				t.Errorf("String() = %q; want %q", got, want)
+			}
+			if tc.fsys.unblock != nil {
+				close(tc.fsys.unblock)
+			}
+			waitUploads(l)
+		})
+	}
+}
+
+func TestLogMemory(t *testing.T) {
+	l := NewLog(nil, "B123")
+	l.chunkSize = 16
+	want := strings.Repeat("x", MaxBufferSize+100)
+	l.Write([]byte(want))
+	if got := l.String(); got != want {
+		t.Errorf("String() of a Log without a file system has %d bytes; want %d", len(got), len(want))
+	}
+	if err := l.Remove(); err != nil {
+		t.Errorf("Remove() = %v", err)
+	}
+}
+
+func TestLogServeHTTP(t *testing.T) {
+	l, _ := newTestLog(t)
+	data := testLines(50)
+	l.Write([]byte(data))
+	l.Close()
+
+	tests := []struct {
+		query, rng string
+		wantCode   int
+		want       string
+	}{
+		{"", "", http.StatusOK, data},
+		{"?offset=10", "", http.StatusOK, data[10:]},
+		{"?line=48", "", http.StatusOK, "line 48\nline 49\n"},
+		{"?tail=1", "", http.StatusOK, "line 49\n"},
+		{"?tail=x", "", http.StatusBadRequest, "invalid tail \"x\"\n"},
+		{"", "bytes=7-20", http.StatusPartialContent, data[7:21]},
+		{"", "bytes=-8", http.StatusPartialContent, "line 49\n"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/log"+tt.query, nil)
+		if tt.rng != "" {
+			req.Header.Set("Range", tt.rng)
+		}
+		rec := httptest.NewRecorder()
+		l.ServeHTTP(rec, req)
+		if rec.Code != tt.wantCode || rec.Body.String() != tt.want {
+			t.Errorf("GET %q with Range %q = %d %q; want %d %q", tt.query, tt.rng, rec.Code, rec.Body, tt.wantCode, tt.want)
+		}
+	}
+}
+
+func TestLogChunkNames(t *testing.T) {
+	l, dir := newTestLog(t)
+	l.Write([]byte(strings.Repeat("a", 40)))
+	waitUploads(l)
+	fsys := gcsfs.DirFS(dir)
+	for _, name := range []string{"B123/00000000", "B123/00000001"} {
+		if _, err := fs.Stat(fsys, name); err != nil {
+			t.Errorf("chunk %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pargzip
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// The content types of the formats that the package writes.
+const (
+	GzipContentType = "application/gzip"
+	ZstdContentType = "application/zstd"
+	XzContentType   = "application/x-xz"
+)
+
+// contentTypes are the content types in order of preference.
+var contentTypes = []string{GzipContentType, ZstdContentType, XzContentType}
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+)
+
+// compressZstd compresses p into one zstd frame.
+func compressZstd(dst *bytes.Buffer, p []byte) error {
+	zstdOnce.Do(func() {
+		// EncodeAll may be called concurrently, and runs each
+		// call on its own goroutine.
+		zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(runtime.NumCPU()))
+	})
+	dst.Write(zstdEncoder.EncodeAll(p, nil))
+	return nil
+}
+
+// NewZstdWriter returns a new Writer that writes zstd instead of gzip.
+// Each chunk is a separate zstd frame.
+func NewZstdWriter(w io.Writer) *Writer {
+	return newWriter(w, compressZstd)
+}
+
+// compressXz compresses p into one xz stream, with an xz child process.
+func compressXz(dst *bytes.Buffer, p []byte) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("xz", "--compress", "--stdout", "--threads=1")
+	cmd.Stdin = bytes.NewReader(p)
+	cmd.Stdout = dst
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pargzip: running xz: %v; stderr: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
+
+// xzChunkSize is the default ChunkSize of an xz Writer. It's the
+// dictionary size of xz's default preset, so larger chunks would hardly
+// compress better.
+const xzChunkSize = 8 << 20
+
+// NewXzWriter returns a new Writer that writes xz instead of gzip.
+// Each chunk is a separate xz stream, compressed by an xz child process,
+// so the xz command must be in $PATH.
+//
+// Starting a process per chunk costs a few milliseconds, and each xz
+// process takes about 100MB of memory, so the default ChunkSize is 8MB
+// rather than 1MB. Up to Parallel processes run at once, and up to
+// Parallel chunks are held in memory.
+func NewXzWriter(w io.Writer) *Writer {
+	zw := newWriter(w, compressXz)
+	zw.ChunkSize = xzChunkSize
+	return zw
+}
+
+// haveXz reports whether the xz command is available.
+var haveXz = sync.OnceValue(func() bool {
+	_, err := exec.LookPath("xz")
+	return err == nil
+})
+
+// NewWriterFor returns a new Writer of the format with the given content
+// type: GzipContentType, ZstdContentType, or XzContentType.
+func NewWriterFor(w io.Writer, contentType string) (*Writer, error) {
+	switch contentType {
+	case GzipContentType:
+		return NewWriter(w), nil
+	case ZstdContentType:
+		return NewZstdWriter(w), nil
+	case XzContentType:
+		return NewXzWriter(w), nil
+	}
+	return nil, fmt.Errorf("pargzip: unsupported content type %q", contentType)
+}
+
+// NewReaderFor returns a reader that decompresses r, of the format with
+// the given content type. Gzip is decompressed by a Reader, zstd in
+// process, and xz by an xz child process. The caller must call Close
+// when done.
+func NewReaderFor(r io.Reader, contentType string) (io.ReadCloser, error) {
+	switch contentType {
+	case GzipContentType:
+		return NewReader(r)
+	case ZstdContentType:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	case XzContentType:
+		cmd := exec.Command("xz", "--decompress", "--stdout")
+		cmd.Stdin = r
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("pargzip: running xz: %v", err)
+		}
+		return &cmdReader{ReadCloser: out, cmd: cmd, stderr: &stderr}, nil
+	}
+	return nil, fmt.Errorf("pargzip: unsupported content type %q", contentType)
+}
+
+// A cmdReader reads the output of a child process.
+type cmdReader struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+}
+
+func (r *cmdReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err == io.EOF {
+		// Report a failure of the process instead of a
+		// truncated output.
+		if werr := r.wait(); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+func (r *cmdReader) wait() error {
+	if r.cmd.ProcessState != nil {
+		if !r.cmd.ProcessState.Success() {
+			return fmt.Errorf("pargzip: xz failed: %v; stderr: %s", r.cmd.ProcessState, bytes.TrimSpace(r.stderr.Bytes()))
+		}
+		return nil
+	}
+	if err := r.cmd.Wait(); err != nil {
+		return fmt.Errorf("pargzip: xz failed: %v; stderr: %s", err, bytes.TrimSpace(r.stderr.Bytes()))
+	}
+	return nil
+}
+
+func (r *cmdReader) Close() error {
+	r.ReadCloser.Close()
+	if r.cmd.ProcessState == nil {
+		r.cmd.Process.Kill()
+		r.cmd.Wait()
+	}
+	return nil
+}
+
+// NegotiateContentType returns the content type in which to compress a
+// response to a request with the given Accept header: the supported one
+// with the highest quality, preferring gzip, zstd and xz in that order
+// among equals. Without an acceptable one, it returns GzipContentType,
+// which clients that predate the negotiation expect.
+//
+// XzContentType is only supported if the xz command is available.
+func NegotiateContentType(accept string) string {
+	best, bestQ := GzipContentType, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		switch mediaType {
+		case GzipContentType, "application/x-gzip":
+			mediaType = GzipContentType
+		case ZstdContentType:
+		case XzContentType:
+			if !haveXz() {
+				continue
+			}
+		default:
+			continue
+		}
+		if q > bestQ || q == bestQ && slices.Index(contentTypes, mediaType) < slices.Index(contentTypes, best) {
+			best, bestQ = mediaType, q
+		}
+	}
+	return best
+}
@@ -5,15 +5,20 @@
 // Package pargzip contains a parallel gzip writer implementation.  By
 // compressing each chunk of data in parallel, all the CPUs on the
 // machine can be used, at a slight loss of compression efficiency.
+//
+// Each chunk is a separate gzip member, whose header records the size
+// of the member, so that a Reader can also decompress the chunks in
+// parallel. The package also has writers of zstd and xz, which compress
+// each chunk into a separate frame or stream in the same way.
 package pargzip
 
 import (
 	"bufio"
 	"bytes"
 	"compress/gzip"
+	"encoding/binary"
 	"io"
 	"runtime"
-	"strings"
 	"sync"
 )
 
@@ -24,7 +29,8 @@ import (
 // Write.
 type Writer struct {
 	// ChunkSize is the number of bytes to gzip at once.
-	// The default from NewWriter is 1MB.
+	// The default from NewWriter is 1MB. A Reader doesn't accept
+	// chunks of more than 8MB.
 	ChunkSize int
 
 	// Parallel is the number of chunks to compress in parallel.
@@ -34,20 +40,34 @@ type Writer struct {
 	w  io.Writer
 	bw *bufio.Writer
 
+	// compress compresses a chunk into one member, appending it to dst.
+	compress func(dst *bytes.Buffer, p []byte) error
+
 	allWritten  chan struct{} // when writing goroutine ends
 	wasWriteErr chan struct{} // closed after 'err' set
 
 	sem    chan bool        // semaphore bounding compressions in flight
 	chunkc chan *writeChunk // closed on Close
 
-	mu     sync.Mutex // guards following
-	closed bool
-	err    error // sticky write error
+	mu      sync.Mutex // guards following
+	closed  bool
+	err     error    // sticky write error
+	members []Member // written so far
+}
+
+// A Member is one compressed chunk of the output of a Writer: a gzip
+// member, a zstd frame, or an xz stream, which decompresses on its own.
+type Member struct {
+	// Offset and Size locate the compressed member in the output.
+	Offset, Size int64
+	// UncompressedOffset and UncompressedSize locate the member's data
+	// in the uncompressed input.
+	UncompressedOffset, UncompressedSize int64
 }
 
 type writeChunk struct {
 	zw *Writer
-	p  string // uncompressed
+	p  []byte // uncompressed
 
 	donec chan struct{} // closed on completion
 
@@ -56,7 +76,7 @@ type writeChunk struct {
 	err error  // exec error
 }
 
-// compress runs the gzip child process.
+// compress compresses the chunk.
 // It runs in its own goroutine.
 func (c *writeChunk) compress() (err error) {
 	defer func() {
@@ -67,14 +87,35 @@ func (c *writeChunk) compress() (err error) {
 		<-c.zw.sem
 	}()
 	var zbuf bytes.Buffer
-	zw := gzip.NewWriter(&zbuf)
-	if _, err := io.Copy(zw, strings.NewReader(c.p)); err != nil {
+	if err := c.zw.compress(&zbuf, c.p); err != nil {
+		return err
+	}
+	c.z = zbuf.Bytes()
+	return nil
+}
+
+// The extra field of the header of each gzip member that a Writer
+// writes has a subfield with ID "PZ", whose 4 bytes are the size of the
+// member in little-endian order. Go's gzip.Writer writes the extra field
+// right after the 10 bytes of the fixed header, and its 2-byte length.
+const (
+	sizeSubfieldID1, sizeSubfieldID2 = 'P', 'Z'
+	sizeOffset                       = 10 + 2 + 4 // of the member size in the header
+)
+
+// compressGzip compresses p into one gzip member, which records its size.
+func compressGzip(dst *bytes.Buffer, p []byte) error {
+	start := dst.Len()
+	zw := gzip.NewWriter(dst)
+	zw.Header.Extra = []byte{sizeSubfieldID1, sizeSubfieldID2, 4, 0, 0, 0, 0, 0}
+	if _, err := zw.Write(p); err != nil {
 		return err
 	}
 	if err := zw.Close(); err != nil {
 		return err
 	}
-	c.z = zbuf.Bytes()
+	member := dst.Bytes()[start:]
+	binary.LittleEndian.PutUint32(member[sizeOffset:], uint32(len(member)))
 	return nil
 }
 
@@ -87,8 +128,13 @@ func (c *writeChunk) compress() (err error) {
 // Any fields on Writer may only be modified before the first call to
 // Write.
 func NewWriter(w io.Writer) *Writer {
+	return newWriter(w, compressGzip)
+}
+
+func newWriter(w io.Writer, compress func(*bytes.Buffer, []byte) error) *Writer {
 	return &Writer{
 		w:           w,
+		compress:    compress,
 		allWritten:  make(chan struct{}),
 		wasWriteErr: make(chan struct{}),
 
@@ -118,7 +164,7 @@ func (w *Writer) startChunk(p []byte) {
 	w.sem <- true // block until we can begin
 	c := &writeChunk{
 		zw:    w,
-		p:     string(p), // string, since the bufio.Writer owns the slice
+		p:     bytes.Clone(p), // a copy, since the bufio.Writer owns the slice
 		donec: make(chan struct{}),
 	}
 	go c.compress() // receives from w.sem
@@ -144,8 +190,28 @@ func (w *Writer) writeCompressedChunk(c *writeChunk) (err error) {
 	if c.err != nil {
 		return c.err
 	}
-	_, err = w.w.Write(c.z)
-	return
+	if _, err := w.w.Write(c.z); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var m Member
+	if n := len(w.members); n > 0 {
+		last := w.members[n-1]
+		m.Offset = last.Offset + last.Size
+		m.UncompressedOffset = last.UncompressedOffset + last.UncompressedSize
+	}
+	m.Size, m.UncompressedSize = int64(len(c.z)), int64(len(c.p))
+	w.members = append(w.members, m)
+	return nil
+}
+
+// Index returns the members that the Writer has written, in order.
+// After Close, they cover all of the output.
+func (w *Writer) Index() []Member {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Member(nil), w.members...)
 }
 
 func (w *Writer) Write(p []byte) (n int, err error) {
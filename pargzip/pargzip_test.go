@@ -7,7 +7,10 @@ package pargzip
 import (
 	"bytes"
 	"compress/gzip"
+	"encoding/binary"
+	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -49,3 +52,269 @@ func TestWriter(t *testing.T) {
 	}
 	t.Logf("correctly read back %d bytes", back.Len())
 }
+
+// testInput returns compressible data of about n bytes.
+func testInput(n int) []byte {
+	var in bytes.Buffer
+	for i := 0; in.Len() < n; i++ {
+		fmt.Fprintf(&in, "line %d of the test input\n", i)
+	}
+	return in.Bytes()
+}
+
+// compress compresses in with zw, writing chunks of chunkSize bytes.
+func compress(t *testing.T, zw *Writer, chunkSize int, in []byte) {
+	t.Helper()
+	zw.ChunkSize = chunkSize
+	zw.Parallel = 4
+	if _, err := zw.Write(in); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestReader(t *testing.T) {
+	in := testInput(1 << 20)
+	var zbuf bytes.Buffer
+	zw := NewWriter(&zbuf)
+	compress(t, zw, 64<<10, in)
+
+	for _, parallel := range []int{1, 3, 8} {
+		zr, err := NewReader(bytes.NewReader(zbuf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		zr.Parallel = parallel
+		back, err := io.ReadAll(zr)
+		zr.Close()
+		if err != nil {
+			t.Fatalf("Parallel=%d: ReadAll: %v", parallel, err)
+		}
+		if !bytes.Equal(back, in) {
+			t.Errorf("Parallel=%d: decompressed data differs from the input", parallel)
+		}
+	}
+
+	index := zw.Index()
+	if len(index) != (len(in)+64<<10-1)/(64<<10) {
+		t.Errorf("Index() has %d members; want one per chunk", len(index))
+	}
+	read, err := ReadIndex(bytes.NewReader(zbuf.Bytes()), int64(zbuf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(read, index) {
+		t.Errorf("ReadIndex() = %v; want Index() = %v", read, index)
+	}
+	// Each member decompresses on its own.
+	m := index[len(index)/2]
+	zr, err := gzip.NewReader(bytes.NewReader(zbuf.Bytes()[m.Offset : m.Offset+m.Size]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil || !bytes.Equal(data, in[m.UncompressedOffset:m.UncompressedOffset+m.UncompressedSize]) {
+		t.Errorf("member %+v doesn't decompress to its part of the input: %v", m, err)
+	}
+}
+
+func TestReaderPlainGzip(t *testing.T) {
+	// Members without their size, after one with it, decompress
+	// sequentially.
+	in := testInput(200 << 10)
+	var zbuf bytes.Buffer
+	compress(t, NewWriter(&zbuf), 64<<10, in[:100<<10])
+	gw := gzip.NewWriter(&zbuf)
+	gw.Write(in[100<<10 : 150<<10])
+	gw.Close()
+	gw = gzip.NewWriter(&zbuf)
+	gw.Write(in[150<<10:])
+	gw.Close()
+
+	zr, err := NewReader(&zbuf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	back, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if !bytes.Equal(back, in) {
+		t.Errorf("decompressed data differs from the input")
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	if _, err := NewReader(strings.NewReader("")); err != io.EOF {
+		t.Errorf("NewReader of empty input = %v; want io.EOF", err)
+	}
+	if _, err := NewReader(strings.NewReader("not gzip")); err != gzip.ErrHeader {
+		t.Errorf("NewReader of invalid input = %v; want gzip.ErrHeader", err)
+	}
+
+	var zbuf bytes.Buffer
+	compress(t, NewWriter(&zbuf), 16<<10, testInput(100<<10))
+	truncated := zbuf.Bytes()[:zbuf.Len()-100]
+	zr, err := NewReader(bytes.NewReader(truncated))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	if _, err := io.ReadAll(zr); err != io.ErrUnexpectedEOF {
+		t.Errorf("ReadAll of truncated input = %v; want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestReaderLimits(t *testing.T) {
+	// A header may not claim a member larger than a Reader accepts.
+	var zbuf bytes.Buffer
+	compress(t, NewWriter(&zbuf), 16<<10, testInput(100<<10))
+	forged := bytes.Clone(zbuf.Bytes())
+	binary.LittleEndian.PutUint32(forged[sizeOffset:], 1<<30)
+	if _, err := NewReader(bytes.NewReader(forged)); err == nil {
+		t.Errorf("NewReader of a member claiming to be 1GB succeeded")
+	}
+
+	// A member may not decompress to more than a Reader accepts,
+	// however small it is.
+	zbuf.Reset()
+	if err := compressGzip(&zbuf, make([]byte, maxChunkSize+1)); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("member of %d bytes compressed to %d bytes", maxChunkSize+1, zbuf.Len())
+	zr, err := NewReader(&zbuf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	if n, err := io.Copy(io.Discard, zr); err == nil || !strings.Contains(err.Error(), "decompresses to more than") {
+		t.Errorf("Copy of a high-ratio member = %d, %v; want an error", n, err)
+	}
+
+	// A member of the largest accepted chunk decompresses.
+	zbuf.Reset()
+	if err := compressGzip(&zbuf, make([]byte, maxChunkSize)); err != nil {
+		t.Fatal(err)
+	}
+	zr, err = NewReader(&zbuf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	if n, err := io.Copy(io.Discard, zr); n != maxChunkSize || err != nil {
+		t.Errorf("Copy of a member of %d bytes = %d, %v", maxChunkSize, n, err)
+	}
+}
+
+func TestReaderClose(t *testing.T) {
+	var zbuf bytes.Buffer
+	compress(t, NewWriter(&zbuf), 16<<10, testInput(1<<20))
+	zr, err := NewReader(&zbuf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr.Parallel = 2
+	if _, err := zr.Read(make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+	zr.Close()
+	if _, err := zr.Read(make([]byte, 10)); err == nil {
+		t.Errorf("Read after Close succeeded")
+	}
+}
+
+func TestFormats(t *testing.T) {
+	in := testInput(300 << 10)
+	for _, ct := range []string{GzipContentType, ZstdContentType, XzContentType} {
+		t.Run(ct, func(t *testing.T) {
+			if ct == XzContentType && !haveXz() {
+				t.Skip("xz command not found")
+			}
+			var zbuf bytes.Buffer
+			zw, err := NewWriterFor(&zbuf, ct)
+			if err != nil {
+				t.Fatal(err)
+			}
+			compress(t, zw, 64<<10, in)
+			if n := len(zw.Index()); n != 5 {
+				t.Errorf("wrote %d members; want 5", n)
+			}
+			zr, err := NewReaderFor(&zbuf, ct)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer zr.Close()
+			back, err := io.ReadAll(zr)
+			if err != nil {
+				t.Fatalf("ReadAll: %v", err)
+			}
+			if !bytes.Equal(back, in) {
+				t.Errorf("decompressed data differs from the input")
+			}
+		})
+	}
+	if _, err := NewWriterFor(io.Discard, "application/x-bzip2"); err == nil {
+		t.Errorf("NewWriterFor of an unsupported content type succeeded")
+	}
+}
+
+func TestXzWriter(t *testing.T) {
+	if !haveXz() {
+		t.Skip("xz command not found")
+	}
+	if testing.Short() {
+		t.Skip("skipping compressing more than 8MB with xz in short mode")
+	}
+	// An xz Writer starts a process per chunk, so its chunks are
+	// larger than a gzip Writer's.
+	in := testInput(xzChunkSize + 1<<20)
+	var zbuf bytes.Buffer
+	zw := NewXzWriter(&zbuf)
+	if _, err := zw.Write(in); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if n := len(zw.Index()); n != 2 {
+		t.Errorf("wrote %d members; want 2", n)
+	}
+	zr, err := NewReaderFor(&zbuf, XzContentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	back, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if !bytes.Equal(back, in) {
+		t.Errorf("decompressed data differs from the input")
+	}
+}
+
+func TestNegotiateContentType(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", GzipContentType},
+		{"*/*", GzipContentType},
+		{"application/zstd", ZstdContentType},
+		{"application/zstd, application/gzip", GzipContentType},
+		{"application/gzip;q=0.5, application/zstd", ZstdContentType},
+		{"application/zstd;q=0", GzipContentType},
+		{"application/x-bzip2", GzipContentType},
+	}
+	if haveXz() {
+		tests = append(tests, struct{ accept, want string }{"application/x-xz, application/zstd;q=0.9", XzContentType})
+	}
+	for _, tt := range tests {
+		if got := NegotiateContentType(tt.accept); got != tt.want {
+			t.Errorf("NegotiateContentType(%q) = %q; want %q", tt.accept, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pargzip
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// A Reader holds each member that it decompresses in parallel in memory,
+// with its data, so it only accepts members of chunks of up to
+// maxChunkSize bytes. Members of a Writer with a larger ChunkSize, and
+// corrupt or malicious headers and members, are an error.
+const (
+	maxChunkSize  = 8 << 20
+	maxMemberSize = maxChunkSize + 64<<10 // allowing for the overhead of incompressible data
+)
+
+// A Reader is an io.ReadCloser that decompresses a gzip stream.
+// It decompresses the members that record their size, like those that
+// a Writer writes, in parallel. A member that doesn't, and all that
+// follows it, are decompressed sequentially.
+//
+// The data of a member that records its size may be at most 8MB, so a
+// Reader holds at most about 16MB per member in flight.
+//
+// Any exported fields may only be mutated before the first call to
+// Read.
+type Reader struct {
+	// Parallel is the number of members to decompress in parallel.
+	// The default from NewReader is runtime.NumCPU().
+	Parallel int
+
+	br    *bufio.Reader
+	hdr   []byte // of the first member
+	size  int64  // of the first member, or zero if not recorded
+	chunk chan *readChunk
+	sem   chan bool
+	stop  chan struct{} // closed by Close
+	done  chan struct{} // closed when the reading goroutine ends
+	once  sync.Once     // guards closing stop
+
+	cur io.Reader // the data of the current chunk
+	err error     // sticky read error
+}
+
+type readChunk struct {
+	donec chan struct{} // closed on completion
+
+	// one of following is set:
+	r   io.Reader // decompressed
+	err error
+}
+
+// NewReader returns a new Reader of the gzip stream r. Like
+// gzip.NewReader, it reads the header of the first member, and returns
+// an error if it's invalid.
+//
+// It is the caller's responsibility to call Close on the Reader when
+// done, which waits until the Reader stops reading r.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	hdr, size, err := readHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{
+		Parallel: runtime.NumCPU(),
+		br:       br,
+		hdr:      hdr,
+		size:     size,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// readHeader reads the header of a gzip member, returning its bytes and
+// the member size that the header records, or zero if it doesn't.
+// It returns io.EOF if br has no more data.
+func readHeader(br *bufio.Reader) (hdr []byte, size int64, err error) {
+	hdr = make([]byte, 10)
+	if n, err := io.ReadFull(br, hdr); err != nil {
+		if err == io.ErrUnexpectedEOF || n > 0 {
+			return nil, 0, gzip.ErrHeader
+		}
+		return nil, 0, err
+	}
+	if hdr[0] != 0x1f || hdr[1] != 0x8b || hdr[2] != 8 {
+		return nil, 0, gzip.ErrHeader
+	}
+	const flagExtra = 1 << 2
+	if hdr[3]&flagExtra == 0 {
+		return hdr, 0, nil
+	}
+	var xlen [2]byte
+	if _, err := io.ReadFull(br, xlen[:]); err != nil {
+		return nil, 0, gzip.ErrHeader
+	}
+	extra := make([]byte, binary.LittleEndian.Uint16(xlen[:]))
+	if _, err := io.ReadFull(br, extra); err != nil {
+		return nil, 0, gzip.ErrHeader
+	}
+	hdr = append(append(hdr, xlen[:]...), extra...)
+	for len(extra) >= 4 {
+		n := int(binary.LittleEndian.Uint16(extra[2:4]))
+		if 4+n > len(extra) {
+			break
+		}
+		if extra[0] == sizeSubfieldID1 && extra[1] == sizeSubfieldID2 && n == 4 {
+			size = int64(binary.LittleEndian.Uint32(extra[4:8]))
+			if size < int64(len(hdr))+8 || size > maxMemberSize {
+				return nil, 0, fmt.Errorf("pargzip: invalid member size %d", size)
+			}
+			return hdr, size, nil
+		}
+		extra = extra[4+n:]
+	}
+	return hdr, 0, nil
+}
+
+// readMembers reads the members, starting their decompression in order.
+// It runs in its own goroutine.
+func (r *Reader) readMembers() {
+	defer close(r.done)
+	defer close(r.chunk)
+	hdr, size := r.hdr, r.size
+	for {
+		c := &readChunk{donec: make(chan struct{})}
+		if size == 0 {
+			// Decompress the rest sequentially, as a gzip.Reader
+			// reading past the header already read.
+			zr, err := gzip.NewReader(io.MultiReader(bytes.NewReader(hdr), r.br))
+			c.r, c.err = zr, err
+			close(c.donec)
+			r.send(c)
+			return
+		}
+		member := make([]byte, size)
+		copy(member, hdr)
+		if _, err := io.ReadFull(r.br, member[len(hdr):]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			c.err = err
+			close(c.donec)
+			r.send(c)
+			return
+		}
+		select {
+		case r.sem <- true:
+		case <-r.stop:
+			return
+		}
+		go c.decompress(member, r.sem)
+		if !r.send(c) {
+			return
+		}
+
+		var err error
+		hdr, size, err = readHeader(r.br)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			c := &readChunk{donec: make(chan struct{}), err: err}
+			close(c.donec)
+			r.send(c)
+			return
+		}
+	}
+}
+
+// send sends c to Read, and reports whether it did before Close.
+func (r *Reader) send(c *readChunk) bool {
+	select {
+	case r.chunk <- c:
+		return true
+	case <-r.stop:
+		return false
+	}
+}
+
+// decompress decompresses the gzip member.
+// It runs in its own goroutine.
+func (c *readChunk) decompress(member []byte, sem chan bool) {
+	defer func() {
+		close(c.donec)
+		<-sem
+	}()
+	zr, err := gzip.NewReader(bytes.NewReader(member))
+	if err != nil {
+		c.err = err
+		return
+	}
+	zr.Multistream(false)
+	data, err := io.ReadAll(io.LimitReader(zr, maxChunkSize+1))
+	if err != nil {
+		c.err = err
+		return
+	}
+	if len(data) > maxChunkSize {
+		c.err = fmt.Errorf("pargzip: member decompresses to more than %d bytes", maxChunkSize)
+		return
+	}
+	c.r = bytes.NewReader(data)
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.chunk == nil {
+		r.chunk = make(chan *readChunk, r.Parallel)
+		r.sem = make(chan bool, r.Parallel)
+		go r.readMembers()
+	}
+	for r.err == nil {
+		if r.cur != nil {
+			n, err := r.cur.Read(p)
+			if err == io.EOF {
+				r.cur = nil
+				err = nil
+			}
+			if n > 0 || err != nil {
+				r.err = err
+				return n, err
+			}
+			continue
+		}
+		c, ok := <-r.chunk
+		if !ok {
+			r.err = io.EOF
+			break
+		}
+		<-c.donec
+		if c.err != nil {
+			r.err = c.err
+			break
+		}
+		r.cur = c.r
+	}
+	return 0, r.err
+}
+
+// Close stops the Reader, and waits until it stops reading from its
+// underlying reader. It doesn't close the underlying reader.
+func (r *Reader) Close() error {
+	r.once.Do(func() { close(r.stop) })
+	if r.chunk != nil {
+		<-r.done
+	}
+	if r.err == nil {
+		r.err = errors.New("pargzip: read after Close")
+	}
+	return nil
+}
+
+// ReadIndex returns the members of the gzip stream r of the given size,
+// which must all record their size, like those that a Writer writes.
+func ReadIndex(r io.ReaderAt, size int64) ([]Member, error) {
+	var members []Member
+	var off, uoff int64
+	for off < size {
+		br := bufio.NewReader(io.NewSectionReader(r, off, size-off))
+		_, msize, err := readHeader(br)
+		if err != nil {
+			return nil, fmt.Errorf("pargzip: member at offset %d: %w", off, err)
+		}
+		if msize == 0 {
+			return nil, fmt.Errorf("pargzip: member at offset %d doesn't record its size", off)
+		}
+		if off+msize > size {
+			return nil, fmt.Errorf("pargzip: member at offset %d exceeds the input", off)
+		}
+		// The last 4 bytes of a member are the size of its data.
+		var isize [4]byte
+		if _, err := r.ReadAt(isize[:], off+msize-4); err != nil {
+			return nil, err
+		}
+		m := Member{
+			Offset:             off,
+			Size:               msize,
+			UncompressedOffset: uoff,
+			UncompressedSize:   int64(binary.LittleEndian.Uint32(isize[:])),
+		}
+		members = append(members, m)
+		off += msize
+		uoff += m.UncompressedSize
+	}
+	return members, nil
+}
@@ -15,6 +15,11 @@
 // sequestered machine connect out to a public machine. Both sides
 // then use revdial and the public machine can become a client for the
 // NATed machine.
+//
+// By default, the Listener dials the public machine again for each
+// connection. If the Listener's registration request offers it (see
+// OfferStreams), a Dialer created with the Multiplexed option instead
+// carries all connections over the original one.
 package revdial
 
 import (
@@ -50,6 +55,26 @@ type Dialer struct {
 	connReady    chan bool
 	donec        chan struct{}
 	closeOnce    sync.Once
+
+	multiplexed bool
+	streams     *session // in streams mode
+	counters    counters
+
+	mu       sync.Mutex // guards the fields below
+	pingSeq  uint64     // of the last ping sent
+	pingSent time.Time  // when the last ping was sent, or zero once answered
+}
+
+// A DialerOption configures a Dialer.
+type DialerOption func(*Dialer)
+
+// Multiplexed makes the Dialer multiplex its connections over the
+// control connection, with flow control for each and keep-alives for
+// the control connection, instead of asking the Listener to dial back
+// for each. It must only be used if the Listener's registration request
+// offered it; see StreamsOffered.
+func Multiplexed() DialerOption {
+	return func(d *Dialer) { d.multiplexed = true }
 }
 
 var (
@@ -63,7 +88,7 @@ var (
 // connection. The connPath is the HTTP path and optional query (but
 // without scheme or host) on the dialer where the ConnHandler is
 // mounted.
-func NewDialer(c net.Conn, connPath string) *Dialer {
+func NewDialer(c net.Conn, connPath string, opts ...DialerOption) *Dialer {
 	d := &Dialer{
 		path:         connPath,
 		uniqID:       newUniqID(),
@@ -73,6 +98,15 @@ func NewDialer(c net.Conn, connPath string) *Dialer {
 		incomingConn: make(chan net.Conn),
 		pickupFailed: make(chan error),
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.multiplexed {
+		d.streams = newSession(c, bufio.NewReader(c), nil, &d.counters)
+		// Hold back frames until serveStreams has switched the
+		// Listener to streams mode.
+		d.streams.wmu.Lock()
+	}
 
 	join := "?"
 	if strings.Contains(connPath, "?") {
@@ -80,7 +114,11 @@ func NewDialer(c net.Conn, connPath string) *Dialer {
 	}
 	d.pickupPath = connPath + join + dialerUniqParam + "=" + d.uniqID
 	d.register()
-	go d.serve()
+	if d.streams != nil {
+		go d.serveStreams()
+	} else {
+		go d.serve()
+	}
 	return d
 }
 
@@ -107,25 +145,54 @@ func (d *Dialer) unregister() {
 // the peer).
 func (d *Dialer) Done() <-chan struct{} { return d.donec }
 
+// Stats returns statistics of the Dialer and its connections.
+func (d *Dialer) Stats() Stats { return d.counters.stats() }
+
+// ErrDialerClosed is returned by Dial after Close has been called, and
+// is the close reason of a Dialer closed by Close.
+var ErrDialerClosed = errors.New("revdial.Dialer closed")
+
 // Close closes the Dialer.
 func (d *Dialer) Close() error {
-	d.closeOnce.Do(d.close)
+	d.closeWithReason(ErrDialerClosed)
 	return nil
 }
 
-func (d *Dialer) close() {
-	d.unregister()
-	d.conn.Close()
-	close(d.donec)
+// closeWithReason closes the Dialer, unless it's already closed, and
+// records why.
+func (d *Dialer) closeWithReason(reason error) {
+	d.closeOnce.Do(func() {
+		d.unregister()
+		if d.streams != nil {
+			d.streams.close(reason)
+		}
+		d.conn.Close()
+		d.counters.closed(reason)
+		close(d.donec)
+	})
 }
 
 // Dial creates a new connection back to the Listener.
 func (d *Dialer) Dial(ctx context.Context) (net.Conn, error) {
+	start := time.Now()
+	d.counters.pending.Add(1)
+	c, err := d.dial(ctx)
+	d.counters.dialDone(start, err)
+	if err != nil {
+		return nil, err
+	}
+	return d.counters.track(c), nil
+}
+
+func (d *Dialer) dial(ctx context.Context) (net.Conn, error) {
+	if d.streams != nil {
+		return d.streams.open(ctx)
+	}
 	// First, tell serve that we want a connection:
 	select {
 	case d.connReady <- true:
 	case <-d.donec:
-		return nil, errors.New("revdial.Dialer closed")
+		return nil, ErrDialerClosed
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	}
@@ -137,7 +204,7 @@ func (d *Dialer) Dial(ctx context.Context) (net.Conn, error) {
 	case err := <-d.pickupFailed:
 		return nil, err
 	case <-d.donec:
-		return nil, errors.New("revdial.Dialer closed")
+		return nil, ErrDialerClosed
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	}
@@ -152,22 +219,25 @@ func (d *Dialer) matchConn(c net.Conn) {
 
 // serve blocks and runs the control message loop, keeping the peer
 // alive and notifying the peer when new connections are available.
-func (d *Dialer) serve() error {
-	defer d.Close()
+func (d *Dialer) serve() (err error) {
+	defer func() { d.closeWithReason(err) }()
 	go func() {
-		defer d.Close()
 		br := bufio.NewReader(d.conn)
 		for {
 			line, err := br.ReadSlice('\n')
 			if err != nil {
+				d.closeWithReason(fmt.Errorf("revdial: reading from Listener: %w", err))
 				return
 			}
 			var msg controlMsg
 			if err := json.Unmarshal(line, &msg); err != nil {
 				log.Printf("revdial.Dialer read invalid JSON: %q: %v", line, err)
+				d.closeWithReason(fmt.Errorf("revdial: invalid control message from Listener: %v", err))
 				return
 			}
 			switch msg.Command {
+			case "pong":
+				d.pong(msg.Ping)
 			case "pickup-failed":
 				err := fmt.Errorf("revdial listener failed to pick up connection: %v", msg.Err)
 				select {
@@ -179,8 +249,10 @@ func (d *Dialer) serve() error {
 		}
 	}()
 	for {
-		if err := d.sendMessage(controlMsg{Command: "keep-alive"}); err != nil {
-			return err
+		// Listeners that know how to answer the keep-alive with a pong,
+		// which measures the round-trip time.
+		if err := d.sendMessage(controlMsg{Command: "keep-alive", Ping: d.ping()}); err != nil {
+			return fmt.Errorf("revdial: writing to Listener: %w", err)
 		}
 
 		t := time.NewTimer(30 * time.Second)
@@ -193,15 +265,51 @@ func (d *Dialer) serve() error {
 				Command:  "conn-ready",
 				ConnPath: d.pickupPath,
 			}); err != nil {
-				return err
+				return fmt.Errorf("revdial: writing to Listener: %w", err)
 			}
 		case <-d.donec:
 			t.Stop()
-			return errors.New("revdial.Dialer closed")
+			return ErrDialerClosed
 		}
 	}
 }
 
+// ping returns the sequence number of a new ping, and notes when it
+// was sent.
+func (d *Dialer) ping() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pingSeq++
+	d.pingSent = time.Now()
+	return d.pingSeq
+}
+
+// pong records the round-trip time of the ping with sequence number seq,
+// if it's the last one sent.
+func (d *Dialer) pong(seq uint64) {
+	d.mu.Lock()
+	if seq != d.pingSeq || d.pingSent.IsZero() {
+		d.mu.Unlock()
+		return
+	}
+	rtt := time.Since(d.pingSent)
+	d.pingSent = time.Time{}
+	d.mu.Unlock()
+	d.counters.ping(rtt)
+}
+
+// serveStreams switches the Listener to streams mode, and then serves
+// the streams until the control connection fails.
+func (d *Dialer) serveStreams() (err error) {
+	defer func() { d.closeWithReason(err) }()
+	err = d.sendMessage(controlMsg{Command: "streams"})
+	d.streams.wmu.Unlock()
+	if err != nil {
+		return fmt.Errorf("revdial: writing to Listener: %w", err)
+	}
+	return d.streams.run()
+}
+
 func (d *Dialer) sendMessage(m controlMsg) error {
 	j, _ := json.Marshal(m)
 	d.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
@@ -239,22 +347,31 @@ type Listener struct {
 	dial   func(context.Context) (net.Conn, error)
 	writec chan<- []byte
 
+	counters counters
+
 	mu      sync.Mutex // guards below, closing connc, and writing to rw
 	readErr error
 	closed  bool
 }
 
+// Stats returns statistics of the Listener and its connections.
+func (ln *Listener) Stats() Stats { return ln.counters.stats() }
+
 type controlMsg struct {
-	Command  string `json:"command,omitempty"`  // "keep-alive", "conn-ready", "pickup-failed"
+	Command  string `json:"command,omitempty"`  // "keep-alive", "pong", "conn-ready", "pickup-failed", "streams"
 	ConnPath string `json:"connPath,omitempty"` // conn pick-up URL path for "conn-url", "pickup-failed"
 	Err      string `json:"err,omitempty"`
+	Ping     uint64 `json:"ping,omitempty"` // sequence number of a "keep-alive" to answer with a "pong"
 }
 
 // run reads control messages from the public server forever until the connection dies, which
 // then closes the listener.
 func (ln *Listener) run() {
-	defer ln.Close()
+	ln.closeWithReason(ln.serve())
+}
 
+// serve reads control messages until the connection dies, and returns why.
+func (ln *Listener) serve() error {
 	// Write loop
 	writec := make(chan []byte, 8)
 	ln.writec = writec
@@ -266,7 +383,7 @@ func (ln *Listener) run() {
 			case msg := <-writec:
 				if _, err := ln.sc.Write(msg); err != nil {
 					log.Printf("revdial.Listener: error writing message to server: %v", err)
-					ln.Close()
+					ln.closeWithReason(fmt.Errorf("revdial: writing to Dialer: %w", err))
 					return
 				}
 			}
@@ -278,29 +395,66 @@ func (ln *Listener) run() {
 	for {
 		line, err := br.ReadSlice('\n')
 		if err != nil {
-			return
+			return fmt.Errorf("revdial: reading from Dialer: %w", err)
 		}
 		var msg controlMsg
 		if err := json.Unmarshal(line, &msg); err != nil {
 			log.Printf("revdial.Listener read invalid JSON: %q: %v", line, err)
-			return
+			return fmt.Errorf("revdial: invalid control message from Dialer: %v", err)
 		}
 		switch msg.Command {
 		case "keep-alive":
-			// Occasional no-op message from server to keep
-			// us alive through NAT timeouts.
+			// Occasional message from server to keep us
+			// alive through NAT timeouts.
+			if msg.Ping != 0 {
+				go ln.sendMessage(controlMsg{Command: "pong", Ping: msg.Ping})
+			}
 		case "conn-ready":
 			go ln.grabConn(msg.ConnPath)
+		case "streams":
+			// The rest of the connection is frames.
+			return ln.serveStreams(br)
 		default:
 			// Ignore unknown messages
 		}
 	}
 }
 
+// serveStreams accepts the streams that the Dialer opens, until the
+// control connection fails.
+func (ln *Listener) serveStreams(br *bufio.Reader) error {
+	s := newSession(ln.sc, br, func(st *stream) {
+		go ln.deliver(st)
+	}, &ln.counters)
+	go func() {
+		<-ln.donec
+		s.close(nil)
+	}()
+	err := s.run()
+	if err != nil && !errors.Is(err, errSessionClosed) {
+		ln.mu.Lock()
+		ln.readErr = err
+		ln.mu.Unlock()
+	}
+	return err
+}
+
+// deliver passes c to Accept, or closes it if the Listener is closed.
+func (ln *Listener) deliver(c net.Conn) {
+	select {
+	case ln.connc <- c:
+	case <-ln.donec:
+		c.Close()
+	}
+}
+
 func (ln *Listener) sendMessage(m controlMsg) {
 	j, _ := json.Marshal(m)
 	j = append(j, '\n')
-	ln.writec <- j
+	select {
+	case ln.writec <- j:
+	case <-ln.donec:
+	}
 }
 
 func (ln *Listener) grabConn(path string) {
@@ -341,10 +495,7 @@ func (ln *Listener) grabConn(path string) {
 		return
 	}
 
-	select {
-	case ln.connc <- c:
-	case <-ln.donec:
-	}
+	ln.deliver(c)
 }
 
 // Closed reports whether the listener has been closed.
@@ -356,8 +507,10 @@ func (ln *Listener) Closed() bool {
 
 // Accept blocks and returns a new connection, or an error.
 func (ln *Listener) Accept() (net.Conn, error) {
-	c, ok := <-ln.connc
-	if !ok {
+	select {
+	case c := <-ln.connc:
+		return ln.counters.track(c), nil
+	case <-ln.donec:
 		ln.mu.Lock()
 		err, closed := ln.readErr, ln.closed
 		ln.mu.Unlock()
@@ -366,7 +519,6 @@ func (ln *Listener) Accept() (net.Conn, error) {
 		}
 		return nil, ErrListenerClosed
 	}
-	return c, nil
 }
 
 // ErrListenerClosed is returned by Accept after Close has been called.
@@ -375,16 +527,23 @@ var ErrListenerClosed = errors.New("revdial: Listener closed")
 // Close closes the Listener, making future Accept calls return an
 // error.
 func (ln *Listener) Close() error {
+	ln.closeWithReason(ErrListenerClosed)
+	return nil
+}
+
+// closeWithReason closes the Listener, unless it's already closed, and
+// records why.
+func (ln *Listener) closeWithReason(reason error) {
 	ln.mu.Lock()
-	defer ln.mu.Unlock()
 	if ln.closed {
-		return nil
+		ln.mu.Unlock()
+		return
 	}
 	go ln.sc.Close()
 	ln.closed = true
-	close(ln.connc)
+	ln.counters.closed(reason)
 	close(ln.donec)
-	return nil
+	ln.mu.Unlock()
 }
 
 // Addr returns a dummy address. This exists only to conform to the
@@ -433,6 +592,22 @@ func connHandler(w http.ResponseWriter, r *http.Request) {
 	d.matchConn(conn)
 }
 
+// BufferedConn returns a net.Conn that reads from br, which buffers
+// reads from c. It lets a Listener be created with a connection whose
+// protocol switch response was read with br, such as by
+// ReadProtoSwitchOrRedirect, which may have buffered the first control
+// messages too.
+func BufferedConn(c net.Conn, br *bufio.Reader) net.Conn {
+	return bufferedConn{c, br}
+}
+
+type bufferedConn struct {
+	net.Conn
+	br *bufio.Reader
+}
+
+func (c bufferedConn) Read(p []byte) (int, error) { return c.br.Read(p) }
+
 // checkRelativeURL verifies that URL s does not change scheme or host.
 func checkRelativeURL(s string) error {
 	u, err := url.Parse(s)
@@ -450,9 +625,22 @@ func checkRelativeURL(s string) error {
 	return nil
 }
 
+// A StatusError is the error of ReadProtoSwitchOrRedirect for a response
+// with a status other than a protocol switch or a redirect.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("want HTTP status 101 or 307; got %v:\n\t%s", e.Status, e.Body)
+}
+
 // ReadProtoSwitchOrRedirect is a helper for completing revdial protocol switch
 // requests. If the response indicates successful switch, nothing is returned.
 // If the response indicates a redirect, the new location is returned.
+// Any other status is reported as a *StatusError.
 func ReadProtoSwitchOrRedirect(r *bufio.Reader, req *http.Request) (location string, err error) {
 	resp, err := http.ReadResponse(r, req)
 	if err != nil {
@@ -476,6 +664,6 @@ func ReadProtoSwitchOrRedirect(r *bufio.Reader, req *http.Request) (location str
 		return location, nil
 	default:
 		msg, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("want HTTP status 101 or 307; got %v:\n\t%s", resp.Status, msg)
+		return "", &StatusError{resp.StatusCode, resp.Status, msg}
 	}
 }
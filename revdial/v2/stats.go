@@ -0,0 +1,160 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package revdial
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats are statistics of a Dialer or a Listener.
+type Stats struct {
+	// RTT is the round-trip time of the most recent ping of the peer
+	// over the control connection, or zero if none has completed.
+	// A Dialer pings in both modes, and a Listener only in streams mode.
+	RTT time.Duration
+	// Active is the number of connections that aren't closed yet.
+	Active int
+	// Pending is the number of Dial calls waiting for a connection.
+	Pending int
+	// Conns is the number of connections dialed or accepted.
+	Conns int64
+	// DialErrors is the number of Dial calls that failed.
+	DialErrors int64
+	// BytesRead and BytesWritten count the data of all connections.
+	BytesRead, BytesWritten int64
+	// CloseReason is why the control connection was closed, or empty
+	// while it's open.
+	CloseReason string
+}
+
+// ConnStats are statistics of a closed connection.
+type ConnStats struct {
+	BytesRead, BytesWritten int64
+	Duration                time.Duration
+}
+
+// A Trace has hooks that a Dialer calls. Any hook may be nil. Hooks are
+// called synchronously and must not block.
+type Trace struct {
+	// Ping is called with the round-trip time of each ping of the
+	// Listener over the control connection.
+	Ping func(rtt time.Duration)
+	// DialDone is called when Dial returns, with how long it took.
+	DialDone func(d time.Duration, err error)
+	// ConnClosed is called when a dialed connection is first closed.
+	ConnClosed func(ConnStats)
+	// Closed is called once the control connection is closed, with the
+	// reason.
+	Closed func(reason error)
+}
+
+// WithTrace makes the Dialer call the hooks of t.
+func WithTrace(t *Trace) DialerOption {
+	return func(d *Dialer) { d.counters.trace = t }
+}
+
+// counters count the activity of a Dialer or Listener.
+type counters struct {
+	trace *Trace // or nil
+
+	rtt                     atomic.Int64 // a time.Duration
+	active, pending         atomic.Int64
+	conns, dialErrors       atomic.Int64
+	bytesRead, bytesWritten atomic.Int64
+
+	mu          sync.Mutex
+	closeReason string
+}
+
+func (c *counters) stats() Stats {
+	c.mu.Lock()
+	reason := c.closeReason
+	c.mu.Unlock()
+	return Stats{
+		RTT:          time.Duration(c.rtt.Load()),
+		Active:       int(c.active.Load()),
+		Pending:      int(c.pending.Load()),
+		Conns:        c.conns.Load(),
+		DialErrors:   c.dialErrors.Load(),
+		BytesRead:    c.bytesRead.Load(),
+		BytesWritten: c.bytesWritten.Load(),
+		CloseReason:  reason,
+	}
+}
+
+func (c *counters) ping(rtt time.Duration) {
+	c.rtt.Store(int64(rtt))
+	if c.trace != nil && c.trace.Ping != nil {
+		c.trace.Ping(rtt)
+	}
+}
+
+func (c *counters) dialDone(start time.Time, err error) {
+	c.pending.Add(-1)
+	if err != nil {
+		c.dialErrors.Add(1)
+	}
+	if c.trace != nil && c.trace.DialDone != nil {
+		c.trace.DialDone(time.Since(start), err)
+	}
+}
+
+func (c *counters) closed(reason error) {
+	c.mu.Lock()
+	c.closeReason = reason.Error()
+	c.mu.Unlock()
+	if c.trace != nil && c.trace.Closed != nil {
+		c.trace.Closed(reason)
+	}
+}
+
+// track returns conn, counting its traffic.
+func (c *counters) track(conn net.Conn) net.Conn {
+	c.conns.Add(1)
+	c.active.Add(1)
+	return &countingConn{Conn: conn, c: c, start: time.Now()}
+}
+
+// A countingConn counts the traffic of a connection.
+type countingConn struct {
+	net.Conn
+	c     *counters
+	start time.Time
+
+	read, written atomic.Int64
+	closeOnce     sync.Once
+}
+
+func (cc *countingConn) Read(p []byte) (int, error) {
+	n, err := cc.Conn.Read(p)
+	cc.read.Add(int64(n))
+	cc.c.bytesRead.Add(int64(n))
+	return n, err
+}
+
+func (cc *countingConn) Write(p []byte) (int, error) {
+	n, err := cc.Conn.Write(p)
+	cc.written.Add(int64(n))
+	cc.c.bytesWritten.Add(int64(n))
+	return n, err
+}
+
+func (cc *countingConn) Close() error {
+	err := cc.Conn.Close()
+	cc.closeOnce.Do(func() {
+		cc.c.active.Add(-1)
+		if t := cc.c.trace; t != nil && t.ConnClosed != nil {
+			t.ConnClosed(ConnStats{
+				BytesRead:    cc.read.Load(),
+				BytesWritten: cc.written.Load(),
+				Duration:     time.Since(cc.start),
+			})
+		}
+	})
+	return err
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package revdial
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it's true or the test times out.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStatsRTT(t *testing.T) {
+	// The first keep-alive of a Dialer is sent right away, and a
+	// Listener answers it in either mode.
+	t.Run("classic", func(t *testing.T) {
+		dc, lc := net.Pipe()
+		ln := NewListener(lc, func(context.Context) (net.Conn, error) {
+			return nil, errors.New("no dialing back")
+		})
+		defer ln.Close()
+		d := NewDialer(dc, "/revdial")
+		defer d.Close()
+		waitFor(t, "RTT", func() bool { return d.Stats().RTT > 0 })
+	})
+	t.Run("streams", func(t *testing.T) {
+		old := keepAliveInterval
+		keepAliveInterval = 10 * time.Millisecond
+		t.Cleanup(func() { keepAliveInterval = old })
+		d, ln := newStreamsPair(t)
+		waitFor(t, "Dialer RTT", func() bool { return d.Stats().RTT > 0 })
+		waitFor(t, "Listener RTT", func() bool { return ln.Stats().RTT > 0 })
+	})
+}
+
+func TestStatsConns(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		dials  int
+		closed []ConnStats
+		reason error
+		trace  = &Trace{
+			DialDone: func(_ time.Duration, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				dials++
+			},
+			ConnClosed: func(cs ConnStats) {
+				mu.Lock()
+				defer mu.Unlock()
+				closed = append(closed, cs)
+			},
+			Closed: func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				reason = err
+			},
+		}
+	)
+	dc, lc := net.Pipe()
+	ln := NewListener(lc, nil)
+	defer ln.Close()
+	d := NewDialer(dc, "/revdial", Multiplexed(), WithTrace(trace))
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		io.Copy(c, c)
+	}()
+
+	c, err := d.Dial(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.Stats(); got.Active != 1 || got.Pending != 0 || got.Conns != 1 {
+		t.Errorf("Stats() of an open connection = %+v; want 1 active, 0 pending, 1 conn", got)
+	}
+	io.WriteString(c, "hello")
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(c, buf); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+	c.Close() // reported once
+
+	got := d.Stats()
+	if got.Active != 0 || got.BytesRead != 5 || got.BytesWritten != 5 {
+		t.Errorf("Stats() after Close = %+v; want 0 active, 5 bytes read and written", got)
+	}
+	waitFor(t, "Listener byte counts", func() bool {
+		s := ln.Stats()
+		return s.BytesRead == 5 && s.BytesWritten == 5
+	})
+
+	d.Close()
+	if got := d.Stats().CloseReason; got != ErrDialerClosed.Error() {
+		t.Errorf("CloseReason = %q; want %q", got, ErrDialerClosed)
+	}
+	waitFor(t, "Listener close", func() bool { return ln.Stats().CloseReason != "" })
+	mu.Lock()
+	defer mu.Unlock()
+	if dials != 1 {
+		t.Errorf("DialDone called %d times; want 1", dials)
+	}
+	if len(closed) != 1 || closed[0].BytesRead != 5 || closed[0].BytesWritten != 5 {
+		t.Errorf("ConnClosed calls = %+v; want one with 5 bytes read and written", closed)
+	}
+	if !errors.Is(reason, ErrDialerClosed) {
+		t.Errorf("Closed called with %v; want %v", reason, ErrDialerClosed)
+	}
+}
+
+func TestStatsCloseReason(t *testing.T) {
+	dc, lc := net.Pipe()
+	ln := NewListener(lc, nil)
+	d := NewDialer(dc, "/revdial", Multiplexed())
+	defer d.Close()
+	ln.Close()
+	<-d.Done()
+	if got := ln.Stats().CloseReason; got != ErrListenerClosed.Error() {
+		t.Errorf("Listener CloseReason = %q; want %q", got, ErrListenerClosed)
+	}
+	if got := d.Stats().CloseReason; got == "" || strings.Contains(got, "Dialer closed") {
+		t.Errorf("Dialer CloseReason = %q; want the Listener's disconnection", got)
+	}
+}
@@ -0,0 +1,504 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package revdial
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// In streams mode, the Dialer and Listener multiplex their connections
+// over the control connection instead of having the Listener dial back
+// for each one. The Dialer switches the control connection to streams
+// mode by sending a "streams" control message, after which both sides
+// only send frames. A frame is a 9-byte header, holding its type, its
+// stream ID and the length of its payload, followed by the payload.
+//
+// Each side may only send as much data on a stream as the other side
+// has room for: initially streamWindow bytes, plus the increments in
+// the window frames that the other side sends as it reads the data.
+const (
+	frameOpen   = 1 // the Dialer opens a stream
+	frameData   = 2 // stream data
+	frameWindow = 3 // a 4-byte increment of the sender's receive window
+	frameClose  = 4 // the sender closed the stream
+	framePing   = 5
+	framePong   = 6
+
+	frameHeaderLen  = 9
+	maxFramePayload = 16 << 10
+	streamWindow    = 256 << 10
+)
+
+// streamsHeader is the HTTP request header with which a Listener's
+// registration request offers to use streams mode.
+const streamsHeader = "X-Revdial-Streams"
+
+// keepAliveInterval is how often each side pings the other in streams
+// mode. A side closes the connection if it hears nothing from the other
+// for three intervals.
+var keepAliveInterval = 30 * time.Second
+
+// ErrKeepAliveTimeout is the close reason of a Dialer or Listener in
+// streams mode that heard nothing from its peer for too long.
+var ErrKeepAliveTimeout = errors.New("revdial: no keep-alive from peer")
+
+// OfferStreams marks req, the request that registers a Listener with a
+// server, as offering to multiplex connections over the registered
+// connection. NewListener accepts both modes, so a Listener whose
+// request offers streams works with Dialers that don't use them.
+func OfferStreams(req *http.Request) {
+	req.Header.Set(streamsHeader, "1")
+}
+
+// StreamsOffered reports whether the registration request r of a
+// Listener offers streams, in which case the server may create its
+// Dialer with the Multiplexed option.
+func StreamsOffered(r *http.Request) bool {
+	return r.Header.Get(streamsHeader) == "1"
+}
+
+// errSessionClosed is the error of streams whose control connection
+// was closed.
+var errSessionClosed = errors.New("revdial: connection closed")
+
+// A session multiplexes streams over a control connection.
+type session struct {
+	conn net.Conn
+	br   *bufio.Reader
+	// accept is called with each stream that the other side opens.
+	// It is nil for the Dialer, which the Listener can't open streams to.
+	accept func(*stream)
+	// interval is the keep-alive interval.
+	interval time.Duration
+	counters *counters
+
+	wmu sync.Mutex // serializes writes to conn
+
+	mu       sync.Mutex
+	streams  map[uint32]*stream
+	nextID   uint32
+	lastRecv time.Time
+	pingSent time.Time // when the unanswered ping was sent, if any
+	err      error     // set once the session is closed
+	donec    chan struct{}
+}
+
+func newSession(conn net.Conn, br *bufio.Reader, accept func(*stream), c *counters) *session {
+	return &session{
+		conn:     conn,
+		br:       br,
+		accept:   accept,
+		interval: keepAliveInterval,
+		counters: c,
+		streams:  make(map[uint32]*stream),
+		lastRecv: time.Now(),
+		donec:    make(chan struct{}),
+	}
+}
+
+// run reads frames and pings the other side until the connection
+// fails or the session is closed, and returns why it was closed.
+func (s *session) run() error {
+	go s.keepAlive()
+	s.close(s.readFrames())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *session) keepAlive() {
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.donec:
+			return
+		case <-t.C:
+		}
+		s.mu.Lock()
+		idle := time.Since(s.lastRecv)
+		if s.pingSent.IsZero() {
+			s.pingSent = time.Now()
+		}
+		s.mu.Unlock()
+		if idle > 3*s.interval {
+			s.close(fmt.Errorf("%w for %v", ErrKeepAliveTimeout, idle.Round(time.Second)))
+			return
+		}
+		if err := s.writeFrame(framePing, 0, nil); err != nil {
+			s.close(err)
+			return
+		}
+	}
+}
+
+func (s *session) readFrames() error {
+	var hdr [frameHeaderLen]byte
+	for {
+		if _, err := io.ReadFull(s.br, hdr[:]); err != nil {
+			return err
+		}
+		typ, id, n := hdr[0], binary.BigEndian.Uint32(hdr[1:5]), binary.BigEndian.Uint32(hdr[5:9])
+		if n > maxFramePayload {
+			return fmt.Errorf("revdial: frame of %d bytes is too large", n)
+		}
+		payload := make([]byte, n)
+		if _, err := io.ReadFull(s.br, payload); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.lastRecv = time.Now()
+		st := s.streams[id]
+		s.mu.Unlock()
+
+		switch typ {
+		case frameOpen:
+			if s.accept == nil || st != nil {
+				return fmt.Errorf("revdial: unexpected open of stream %d", id)
+			}
+			st = newStream(s, id)
+			s.mu.Lock()
+			if s.err != nil {
+				s.mu.Unlock()
+				return s.err
+			}
+			s.streams[id] = st
+			s.mu.Unlock()
+			s.accept(st)
+		case frameData:
+			// Data for a stream that was closed on this side is dropped.
+			if st != nil && !st.receive(payload) {
+				return fmt.Errorf("revdial: stream %d exceeded its flow control window", id)
+			}
+		case frameWindow:
+			if n != 4 {
+				return fmt.Errorf("revdial: window frame has %d bytes", n)
+			}
+			if st != nil {
+				st.addSendWindow(int(binary.BigEndian.Uint32(payload)))
+			}
+		case frameClose:
+			if st != nil {
+				st.closeRemote()
+			}
+		case framePing:
+			// Don't block reading on the write: over an unbuffered
+			// connection, the peer may be writing its own pong.
+			go func() {
+				if err := s.writeFrame(framePong, 0, nil); err != nil {
+					s.close(err)
+				}
+			}()
+		case framePong:
+			s.mu.Lock()
+			sent := s.pingSent
+			s.pingSent = time.Time{}
+			s.mu.Unlock()
+			if !sent.IsZero() {
+				s.counters.ping(time.Since(sent))
+			}
+		default:
+			// Ignore unknown frames.
+		}
+	}
+}
+
+// writeFrame writes a frame to the connection.
+func (s *session) writeFrame(typ byte, id uint32, payload []byte) error {
+	buf := make([]byte, frameHeaderLen+len(payload))
+	buf[0] = typ
+	binary.BigEndian.PutUint32(buf[1:5], id)
+	binary.BigEndian.PutUint32(buf[5:9], uint32(len(payload)))
+	copy(buf[frameHeaderLen:], payload)
+
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	s.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	_, err := s.conn.Write(buf)
+	s.conn.SetWriteDeadline(time.Time{})
+	return err
+}
+
+// open opens a new stream to the other side.
+func (s *session) open(ctx context.Context) (net.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return nil, s.err
+	}
+	s.nextID++
+	st := newStream(s, s.nextID)
+	s.streams[st.id] = st
+	s.mu.Unlock()
+	if err := s.writeFrame(frameOpen, st.id, nil); err != nil {
+		s.close(err)
+		return nil, err
+	}
+	return st, nil
+}
+
+// close closes the connection and fails its streams with err, unless
+// the session is already closed.
+func (s *session) close(err error) {
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return
+	}
+	switch {
+	case err == nil || errors.Is(err, net.ErrClosed):
+		err = errSessionClosed
+	case errors.Is(err, io.EOF):
+		// The peer went away; keep the EOF for the close reason.
+		err = fmt.Errorf("%w: %w", errSessionClosed, err)
+	}
+	s.err = err
+	streams := s.streams
+	s.streams = nil
+	close(s.donec)
+	s.mu.Unlock()
+
+	s.conn.Close()
+	for _, st := range streams {
+		st.fail(err)
+	}
+}
+
+func (s *session) remove(id uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.streams, id)
+}
+
+// A stream is a net.Conn multiplexed over a session.
+type stream struct {
+	s  *session
+	id uint32
+
+	mu            sync.Mutex
+	buf           bytes.Buffer // received data not yet read
+	recvWindow    int          // bytes the other side may still send
+	unacked       int          // bytes read but not yet added back to recvWindow
+	sendWindow    int          // bytes this side may still send
+	localClosed   bool
+	remoteClosed  bool
+	err           error // set if the session failed
+	readDeadline  time.Time
+	writeDeadline time.Time
+	changed       chan struct{} // closed when any of the above changes
+}
+
+var _ net.Conn = (*stream)(nil)
+
+func newStream(s *session, id uint32) *stream {
+	return &stream{
+		s:          s,
+		id:         id,
+		recvWindow: streamWindow,
+		sendWindow: streamWindow,
+		changed:    make(chan struct{}),
+	}
+}
+
+// broadcastLocked wakes the callers of Read and Write waiting for st
+// to change. The lock st.mu must be held.
+func (st *stream) broadcastLocked() {
+	close(st.changed)
+	st.changed = make(chan struct{})
+}
+
+// wait waits until ch is closed or the deadline, if any, passes.
+func wait(ch <-chan struct{}, deadline time.Time) {
+	if deadline.IsZero() {
+		<-ch
+		return
+	}
+	t := time.NewTimer(time.Until(deadline))
+	defer t.Stop()
+	select {
+	case <-ch:
+	case <-t.C:
+	}
+}
+
+// receive buffers data from the other side, and reports whether it fit
+// in the receive window.
+func (st *stream) receive(data []byte) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if len(data) > st.recvWindow {
+		return false
+	}
+	st.recvWindow -= len(data)
+	st.buf.Write(data)
+	st.broadcastLocked()
+	return true
+}
+
+func (st *stream) addSendWindow(n int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sendWindow += n
+	st.broadcastLocked()
+}
+
+func (st *stream) closeRemote() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.remoteClosed = true
+	st.broadcastLocked()
+}
+
+func (st *stream) fail(err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.err = err
+	st.broadcastLocked()
+}
+
+func (st *stream) Read(p []byte) (int, error) {
+	st.mu.Lock()
+	for {
+		if st.buf.Len() > 0 {
+			n, _ := st.buf.Read(p)
+			st.unacked += n
+			var incr int
+			if st.unacked >= streamWindow/2 && !st.remoteClosed && st.err == nil {
+				incr = st.unacked
+				st.recvWindow += incr
+				st.unacked = 0
+			}
+			st.mu.Unlock()
+			if incr > 0 {
+				var payload [4]byte
+				binary.BigEndian.PutUint32(payload[:], uint32(incr))
+				if err := st.s.writeFrame(frameWindow, st.id, payload[:]); err != nil {
+					st.s.close(err)
+				}
+			}
+			return n, nil
+		}
+		switch {
+		case st.localClosed:
+			st.mu.Unlock()
+			return 0, net.ErrClosed
+		case st.remoteClosed:
+			st.mu.Unlock()
+			return 0, io.EOF
+		case st.err != nil:
+			err := st.err
+			st.mu.Unlock()
+			return 0, err
+		case !st.readDeadline.IsZero() && !time.Now().Before(st.readDeadline):
+			st.mu.Unlock()
+			return 0, os.ErrDeadlineExceeded
+		}
+		ch, deadline := st.changed, st.readDeadline
+		st.mu.Unlock()
+		wait(ch, deadline)
+		st.mu.Lock()
+	}
+}
+
+func (st *stream) Write(p []byte) (int, error) {
+	written := 0
+	st.mu.Lock()
+	for len(p) > 0 {
+		switch {
+		case st.localClosed:
+			st.mu.Unlock()
+			return written, net.ErrClosed
+		case st.err != nil:
+			err := st.err
+			st.mu.Unlock()
+			return written, err
+		case st.remoteClosed:
+			st.mu.Unlock()
+			return written, io.ErrClosedPipe
+		case !st.writeDeadline.IsZero() && !time.Now().Before(st.writeDeadline):
+			st.mu.Unlock()
+			return written, os.ErrDeadlineExceeded
+		}
+		if st.sendWindow == 0 {
+			ch, deadline := st.changed, st.writeDeadline
+			st.mu.Unlock()
+			wait(ch, deadline)
+			st.mu.Lock()
+			continue
+		}
+		n := min(len(p), st.sendWindow, maxFramePayload)
+		st.sendWindow -= n
+		st.mu.Unlock()
+		if err := st.s.writeFrame(frameData, st.id, p[:n]); err != nil {
+			st.s.close(err)
+			return written, err
+		}
+		written += n
+		p = p[n:]
+		st.mu.Lock()
+	}
+	st.mu.Unlock()
+	return written, nil
+}
+
+// Close closes the stream and tells the other side.
+func (st *stream) Close() error {
+	st.mu.Lock()
+	if st.localClosed {
+		st.mu.Unlock()
+		return nil
+	}
+	st.localClosed = true
+	failed := st.err != nil
+	st.broadcastLocked()
+	st.mu.Unlock()
+
+	st.s.remove(st.id)
+	if failed {
+		return nil
+	}
+	return st.s.writeFrame(frameClose, st.id, nil)
+}
+
+func (st *stream) LocalAddr() net.Addr  { return fakeAddr{} }
+func (st *stream) RemoteAddr() net.Addr { return fakeAddr{} }
+
+func (st *stream) SetDeadline(t time.Time) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.readDeadline, st.writeDeadline = t, t
+	st.broadcastLocked()
+	return nil
+}
+
+func (st *stream) SetReadDeadline(t time.Time) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.readDeadline = t
+	st.broadcastLocked()
+	return nil
+}
+
+func (st *stream) SetWriteDeadline(t time.Time) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.writeDeadline = t
+	st.broadcastLocked()
+	return nil
+}
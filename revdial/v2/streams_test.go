@@ -0,0 +1,221 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package revdial
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newStreamsPair returns a Dialer and Listener in streams mode,
+// connected by a pipe.
+func newStreamsPair(t *testing.T) (*Dialer, *Listener) {
+	dc, lc := net.Pipe()
+	noDial := func(context.Context) (net.Conn, error) {
+		t.Error("Listener dialed back in streams mode")
+		return nil, errors.New("no dialing back")
+	}
+	ln := NewListener(lc, noDial)
+	d := NewDialer(dc, "/revdial", Multiplexed())
+	t.Cleanup(func() {
+		d.Close()
+		ln.Close()
+	})
+	return d, ln
+}
+
+func TestStreamsHTTP(t *testing.T) {
+	d, ln := newStreamsPair(t)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello "+r.URL.Path)
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return d.Dial(ctx)
+		},
+		// Use a new stream for each request.
+		DisableKeepAlives: true,
+	}}
+	var wg sync.WaitGroup
+	for _, path := range []string{"/a", "/b", "/c", "/d"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get("http://buildlet" + path)
+			if err != nil {
+				t.Errorf("Get(%s) = %v", path, err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil || string(body) != "hello "+path {
+				t.Errorf("Get(%s) body = %q, %v; want %q", path, body, err, "hello "+path)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStreamsFlowControl(t *testing.T) {
+	d, ln := newStreamsPair(t)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		io.Copy(c, c)
+	}()
+
+	c, err := d.Dial(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	// Send more than the windows of both directions, so the echo
+	// only completes if each side tells the other as it reads.
+	want := make([]byte, 4*streamWindow+123)
+	rand.Read(want)
+	go func() {
+		if _, err := c.Write(want); err != nil {
+			t.Errorf("Write() = %v", err)
+		}
+	}()
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(c, got); err != nil {
+		t.Fatalf("ReadFull() = %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("echoed data differs from the data written")
+	}
+}
+
+func TestStreamsClose(t *testing.T) {
+	d, ln := newStreamsPair(t)
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		c, err := ln.Accept()
+		if err == nil {
+			accepted <- c
+		}
+	}()
+	c, err := d.Dial(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(c, "bye")
+	c.Close()
+
+	lc := <-accepted
+	defer lc.Close()
+	if got, err := io.ReadAll(lc); err != nil || string(got) != "bye" {
+		t.Errorf("ReadAll() after peer closed = %q, %v; want %q, nil", got, err, "bye")
+	}
+	if _, err := lc.Write([]byte("x")); err == nil {
+		t.Errorf("Write() to a stream closed by its peer succeeded; want error")
+	}
+	if _, err := c.Read(make([]byte, 1)); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("Read() after Close = %v; want net.ErrClosed", err)
+	}
+
+	// Closing the Dialer fails its streams and closes the Listener.
+	c2, err := d.Dial(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+	if _, err := c2.Read(make([]byte, 1)); err == nil {
+		t.Errorf("Read() after Dialer closed succeeded; want error")
+	}
+	if _, err := d.Dial(context.Background()); err == nil {
+		t.Errorf("Dial() after Dialer closed succeeded; want error")
+	}
+	for {
+		c, err := ln.Accept()
+		if err != nil {
+			break
+		}
+		c.Close()
+	}
+}
+
+func TestStreamsDeadline(t *testing.T) {
+	d, _ := newStreamsPair(t)
+	c, err := d.Dial(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	if _, err := c.Read(make([]byte, 1)); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("Read() past its deadline = %v; want os.ErrDeadlineExceeded", err)
+	}
+
+	// Moving the deadline into the past interrupts a blocked Read.
+	c.SetReadDeadline(time.Time{})
+	errc := make(chan error, 1)
+	go func() {
+		_, err := c.Read(make([]byte, 1))
+		errc <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	c.SetReadDeadline(time.Unix(1, 0))
+	if err := <-errc; !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("Read() interrupted by a past deadline = %v; want os.ErrDeadlineExceeded", err)
+	}
+}
+
+func TestStreamsKeepAlive(t *testing.T) {
+	old := keepAliveInterval
+	keepAliveInterval = 10 * time.Millisecond
+	t.Cleanup(func() { keepAliveInterval = old })
+
+	// A peer that reads everything but never answers.
+	dc, peer := net.Pipe()
+	defer peer.Close()
+	go io.Copy(io.Discard, peer)
+	d := NewDialer(dc, "/revdial", Multiplexed())
+	defer d.Close()
+	select {
+	case <-d.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Dialer didn't close without keep-alives from its peer")
+	}
+	if got := d.Stats().CloseReason; !strings.HasPrefix(got, ErrKeepAliveTimeout.Error()) {
+		t.Errorf("CloseReason = %q; want %q", got, ErrKeepAliveTimeout)
+	}
+
+	// A Listener in streams mode answers, so the Dialer stays open.
+	d2, _ := newStreamsPair(t)
+	select {
+	case <-d2.Done():
+		t.Fatal("Dialer closed despite keep-alives from its Listener")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestStreamsOffered(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/reverse", nil)
+	if StreamsOffered(req) {
+		t.Errorf("StreamsOffered() = true for a request without the offer")
+	}
+	OfferStreams(req)
+	if !StreamsOffered(req) {
+		t.Errorf("StreamsOffered() = false after OfferStreams")
+	}
+}
@@ -0,0 +1,488 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tarutil
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Format is an archive format that WriteReproducible writes.
+type Format int
+
+const (
+	FormatTarGz  Format = iota // gzip-compressed tar
+	FormatZip                  // zip, with deflated files
+	FormatCpioGz               // gzip-compressed cpio, in the portable ASCII ("odc") format
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatTarGz:
+		return "tar.gz"
+	case FormatZip:
+		return "zip"
+	case FormatCpioGz:
+		return "cpio.gz"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// WriteReproducible writes the contents of the FileList to w as an
+// archive of the given format, such that the same files always produce
+// the same bytes, regardless of the order in which they were added or
+// of who added them:
+//
+//   - the entries are sorted by name;
+//   - every entry has the modification time modTime, truncated to the
+//     second, owner and group 0, and no owner or group names;
+//   - directories have mode 0755, symbolic links 0777, and regular files
+//     0755 if any of their execute bits is set and 0644 otherwise;
+//   - the gzip header records no name and no modification time.
+//
+// The FileList may only contain regular files, directories, and
+// symbolic links. Entries are named as in the tar headers, except that
+// the names of directories end in a slash in tar and zip files, and in
+// no slash in cpio files.
+func (fl *FileList) WriteReproducible(w io.Writer, format Format, modTime time.Time) error {
+	files, err := fl.normalize(modTime)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case FormatTarGz:
+		zw := newReproducibleGzip(w)
+		if err := writeTarEntries(zw, files); err != nil {
+			return err
+		}
+		return zw.Close()
+	case FormatZip:
+		return writeZipEntries(w, files)
+	case FormatCpioGz:
+		zw := newReproducibleGzip(w)
+		if err := writeCpioEntries(zw, files); err != nil {
+			return err
+		}
+		return zw.Close()
+	}
+	return fmt.Errorf("tarutil: unsupported format %v", format)
+}
+
+// newReproducibleGzip returns a gzip.Writer whose header doesn't depend
+// on the input: the zero Header records no name, no modification time,
+// and an unknown operating system.
+func newReproducibleGzip(w io.Writer) *gzip.Writer {
+	zw, _ := gzip.NewWriterLevel(w, gzip.BestCompression)
+	return zw
+}
+
+// normalize returns copies of the entries of the FileList with
+// normalized headers, sorted by name.
+func (fl *FileList) normalize(modTime time.Time) ([]headerContent, error) {
+	modTime = modTime.UTC().Truncate(time.Second)
+	files := make([]headerContent, 0, len(fl.files))
+	seen := make(map[string]bool)
+	for _, f := range fl.files {
+		h := f.header
+		name := strings.TrimSuffix(h.Name, "/")
+		if name == "" {
+			return nil, fmt.Errorf("tarutil: entry with an empty name")
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("tarutil: duplicate entry %q", name)
+		}
+		seen[name] = true
+		nh := &tar.Header{
+			Name:     name,
+			Typeflag: h.Typeflag,
+			ModTime:  modTime,
+		}
+		switch h.Typeflag {
+		case tar.TypeReg:
+			nh.Mode = 0644
+			if h.Mode&0111 != 0 {
+				nh.Mode = 0755
+			}
+			nh.Size = f.size
+		case tar.TypeDir:
+			nh.Mode = 0755
+		case tar.TypeSymlink:
+			nh.Mode = 0777
+			nh.Linkname = h.Linkname
+		default:
+			return nil, fmt.Errorf("tarutil: %s: unsupported entry type %q", name, h.Typeflag)
+		}
+		files = append(files, headerContent{header: nh, size: f.size, content: f.content})
+	}
+	slices.SortFunc(files, func(a, b headerContent) int {
+		return strings.Compare(a.header.Name, b.header.Name)
+	})
+	return files, nil
+}
+
+// contentReader returns a reader of the content of f, which is empty for
+// anything but a regular file.
+func (f headerContent) contentReader() io.Reader {
+	if f.header.Typeflag != tar.TypeReg || f.content == nil {
+		return strings.NewReader("")
+	}
+	return io.NewSectionReader(f.content, 0, f.size)
+}
+
+func writeTarEntries(w io.Writer, files []headerContent) error {
+	tw := tar.NewWriter(w)
+	for _, f := range files {
+		h := *f.header
+		if h.Typeflag == tar.TypeDir {
+			h.Name += "/"
+		}
+		if err := tw.WriteHeader(&h); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(tw, f.contentReader(), h.Size); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func writeZipEntries(w io.Writer, files []headerContent) error {
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		h := f.header
+		zh := &zip.FileHeader{
+			Name:     h.Name,
+			Method:   zip.Deflate,
+			Modified: h.ModTime,
+		}
+		zh.SetMode(h.FileInfo().Mode())
+		content := f.contentReader()
+		switch h.Typeflag {
+		case tar.TypeDir:
+			zh.Name += "/"
+			zh.Method = zip.Store
+		case tar.TypeSymlink:
+			// Zip records the target of a symbolic link as its content.
+			content = strings.NewReader(h.Linkname)
+		}
+		fw, err := zw.CreateHeader(zh)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(fw, content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// The cpio header fields, in the portable ASCII format. Each is an octal
+// number of the given width, after the magic number "070707".
+var cpioFields = []struct {
+	name  string
+	width int
+}{
+	{"dev", 6}, {"ino", 6}, {"mode", 6}, {"uid", 6}, {"gid", 6}, {"nlink", 6},
+	{"rdev", 6}, {"mtime", 11}, {"namesize", 6}, {"filesize", 11},
+}
+
+const (
+	cpioMagic      = "070707"
+	cpioHeaderSize = 76
+	cpioTrailer    = "TRAILER!!!"
+
+	cpioTypeReg     = 0100000
+	cpioTypeDir     = 0040000
+	cpioTypeSymlink = 0120000
+)
+
+// writeCpioHeader writes a cpio header with the given field values, in
+// the order of cpioFields, and the name.
+func writeCpioHeader(w io.Writer, name string, values ...int64) error {
+	var b strings.Builder
+	b.WriteString(cpioMagic)
+	for i, f := range cpioFields {
+		s := strconv.FormatInt(values[i], 8)
+		if len(s) > f.width {
+			return fmt.Errorf("tarutil: %s: cpio %s %d is too large", name, f.name, values[i])
+		}
+		b.WriteString(strings.Repeat("0", f.width-len(s)))
+		b.WriteString(s)
+	}
+	b.WriteString(name)
+	b.WriteByte(0)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeCpioEntries(w io.Writer, files []headerContent) error {
+	for i, f := range files {
+		h := f.header
+		mode, size := h.Mode, h.Size
+		content := f.contentReader()
+		switch h.Typeflag {
+		case tar.TypeReg:
+			mode |= cpioTypeReg
+		case tar.TypeDir:
+			mode |= cpioTypeDir
+		case tar.TypeSymlink:
+			// Like zip, cpio records the target of a symbolic link
+			// as its content.
+			mode |= cpioTypeSymlink
+			size = int64(len(h.Linkname))
+			content = strings.NewReader(h.Linkname)
+		}
+		// Number the inodes in order, so that no entries look like
+		// hard links of each other.
+		ino := int64(i + 1)
+		if err := writeCpioHeader(w, h.Name, 0, ino, mode, 0, 0, 1, 0, h.ModTime.Unix(), int64(len(h.Name)+1), size); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(w, content, size); err != nil {
+			return err
+		}
+	}
+	return writeCpioHeader(w, cpioTrailer, 0, 0, 0, 0, 0, 1, 0, 0, int64(len(cpioTrailer)+1), 0)
+}
+
+// An Entry is an entry of an archive, as read by ReadEntries.
+type Entry struct {
+	Name     string      // without a trailing slash
+	Mode     fs.FileMode // including the type bits
+	ModTime  time.Time
+	Uid, Gid int
+	Linkname string // target of a symbolic link
+	Size     int64
+	SHA256   [sha256.Size]byte // of the content of a regular file
+}
+
+// ReadEntries returns the entries of the archive data of the given
+// format, in the order in which they appear.
+func ReadEntries(data []byte, format Format) ([]Entry, error) {
+	switch format {
+	case FormatTarGz:
+		return readTarEntries(data)
+	case FormatZip:
+		return readZipEntries(data)
+	case FormatCpioGz:
+		return readCpioEntries(data)
+	}
+	return nil, fmt.Errorf("tarutil: unsupported format %v", format)
+}
+
+func readTarEntries(data []byte) ([]Entry, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	tr := tar.NewReader(zr)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		e := Entry{
+			Name:     strings.TrimSuffix(h.Name, "/"),
+			Mode:     h.FileInfo().Mode(),
+			ModTime:  h.ModTime.UTC(),
+			Uid:      h.Uid,
+			Gid:      h.Gid,
+			Linkname: h.Linkname,
+		}
+		if e.Size, e.SHA256, err = hashContent(tr); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+}
+
+func readZipEntries(data []byte) ([]Entry, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, f := range zr.File {
+		e := Entry{
+			Name:    strings.TrimSuffix(f.Name, "/"),
+			Mode:    f.Mode(),
+			ModTime: f.Modified.UTC(),
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		if e.Mode&fs.ModeSymlink != 0 {
+			target, err := io.ReadAll(rc)
+			if err != nil {
+				rc.Close()
+				return nil, err
+			}
+			e.Linkname = string(target)
+		} else if e.Size, e.SHA256, err = hashContent(rc); err != nil {
+			rc.Close()
+			return nil, err
+		}
+		rc.Close()
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func readCpioEntries(data []byte) ([]Entry, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(zr)
+	var entries []Entry
+	hdr := make([]byte, cpioHeaderSize)
+	for {
+		if _, err := io.ReadFull(br, hdr); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("tarutil: reading cpio header: %w", err)
+		}
+		if string(hdr[:len(cpioMagic)]) != cpioMagic {
+			return nil, fmt.Errorf("tarutil: malformed cpio header")
+		}
+		values := make(map[string]int64)
+		off := len(cpioMagic)
+		for _, f := range cpioFields {
+			v, err := strconv.ParseInt(string(hdr[off:off+f.width]), 8, 64)
+			if err != nil {
+				return nil, fmt.Errorf("tarutil: malformed cpio header %s", f.name)
+			}
+			values[f.name] = v
+			off += f.width
+		}
+		nameBuf := make([]byte, values["namesize"])
+		if _, err := io.ReadFull(br, nameBuf); err != nil {
+			return nil, fmt.Errorf("tarutil: reading cpio entry name: %w", err)
+		}
+		if len(nameBuf) == 0 || nameBuf[len(nameBuf)-1] != 0 {
+			return nil, fmt.Errorf("tarutil: malformed cpio entry name")
+		}
+		name := string(nameBuf[:len(nameBuf)-1])
+		if name == cpioTrailer {
+			return entries, nil
+		}
+
+		mode := values["mode"]
+		e := Entry{
+			Name:    strings.TrimSuffix(name, "/"),
+			Mode:    fs.FileMode(mode & 0777),
+			ModTime: time.Unix(values["mtime"], 0).UTC(),
+			Uid:     int(values["uid"]),
+			Gid:     int(values["gid"]),
+		}
+		content := io.LimitReader(br, values["filesize"])
+		switch mode & 0170000 {
+		case cpioTypeDir:
+			e.Mode |= fs.ModeDir
+		case cpioTypeSymlink:
+			e.Mode |= fs.ModeSymlink
+			target, err := io.ReadAll(content)
+			if err != nil {
+				return nil, err
+			}
+			e.Linkname = string(target)
+		}
+		if e.Size, e.SHA256, err = hashContent(content); err != nil {
+			return nil, err
+		}
+		if e.Mode&fs.ModeSymlink == 0 && e.Size != values["filesize"] {
+			return nil, fmt.Errorf("tarutil: %s: short cpio entry", e.Name)
+		}
+		entries = append(entries, e)
+	}
+}
+
+// hashContent returns the size and SHA-256 hash of the content of r.
+func hashContent(r io.Reader) (int64, [sha256.Size]byte, error) {
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return n, sum, err
+}
+
+// Compare compares the archives a and b of the given format entry by
+// entry, and returns a description of each difference between them:
+// entries that only one has, entries whose fields or content differ,
+// and a different order of the entries. Archives for which it returns
+// no differences may still differ in their bytes, for instance in their
+// compression.
+func Compare(a, b []byte, format Format) ([]string, error) {
+	ea, err := ReadEntries(a, format)
+	if err != nil {
+		return nil, fmt.Errorf("reading first archive: %w", err)
+	}
+	eb, err := ReadEntries(b, format)
+	if err != nil {
+		return nil, fmt.Errorf("reading second archive: %w", err)
+	}
+
+	byName := make(map[string]*Entry)
+	for i := range eb {
+		byName[eb[i].Name] = &eb[i]
+	}
+	var diffs []string
+	field := func(name, what string, va, vb any) {
+		if va != vb {
+			diffs = append(diffs, fmt.Sprintf("%s: %s %v != %v", name, what, va, vb))
+		}
+	}
+	inA := make(map[string]bool)
+	for _, x := range ea {
+		inA[x.Name] = true
+		y, ok := byName[x.Name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: only in first archive", x.Name))
+			continue
+		}
+		field(x.Name, "mode", x.Mode, y.Mode)
+		if !x.ModTime.Equal(y.ModTime) {
+			diffs = append(diffs, fmt.Sprintf("%s: mtime %v != %v", x.Name, x.ModTime, y.ModTime))
+		}
+		field(x.Name, "uid", x.Uid, y.Uid)
+		field(x.Name, "gid", x.Gid, y.Gid)
+		field(x.Name, "linkname", x.Linkname, y.Linkname)
+		field(x.Name, "size", x.Size, y.Size)
+		if x.SHA256 != y.SHA256 {
+			diffs = append(diffs, fmt.Sprintf("%s: content differs", x.Name))
+		}
+	}
+	for _, y := range eb {
+		if !inA[y.Name] {
+			diffs = append(diffs, fmt.Sprintf("%s: only in second archive", y.Name))
+		}
+	}
+	if len(diffs) == 0 {
+		for i := range ea {
+			if ea[i].Name != eb[i].Name {
+				diffs = append(diffs, fmt.Sprintf("entry %d: %s != %s", i, ea[i].Name, eb[i].Name))
+				break
+			}
+		}
+	}
+	return diffs, nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tarutil
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testModTime = time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC)
+
+// testFileList returns a FileList of a few files, added in the given
+// order, with headers that vary like those from different machines.
+func testFileList(t *testing.T, order []int, owner int) *FileList {
+	type file struct {
+		fi      fileInfo
+		content string
+	}
+	files := []file{
+		{fileInfo{name: "go/bin/go", mode: 0775, size: 6}, "binary"},
+		{fileInfo{name: "go/VERSION", mode: 0664, size: 8}, "go1.99.0"},
+		{fileInfo{name: "go", mode: 0775 | os.ModeDir}, ""},
+		{fileInfo{name: "go/bin/", mode: 0700 | os.ModeDir}, ""},
+		{fileInfo{name: "go/link", mode: 0755 | os.ModeSymlink, target: "VERSION"}, ""},
+	}
+	fl := new(FileList)
+	for _, i := range order {
+		f := files[i]
+		h := tarHeader(t, f.fi)
+		h.Uid, h.Gid, h.Uname = owner, owner, "user"
+		h.ModTime = time.Unix(int64(1e9+owner), 0)
+		if f.fi.mode.IsRegular() {
+			fl.AddRegular(h, f.fi.size, strings.NewReader(f.content))
+		} else {
+			fl.AddHeader(h)
+		}
+	}
+	return fl
+}
+
+var testFormats = []Format{FormatTarGz, FormatZip, FormatCpioGz}
+
+func TestWriteReproducible(t *testing.T) {
+	for _, format := range testFormats {
+		t.Run(format.String(), func(t *testing.T) {
+			var a, b bytes.Buffer
+			if err := testFileList(t, []int{0, 1, 2, 3, 4}, 1000).WriteReproducible(&a, format, testModTime); err != nil {
+				t.Fatal(err)
+			}
+			// The same files, added in another order by someone else, at
+			// a time in another zone and with a fraction of a second.
+			modTime := testModTime.In(time.FixedZone("x", 3600)).Add(time.Second / 3)
+			if err := testFileList(t, []int{4, 2, 1, 3, 0}, 42).WriteReproducible(&b, format, modTime); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(a.Bytes(), b.Bytes()) {
+				diffs, err := Compare(a.Bytes(), b.Bytes(), format)
+				t.Fatalf("archives differ; Compare = %q, %v", diffs, err)
+			}
+			if format != FormatZip {
+				// The gzip header has no name and no modification time.
+				if got, want := a.Bytes()[3:8], []byte{0, 0, 0, 0, 0}; !bytes.Equal(got, want) {
+					t.Errorf("gzip header flags and mtime = %x; want %x", got, want)
+				}
+			}
+
+			entries, err := ReadEntries(a.Bytes(), format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, e := range entries {
+				names = append(names, e.Name)
+				if !e.ModTime.Equal(testModTime) || e.Uid != 0 || e.Gid != 0 {
+					t.Errorf("%s: mtime %v, uid %d, gid %d; want %v, 0, 0", e.Name, e.ModTime, e.Uid, e.Gid, testModTime)
+				}
+			}
+			if want := []string{"go", "go/VERSION", "go/bin", "go/bin/go", "go/link"}; !slices.Equal(names, want) {
+				t.Errorf("entries %q; want %q", names, want)
+			}
+			modes := map[string]fs.FileMode{
+				"go":         0755 | fs.ModeDir,
+				"go/VERSION": 0644,
+				"go/bin":     0755 | fs.ModeDir,
+				"go/bin/go":  0755,
+				"go/link":    0777 | fs.ModeSymlink,
+			}
+			for _, e := range entries {
+				if e.Mode != modes[e.Name] {
+					t.Errorf("%s: mode %v; want %v", e.Name, e.Mode, modes[e.Name])
+				}
+			}
+			if e := entries[1]; e.Size != 8 || e.SHA256 != sha256.Sum256([]byte("go1.99.0")) {
+				t.Errorf("%s: size %d, wrong content hash", e.Name, e.Size)
+			}
+			if e := entries[4]; e.Linkname != "VERSION" {
+				t.Errorf("%s: link target %q; want %q", e.Name, e.Linkname, "VERSION")
+			}
+		})
+	}
+}
+
+func TestWriteReproducibleErrors(t *testing.T) {
+	fl := new(FileList)
+	fl.AddRegular(tarHeader(t, fileInfo{name: "a", mode: 0644, size: 1}), 1, strings.NewReader("a"))
+	fl.AddHeader(&tar.Header{Name: "b", Typeflag: tar.TypeLink, Linkname: "a"})
+	if err := fl.WriteReproducible(new(bytes.Buffer), FormatTarGz, testModTime); err == nil {
+		t.Errorf("WriteReproducible of a hard link succeeded")
+	}
+
+	fl = new(FileList)
+	fl.AddHeader(tarHeader(t, fileInfo{name: "d", mode: 0755 | os.ModeDir}))
+	fl.AddHeader(tarHeader(t, fileInfo{name: "d/", mode: 0755 | os.ModeDir}))
+	if err := fl.WriteReproducible(new(bytes.Buffer), FormatZip, testModTime); err == nil {
+		t.Errorf("WriteReproducible of a duplicate entry succeeded")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	for _, format := range testFormats {
+		t.Run(format.String(), func(t *testing.T) {
+			write := func(fl *FileList, modTime time.Time) []byte {
+				var buf bytes.Buffer
+				if err := fl.WriteReproducible(&buf, format, modTime); err != nil {
+					t.Fatal(err)
+				}
+				return buf.Bytes()
+			}
+			a := write(testFileList(t, []int{0, 1, 2}, 0), testModTime)
+
+			diffs, err := Compare(a, a, format)
+			if err != nil || len(diffs) != 0 {
+				t.Errorf("Compare of an archive with itself = %q, %v; want none", diffs, err)
+			}
+
+			fl := testFileList(t, []int{0, 2, 4}, 0)
+			fl.AddRegular(tarHeader(t, fileInfo{name: "go/VERSION", mode: 0755, size: 8}), 8, strings.NewReader("go1.99.1"))
+			b := write(fl, testModTime.Add(time.Hour))
+			diffs, err = Compare(a, b, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []string{
+				"go/VERSION: mode -rw-r--r-- != -rwxr-xr-x",
+				"go/VERSION: content differs",
+				"go/link: only in second archive",
+			}
+			for _, w := range want {
+				if !slices.Contains(diffs, w) {
+					t.Errorf("Compare = %q; want it to include %q", diffs, w)
+				}
+			}
+			if !slices.ContainsFunc(diffs, func(d string) bool { return strings.Contains(d, ": mtime ") }) {
+				t.Errorf("Compare = %q; want a difference in mtime", diffs)
+			}
+
+			if _, err := Compare(a, []byte("not an archive"), format); err == nil {
+				t.Errorf("Compare with a malformed archive succeeded")
+			}
+		})
+	}
+}
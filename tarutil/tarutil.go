@@ -2,7 +2,8 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package tarutil contains utilities for working with tar archives.
+// Package tarutil contains utilities for working with tar archives,
+// and for writing reproducible tar, zip, and cpio archives.
 package tarutil
 
 import (
@@ -10,6 +11,8 @@ import (
 	"compress/gzip"
 	"errors"
 	"io"
+
+	"golang.org/x/build/pargzip"
 )
 
 // FileList is a list of entries in a tar archive which acts
@@ -50,9 +53,31 @@ func (fl *FileList) AddRegular(h *tar.Header, size int64, content io.ReaderAt) {
 // Callers must call Close on the returned ReadCloser to release
 // resources.
 func (fl *FileList) TarGz() io.ReadCloser {
+	return fl.compressedTar(func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })
+}
+
+// TarAs is like TarGz, but compresses the tar file in parallel with a
+// pargzip.Writer, in the format with the given content type: one of
+// pargzip.GzipContentType, pargzip.ZstdContentType, or
+// pargzip.XzContentType.
+func (fl *FileList) TarAs(contentType string) (io.ReadCloser, error) {
+	// Check the content type before starting.
+	if _, err := pargzip.NewWriterFor(io.Discard, contentType); err != nil {
+		return nil, err
+	}
+	return fl.compressedTar(func(w io.Writer) io.WriteCloser {
+		zw, _ := pargzip.NewWriterFor(w, contentType)
+		return zw
+	}), nil
+}
+
+// compressedTar returns an io.ReadCloser of a tar file containing the
+// contents of the FileList, compressed by the writer that newWriter
+// returns.
+func (fl *FileList) compressedTar(newWriter func(io.Writer) io.WriteCloser) io.ReadCloser {
 	pr, pw := io.Pipe()
 	go func() {
-		err := fl.writeTarGz(pw)
+		err := fl.writeTar(newWriter(pw))
 		pw.CloseWithError(err)
 	}()
 	return struct {
@@ -67,8 +92,7 @@ func (fl *FileList) TarGz() io.ReadCloser {
 	}
 }
 
-func (fl *FileList) writeTarGz(w *io.PipeWriter) error {
-	zw := gzip.NewWriter(w)
+func (fl *FileList) writeTar(zw io.WriteCloser) error {
 	tw := tar.NewWriter(zw)
 	for _, f := range fl.files {
 		if err := tw.WriteHeader(f.header); err != nil {
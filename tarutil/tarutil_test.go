@@ -13,6 +13,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"golang.org/x/build/pargzip"
 )
 
 // fileInfo is an os.FileInfo implementation for tarHeader.
@@ -80,3 +82,33 @@ func TestFileList(t *testing.T) {
 		t.Errorf("number of entries = %d; want 2", saw)
 	}
 }
+
+func TestFileListTarAs(t *testing.T) {
+	fl := new(FileList)
+	fl.AddRegular(tarHeader(t, fileInfo{name: "regular.txt", mode: 0644, size: 7}), 7, strings.NewReader("foo bar"))
+
+	for _, ct := range []string{pargzip.GzipContentType, pargzip.ZstdContentType} {
+		tz, err := fl.TarAs(ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zr, err := pargzip.NewReaderFor(tz, ct)
+		if err != nil {
+			t.Fatalf("%s: NewReaderFor: %v", ct, err)
+		}
+		tr := tar.NewReader(zr)
+		h, err := tr.Next()
+		if err != nil {
+			t.Fatalf("%s: tar.Reader.Next: %v", ct, err)
+		}
+		all, err := io.ReadAll(tr)
+		if h.Name != "regular.txt" || err != nil || string(all) != "foo bar" {
+			t.Errorf("%s: read %q = %q, %v; want regular.txt = \"foo bar\"", ct, h.Name, all, err)
+		}
+		zr.Close()
+		tz.Close()
+	}
+	if _, err := fl.TarAs("application/x-bzip2"); err == nil {
+		t.Errorf("TarAs of an unsupported content type succeeded")
+	}
+}